@@ -11,8 +11,12 @@ import (
 	"go.temporal.io/sdk/client"
 
 	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
 	"github.com/finops-claw-gang/finops-go/internal/config"
+	"github.com/finops-claw-gang/finops-go/internal/history"
 	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 )
 
@@ -35,9 +39,22 @@ func main() {
 		}
 	}
 
-	c, err := client.Dial(client.Options{
-		Logger: temporalLogger,
-	})
+	if cfg.MetricsPort != "0" {
+		handler, shutdown, err := observability.InitMetrics("api")
+		if err != nil {
+			logger.Error("metrics init failed", "error", err)
+		} else {
+			defer shutdown(context.Background())
+			observability.ServeMetrics(":"+cfg.MetricsPort, handler)
+		}
+	}
+
+	clientOpts := client.Options{Logger: temporalLogger}
+	if err := observability.ConfigureTemporal(&clientOpts, cfg.OTelEnabled); err != nil {
+		logger.Error("temporal tracing init failed", "error", err)
+		os.Exit(1)
+	}
+	c, err := client.Dial(clientOpts)
 	if err != nil {
 		logger.Error("unable to create Temporal client", "error", err)
 		os.Exit(1)
@@ -57,6 +74,47 @@ func main() {
 		os.Exit(1)
 	}
 
+	srv.SetTenantScheduling(cfg.PremiumTenants, cfg.TenantWeights)
+
+	if cfg.SavingsLedgerPath != "" {
+		ledger, err := savings.OpenFileStore(cfg.SavingsLedgerPath)
+		if err != nil {
+			logger.Error("savings ledger open failed", "error", err)
+			os.Exit(1)
+		}
+		srv.SetSavings(ledger)
+	}
+
+	if cfg.HistoryStorePath != "" {
+		hist, err := history.OpenFileStore(cfg.HistoryStorePath)
+		if err != nil {
+			logger.Error("anomaly history open failed", "error", err)
+			os.Exit(1)
+		}
+		srv.SetHistory(hist)
+	}
+
+	if cfg.AuditLogPath != "" {
+		auditLog, err := audit.OpenFileLog(cfg.AuditLogPath)
+		if err != nil {
+			logger.Error("audit log open failed", "error", err)
+			os.Exit(1)
+		}
+		srv.SetAudit(auditLog)
+	}
+
+	if cfg.APIKeysPath != "" {
+		keys, err := apikey.OpenFileStore(cfg.APIKeysPath)
+		if err != nil {
+			logger.Error("api key store open failed", "error", err)
+			os.Exit(1)
+		}
+		srv.SetAPIKeys(keys)
+		logger.Info("API key authentication enabled", "path", cfg.APIKeysPath)
+	}
+
+	srv.SetMetrics(observability.Default())
+
 	var handler http.Handler = srv
 	if cfg.OTelEnabled {
 		handler = otelhttp.NewHandler(handler, "finops-api")
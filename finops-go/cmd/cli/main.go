@@ -2,10 +2,24 @@
 //
 // Usage:
 //
-//	finops trigger --tenant T --service S --delta D
+//	finops trigger --tenant T --service S --delta D [--idempotency-key K]
 //	finops status  --workflow-id WID
-//	finops approve --workflow-id WID --by USER
-//	finops deny    --workflow-id WID --by USER --reason R
+//	finops list    [--tenant T] [--severity S] [--approval-status A] [--min-delta D] ...
+//	finops approve --workflow-id WID [--by USER]
+//	finops deny    --workflow-id WID [--by USER] --reason R
+//	finops audit verify [--file PATH]
+//	finops apikey create --name N --tenant T --roles R1,R2 [--expires 720h] [--admin] [--file PATH]
+//	finops apikey list   [--tenant T] [--file PATH]
+//	finops apikey revoke --id ID [--file PATH]
+//
+// Workflow commands go through the HTTP API at $FINOPS_API_URL (default
+// http://localhost:8080), authenticated with $FINOPS_API_KEY or the OIDC
+// token in $FINOPS_API_TOKEN. The audit and apikey commands work on local
+// files.
+//
+// Failures print the error code and request ID, and exit 3 for not_found,
+// 4 for conflict or invalid_state, 5 for unauthenticated or forbidden, 6
+// for upstream_unavailable, unavailable or budget_exceeded, and 1 otherwise.
 package main
 
 import (
@@ -15,13 +29,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
-	"go.temporal.io/sdk/client"
-
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/client"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
-	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
-	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
-	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
 )
 
 func main() {
@@ -34,26 +51,39 @@ func main() {
 		cmdTrigger(os.Args[2:])
 	case "status":
 		cmdStatus(os.Args[2:])
+	case "list":
+		cmdList(os.Args[2:])
 	case "approve":
 		cmdApprove(os.Args[2:])
 	case "deny":
 		cmdDeny(os.Args[2:])
+	case "audit":
+		cmdAudit(os.Args[2:])
+	case "apikey":
+		cmdAPIKey(os.Args[2:])
 	default:
 		usage()
 	}
 }
 
 func usage() {
-	fmt.Fprintln(os.Stderr, "usage: finops <trigger|status|approve|deny> [flags]")
+	fmt.Fprintln(os.Stderr, "usage: finops <trigger|status|list|approve|deny|audit|apikey> [flags]")
 	os.Exit(1)
 }
 
-func dial() client.Client {
-	c, err := client.Dial(client.Options{})
-	if err != nil {
-		log.Fatalf("unable to create Temporal client: %v", err)
+// apiClient connects to the API named by the environment.
+func apiClient() *client.Client {
+	base := os.Getenv("FINOPS_API_URL")
+	if base == "" {
+		base = "http://localhost:8080"
 	}
-	return c
+	var opts []client.Option
+	if key := os.Getenv("FINOPS_API_KEY"); key != "" {
+		opts = append(opts, client.WithAPIKey(key))
+	} else if token := os.Getenv("FINOPS_API_TOKEN"); token != "" {
+		opts = append(opts, client.WithToken(token))
+	}
+	return client.New(base, opts...)
 }
 
 func cmdTrigger(args []string) {
@@ -64,6 +94,7 @@ func cmdTrigger(args []string) {
 	account := fs.String("account", "123456789012", "AWS account ID")
 	windowStart := fs.String("window-start", "2026-02-01", "analysis window start")
 	windowEnd := fs.String("window-end", "2026-02-16", "analysis window end")
+	idemKey := fs.String("idempotency-key", "", "names the workflow; retries with the same key start it once")
 	_ = fs.Parse(args)
 
 	if *tenant == "" || *service == "" || *delta == 0 {
@@ -71,30 +102,20 @@ func cmdTrigger(args []string) {
 		os.Exit(1)
 	}
 
-	anomaly := domain.NewCostAnomaly()
-	anomaly.Service = *service
-	anomaly.AccountID = *account
-	anomaly.DeltaDollars = *delta
-
-	input := workflows.WorkflowInput{
-		Tenant:      domain.NewTenantContext(*tenant),
-		Anomaly:     &anomaly,
+	res, err := apiClient().TriggerAnomaly(context.Background(), api.TriggerAnomalyRequest{
+		Tenant:      *tenant,
+		Anomaly:     domain.CostAnomaly{Service: *service, AccountID: *account, DeltaDollars: *delta},
 		WindowStart: *windowStart,
 		WindowEnd:   *windowEnd,
-	}
-
-	wfID := fmt.Sprintf("finops-anomaly-%s-%s", *tenant, anomaly.AnomalyID)
-	c := dial()
-	defer c.Close()
-
-	run, err := c.ExecuteWorkflow(context.Background(), client.StartWorkflowOptions{
-		ID:        wfID,
-		TaskQueue: versioning.QueueAnomaly,
-	}, workflows.AnomalyLifecycleWorkflow, input)
+	}, *idemKey)
 	if err != nil {
-		log.Fatalf("failed to start workflow: %v", err)
+		fatal("failed to start workflow", err)
+	}
+	if res.Existing {
+		fmt.Printf("workflow %s already started (run=%s)\n", res.WorkflowID, res.RunID)
+		return
 	}
-	fmt.Printf("started workflow %s (run=%s)\n", run.GetID(), run.GetRunID())
+	fmt.Printf("started workflow %s (run=%s)\n", res.WorkflowID, res.RunID)
 }
 
 func cmdStatus(args []string) {
@@ -107,72 +128,229 @@ func cmdStatus(args []string) {
 		os.Exit(1)
 	}
 
-	c := dial()
-	defer c.Close()
-
-	desc, err := c.DescribeWorkflowExecution(context.Background(), *wfID, "")
+	res, err := apiClient().GetWorkflow(context.Background(), *wfID)
 	if err != nil {
-		log.Fatalf("failed to describe workflow: %v", err)
+		fatal("failed to get workflow", err)
+	}
+	printJSON(map[string]any{
+		"workflow_id":      *wfID,
+		"tenant":           res.State.Tenant.TenantID,
+		"phase":            res.State.CurrentPhase,
+		"approval":         res.State.Approval,
+		"should_terminate": res.State.ShouldTerminate,
+		"reason":           res.Reason,
+	})
+}
+
+func cmdList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	status := fs.String("status", "", "workflow status (e.g. Running, Completed)")
+	tenant := fs.String("tenant", "", "tenant ID")
+	service := fs.String("service", "", "AWS service name")
+	account := fs.String("account", "", "AWS account ID")
+	category := fs.String("category", "", "triage category")
+	severity := fs.String("severity", "", "triage severity")
+	phase := fs.String("phase", "", "lifecycle phase (e.g. hil_gate, monitoring)")
+	approval := fs.String("approval-status", "", "approval status (e.g. pending)")
+	minDelta := fs.Float64("min-delta", 0, "minimum daily dollar delta")
+	limit := fs.Int("limit", 50, "maximum results")
+	_ = fs.Parse(args)
+
+	filter := client.WorkflowFilter{
+		Status:         *status,
+		Tenant:         *tenant,
+		Service:        *service,
+		Account:        *account,
+		Category:       *category,
+		Severity:       *severity,
+		Phase:          *phase,
+		ApprovalStatus: *approval,
+		Limit:          *limit,
+	}
+	if *minDelta > 0 {
+		filter.MinDelta = minDelta
 	}
 
-	data, err := json.MarshalIndent(map[string]any{
-		"workflow_id": *wfID,
-		"status":      desc.WorkflowExecutionInfo.Status.String(),
-		"start_time":  desc.WorkflowExecutionInfo.StartTime,
-		"close_time":  desc.WorkflowExecutionInfo.CloseTime,
-	}, "", "  ")
+	summaries, err := apiClient().ListWorkflows(context.Background(), filter)
 	if err != nil {
-		log.Fatalf("failed to marshal status: %v", err)
+		fatal("failed to list workflows", err)
 	}
-	fmt.Println(string(data))
+	printJSON(summaries)
 }
 
 func cmdApprove(args []string) {
 	fs := flag.NewFlagSet("approve", flag.ExitOnError)
 	wfID := fs.String("workflow-id", "", "workflow ID (required)")
-	by := fs.String("by", "", "approver identity (required)")
+	by := fs.String("by", "", "approver identity (default: the authenticated caller)")
 	_ = fs.Parse(args)
 
-	if *wfID == "" || *by == "" {
+	if *wfID == "" {
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	sendUpdate(*wfID, activities.ApprovalResponse{Approved: true, By: *by})
+	result, err := apiClient().Approve(context.Background(), *wfID, *by)
+	if err != nil {
+		fatal("approval failed", err)
+	}
+	fmt.Printf("update result: %s\n", result)
 }
 
 func cmdDeny(args []string) {
 	fs := flag.NewFlagSet("deny", flag.ExitOnError)
 	wfID := fs.String("workflow-id", "", "workflow ID (required)")
-	by := fs.String("by", "", "denier identity (required)")
+	by := fs.String("by", "", "denier identity (default: the authenticated caller)")
 	reason := fs.String("reason", "", "denial reason")
 	_ = fs.Parse(args)
 
-	if *wfID == "" || *by == "" {
+	if *wfID == "" {
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	sendUpdate(*wfID, activities.ApprovalResponse{Approved: false, By: *by, Reason: *reason})
+	result, err := apiClient().Deny(context.Background(), *wfID, *by, *reason)
+	if err != nil {
+		fatal("denial failed", err)
+	}
+	fmt.Printf("update result: %s\n", result)
 }
 
-func sendUpdate(wfID string, resp activities.ApprovalResponse) {
-	c := dial()
-	defer c.Close()
+func cmdAudit(args []string) {
+	if len(args) == 0 || args[0] != "verify" {
+		fmt.Fprintln(os.Stderr, "usage: finops audit verify [--file PATH]")
+		os.Exit(1)
+	}
+	fs := flag.NewFlagSet("audit verify", flag.ExitOnError)
+	file := fs.String("file", os.Getenv("FINOPS_AUDIT_LOG"), "audit log file (default $FINOPS_AUDIT_LOG)")
+	_ = fs.Parse(args[1:])
 
-	handle, err := c.UpdateWorkflow(context.Background(), client.UpdateWorkflowOptions{
-		WorkflowID:   wfID,
-		UpdateName:   workflows.UpdateNameApproval,
-		Args:         []any{resp},
-		WaitForStage: client.WorkflowUpdateStageCompleted,
-	})
+	if *file == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	rep, err := audit.Verify(*file)
 	if err != nil {
-		log.Fatalf("failed to send update: %v", err)
+		fmt.Fprintf(os.Stderr, "audit chain INVALID after %d good events: %v\n", rep.Events, err)
+		os.Exit(2)
 	}
+	fmt.Printf("audit chain OK: %d events, head %s\n", rep.Events, rep.Head)
+}
 
-	var result string
-	if err := handle.Get(context.Background(), &result); err != nil {
-		log.Fatalf("update failed: %v", err)
+// cmdAPIKey manages the API key file directly, so the first admin key can
+// be issued before the API server accepts any.
+func cmdAPIKey(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: finops apikey <create|list|revoke> [flags]")
+		os.Exit(1)
 	}
-	fmt.Printf("update result: %s\n", result)
+	fs := flag.NewFlagSet("apikey "+args[0], flag.ExitOnError)
+	file := fs.String("file", os.Getenv("FINOPS_API_KEYS"), "API key file (default $FINOPS_API_KEYS)")
+	open := func() *apikey.FileStore {
+		if *file == "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+		store, err := apikey.OpenFileStore(*file)
+		if err != nil {
+			log.Fatalf("failed to open key file: %v", err)
+		}
+		return store
+	}
+
+	switch args[0] {
+	case "create":
+		name := fs.String("name", "", "key name (required)")
+		tenant := fs.String("tenant", "", "tenant the key is scoped to (required unless --admin)")
+		roles := fs.String("roles", "", "comma-separated roles (required)")
+		expires := fs.Duration("expires", 0, "lifetime, e.g. 720h (default: never expires)")
+		admin := fs.Bool("admin", false, "grant every permission in every tenant")
+		_ = fs.Parse(args[1:])
+
+		spec := apikey.Key{Name: *name, TenantID: *tenant, Admin: *admin, CreatedBy: "cli"}
+		if user := os.Getenv("USER"); user != "" {
+			spec.CreatedBy += ":" + user
+		}
+		for _, r := range strings.Split(*roles, ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				spec.Roles = append(spec.Roles, rbac.Role(r))
+			}
+		}
+		now := time.Now()
+		if *expires > 0 {
+			spec.ExpiresAt = now.Add(*expires).UTC()
+		}
+		token, k, err := apikey.Issue(open(), spec, now)
+		if err != nil {
+			fatal("failed to create key", err)
+		}
+		printJSON(map[string]any{"token": token, "key": k.Redacted()})
+		fmt.Fprintln(os.Stderr, "store the token now: it cannot be shown again")
+
+	case "list":
+		tenant := fs.String("tenant", "", "only keys of this tenant")
+		_ = fs.Parse(args[1:])
+
+		keys, err := open().List(*tenant)
+		if err != nil {
+			fatal("failed to list keys", err)
+		}
+		for i := range keys {
+			keys[i] = keys[i].Redacted()
+		}
+		printJSON(keys)
+
+	case "revoke":
+		id := fs.String("id", "", "key ID (required)")
+		_ = fs.Parse(args[1:])
+
+		if *id == "" {
+			fs.Usage()
+			os.Exit(1)
+		}
+		if err := open().Revoke(*id, time.Now()); err != nil {
+			fatal("failed to revoke key", err)
+		}
+		fmt.Printf("revoked %s\n", *id)
+
+	default:
+		fmt.Fprintln(os.Stderr, "usage: finops apikey <create|list|revoke> [flags]")
+		os.Exit(1)
+	}
+}
+
+// exitCodes are the exit statuses for error codes, so scripts can tell a
+// missing workflow or a decided approval from an outage. Anything else
+// exits 1; a broken audit chain exits 2.
+var exitCodes = map[apperr.Code]int{
+	apperr.NotFound:            3,
+	apperr.Conflict:            4,
+	apperr.InvalidState:        4,
+	apperr.Unauthenticated:     5,
+	apperr.Forbidden:           5,
+	apperr.UpstreamUnavailable: 6,
+	apperr.Unavailable:         6,
+	apperr.BudgetExceeded:      6,
+}
+
+// fatal reports a failed command and exits with the status for the
+// error's code: the API's for API errors, else apperr's classification.
+func fatal(what string, err error) {
+	code := client.CodeOf(err)
+	if code == "" {
+		code = apperr.CodeOf(err)
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", what, err)
+	if status, ok := exitCodes[code]; ok {
+		os.Exit(status)
+	}
+	os.Exit(1)
+}
+
+func printJSON(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("failed to marshal output: %v", err)
+	}
+	fmt.Println(string(data))
 }
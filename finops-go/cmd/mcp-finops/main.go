@@ -5,15 +5,25 @@ package main
 import (
 	"context"
 	"log"
+	"slices"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"go.temporal.io/sdk/client"
 
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/config"
 	"github.com/finops-claw-gang/finops-go/internal/mcpserver"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 )
 
 func main() {
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+
 	c, err := client.Dial(client.Options{})
 	if err != nil {
 		log.Fatalf("unable to create Temporal client: %v", err)
@@ -26,7 +36,49 @@ func main() {
 		Name:    "finops-claw-gang",
 		Version: "v1.0.0",
 	}, nil)
-	mcpserver.RegisterTools(server, q)
+	var access mcpserver.Access
+	switch {
+	case cfg.OIDCEnabled():
+		// With OIDC on, only a verified token identifies the caller;
+		// FINOPS_MCP_SUBJECT and FINOPS_MCP_ROLES are self-asserted.
+		if cfg.MCPToken == "" {
+			log.Fatalf("FINOPS_OIDC_ISSUER requires FINOPS_MCP_TOKEN")
+		}
+		verifier, err := api.NewTokenVerifier(context.Background(), api.OIDCConfig{
+			IssuerURL: cfg.OIDCIssuer,
+			Audience:  cfg.OIDCAudience,
+			Enabled:   true,
+		})
+		if err != nil {
+			log.Fatalf("mcp token: %v", err)
+		}
+		p, err := verifier.Verify(context.Background(), cfg.MCPToken)
+		if err != nil {
+			log.Fatalf("mcp token: %v", err)
+		}
+		access.Principal = &p
+		access.Authenticate = func(ctx context.Context) error {
+			_, err := verifier.Verify(ctx, cfg.MCPToken)
+			return err
+		}
+	case cfg.MCPToken != "":
+		log.Fatalf("FINOPS_MCP_TOKEN requires FINOPS_OIDC_ISSUER")
+	case len(cfg.MCPRoles) > 0:
+		access.Principal = &rbac.Principal{
+			Subject:  cfg.MCPSubject,
+			TenantID: cfg.MCPTenantID,
+			Roles:    rbac.RolesFromClaims(cfg.MCPRoles),
+			Admin:    slices.Contains(cfg.MCPRoles, "admin"),
+		}
+	}
+	if cfg.AuditLogPath != "" {
+		auditLog, err := audit.OpenFileLog(cfg.AuditLogPath)
+		if err != nil {
+			log.Fatalf("audit log open failed: %v", err)
+		}
+		access.Audit = auditLog
+	}
+	mcpserver.RegisterTools(server, q, access)
 
 	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
 		log.Fatalf("mcp server error: %v", err)
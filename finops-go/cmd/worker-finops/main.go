@@ -1,6 +1,7 @@
 // Command worker-finops runs the Temporal worker for FinOps workflows.
 // Supports stub mode (fixtures) and production mode (real AWS connectors).
-// Supports multi-queue operation via FINOPS_WORKER_QUEUES env var.
+// Supports multi-queue operation via FINOPS_WORKER_QUEUES env var, plus the
+// dedicated queues of FINOPS_PREMIUM_TENANTS.
 package main
 
 import (
@@ -14,14 +15,21 @@ import (
 	"go.temporal.io/sdk/worker"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/calendar"
 	"github.com/finops-claw-gang/finops-go/internal/config"
 	"github.com/finops-claw-gang/finops-go/internal/connectors"
 	awsauth "github.com/finops-claw-gang/finops-go/internal/connectors/aws"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/awsdoctor"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/kubecost"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/executor"
+	"github.com/finops-claw-gang/finops-go/internal/history"
 	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/policy"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/queues"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
@@ -62,11 +70,35 @@ func main() {
 		}
 	}
 
+	if cfg.MetricsPort != "0" {
+		handler, shutdown, err := observability.InitMetrics("worker-finops")
+		if err != nil {
+			logger.Error("metrics init failed", "error", err)
+		} else {
+			defer shutdown(context.Background())
+			observability.ServeMetrics(":"+cfg.MetricsPort, handler)
+		}
+	}
+
+	// Rate limits and activity budgets are per replica unless a shared
+	// state file is configured.
+	var limitStore ratelimit.Store
+	if cfg.RateLimitStatePath != "" {
+		fileStore, err := ratelimit.OpenFileStore(cfg.RateLimitStatePath)
+		if err != nil {
+			logger.Error("rate limit state open failed", "error", err)
+			os.Exit(1)
+		}
+		limitStore = fileStore
+		logger.Info("shared rate limit state enabled", "path", cfg.RateLimitStatePath)
+	}
+
 	var (
-		cost     activities.CostDeps
-		infra    activities.InfraDeps
-		kubeCost triage.KubeCostQuerier
-		awsDoc   activities.AWSDocDeps
+		cost       activities.CostDeps
+		infra      activities.InfraDeps
+		kubeCost   triage.KubeCostQuerier
+		awsDoc     activities.AWSDocDeps
+		costBudget *ratelimit.CostBudget
 	)
 
 	switch cfg.Mode {
@@ -77,11 +109,55 @@ func main() {
 			os.Exit(1)
 		}
 
-		cost = connectors.NewAWSCostClient(awsCfg, cfg.CURDatabase, cfg.CURTable, cfg.CURWorkgroup, cfg.CUROutputBucket)
-		infra = connectors.NewAWSInfraClient(awsCfg)
+		costBudget = ratelimit.NewCostBudget(cfg.ToolingBudgetMonthly)
+		if limitStore != nil {
+			costBudget.SetStore(limitStore)
+		}
+		for tenantID, limit := range cfg.ToolingBudgetTenants {
+			costBudget.SetTenantLimit(tenantID, limit)
+		}
+		meter := apicost.NewMeter(apicost.DefaultPrices())
+		meter.SetBudget(costBudget)
+		meter.Attach(&awsCfg)
+
+		limiter := ratelimit.NewServiceLimiter(ratelimit.ServiceRates{
+			CostExplorer: cfg.RateLimitCE,
+			Athena:       cfg.RateLimitAthena,
+			CloudWatch:   cfg.RateLimitCW,
+			STS:          cfg.RateLimitSTS,
+			CodeDeploy:   cfg.RateLimitCodeDeploy,
+			Tagging:      cfg.RateLimitTagging,
+			ELBv2:        cfg.RateLimitELB,
+			KubeCost:     cfg.RateLimitKubeCost,
+			TenantShare:  cfg.RateLimitTenantShare,
+		})
+		if limitStore != nil {
+			limiter.SetStore(limitStore)
+		}
+
+		costClient := connectors.NewAWSCostClient(awsCfg, cfg.CURDatabase, cfg.CURTable, cfg.CURWorkgroup, cfg.CUROutputBucket)
+		costClient.SetLimiter(limiter)
+		cost = costClient
+		if cfg.CostCacheEntries > 0 {
+			cached, err := connectors.NewCachedCostClient(cost, connectors.CacheOptions{
+				Entries: cfg.CostCacheEntries,
+				Dir:     cfg.CostCacheDir,
+				TTLs:    connectors.DefaultCacheTTLs(),
+			})
+			if err != nil {
+				logger.Error("cost cache init failed", "error", err)
+				os.Exit(1)
+			}
+			cost = cached
+		}
+		infraClient := connectors.NewAWSInfraClient(awsCfg)
+		infraClient.SetLimiter(limiter)
+		infra = infraClient
 
 		if cfg.KubeCostEndpoint != "" {
-			kubeCost = kubecost.New(cfg.KubeCostEndpoint)
+			kc := kubecost.New(cfg.KubeCostEndpoint)
+			kc.SetLimiter(limiter)
+			kubeCost = kc
 		} else {
 			kubeCost = &testutil.StubKubeCost{FixturesDir: testutil.GoldenDir()}
 		}
@@ -101,9 +177,12 @@ func main() {
 		awsDoc = &testutil.StubAWSDoctor{FixturesDir: fixturesDir}
 	}
 
-	c, err := client.Dial(client.Options{
-		Logger: temporalLogger,
-	})
+	clientOpts := client.Options{Logger: temporalLogger}
+	if err := observability.ConfigureTemporal(&clientOpts, cfg.OTelEnabled); err != nil {
+		logger.Error("temporal tracing init failed", "error", err)
+		os.Exit(1)
+	}
+	c, err := client.Dial(clientOpts)
 	if err != nil {
 		logger.Error("unable to create Temporal client", "error", err)
 		os.Exit(1)
@@ -111,13 +190,106 @@ func main() {
 	defer c.Close()
 
 	exec := executor.NewExecutor(infra)
+	exec.SetBlastRadius(cfg.BlastRadius)
+	if limitStore != nil {
+		exec.SetStore(limitStore)
+	}
+	if cfg.KillSwitchDir != "" {
+		exec.SetKillSwitch(executor.FileKillSwitch{Dir: cfg.KillSwitchDir})
+		logger.Info("kill switch enabled", "dir", cfg.KillSwitchDir)
+	}
+	if cfg.ExecutionLedgerPath != "" {
+		ledger, err := executor.OpenFileLedger(cfg.ExecutionLedgerPath)
+		if err != nil {
+			logger.Error("execution ledger open failed", "path", cfg.ExecutionLedgerPath, "error", err)
+			os.Exit(1)
+		}
+		exec.SetLedger(ledger)
+		logger.Info("execution ledger enabled", "path", cfg.ExecutionLedgerPath)
+	}
+	if cfg.ProtectionRulesPath != "" {
+		data, err := os.ReadFile(cfg.ProtectionRulesPath)
+		if err != nil {
+			logger.Error("protection rules read failed", "error", err)
+			os.Exit(1)
+		}
+		rules, err := policy.ParseProtectionRules(data)
+		if err != nil {
+			logger.Error("protection rules invalid", "error", err)
+			os.Exit(1)
+		}
+		exec.SetProtectionRules(rules)
+		logger.Info("protection rules loaded", "path", cfg.ProtectionRulesPath, "rules", len(rules.Rules))
+	}
+
+	var changeCal *calendar.Calendar
+	if cfg.ChangeCalendarPath != "" {
+		changeCal, err = calendar.Load(cfg.ChangeCalendarPath)
+		if err != nil {
+			logger.Error("change calendar load failed", "error", err)
+			os.Exit(1)
+		}
+		exec.SetCalendar(changeCal)
+		logger.Info("change calendar loaded", "path", cfg.ChangeCalendarPath,
+			"windows", len(changeCal.Windows), "freezes", len(changeCal.Freezes))
+	}
+
+	metrics := observability.Default()
+
+	var ledger savings.Repository
+	if cfg.SavingsLedgerPath != "" {
+		store, err := savings.OpenFileStore(cfg.SavingsLedgerPath)
+		if err != nil {
+			logger.Error("savings ledger open failed", "error", err)
+			os.Exit(1)
+		}
+		ledger = store
+		logger.Info("savings ledger enabled", "path", cfg.SavingsLedgerPath)
+	}
+
+	var hist history.Repository
+	if cfg.HistoryStorePath != "" {
+		store, err := history.OpenFileStore(cfg.HistoryStorePath)
+		if err != nil {
+			logger.Error("anomaly history open failed", "error", err)
+			os.Exit(1)
+		}
+		hist = store
+		logger.Info("anomaly history enabled", "path", cfg.HistoryStorePath)
+	}
+
+	var auditLog audit.Logger
+	if cfg.AuditLogPath != "" {
+		l, err := audit.OpenFileLog(cfg.AuditLogPath)
+		if err != nil {
+			logger.Error("audit log open failed", "error", err)
+			os.Exit(1)
+		}
+		auditLog = l
+		logger.Info("audit log enabled", "path", cfg.AuditLogPath)
+	}
+
+	var activityBudget *ratelimit.ActivityBudget
+	if cfg.ActivityBudgetMax > 0 {
+		activityBudget = ratelimit.NewActivityBudget(cfg.ActivityBudgetMax, cfg.ActivityBudgetWindow)
+		if limitStore != nil {
+			activityBudget.SetStore(limitStore)
+		}
+	}
 
 	acts := &activities.Activities{
-		Cost:     cost,
-		Infra:    infra,
-		KubeCost: kubeCost,
-		AWSDoc:   awsDoc,
-		Executor: exec,
+		Cost:       cost,
+		Infra:      infra,
+		KubeCost:   kubeCost,
+		AWSDoc:     awsDoc,
+		Executor:   exec,
+		Budget:     activityBudget,
+		Calendar:   changeCal,
+		Metrics:    metrics,
+		Savings:    ledger,
+		History:    hist,
+		Audit:      auditLog,
+		CostBudget: costBudget,
 	}
 
 	queueNames, err := queues.ParseQueues(cfg.WorkerQueues)
@@ -125,7 +297,8 @@ func main() {
 		logger.Error("parse queues failed", "error", err)
 		os.Exit(1)
 	}
-	queueConfigs := queues.DefaultConfigs()
+	queueNames = queues.WithTenantQueues(queueNames, cfg.PremiumTenants)
+	queueConfigs := queues.DefaultConfigs(cfg.PremiumTenants...)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
@@ -133,9 +306,16 @@ func main() {
 	g, ctx := errgroup.WithContext(ctx)
 	for _, qName := range queueNames {
 		qcfg := queueConfigs[qName]
-		w := worker.New(c, qName, qcfg.Options)
+		opts := qcfg.Options
+		opts.Interceptors = append(opts.Interceptors, metrics.WorkerInterceptor(workflows.UpdateNameApproval))
+		// Fair admission runs inside the metrics interceptor, so activity
+		// durations include the wait for a tenant's turn.
+		if sched := queues.NewScheduler(qcfg.Fairness); sched != nil {
+			opts.Interceptors = append(opts.Interceptors, sched.WorkerInterceptor())
+		}
+		w := worker.New(c, qName, opts)
 
-		switch qName {
+		switch qcfg.Base {
 		case versioning.QueueAnomaly:
 			w.RegisterWorkflow(workflows.AnomalyLifecycleWorkflow)
 			w.RegisterWorkflow(workflows.AWSDocSweepWorkflow)
@@ -147,7 +327,8 @@ func main() {
 			w.RegisterActivity(acts)
 		}
 
-		logger.Info("starting worker", "queue", qName, "mode", cfg.Mode)
+		logger.Info("starting worker", "queue", qName, "mode", cfg.Mode,
+			"capacity", qcfg.Fairness.Capacity, "tenant_cap", qcfg.Fairness.TenantCap)
 		g.Go(func() error {
 			return w.Run(worker.InterruptCh())
 		})
@@ -1,6 +1,8 @@
 package analysis
 
+import "context"
+
 // CostQuerier provides cost data needed by the analysis planner.
 type CostQuerier interface {
-	GetCURLineItems(accountID, startDate, endDate string, service string) ([]map[string]any, error)
+	GetCURLineItems(ctx context.Context, accountID, startDate, endDate string, service string) ([]map[string]any, error)
 }
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/finops-claw-gang/finops-go/internal/domain"
@@ -11,10 +12,11 @@ import (
 // This is a deterministic placeholder; in production the LLM may add narrative,
 // but actions must still pass policy validation.
 func AnalyzeAndRecommend(
+	ctx context.Context,
 	accountID, service, windowStart, windowEnd string,
 	cost CostQuerier,
 ) (domain.AnalysisResult, error) {
-	_, err := cost.GetCURLineItems(accountID, windowStart, windowEnd, service)
+	_, err := cost.GetCURLineItems(ctx, accountID, windowStart, windowEnd, service)
 	if err != nil {
 		return domain.AnalysisResult{}, fmt.Errorf("analysis: get CUR line items: %w", err)
 	}
@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -13,7 +14,7 @@ func TestAnalyzeAndRecommend(t *testing.T) {
 	goldenDir := testutil.GoldenDir()
 	cost := &testutil.StubCost{FixturesDir: goldenDir}
 
-	result, err := AnalyzeAndRecommend("123456789012", "EC2", "2026-02-01", "2026-02-16", cost)
+	result, err := AnalyzeAndRecommend(context.Background(), "123456789012", "EC2", "2026-02-01", "2026-02-16", cost)
 	if err != nil {
 		t.Fatalf("AnalyzeAndRecommend: %v", err)
 	}
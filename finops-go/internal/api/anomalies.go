@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+)
+
+// SetHistory enables GET /api/v1/anomalies backed by the given store.
+func (s *Server) SetHistory(repo history.Repository) {
+	s.history = repo
+}
+
+// handleListAnomalies searches the anomaly history. Query parameters:
+// tenant, service, account, team, category, severity, phase, reason,
+// min_delta and max_delta (dollars), from and to (inclusive YYYY-MM-DD on
+// start time), sort (started_at, updated_at or delta_dollars; "-" prefix
+// for descending), limit and cursor. Callers confined to a tenant only see
+// its anomalies.
+func (s *Server) handleListAnomalies(w http.ResponseWriter, r *http.Request) {
+	if s.history == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "anomaly history not configured"))
+		return
+	}
+
+	v := r.URL.Query()
+	tenant, ok := scopeTenant(w, r, v.Get("tenant"))
+	if !ok {
+		return
+	}
+	q := history.Query{
+		TenantID:  tenant,
+		Service:   v.Get("service"),
+		AccountID: v.Get("account"),
+		Team:      v.Get("team"),
+		Category:  domain.AnomalyCategory(v.Get("category")),
+		Severity:  domain.AnomalySeverity(v.Get("severity")),
+		Phase:     v.Get("phase"),
+		Reason:    v.Get("reason"),
+		From:      v.Get("from"),
+		To:        v.Get("to"),
+		Sort:      v.Get("sort"),
+		Cursor:    v.Get("cursor"),
+	}
+	for _, d := range []string{q.From, q.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "from/to must be YYYY-MM-DD"))
+			return
+		}
+	}
+	for name, dst := range map[string]**float64{"min_delta": &q.MinDelta, "max_delta": &q.MaxDelta} {
+		raw := v.Get(name)
+		if raw == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, name+" must be a number"))
+			return
+		}
+		*dst = &f
+	}
+	if raw := v.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "limit must be a positive integer"))
+			return
+		}
+		q.Limit = n
+	}
+
+	// Invalid queries are classified as invalid_argument.
+	page, err := s.history.Query(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
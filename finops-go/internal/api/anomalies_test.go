@@ -0,0 +1,97 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+)
+
+func newHistoryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	store := history.NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.Upsert(history.Record{
+			WorkflowID:   fmt.Sprintf("wf-%d", i),
+			TenantID:     "acme",
+			Service:      []string{"EC2", "S3"}[i%2],
+			Category:     domain.CategoryResourceWaste,
+			Severity:     domain.SeverityHigh,
+			DeltaDollars: float64(100 * (i + 1)),
+			Phase:        "completed",
+			StartedAt:    fmt.Sprintf("2026-03-0%dT10:00:00Z", i+1),
+		}))
+	}
+	srv, err := api.New(&stubQuerier{}, []string{"*"}, api.OIDCConfig{})
+	require.NoError(t, err)
+	srv.SetHistory(store)
+	return httptest.NewServer(srv)
+}
+
+func getAnomalies(t *testing.T, url string) history.Page {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page history.Page
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	return page
+}
+
+func TestListAnomalies_FilterAndSort(t *testing.T) {
+	ts := newHistoryServer(t)
+	defer ts.Close()
+
+	page := getAnomalies(t, ts.URL+"/api/v1/anomalies?service=EC2&min_delta=200&sort=-delta_dollars")
+	require.Len(t, page.Records, 2)
+	assert.Equal(t, "wf-4", page.Records[0].WorkflowID)
+	assert.Equal(t, "wf-2", page.Records[1].WorkflowID)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestListAnomalies_CursorPagination(t *testing.T) {
+	ts := newHistoryServer(t)
+	defer ts.Close()
+
+	first := getAnomalies(t, ts.URL+"/api/v1/anomalies?category=resource_waste&limit=3")
+	require.Len(t, first.Records, 3)
+	assert.Equal(t, "wf-4", first.Records[0].WorkflowID)
+	require.NotEmpty(t, first.NextCursor)
+
+	second := getAnomalies(t, ts.URL+"/api/v1/anomalies?category=resource_waste&limit=3&cursor="+first.NextCursor)
+	require.Len(t, second.Records, 2)
+	assert.Equal(t, "wf-1", second.Records[0].WorkflowID)
+	assert.Equal(t, "wf-0", second.Records[1].WorkflowID)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestListAnomalies_BadRequests(t *testing.T) {
+	ts := newHistoryServer(t)
+	defer ts.Close()
+
+	for _, q := range []string{"sort=severity", "cursor=bogus", "min_delta=lots", "limit=0", "from=yesterday"} {
+		resp, err := http.Get(ts.URL + "/api/v1/anomalies?" + q)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, q)
+	}
+}
+
+func TestListAnomalies_NotConfigured(t *testing.T) {
+	ts := newTestServer(t, &stubQuerier{})
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/anomalies")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
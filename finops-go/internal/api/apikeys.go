@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+// SetAPIKeys accepts API keys from store, alongside OIDC tokens, and
+// enables the /api/v1/apikeys endpoints. Without OIDC, every request
+// other than the public ones then needs a key.
+func (s *Server) SetAPIKeys(store apikey.Store) {
+	s.keys = store
+}
+
+// apiKeyFromRequest returns the API key a request carries, in X-API-Key
+// or as a Bearer token with the key prefix.
+func apiKeyFromRequest(r *http.Request) (string, bool) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key, true
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") && apikey.IsKey(token) {
+		return token, true
+	}
+	return "", false
+}
+
+// serveWithKey authenticates the request by its API key and serves it as
+// the key's principal.
+func (s *Server) serveWithKey(w http.ResponseWriter, r *http.Request, key string) {
+	k, err := apikey.Authenticate(s.keys, key, time.Now())
+	switch {
+	case errors.Is(err, apikey.ErrExpired):
+		writeError(w, r, apperr.New(apperr.Unauthenticated, "API key expired"))
+		return
+	case errors.Is(err, apikey.ErrRevoked):
+		writeError(w, r, apperr.New(apperr.Unauthenticated, "API key revoked"))
+		return
+	case errors.Is(err, apikey.ErrInvalid):
+		writeError(w, r, apperr.New(apperr.Unauthenticated, "invalid API key"))
+		return
+	case err != nil:
+		slog.ErrorContext(r.Context(), "api key lookup failed", "error", err)
+		writeError(w, r, apperr.New(apperr.Internal, "API key lookup failed"))
+		return
+	}
+	ctx := withPrincipal(r.Context(), k.Principal(), k.ID)
+	s.app.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateAPIKeyRequest is the body of POST /api/v1/apikeys. ExpiresIn is a
+// Go duration such as "720h"; empty means the key does not expire.
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name"`
+	Tenant    string   `json:"tenant,omitempty"`
+	Roles     []string `json:"roles"`
+	Admin     bool     `json:"admin,omitempty"`
+	ExpiresIn string   `json:"expires_in,omitempty"`
+}
+
+// CreateAPIKeyResponse carries the key string, shown only here.
+type CreateAPIKeyResponse struct {
+	Token string     `json:"token"`
+	Key   apikey.Key `json:"key"`
+}
+
+// APIKeyList is the JSON body of GET /api/v1/apikeys.
+type APIKeyList struct {
+	Keys []apikey.Key `json:"keys"`
+}
+
+// handleCreateAPIKey issues a key. Scoped callers issue keys for their own
+// tenant; only admins issue admin keys.
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if s.keys == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "API keys not configured"))
+		return
+	}
+	var body CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "invalid request body"))
+		return
+	}
+	if body.Admin && !IsAdminFromContext(r.Context()) {
+		writeError(w, r, apperr.New(apperr.Forbidden, "only admins may issue admin keys"))
+		return
+	}
+	tenant, ok := scopeTenant(w, r, body.Tenant)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	spec := apikey.Key{
+		Name:      body.Name,
+		TenantID:  tenant,
+		Admin:     body.Admin,
+		CreatedBy: UserFromContext(r.Context()),
+	}
+	for _, role := range body.Roles {
+		spec.Roles = append(spec.Roles, rbac.Role(role))
+	}
+	if body.ExpiresIn != "" {
+		d, err := time.ParseDuration(body.ExpiresIn)
+		if err != nil || d <= 0 {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "invalid 'expires_in' duration"))
+			return
+		}
+		spec.ExpiresAt = now.Add(d).UTC()
+	}
+
+	token, k, err := apikey.Issue(s.keys, spec, now)
+	if err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, err.Error()))
+		return
+	}
+
+	e := auditEvent(r, audit.ActionKeyCreated, "")
+	e.TenantID = k.TenantID
+	e.Resource = k.ID
+	e.Outcome = "created"
+	if e.Details == nil {
+		e.Details = map[string]string{}
+	}
+	e.Details["name"] = k.Name
+	e.Details["roles"] = joinRoles(k.Roles)
+	audit.Record(r.Context(), s.audit, e)
+
+	writeJSON(w, http.StatusCreated, CreateAPIKeyResponse{Token: token, Key: k.Redacted()})
+}
+
+// handleListAPIKeys lists keys without their hashes. Parameter: tenant.
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if s.keys == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "API keys not configured"))
+		return
+	}
+	tenant, ok := scopeTenant(w, r, r.URL.Query().Get("tenant"))
+	if !ok {
+		return
+	}
+	keys, err := s.keys.List(tenant)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	for i := range keys {
+		keys[i] = keys[i].Redacted()
+	}
+	writeJSON(w, http.StatusOK, APIKeyList{Keys: keys})
+}
+
+// handleRevokeAPIKey revokes a key. Another tenant's key is reported as
+// not found.
+func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if s.keys == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "API keys not configured"))
+		return
+	}
+	id := r.PathValue("id")
+	k, err := s.keys.Get(id)
+	if errors.Is(err, apikey.ErrNotFound) {
+		writeError(w, r, apperr.New(apperr.NotFound, "API key not found"))
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if own, scoped := tenantScope(r.Context()); scoped && (own == "" || k.TenantID != own) {
+		writeError(w, r, apperr.New(apperr.NotFound, "API key not found"))
+		return
+	}
+	if err := s.keys.Revoke(id, time.Now()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	e := auditEvent(r, audit.ActionKeyRevoked, "")
+	e.TenantID = k.TenantID
+	e.Resource = k.ID
+	e.Outcome = "revoked"
+	audit.Record(r.Context(), s.audit, e)
+
+	writeJSON(w, http.StatusOK, ResultResponse{Result: "revoked"})
+}
+
+func joinRoles(roles []rbac.Role) string {
+	s := make([]string, len(roles))
+	for i, r := range roles {
+		s[i] = string(r)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+type keyEnv struct {
+	srv   *Server
+	q     *tenantQuerier
+	store *apikey.FileStore
+}
+
+func newKeyEnv(t *testing.T) keyEnv {
+	t.Helper()
+	store, err := apikey.OpenFileStore(filepath.Join(t.TempDir(), "keys.jsonl"))
+	require.NoError(t, err)
+	q := &tenantQuerier{tenant: "acme"}
+	srv := newTenancyServer(t, q)
+	srv.SetAPIKeys(store)
+	return keyEnv{srv: srv, q: q, store: store}
+}
+
+// issue creates a key directly in the store, as the CLI does.
+func (e keyEnv) issue(t *testing.T, spec apikey.Key) string {
+	t.Helper()
+	if spec.Name == "" {
+		spec.Name = "test"
+	}
+	token, _, err := apikey.Issue(e.store, spec, time.Now())
+	require.NoError(t, err)
+	return token
+}
+
+func (e keyEnv) do(t *testing.T, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	e.srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	env := newKeyEnv(t)
+	approver := env.issue(t, apikey.Key{TenantID: "acme", Roles: []rbac.Role{rbac.Approver}})
+	viewer := env.issue(t, apikey.Key{TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}})
+	globex := env.issue(t, apikey.Key{TenantID: "globex", Roles: []rbac.Role{rbac.Approver}})
+	expired := env.issue(t, apikey.Key{TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}, ExpiresAt: time.Now().Add(-time.Minute)})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "health needs no key", method: http.MethodGet, path: "/api/v1/health", wantStatus: http.StatusOK},
+		{name: "no key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", wantStatus: http.StatusUnauthorized},
+		{name: "X-API-Key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", header: map[string]string{"X-API-Key": viewer}, wantStatus: http.StatusOK},
+		{name: "Bearer key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", header: map[string]string{"Authorization": "Bearer " + viewer}, wantStatus: http.StatusOK},
+		{name: "bad key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", header: map[string]string{"X-API-Key": viewer + "x"}, wantStatus: http.StatusUnauthorized},
+		{name: "expired key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", header: map[string]string{"X-API-Key": expired}, wantStatus: http.StatusUnauthorized},
+		{name: "other tenant's key", method: http.MethodGet, path: "/api/v1/workflows/wf-1", header: map[string]string{"X-API-Key": globex}, wantStatus: http.StatusNotFound},
+		{name: "viewer cannot approve", method: http.MethodPost, path: "/api/v1/workflows/wf-1/approve", header: map[string]string{"X-API-Key": viewer}, wantStatus: http.StatusForbidden},
+		{name: "approver approves", method: http.MethodPost, path: "/api/v1/workflows/wf-1/approve", header: map[string]string{"X-API-Key": approver}, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := env.do(t, tt.method, tt.path, "{}", tt.header)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+
+	// The approval is made as the key.
+	assert.True(t, strings.HasPrefix(env.q.resp.By, "apikey:"), env.q.resp.By)
+}
+
+func TestAPIKeys_Admin(t *testing.T) {
+	env := newKeyEnv(t)
+	log, err := audit.OpenFileLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	env.srv.SetAudit(log)
+	admin := map[string]string{"X-API-Key": env.issue(t, apikey.Key{TenantID: "acme", Roles: []rbac.Role{rbac.TenantAdmin}})}
+	other := env.issue(t, apikey.Key{TenantID: "globex", Roles: []rbac.Role{rbac.Viewer}})
+
+	// Create: the tenant defaults to the caller's.
+	rec := env.do(t, http.MethodPost, "/api/v1/apikeys", `{"name":"ci","roles":["analyst"],"expires_in":"720h"}`, admin)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created CreateAPIKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "acme", created.Key.TenantID)
+	assert.Empty(t, created.Key.Hash)
+	assert.False(t, created.Key.ExpiresAt.IsZero())
+	assert.Equal(t, http.StatusOK, env.do(t, http.MethodGet, "/api/v1/workflows/wf-1", "", map[string]string{"X-API-Key": created.Token}).Code)
+
+	for name, body := range map[string]string{
+		"other tenant": `{"name":"x","tenant":"globex","roles":["viewer"]}`,
+		"admin key":    `{"name":"x","roles":["viewer"],"admin":true}`,
+		"bad role":     `{"name":"x","roles":["root"]}`,
+		"bad expiry":   `{"name":"x","roles":["viewer"],"expires_in":"soon"}`,
+	} {
+		rec := env.do(t, http.MethodPost, "/api/v1/apikeys", body, admin)
+		assert.GreaterOrEqual(t, rec.Code, 400, name)
+	}
+	analyst := map[string]string{"X-API-Key": created.Token}
+	assert.Equal(t, http.StatusForbidden, env.do(t, http.MethodPost, "/api/v1/apikeys", `{"name":"x","roles":["viewer"]}`, analyst).Code)
+
+	// List: own tenant only, without hashes.
+	rec = env.do(t, http.MethodGet, "/api/v1/apikeys", "", admin)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct{ Keys []apikey.Key }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Keys, 2)
+	for _, k := range listed.Keys {
+		assert.Equal(t, "acme", k.TenantID)
+		assert.Empty(t, k.Hash)
+	}
+
+	// Revoke: another tenant's key is not found; a revoked key stops working.
+	otherID := strings.SplitN(strings.TrimPrefix(other, apikey.Prefix), "_", 2)[0]
+	assert.Equal(t, http.StatusNotFound, env.do(t, http.MethodDelete, "/api/v1/apikeys/"+otherID, "", admin).Code)
+	assert.Equal(t, http.StatusOK, env.do(t, http.MethodDelete, "/api/v1/apikeys/"+created.Key.ID, "", admin).Code)
+	assert.Equal(t, http.StatusUnauthorized, env.do(t, http.MethodGet, "/api/v1/workflows/wf-1", "", analyst).Code)
+
+	events, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	assert.Contains(t, actions, audit.ActionKeyCreated)
+	assert.Contains(t, actions, audit.ActionKeyRevoked)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+)
+
+// SetAudit enables the audit trail: approvals, denials and permission
+// denials are appended to log, and GET /api/v1/audit queries it.
+func (s *Server) SetAudit(log audit.Log) {
+	s.audit = log
+	s.auditor = audit.RBACAuditor{Log: log}
+}
+
+// auditEvent starts an event for the request's caller.
+func auditEvent(r *http.Request, action, workflowID string) audit.Event {
+	ctx := r.Context()
+	e := audit.Event{
+		Actor:      UserFromContext(ctx),
+		Email:      EmailFromContext(ctx),
+		Issuer:     IssuerFromContext(ctx),
+		Source:     audit.SourceAPI,
+		IP:         clientIP(r),
+		TenantID:   TenantFromContext(ctx),
+		Action:     action,
+		WorkflowID: workflowID,
+	}
+	if v, ok := ctx.Value(ctxTokenID).(string); ok {
+		e.TokenID = v
+	}
+	if e.Actor == "" {
+		e.Actor = "unauthenticated"
+	}
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		e.Details = map[string]string{"forwarded_for": fwd}
+	}
+	return e
+}
+
+// clientIP is the peer address of the request. X-Forwarded-For is
+// caller-controlled, so it is recorded separately rather than trusted.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// AuditResponse is the JSON body of GET /api/v1/audit.
+type AuditResponse struct {
+	Events []audit.Event `json:"events"`
+	// NextAfterSeq pages to older events; zero on the last page.
+	NextAfterSeq int64 `json:"next_after_seq,omitempty"`
+}
+
+// handleAudit queries the audit trail, newest first. Query parameters:
+// tenant, actor, action, source, workflow_id, from and to (RFC3339 or
+// YYYY-MM-DD), after_seq and limit. Callers confined to a tenant only see
+// its events.
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if s.audit == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "audit log not configured"))
+		return
+	}
+
+	v := r.URL.Query()
+	tenant, ok := scopeTenant(w, r, v.Get("tenant"))
+	if !ok {
+		return
+	}
+	f := audit.Filter{
+		TenantID:   tenant,
+		Actor:      v.Get("actor"),
+		Action:     v.Get("action"),
+		Source:     v.Get("source"),
+		WorkflowID: v.Get("workflow_id"),
+		From:       v.Get("from"),
+		To:         v.Get("to"),
+	}
+	if raw := v.Get("after_seq"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 1 {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "after_seq must be a positive integer"))
+			return
+		}
+		f.AfterSeq = n
+	}
+	if raw := v.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "limit must be a positive integer"))
+			return
+		}
+		f.Limit = n
+	}
+
+	events, err := s.audit.Query(f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	resp := AuditResponse{Events: events}
+	if resp.Events == nil {
+		resp.Events = []audit.Event{}
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = audit.DefaultLimit
+	}
+	if len(events) == min(limit, audit.MaxLimit) && len(events) > 0 {
+		resp.NextAfterSeq = events[len(events)-1].Seq
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
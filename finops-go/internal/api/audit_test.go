@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+func newAuditServer(t *testing.T) (*Server, *audit.FileLog) {
+	t.Helper()
+	log, err := audit.OpenFileLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	srv := newTenancyServer(t, &tenantQuerier{tenant: "acme"})
+	srv.SetAudit(log)
+	return srv, log
+}
+
+func TestAudit_RecordsApprovals(t *testing.T) {
+	srv, log := newAuditServer(t)
+
+	ctx := context.WithValue(context.Background(), ctxUserID, "alice")
+	ctx = context.WithValue(ctx, ctxTokenID, "jti-1")
+	rec := caller{tenant: "acme"}.doCtx(t, ctx, srv, http.MethodPost, "/api/v1/workflows/wf-1/deny", `{"by":"alice","reason":"too risky"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	events, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	e := events[0]
+	assert.Equal(t, audit.ActionDenied, e.Action)
+	assert.Equal(t, "alice", e.Actor)
+	assert.Equal(t, audit.SourceAPI, e.Source)
+	assert.Equal(t, "acme", e.TenantID)
+	assert.Equal(t, "wf-1", e.WorkflowID)
+	assert.Equal(t, "jti-1", e.TokenID)
+	assert.Equal(t, "192.0.2.1", e.IP) // httptest's RemoteAddr
+	assert.Equal(t, "too risky", e.Reason)
+	assert.Equal(t, "approved", e.Outcome)
+}
+
+func TestAudit_RecordsPermissionDenials(t *testing.T) {
+	srv, log := newAuditServer(t)
+
+	rec := caller{tenant: "acme", roles: []rbac.Role{rbac.Viewer}}.do(t, srv, http.MethodPost, "/api/v1/workflows/wf-1/approve", `{"by":"bob"}`)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	events, err := log.Query(audit.Filter{Action: audit.ActionPermissionDenied})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "approve", events[0].Resource)
+	assert.Equal(t, "approve", events[0].Details["permission"])
+}
+
+func TestAudit_Query(t *testing.T) {
+	srv, log := newAuditServer(t)
+	for _, tenant := range []string{"acme", "globex", "acme"} {
+		_, err := log.Append(audit.Event{Actor: "alice", Source: audit.SourceAPI, TenantID: tenant, Action: audit.ActionApproved})
+		require.NoError(t, err)
+	}
+
+	tenantAdmin := caller{tenant: "acme", roles: []rbac.Role{rbac.TenantAdmin}}
+	rec := tenantAdmin.do(t, srv, http.MethodGet, "/api/v1/audit?limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page AuditResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Events, 1)
+	assert.Equal(t, int64(3), page.Events[0].Seq)
+	assert.Equal(t, int64(3), page.NextAfterSeq)
+
+	rec = tenantAdmin.do(t, srv, http.MethodGet, "/api/v1/audit?after_seq=3", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	page = AuditResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Events, 1, "other tenants' events are hidden")
+	assert.Equal(t, int64(1), page.Events[0].Seq)
+	assert.Zero(t, page.NextAfterSeq)
+
+	rec = tenantAdmin.do(t, srv, http.MethodGet, "/api/v1/audit?tenant=globex", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = caller{tenant: "acme", roles: []rbac.Role{rbac.Approver}}.do(t, srv, http.MethodGet, "/api/v1/audit", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = tenantAdmin.do(t, srv, http.MethodGet, "/api/v1/audit?after_seq=x", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAudit_NotConfigured(t *testing.T) {
+	srv := newTenancyServer(t, &tenantQuerier{tenant: "acme"})
+	rec := caller{admin: true}.do(t, srv, http.MethodGet, "/api/v1/audit", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -2,10 +2,15 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
 )
 
 // OIDCConfig holds OIDC authentication settings.
@@ -18,10 +23,20 @@ type OIDCConfig struct {
 type contextKey string
 
 const (
-	ctxTenantID contextKey = "tenant_id"
-	ctxUserID   contextKey = "user_id"
+	ctxTenantID      contextKey = "tenant_id"
+	ctxUserID        contextKey = "user_id"
+	ctxEmail         contextKey = "email"
+	ctxIssuer        contextKey = "issuer"
+	ctxAdmin         contextKey = "admin"
+	ctxRoles         contextKey = "roles"
+	ctxTokenID       contextKey = "token_id"
+	ctxAuthenticated contextKey = "authenticated"
 )
 
+// AdminRole is the roles or groups claim value that lets a caller work
+// across tenants.
+const AdminRole = "admin"
+
 // TenantFromContext extracts the tenant ID from the request context.
 func TenantFromContext(ctx context.Context) string {
 	v, _ := ctx.Value(ctxTenantID).(string)
@@ -34,60 +49,186 @@ func UserFromContext(ctx context.Context) string {
 	return v
 }
 
+// EmailFromContext extracts the caller's verified email from the request
+// context.
+func EmailFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(ctxEmail).(string)
+	return v
+}
+
+// IssuerFromContext extracts the issuer of the caller's token from the
+// request context.
+func IssuerFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(ctxIssuer).(string)
+	return v
+}
+
+// IsAdminFromContext reports whether the caller holds AdminRole.
+func IsAdminFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(ctxAdmin).(bool)
+	return v
+}
+
+// RolesFromContext returns the caller's roles.
+func RolesFromContext(ctx context.Context) []rbac.Role {
+	v, _ := ctx.Value(ctxRoles).([]rbac.Role)
+	return v
+}
+
+// principalFromContext returns the authenticated caller. ok is false when
+// authentication is off.
+func principalFromContext(ctx context.Context) (p rbac.Principal, ok bool) {
+	if authed, _ := ctx.Value(ctxAuthenticated).(bool); !authed {
+		return rbac.Principal{}, false
+	}
+	return rbac.Principal{
+		Subject:  UserFromContext(ctx),
+		TenantID: TenantFromContext(ctx),
+		Roles:    RolesFromContext(ctx),
+		Admin:    IsAdminFromContext(ctx),
+		Email:    EmailFromContext(ctx),
+		Issuer:   IssuerFromContext(ctx),
+	}, true
+}
+
+// withPrincipal marks ctx as authenticated as p. tokenID identifies the
+// credential, for the audit trail, and may be empty.
+func withPrincipal(ctx context.Context, p rbac.Principal, tokenID string) context.Context {
+	ctx = context.WithValue(ctx, ctxAuthenticated, true)
+	if p.Admin {
+		ctx = context.WithValue(ctx, ctxAdmin, true)
+	}
+	ctx = context.WithValue(ctx, ctxRoles, p.Roles)
+	values := []struct {
+		key contextKey
+		v   string
+	}{
+		{ctxTokenID, tokenID},
+		{ctxTenantID, p.TenantID},
+		{ctxUserID, p.Subject},
+		{ctxEmail, p.Email},
+		{ctxIssuer, p.Issuer},
+	}
+	for _, kv := range values {
+		if kv.v != "" {
+			ctx = context.WithValue(ctx, kv.key, kv.v)
+		}
+	}
+	return ctx
+}
+
+// tokenClaims are the ID token claims the desk reads.
+type tokenClaims struct {
+	TenantID string   `json:"tenant_id"`
+	Sub      string   `json:"sub"`
+	Email    string   `json:"email"`
+	Roles    []string `json:"roles"`
+	Groups   []string `json:"groups"`
+	JTI      string   `json:"jti"`
+}
+
+// principal builds the caller from verified claims. The subject falls back
+// to the email for issuers that omit sub.
+func (c tokenClaims) principal(issuer string) rbac.Principal {
+	p := rbac.Principal{
+		Subject:  c.Sub,
+		TenantID: c.TenantID,
+		Roles:    rbac.RolesFromClaims(c.Roles, c.Groups),
+		Admin:    slices.Contains(c.Roles, AdminRole) || slices.Contains(c.Groups, AdminRole),
+		Email:    c.Email,
+		Issuer:   issuer,
+	}
+	if p.Subject == "" {
+		p.Subject = c.Email
+	}
+	return p
+}
+
+// TokenVerifier verifies ID tokens against one issuer and audience. It
+// discovers the issuer once and caches its signing keys, so callers that
+// hold a token outside HTTP, such as the MCP server, can re-verify it on
+// every use and notice when it expires.
+type TokenVerifier struct {
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewTokenVerifier discovers cfg's issuer. ctx also bounds later fetches
+// of the issuer's signing keys, so it should outlive the verifier.
+func NewTokenVerifier(ctx context.Context, cfg OIDCConfig) (*TokenVerifier, error) {
+	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("api: oidc provider %s: %w", cfg.IssuerURL, err)
+	}
+	return &TokenVerifier{verifier: provider.Verifier(&oidc.Config{ClientID: cfg.Audience})}, nil
+}
+
+// Verify checks raw's signature, audience and expiry and returns its
+// principal.
+func (v *TokenVerifier) Verify(ctx context.Context, raw string) (rbac.Principal, error) {
+	token, err := v.verifier.Verify(ctx, raw)
+	if err != nil {
+		return rbac.Principal{}, fmt.Errorf("api: verify token: %w", err)
+	}
+	var claims tokenClaims
+	if err := token.Claims(&claims); err != nil {
+		return rbac.Principal{}, fmt.Errorf("api: token claims: %w", err)
+	}
+	return claims.principal(token.Issuer), nil
+}
+
+// VerifyToken verifies a raw ID token against the configured issuer and
+// audience and returns its principal.
+func VerifyToken(ctx context.Context, cfg OIDCConfig, raw string) (rbac.Principal, error) {
+	v, err := NewTokenVerifier(ctx, cfg)
+	if err != nil {
+		return rbac.Principal{}, err
+	}
+	return v.Verify(ctx, raw)
+}
+
+// isPublic reports whether a path is served without authentication: the
+// health check and the OpenAPI document.
+func isPublic(path string) bool {
+	return path == "/api/v1/health" || path == "/api/v1/openapi.json"
+}
+
 // oidcAuth returns middleware that verifies JWT Bearer tokens using OIDC discovery.
-// The /health endpoint bypasses authentication.
+// Public paths bypass authentication.
 func oidcAuth(provider *oidc.Provider, audience string) func(http.Handler) http.Handler {
 	verifier := provider.Verifier(&oidc.Config{ClientID: audience})
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Health check bypasses auth.
-			if r.URL.Path == "/api/v1/health" {
+			if isPublic(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				writeError(w, http.StatusUnauthorized, "missing Authorization header")
+				writeError(w, r, apperr.New(apperr.Unauthenticated, "missing Authorization header"))
 				return
 			}
 
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
-				writeError(w, http.StatusUnauthorized, "invalid Authorization header format")
+				writeError(w, r, apperr.New(apperr.Unauthenticated, "invalid Authorization header format"))
 				return
 			}
 
 			token, err := verifier.Verify(r.Context(), parts[1])
 			if err != nil {
-				writeError(w, http.StatusUnauthorized, "invalid token: "+err.Error())
+				writeError(w, r, apperr.New(apperr.Unauthenticated, "invalid token: "+err.Error()))
 				return
 			}
 
 			// Extract claims for tenant and user context.
-			var claims struct {
-				TenantID string `json:"tenant_id"`
-				Sub      string `json:"sub"`
-				Email    string `json:"email"`
-			}
+			var claims tokenClaims
 			if err := token.Claims(&claims); err != nil {
-				writeError(w, http.StatusUnauthorized, "invalid token claims")
+				writeError(w, r, apperr.New(apperr.Unauthenticated, "invalid token claims"))
 				return
 			}
-
-			ctx := r.Context()
-			if claims.TenantID != "" {
-				ctx = context.WithValue(ctx, ctxTenantID, claims.TenantID)
-			}
-			userID := claims.Sub
-			if userID == "" {
-				userID = claims.Email
-			}
-			if userID != "" {
-				ctx = context.WithValue(ctx, ctxUserID, userID)
-			}
-
+			ctx := withPrincipal(r.Context(), claims.principal(token.Issuer), claims.JTI)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -14,6 +16,9 @@ import (
 	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 )
 
 // testOIDCServer creates a fake OIDC issuer serving JWKS.
@@ -88,6 +93,7 @@ func echoHandler() http.Handler {
 		if err := json.NewEncoder(w).Encode(map[string]string{
 			"tenant_id": TenantFromContext(r.Context()),
 			"user_id":   UserFromContext(r.Context()),
+			"admin":     strconv.FormatBool(IsAdminFromContext(r.Context())),
 		}); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -104,6 +110,11 @@ func TestOIDCAuth(t *testing.T) {
 		"sub": "user-123", "tenant_id": "tenant-abc",
 		"exp": now.Add(time.Hour).Unix(), "iat": now.Unix(),
 	})
+	adminToken := signJWT(t, env.key, map[string]any{
+		"iss": env.issuerURL, "aud": "test-audience",
+		"sub": "user-456", "groups": []string{"finops", AdminRole},
+		"exp": now.Add(time.Hour).Unix(), "iat": now.Unix(),
+	})
 	expiredToken := signJWT(t, env.key, map[string]any{
 		"iss": env.issuerURL, "aud": "test-audience", "sub": "user-123",
 		"exp": now.Add(-time.Hour).Unix(), "iat": now.Add(-2 * time.Hour).Unix(),
@@ -125,7 +136,14 @@ func TestOIDCAuth(t *testing.T) {
 			path:       "/api/v1/workflows",
 			authHeader: "Bearer " + validToken,
 			wantStatus: http.StatusOK,
-			wantBody:   map[string]string{"tenant_id": "tenant-abc", "user_id": "user-123"},
+			wantBody:   map[string]string{"tenant_id": "tenant-abc", "user_id": "user-123", "admin": "false"},
+		},
+		{
+			name:       "admin group",
+			path:       "/api/v1/workflows",
+			authHeader: "Bearer " + adminToken,
+			wantStatus: http.StatusOK,
+			wantBody:   map[string]string{"tenant_id": "", "user_id": "user-456", "admin": "true"},
 		},
 		{
 			name:       "missing header",
@@ -180,3 +198,71 @@ func TestOIDCAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyToken(t *testing.T) {
+	env := setupAuthMiddleware(t)
+	cfg := OIDCConfig{IssuerURL: env.issuerURL, Audience: "test-audience", Enabled: true}
+
+	now := time.Now()
+	token := signJWT(t, env.key, map[string]any{
+		"iss": env.issuerURL, "aud": "test-audience",
+		"sub": "user-123", "email": "lead@example.com", "tenant_id": "tenant-abc",
+		"roles": []string{"approver"},
+		"exp":   now.Add(time.Hour).Unix(), "iat": now.Unix(),
+	})
+	p, err := VerifyToken(t.Context(), cfg, token)
+	require.NoError(t, err)
+	assert.Equal(t, rbac.Principal{
+		Subject: "user-123", TenantID: "tenant-abc", Roles: []rbac.Role{rbac.Approver},
+		Email: "lead@example.com", Issuer: env.issuerURL,
+	}, p)
+
+	expired := signJWT(t, env.key, map[string]any{
+		"iss": env.issuerURL, "aud": "test-audience", "sub": "user-123",
+		"exp": now.Add(-time.Hour).Unix(), "iat": now.Add(-2 * time.Hour).Unix(),
+	})
+	_, err = VerifyToken(t.Context(), cfg, expired)
+	assert.Error(t, err)
+
+	// A verifier kept across calls checks expiry each time.
+	v, err := NewTokenVerifier(t.Context(), cfg)
+	require.NoError(t, err)
+	_, err = v.Verify(t.Context(), token)
+	require.NoError(t, err)
+	_, err = v.Verify(t.Context(), expired)
+	assert.Error(t, err)
+}
+
+func TestApproval_BindsIdentity(t *testing.T) {
+	authed := func(user string) context.Context {
+		ctx := context.WithValue(context.Background(), ctxUserID, user)
+		ctx = context.WithValue(ctx, ctxEmail, "lead@example.com")
+		return context.WithValue(ctx, ctxIssuer, "https://issuer.example")
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "by omitted", body: `{}`, wantStatus: http.StatusOK},
+		{name: "by is subject", body: `{"by":"user-123"}`, wantStatus: http.StatusOK},
+		{name: "by is email", body: `{"by":"lead@example.com"}`, wantStatus: http.StatusOK},
+		{name: "by is someone else", body: `{"by":"cfo@example.com"}`, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &tenantQuerier{tenant: "acme"}
+			rec := caller{tenant: "acme"}.doCtx(t, authed("user-123"), newTenancyServer(t, q),
+				http.MethodPost, "/api/v1/workflows/wf-1/approve", tt.body)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				assert.False(t, q.submitted)
+				return
+			}
+			assert.Equal(t, activities.ApprovalResponse{
+				Approved: true, By: "user-123",
+				Subject: "user-123", Email: "lead@example.com", Issuer: "https://issuer.example",
+			}, q.resp)
+		})
+	}
+}
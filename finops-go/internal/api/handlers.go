@@ -3,7 +3,10 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
@@ -11,50 +14,67 @@ import (
 )
 
 func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
 }
 
+// handleListWorkflows lists lifecycle workflows from Temporal visibility,
+// within the caller's tenant.
+// Query parameters: status, and the search-attribute filters tenant,
+// service, account, category, severity, phase, approval_status and
+// min_delta (dollars), and limit.
 func (s *Server) handleListWorkflows(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	tenant, ok := scopeTenant(w, r, v.Get("tenant"))
+	if !ok {
+		return
+	}
 	opts := querier.ListOptions{
-		TaskQueue: versioning.QueueAnomaly,
+		TaskQueue:      versioning.QueueAnomaly,
+		StatusFilter:   v.Get("status"),
+		TenantID:       tenant,
+		Service:        v.Get("service"),
+		AccountID:      v.Get("account"),
+		Category:       v.Get("category"),
+		Severity:       v.Get("severity"),
+		Phase:          v.Get("phase"),
+		ApprovalStatus: v.Get("approval_status"),
 	}
-	if status := r.URL.Query().Get("status"); status != "" {
-		opts.StatusFilter = status
+	if raw := v.Get("min_delta"); raw != "" {
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "min_delta must be a number"))
+			return
+		}
+		opts.MinDeltaDollars = &f
+	}
+	if raw := v.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "limit must be a positive integer"))
+			return
+		}
+		opts.PageSize = n
 	}
 
 	workflows, err := s.querier.ListWorkflows(r.Context(), opts)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, workflows)
 }
 
 func (s *Server) handleGetWorkflow(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	if id == "" {
-		writeError(w, http.StatusBadRequest, "workflow id required")
-		return
-	}
-
-	result, err := s.querier.GetWorkflowState(r.Context(), id)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	result, ok := s.loadWorkflow(w, r)
+	if !ok {
 		return
 	}
 	writeJSON(w, http.StatusOK, result)
 }
 
 func (s *Server) handleGetWorkflowUI(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	if id == "" {
-		writeError(w, http.StatusBadRequest, "workflow id required")
-		return
-	}
-
-	result, err := s.querier.GetWorkflowState(r.Context(), id)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	result, ok := s.loadWorkflow(w, r)
+	if !ok {
 		return
 	}
 
@@ -62,6 +82,13 @@ func (s *Server) handleGetWorkflowUI(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, schema)
 }
 
+// ApprovalRequest is the body of the approve and deny endpoints. With
+// authentication on, By defaults to the caller and may only restate it.
+type ApprovalRequest struct {
+	By     string `json:"by,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
 func (s *Server) handleApprove(w http.ResponseWriter, r *http.Request) {
 	s.handleApprovalAction(w, r, true)
 }
@@ -71,34 +98,56 @@ func (s *Server) handleDeny(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleApprovalAction(w http.ResponseWriter, r *http.Request, approved bool) {
-	id := r.PathValue("id")
-	if id == "" {
-		writeError(w, http.StatusBadRequest, "workflow id required")
+	if _, ok := s.loadWorkflow(w, r); !ok {
 		return
 	}
+	id := r.PathValue("id")
 
-	var body struct {
-		By     string `json:"by"`
-		Reason string `json:"reason,omitempty"`
-	}
+	var body ApprovalRequest
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body")
-		return
-	}
-	if body.By == "" {
-		writeError(w, http.StatusBadRequest, "'by' field is required")
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "invalid request body"))
 		return
 	}
-
 	resp := activities.ApprovalResponse{
 		Approved: approved,
 		By:       body.By,
 		Reason:   body.Reason,
 	}
+	// With authentication on, the approver is the token's subject; 'by'
+	// may only restate it.
+	if p, ok := principalFromContext(r.Context()); ok {
+		by, err := p.ActingAs(body.By)
+		if err != nil {
+			writeError(w, r, apperr.New(apperr.Forbidden, "'by' must be the authenticated caller"))
+			return
+		}
+		resp.By, resp.Subject, resp.Email, resp.Issuer = by, p.Subject, p.Email, p.Issuer
+	}
+	if resp.By == "" {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "'by' field is required"))
+		return
+	}
 	result, err := s.querier.SubmitApproval(r.Context(), id, resp)
+
+	action := audit.ActionDenied
+	if approved {
+		action = audit.ActionApproved
+	}
+	e := auditEvent(r, action, id)
+	e.Reason = body.Reason
+	if e.Details == nil {
+		e.Details = map[string]string{}
+	}
+	e.Details["by"] = resp.By
+	e.Outcome = result
+	if err != nil {
+		e.Outcome = "error: " + err.Error()
+	}
+	audit.Record(r.Context(), s.audit, e)
+
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, r, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]string{"result": result})
+	writeJSON(w, http.StatusOK, ResultResponse{Result: result})
 }
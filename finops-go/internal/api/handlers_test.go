@@ -11,9 +11,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.temporal.io/api/serviceerror"
 
 	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
@@ -25,9 +30,13 @@ type stubQuerier struct {
 	desc      *querier.WorkflowDescription
 	approval  string
 	err       error
+	listOpts  querier.ListOptions
+	// requestID is the request ID SubmitApproval was called with.
+	requestID string
 }
 
-func (s *stubQuerier) ListWorkflows(_ context.Context, _ querier.ListOptions) ([]querier.WorkflowSummary, error) {
+func (s *stubQuerier) ListWorkflows(_ context.Context, opts querier.ListOptions) ([]querier.WorkflowSummary, error) {
+	s.listOpts = opts
 	return s.workflows, s.err
 }
 
@@ -39,7 +48,8 @@ func (s *stubQuerier) DescribeWorkflow(_ context.Context, _ string) (*querier.Wo
 	return s.desc, s.err
 }
 
-func (s *stubQuerier) SubmitApproval(_ context.Context, _ string, _ activities.ApprovalResponse) (string, error) {
+func (s *stubQuerier) SubmitApproval(ctx context.Context, _ string, _ activities.ApprovalResponse) (string, error) {
+	s.requestID = observability.RequestIDFromContext(ctx)
 	return s.approval, s.err
 }
 
@@ -84,6 +94,29 @@ func TestListWorkflows(t *testing.T) {
 	assert.Len(t, wfs, 2)
 }
 
+func TestListWorkflows_Filters(t *testing.T) {
+	q := &stubQuerier{}
+	ts := newTestServer(t, q)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/workflows?status=Running&tenant=acme&severity=critical&approval_status=pending&min_delta=250.5")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "Running", q.listOpts.StatusFilter)
+	assert.Equal(t, "acme", q.listOpts.TenantID)
+	assert.Equal(t, "critical", q.listOpts.Severity)
+	assert.Equal(t, "pending", q.listOpts.ApprovalStatus)
+	require.NotNil(t, q.listOpts.MinDeltaDollars)
+	assert.Equal(t, 250.5, *q.listOpts.MinDeltaDollars)
+
+	resp, err = http.Get(ts.URL + "/api/v1/workflows?min_delta=lots")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func TestGetWorkflow(t *testing.T) {
 	state := domain.NewFinOpsState(domain.NewTenantContext("t1"))
 	state.CurrentPhase = "triage"
@@ -174,14 +207,164 @@ func TestListWorkflows_Error(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 }
 
+func TestErrors_Problem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		path       string
+		wantStatus int
+		wantCode   apperr.Code
+		wantDetail string
+	}{
+		{
+			name:       "workflow not found",
+			err:        serviceerror.NewNotFound("workflow not found for ID: wf-1"),
+			path:       "/api/v1/workflows/wf-1",
+			wantStatus: http.StatusNotFound,
+			wantCode:   apperr.NotFound,
+		},
+		{
+			name:       "already decided",
+			err:        fmt.Errorf("get approval result: %w", querier.ErrAlreadyDecided),
+			path:       "/api/v1/workflows/wf-1/approve",
+			wantStatus: http.StatusConflict,
+			wantCode:   apperr.Conflict,
+		},
+		{
+			name:       "not at the gate",
+			err:        fmt.Errorf("get approval result: %w", querier.ErrNotAwaitingApproval),
+			path:       "/api/v1/workflows/wf-1/approve",
+			wantStatus: http.StatusConflict,
+			wantCode:   apperr.InvalidState,
+		},
+		{
+			name:       "temporal down",
+			err:        serviceerror.NewUnavailable("connection refused to 10.0.0.7:7233"),
+			path:       "/api/v1/workflows",
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   apperr.UpstreamUnavailable,
+			wantDetail: "upstream service unavailable",
+		},
+		{
+			name:       "internal",
+			err:        fmt.Errorf("decode query result: secret detail"),
+			path:       "/api/v1/workflows/wf-1",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   apperr.Internal,
+			wantDetail: "internal error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t, &stubQuerier{err: tt.err})
+			defer ts.Close()
+
+			var resp *http.Response
+			var err error
+			if strings.HasSuffix(tt.path, "/approve") {
+				resp, err = http.Post(ts.URL+tt.path, "application/json", strings.NewReader(`{"by": "ops-user"}`))
+			} else {
+				resp, err = http.Get(ts.URL + tt.path)
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, api.ProblemContentType, resp.Header.Get("Content-Type"))
+
+			var p api.Problem
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+			assert.Equal(t, tt.wantCode, p.Code)
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, "urn:finops:error:"+string(tt.wantCode), p.Type)
+			assert.Equal(t, tt.path, p.Instance)
+			assert.Equal(t, resp.Header.Get("X-Request-ID"), p.RequestID)
+			if tt.wantDetail != "" {
+				assert.Equal(t, tt.wantDetail, p.Detail)
+			}
+		})
+	}
+}
+
 func TestRequestIDHeader(t *testing.T) {
-	ts := newTestServer(t, &stubQuerier{})
+	tests := []struct {
+		name   string
+		sent   string
+		wantID string // "" = a generated ID
+	}{
+		{name: "generated"},
+		{name: "caller's kept", sent: "deploy-42.retry_1", wantID: "deploy-42.retry_1"},
+		{name: "malformed replaced", sent: "<script>alert(1)</script>"},
+		{name: "too long replaced", sent: strings.Repeat("x", 65)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &stubQuerier{approval: "approved"}
+			ts := newTestServer(t, q)
+			defer ts.Close()
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/workflows/wf-1/approve", strings.NewReader(`{"by": "ops-user"}`))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.sent != "" {
+				req.Header.Set("X-Request-ID", tt.sent)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			got := resp.Header.Get("X-Request-ID")
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, got)
+			} else {
+				assert.NotEmpty(t, got)
+				assert.NotEqual(t, tt.sent, got)
+			}
+			// The ID reaches the Temporal call.
+			assert.Equal(t, got, q.requestID)
+		})
+	}
+}
+
+func TestRequestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	m, err := observability.NewMetricsFor(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	require.NoError(t, err)
+	srv, err := api.New(&stubQuerier{approval: "approved"}, []string{"*"}, api.OIDCConfig{})
+	require.NoError(t, err)
+	srv.SetMetrics(m)
+	ts := httptest.NewServer(srv)
 	defer ts.Close()
 
-	resp, err := http.Get(ts.URL + "/api/v1/health")
+	for _, id := range []string{"wf-1", "wf-2"} {
+		resp, err := http.Post(ts.URL+"/api/v1/workflows/"+id+"/approve", "application/json", strings.NewReader(`{"by": "ops-user"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	resp, err := http.Get(ts.URL + "/api/v1/nope")
 	require.NoError(t, err)
-	defer resp.Body.Close()
-	assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+	resp.Body.Close()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	got := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			if md.Name != "finops.http.request.duration_seconds" {
+				continue
+			}
+			for _, dp := range md.Data.(metricdata.Histogram[float64]).DataPoints {
+				route, _ := dp.Attributes.Value("route")
+				status, _ := dp.Attributes.Value("status")
+				got[fmt.Sprintf("%s %d", route.AsString(), status.AsInt64())] = dp.Count
+			}
+		}
+	}
+	// Path parameters collapse into the route pattern.
+	assert.Equal(t, map[string]uint64{
+		"POST /api/v1/workflows/{id}/approve 200": 2,
+		"unmatched 404": 1,
+	}, got)
 }
 
 func TestCORSHeaders(t *testing.T) {
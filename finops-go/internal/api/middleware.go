@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"log/slog"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 )
 
 // cors wraps a handler with CORS headers.
@@ -14,8 +17,8 @@ func cors(origins []string, next http.Handler) http.Handler {
 	allowed := strings.Join(origins, ", ")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", allowed)
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, Idempotency-Key")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -25,22 +28,58 @@ func cors(origins []string, next http.Handler) http.Handler {
 	})
 }
 
-// requestID adds a unique request ID header.
-func requestID(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		id := shortID()
-		w.Header().Set("X-Request-ID", id)
-		next.ServeHTTP(w, r)
-	})
+// withRequestID gives the request an ID, in its context and the
+// X-Request-ID response header. A well-formed X-Request-ID sent by the
+// caller is kept, so that one ID follows a request across services. The ID
+// travels with Temporal calls into workflows and activities, and appears
+// in their logs and in error bodies.
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get("X-Request-ID")
+	if !validRequestID(id) {
+		id = shortID()
+	}
+	w.Header().Set("X-Request-ID", id)
+	return r.WithContext(observability.WithRequestID(r.Context(), id))
+}
+
+// validRequestID accepts up to 64 letters, digits, '-', '_' and '.'.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
-// logging logs each request with method, path, status, and duration.
-func logging(next http.Handler) http.Handler {
+// RequestIDFromContext returns the ID of the request being served.
+func RequestIDFromContext(ctx context.Context) string {
+	return observability.RequestIDFromContext(ctx)
+}
+
+// logging logs each request with method, path, status, and duration, and
+// records the duration under the matched route when metrics are set.
+func (s *Server) logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(sw, r)
-		slog.Info("request", "method", r.Method, "path", r.URL.Path, "status", sw.status, "duration", time.Since(start))
+		d := time.Since(start)
+		slog.InfoContext(r.Context(), "request", "method", r.Method, "path", r.URL.Path, "status", sw.status, "duration", d)
+		if s.metrics != nil {
+			// The mux sets r.Pattern on the request it was given; requests
+			// that matched no route share one series.
+			route := r.Pattern
+			if route == "" {
+				route = "unmatched"
+			}
+			s.metrics.RecordHTTPRequest(r.Context(), route, sw.status, d)
+		}
 	})
 }
 
@@ -54,6 +93,19 @@ func (sw *statusWriter) WriteHeader(code int) {
 	sw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush lets streaming handlers, such as the AG-UI stream, flush through
+// the wrapper.
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
 func shortID() string {
 	b := make([]byte, 8)
 	_, _ = rand.Read(b)
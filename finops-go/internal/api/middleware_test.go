@@ -0,0 +1,28 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	ts := newTestServer(t, &stubQuerier{})
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodOptions, ts.URL+"/api/v1/apikeys/key-1", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://ui.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), http.MethodDelete, "API keys are revoked with DELETE")
+	for _, h := range []string{"Authorization", "X-API-Key", "Idempotency-Key"} {
+		assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), h)
+	}
+}
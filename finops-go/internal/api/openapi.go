@@ -0,0 +1,567 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/finops-claw-gang/finops-go/internal/agui"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+	"github.com/finops-claw-gang/finops-go/internal/uischema"
+)
+
+// OpenAPIVersion is the OpenAPI version of the document served at
+// /api/v1/openapi.json.
+const OpenAPIVersion = "3.1.0"
+
+// The document is assembled from the registered endpoints and the Go types
+// the handlers encode, so paths, permissions and body shapes follow the
+// code. What the code cannot say, such as summaries, parameters and the
+// statuses each handler answers with, is described in operationDocs.
+
+// opDoc documents one operation.
+type opDoc struct {
+	Summary   string
+	Params    []paramDoc
+	Body      reflect.Type // request body; nil for none
+	Responses map[int]respDoc
+}
+
+type paramDoc struct {
+	Name        string
+	In          string // "query" or "header"
+	Type        string
+	Description string
+	Enum        []any
+}
+
+// respDoc is a response. Body is encoded as JSON unless Media says
+// otherwise; a nil Body is the error body.
+type respDoc struct {
+	Description string
+	Body        reflect.Type
+	Media       map[string]reflect.Type // extra media types, e.g. text/csv
+}
+
+func query(name, typ, description string) paramDoc {
+	return paramDoc{Name: name, In: "query", Type: typ, Description: description}
+}
+
+func ok[T any](description string) respDoc {
+	return respDoc{Description: description, Body: reflect.TypeFor[T]()}
+}
+
+func fail(description string) respDoc {
+	return respDoc{Description: description}
+}
+
+var (
+	tenantParam = query("tenant", "string", "Tenant ID. Callers confined to a tenant may only name their own.")
+	dateFrom    = query("from", "string", "Inclusive start date, YYYY-MM-DD.")
+	dateTo      = query("to", "string", "Inclusive end date, YYYY-MM-DD.")
+	limitParam  = query("limit", "integer", "Maximum results.")
+	idemParam   = paramDoc{
+		Name: "Idempotency-Key", In: "header", Type: "string",
+		Description: "Names the workflow to start. Retries with the same key and body return it; another body conflicts. Defaults to a hash of the body.",
+	}
+
+	notFound     = fail("Unknown, or another tenant's, resource")
+	undecidable  = fail("Already decided, or not awaiting approval (conflict or invalid_state)")
+	badRequest   = fail("Invalid parameters or body")
+	notEnabled   = fail("The backing store is not configured")
+	startedAgain = ok[querier.StartResult]("The same request already started the workflow")
+)
+
+// operationDocs describes every operation, keyed by operation name.
+// Endpoints that require a permission also answer 401, 403 and 500.
+var operationDocs = map[string]opDoc{
+	"health": {
+		Summary:   "Liveness check",
+		Responses: map[int]respDoc{200: ok[HealthResponse]("Server is up")},
+	},
+	"get_openapi": {
+		Summary:   "This OpenAPI document",
+		Responses: map[int]respDoc{200: ok[map[string]any]("OpenAPI 3.1 document")},
+	},
+	"list_workflows": {
+		Summary: "List anomaly lifecycle workflows",
+		Params: []paramDoc{
+			query("status", "string", "Workflow execution status, e.g. Running or Completed."),
+			tenantParam,
+			query("service", "string", "AWS service name."),
+			query("account", "string", "AWS account ID."),
+			query("category", "string", "Triage category."),
+			query("severity", "string", "Triage severity."),
+			query("phase", "string", "Lifecycle phase, e.g. hil_gate."),
+			query("approval_status", "string", "Approval status, e.g. pending."),
+			query("min_delta", "number", "Minimum daily dollar delta."),
+			limitParam,
+		},
+		Responses: map[int]respDoc{
+			200: ok[[]querier.WorkflowSummary]("Matching workflows"),
+			400: badRequest,
+			404: notFound,
+		},
+	},
+	"get_workflow": {
+		Summary: "Get a workflow's state",
+		Responses: map[int]respDoc{
+			200: ok[workflows.WorkflowResult]("Workflow state"),
+			404: notFound,
+		},
+	},
+	"get_workflow_ui": {
+		Summary: "Get the UI schema for a workflow's state",
+		Responses: map[int]respDoc{
+			200: ok[uischema.UISchema]("UI schema"),
+			404: notFound,
+		},
+	},
+	"approve": {
+		Summary: "Approve a workflow's pending actions",
+		Body:    reflect.TypeFor[ApprovalRequest](),
+		Responses: map[int]respDoc{
+			200: ok[ResultResponse]("Approval recorded"),
+			400: badRequest,
+			404: notFound,
+			409: undecidable,
+		},
+	},
+	"deny": {
+		Summary: "Deny a workflow's pending actions",
+		Body:    reflect.TypeFor[ApprovalRequest](),
+		Responses: map[int]respDoc{
+			200: ok[ResultResponse]("Denial recorded"),
+			400: badRequest,
+			404: notFound,
+			409: undecidable,
+		},
+	},
+	"get_savings": {
+		Summary: "Report the savings ledger",
+		Params: []paramDoc{
+			tenantParam,
+			query("team", "string", "Owning team."),
+			query("account", "string", "AWS account ID."),
+			query("service", "string", "AWS service name."),
+			query("action_type", "string", "Executed action type."),
+			dateFrom, dateTo,
+			query("group_by", "string", "Comma-separated dimensions to group totals by."),
+			{Name: "format", In: "query", Type: "string", Description: "csv for a CSV download.", Enum: []any{"csv"}},
+		},
+		Responses: map[int]respDoc{
+			200: {
+				Description: "Ledger entries, or groups with group_by",
+				Body:        reflect.TypeFor[SavingsResponse](),
+				Media:       map[string]reflect.Type{"text/csv": reflect.TypeFor[string]()},
+			},
+			400: badRequest,
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"list_anomalies": {
+		Summary: "Search the anomaly history",
+		Params: []paramDoc{
+			tenantParam,
+			query("service", "string", "AWS service name."),
+			query("account", "string", "AWS account ID."),
+			query("team", "string", "Owning team."),
+			query("category", "string", "Triage category."),
+			query("severity", "string", "Triage severity."),
+			query("phase", "string", "Lifecycle phase."),
+			query("reason", "string", "Termination reason."),
+			query("min_delta", "number", "Minimum daily dollar delta."),
+			query("max_delta", "number", "Maximum daily dollar delta."),
+			dateFrom, dateTo,
+			query("sort", "string", "started_at, updated_at or delta_dollars; prefix - for descending."),
+			limitParam,
+			query("cursor", "string", "next_cursor of the previous page."),
+		},
+		Responses: map[int]respDoc{
+			200: ok[history.Page]("A page of anomalies"),
+			400: badRequest,
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"trigger_anomaly": {
+		Summary: "Start an anomaly lifecycle workflow",
+		Params:  []paramDoc{idemParam},
+		Body:    reflect.TypeFor[TriggerAnomalyRequest](),
+		Responses: map[int]respDoc{
+			200: startedAgain,
+			201: ok[querier.StartResult]("Workflow started"),
+			400: badRequest,
+			404: notFound,
+			409: fail("Idempotency key already used with a different request"),
+			501: fail("Starting workflows is not supported"),
+		},
+	},
+	"trigger_sweep": {
+		Summary: "Start a waste sweep over accounts",
+		Params:  []paramDoc{idemParam},
+		Body:    reflect.TypeFor[TriggerSweepRequest](),
+		Responses: map[int]respDoc{
+			200: startedAgain,
+			201: ok[querier.StartResult]("Sweep started"),
+			400: badRequest,
+			404: notFound,
+			409: fail("Idempotency key already used with a different request"),
+			501: fail("Starting workflows is not supported"),
+		},
+	},
+	"get_audit": {
+		Summary: "Query the audit trail, newest first",
+		Params: []paramDoc{
+			tenantParam,
+			query("actor", "string", "Acting subject."),
+			query("action", "string", "Audit action."),
+			query("source", "string", "api, mcp or workflow."),
+			query("workflow_id", "string", "Workflow ID."),
+			query("from", "string", "Start, RFC3339 or YYYY-MM-DD."),
+			query("to", "string", "End, RFC3339 or YYYY-MM-DD."),
+			query("after_seq", "integer", "next_after_seq of the previous page."),
+			limitParam,
+		},
+		Responses: map[int]respDoc{
+			200: ok[AuditResponse]("A page of events"),
+			400: badRequest,
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"create_api_key": {
+		Summary: "Issue an API key",
+		Body:    reflect.TypeFor[CreateAPIKeyRequest](),
+		Responses: map[int]respDoc{
+			201: ok[CreateAPIKeyResponse]("Key issued; the token is shown only here"),
+			400: badRequest,
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"list_api_keys": {
+		Summary: "List API keys",
+		Params:  []paramDoc{tenantParam},
+		Responses: map[int]respDoc{
+			200: ok[APIKeyList]("Keys, without their hashes"),
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"revoke_api_key": {
+		Summary: "Revoke an API key",
+		Responses: map[int]respDoc{
+			200: ok[ResultResponse]("Key revoked"),
+			404: notFound,
+			503: notEnabled,
+		},
+	},
+	"stream_workflow": {
+		Summary: "Stream a workflow's state as AG-UI server-sent events",
+		Responses: map[int]respDoc{
+			200: {
+				Description: "An AG-UI event stream; each event's data is an AGUIEvent",
+				Media:       map[string]reflect.Type{"text/event-stream": reflect.TypeFor[agui.Event]()},
+			},
+			404: notFound,
+		},
+	},
+}
+
+// components are the named schemas of the document.
+var components = []struct {
+	Name string
+	Type reflect.Type
+}{
+	{"Problem", reflect.TypeFor[Problem]()},
+	{"Result", reflect.TypeFor[ResultResponse]()},
+	{"Health", reflect.TypeFor[HealthResponse]()},
+	{"WorkflowSummary", reflect.TypeFor[querier.WorkflowSummary]()},
+	{"WorkflowResult", reflect.TypeFor[workflows.WorkflowResult]()},
+	{"FinOpsState", reflect.TypeFor[domain.FinOpsState]()},
+	{"UISchema", reflect.TypeFor[uischema.UISchema]()},
+	{"UIComponent", reflect.TypeFor[uischema.Component]()},
+	{"UIAction", reflect.TypeFor[uischema.Action]()},
+	{"ApprovalRequest", reflect.TypeFor[ApprovalRequest]()},
+	{"SavingsResponse", reflect.TypeFor[SavingsResponse]()},
+	{"AnomalyPage", reflect.TypeFor[history.Page]()},
+	{"AnomalyRecord", reflect.TypeFor[history.Record]()},
+	{"TriggerAnomalyRequest", reflect.TypeFor[TriggerAnomalyRequest]()},
+	{"TriggerSweepRequest", reflect.TypeFor[TriggerSweepRequest]()},
+	{"StartResult", reflect.TypeFor[querier.StartResult]()},
+	{"AuditPage", reflect.TypeFor[AuditResponse]()},
+	{"AuditEvent", reflect.TypeFor[audit.Event]()},
+	{"CreateAPIKeyRequest", reflect.TypeFor[CreateAPIKeyRequest]()},
+	{"CreateAPIKeyResponse", reflect.TypeFor[CreateAPIKeyResponse]()},
+	{"APIKey", reflect.TypeFor[apikey.Key]()},
+	{"APIKeyList", reflect.TypeFor[APIKeyList]()},
+	{"AGUIEvent", reflect.TypeFor[agui.Event]()},
+	{"StateSnapshotData", reflect.TypeFor[agui.StateSnapshotData]()},
+	{"StateDeltaData", reflect.TypeFor[agui.StateDeltaData]()},
+	{"StepData", reflect.TypeFor[agui.StepData]()},
+	{"ErrorData", reflect.TypeFor[agui.ErrorData]()},
+	{"JSONPatch", reflect.TypeFor[agui.Patch]()},
+}
+
+// requestRequired lists the fields each request body must carry, by
+// property ("" for the body itself; an array property's items). Inferred
+// schemas require every field without omitempty, but decoding accepts
+// missing fields and the handlers check the ones that matter.
+var requestRequired = map[string]map[string][]string{
+	"ApprovalRequest":       {},
+	"TriggerAnomalyRequest": {"": {"anomaly", "window_start", "window_end"}, "anomaly": {"service", "account_id", "delta_dollars"}},
+	"TriggerSweepRequest":   {"": {"accounts"}, "accounts": {"account_id", "region"}},
+	"CreateAPIKeyRequest":   {"": {"name", "roles"}},
+}
+
+// componentRef is the $ref of a named schema.
+func componentRef(name string) *jsonschema.Schema {
+	return &jsonschema.Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaFor infers the schema of t, referring to named schemas rather
+// than inlining them. self, if set, is inlined: it is the type being named.
+func schemaFor(t, self reflect.Type) (*jsonschema.Schema, error) {
+	refs := make(map[reflect.Type]*jsonschema.Schema, len(components))
+	for _, c := range components {
+		if c.Type != self {
+			refs[c.Type] = componentRef(c.Name)
+		}
+	}
+	schema, err := jsonschema.ForType(t, &jsonschema.ForOptions{TypeSchemas: refs})
+	if err != nil {
+		return nil, err
+	}
+	nullableMaps(schema)
+	return schema, nil
+}
+
+// nullableMaps allows null wherever a Go map is encoded: a nil map
+// encodes as null, as a nil slice does. Maps are the open objects; structs
+// forbid additional properties.
+func nullableMaps(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Type == "object" && s.Properties == nil && s.AdditionalProperties != nil && s.AdditionalProperties.Not == nil {
+		s.Type, s.Types = "", []string{"null", "object"}
+	}
+	for _, p := range s.Properties {
+		nullableMaps(p)
+	}
+	nullableMaps(s.Items)
+	nullableMaps(s.AdditionalProperties)
+}
+
+// clearRequired drops every required list in s.
+func clearRequired(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	s.Required = nil
+	for _, p := range s.Properties {
+		clearRequired(p)
+	}
+	clearRequired(s.Items)
+}
+
+// componentSchemas infers the named schemas. Fields the Go types leave
+// open, such as AG-UI event data, are narrowed to what the handlers send.
+func componentSchemas() (map[string]*jsonschema.Schema, error) {
+	schemas := make(map[string]*jsonschema.Schema, len(components)+1)
+	for _, c := range components {
+		s, err := schemaFor(c.Type, c.Type)
+		if err != nil {
+			return nil, fmt.Errorf("api: schema %s: %w", c.Name, err)
+		}
+		schemas[c.Name] = s
+	}
+
+	for name, fields := range requestRequired {
+		clearRequired(schemas[name])
+		for prop, required := range fields {
+			target := schemas[name]
+			if prop != "" {
+				target = target.Properties[prop]
+			}
+			if target.Items != nil {
+				target = target.Items
+			}
+			target.Required = required
+		}
+	}
+
+	schemas["RunFinishedData"] = &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{"reason": {Type: "string"}},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	event := schemas["AGUIEvent"]
+	event.Properties["type"].Enum = []any{
+		string(agui.EventRunStarted), string(agui.EventRunFinished), string(agui.EventRunError),
+		string(agui.EventStepStarted), string(agui.EventStepFinished),
+		string(agui.EventStateSnapshot), string(agui.EventStateDelta), string(agui.EventCustom),
+	}
+	event.Properties["data"] = &jsonschema.Schema{
+		Description: "STATE_SNAPSHOT, STATE_DELTA, STEP_STARTED/STEP_FINISHED, RUN_ERROR or RUN_FINISHED data.",
+		AnyOf: []*jsonschema.Schema{
+			componentRef("StateSnapshotData"), componentRef("StateDeltaData"),
+			componentRef("StepData"), componentRef("ErrorData"), componentRef("RunFinishedData"),
+		},
+	}
+	codes := apperr.Codes()
+	schemas["Problem"].Properties["code"].Enum = make([]any, len(codes))
+	for i, c := range codes {
+		schemas["Problem"].Properties["code"].Enum[i] = string(c)
+	}
+	schemas["StateSnapshotData"].Properties["state"] = componentRef("FinOpsState")
+	schemas["StateSnapshotData"].Properties["ui_schema"] = componentRef("UISchema")
+	schemas["StateDeltaData"].Properties["ui_schema"] = componentRef("UISchema")
+	return schemas, nil
+}
+
+// openAPIDocument builds the OpenAPI document for the registered endpoints.
+func (s *Server) openAPIDocument() (map[string]any, error) {
+	schemas, err := componentSchemas()
+	if err != nil {
+		return nil, err
+	}
+	errorBody := componentRef("Problem")
+
+	paths := map[string]map[string]any{}
+	for _, ep := range s.endpoints {
+		method, path, _ := strings.Cut(ep.Pattern, " ")
+		doc, ok := operationDocs[ep.Operation]
+		if !ok {
+			return nil, fmt.Errorf("api: operation %s is not documented", ep.Operation)
+		}
+
+		op := map[string]any{"operationId": ep.Operation, "summary": doc.Summary}
+		var params []map[string]any
+		if strings.Contains(path, "{id}") {
+			params = append(params, map[string]any{
+				"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, p := range doc.Params {
+			schema := map[string]any{"type": p.Type}
+			if p.Enum != nil {
+				schema["enum"] = p.Enum
+			}
+			params = append(params, map[string]any{
+				"name": p.Name, "in": p.In, "description": p.Description, "schema": schema,
+			})
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if doc.Body != nil {
+			body, err := schemaFor(doc.Body, nil)
+			if err != nil {
+				return nil, fmt.Errorf("api: %s body: %w", ep.Operation, err)
+			}
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": body}},
+			}
+		}
+
+		responses := map[string]any{}
+		all := doc.Responses
+		if ep.Perm != "" {
+			all = map[int]respDoc{
+				401: fail("Missing or invalid credentials"),
+				403: fail("The caller lacks the permission"),
+				500: fail("Internal error"),
+				503: fail("Temporal or a backing store is unavailable"),
+			}
+			for code, r := range doc.Responses {
+				all[code] = r
+			}
+			op["x-permission"] = string(ep.Perm)
+		} else {
+			op["security"] = []any{}
+		}
+		for code, r := range all {
+			content := map[string]any{}
+			switch {
+			case r.Body != nil:
+				body, err := schemaFor(r.Body, nil)
+				if err != nil {
+					return nil, fmt.Errorf("api: %s %d: %w", ep.Operation, code, err)
+				}
+				content["application/json"] = map[string]any{"schema": body}
+			case r.Media == nil:
+				content[ProblemContentType] = map[string]any{"schema": errorBody}
+			}
+			for media, t := range r.Media {
+				body, err := schemaFor(t, nil)
+				if err != nil {
+					return nil, fmt.Errorf("api: %s %d: %w", ep.Operation, code, err)
+				}
+				content[media] = map[string]any{"schema": body}
+			}
+			responses[fmt.Sprint(code)] = map[string]any{"description": r.Description, "content": content}
+		}
+		op["responses"] = responses
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(method)] = op
+	}
+
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":       "FinOps API",
+			"version":     "v1",
+			"description": "Anomaly lifecycle workflows, approvals, savings, audit and API keys.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKey":     map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		"security": []any{
+			map[string]any{"bearerAuth": []any{}},
+			map[string]any{"apiKey": []any{}},
+		},
+	}, nil
+}
+
+// handleOpenAPI serves the OpenAPI document. It needs no authentication.
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	doc, err := s.openAPI()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(doc)
+}
+
+// encodeOpenAPI is openAPIDocument encoded as JSON.
+func (s *Server) encodeOpenAPI() ([]byte, error) {
+	doc, err := s.openAPIDocument()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
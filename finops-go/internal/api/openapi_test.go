@@ -0,0 +1,307 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// contractQuerier serves one finished, triaged workflow of tenant acme.
+type contractQuerier struct {
+	*startQuerier
+}
+
+func (q contractQuerier) ListWorkflows(context.Context, querier.ListOptions) ([]querier.WorkflowSummary, error) {
+	return []querier.WorkflowSummary{{WorkflowID: "wf-1", RunID: "run-1", Status: "Completed",
+		StartTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), TaskQueue: "finops-anomaly"}}, nil
+}
+
+func (q contractQuerier) GetWorkflowState(context.Context, string) (*workflows.WorkflowResult, error) {
+	state := domain.NewFinOpsState(domain.NewTenantContext("acme"))
+	anomaly := domain.NewCostAnomaly()
+	anomaly.Service, anomaly.AccountID, anomaly.DeltaDollars = "EC2", "123456789012", 420
+	state.Anomaly = &anomaly
+	state.Triage = &domain.TriageResult{Category: domain.CategoryResourceWaste, Severity: domain.SeverityHigh, Confidence: 0.9}
+	state.Approver = &domain.Approver{By: "alice", Subject: "alice"}
+	state.CurrentPhase = "completed"
+	state.ShouldTerminate = true
+	return &workflows.WorkflowResult{State: state, Reason: "completed"}, nil
+}
+
+// contractEnv is a server with every optional store configured, and API
+// keys for an admin and a viewer.
+type contractEnv struct {
+	srv    *Server
+	admin  string
+	viewer string
+}
+
+func newContractEnv(t *testing.T) contractEnv {
+	t.Helper()
+	dir := t.TempDir()
+	q := contractQuerier{&startQuerier{tenantQuerier: tenantQuerier{tenant: "acme"}, started: map[string]string{}}}
+	srv, err := New(q, nil, OIDCConfig{})
+	require.NoError(t, err)
+
+	ledger, err := savings.OpenFileStore(filepath.Join(dir, "savings.jsonl"))
+	require.NoError(t, err)
+	require.NoError(t, ledger.Upsert(savings.Entry{ID: "wf-1/a", WorkflowID: "wf-1", ActionID: "a", TenantID: "acme",
+		Service: "EC2", ActionType: "delete_volume", Status: savings.StatusVerified, EstimatedMonthly: 300,
+		RealizedMonthly: 250, PlannedAt: "2026-02-09T22:00:00Z", ExecutedAt: "2026-02-10T22:00:00Z"}))
+	srv.SetSavings(ledger)
+
+	hist := history.NewMemoryStore()
+	require.NoError(t, hist.Upsert(history.Record{WorkflowID: "wf-1", TenantID: "acme", Service: "EC2",
+		AccountID: "123456789012", DeltaDollars: 420, Phase: "completed", StartedAt: "2026-03-01T10:00:00Z"}))
+	srv.SetHistory(hist)
+
+	log, err := audit.OpenFileLog(filepath.Join(dir, "audit.jsonl"))
+	require.NoError(t, err)
+	srv.SetAudit(log)
+
+	keys, err := apikey.OpenFileStore(filepath.Join(dir, "keys.jsonl"))
+	require.NoError(t, err)
+	srv.SetAPIKeys(keys)
+	admin, _, err := apikey.Issue(keys, apikey.Key{Name: "admin", Admin: true, Roles: []rbac.Role{rbac.TenantAdmin}}, time.Now())
+	require.NoError(t, err)
+	viewer, _, err := apikey.Issue(keys, apikey.Key{Name: "viewer", TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}}, time.Now())
+	require.NoError(t, err)
+	return contractEnv{srv: srv, admin: admin, viewer: viewer}
+}
+
+// specOperation is the part of an OpenAPI operation the contract checks.
+type specOperation struct {
+	OperationID string `json:"operationId"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema json.RawMessage `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema json.RawMessage `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+// spec is the served document, with its schemas ready to validate
+// response bodies.
+type spec struct {
+	Paths      map[string]map[string]specOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+// validate checks body against schema, which may refer to the document's
+// component schemas.
+func (s spec) validate(schema json.RawMessage, body []byte) error {
+	// Component references become $defs of a standalone JSON Schema.
+	defs, err := json.Marshal(s.Components.Schemas)
+	if err != nil {
+		return err
+	}
+	root := fmt.Sprintf(`{"$schema":"https://json-schema.org/draft/2020-12/schema","allOf":[%s],"$defs":%s}`, schema, defs)
+	root = strings.ReplaceAll(root, "#/components/schemas/", "#/$defs/")
+	var js jsonschema.Schema
+	if err := json.Unmarshal([]byte(root), &js); err != nil {
+		return err
+	}
+	resolved, err := js.Resolve(nil)
+	if err != nil {
+		return err
+	}
+	var instance any
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return err
+	}
+	return resolved.Validate(instance)
+}
+
+// find returns the operation serving method and path.
+func (s spec) find(method, path string) (specOperation, bool) {
+	segments := strings.Split(path, "/")
+	for tmpl, item := range s.Paths {
+		parts := strings.Split(tmpl, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		match := true
+		for i, part := range parts {
+			if !strings.HasPrefix(part, "{") && part != segments[i] {
+				match = false
+				break
+			}
+		}
+		if op, ok := item[strings.ToLower(method)]; match && ok {
+			return op, true
+		}
+	}
+	return specOperation{}, false
+}
+
+func loadSpec(t *testing.T, srv http.Handler) spec {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	assert.Equal(t, OpenAPIVersion, raw["openapi"])
+	var s spec
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &s))
+	return s
+}
+
+func TestOpenAPI_DocumentsEveryEndpoint(t *testing.T) {
+	env := newContractEnv(t)
+	s := loadSpec(t, env.srv)
+
+	var registered, documented []string
+	for _, ep := range env.srv.endpoints {
+		method, path, _ := strings.Cut(ep.Pattern, " ")
+		registered = append(registered, ep.Operation)
+		op, ok := s.Paths[path][strings.ToLower(method)]
+		if assert.True(t, ok, "%s is not in the spec", ep.Pattern) {
+			assert.Equal(t, ep.Operation, op.OperationID)
+		}
+	}
+	for _, item := range s.Paths {
+		for _, op := range item {
+			documented = append(documented, op.OperationID)
+		}
+	}
+	assert.ElementsMatch(t, registered, documented)
+	assert.Len(t, operationDocs, len(registered), "operationDocs documents an operation that is not registered")
+}
+
+// TestOpenAPI_Contract drives every endpoint and checks each response's
+// status is documented and its body matches the documented schema.
+func TestOpenAPI_Contract(t *testing.T) {
+	env := newContractEnv(t)
+	s := loadSpec(t, env.srv)
+	admin := map[string]string{"X-API-Key": env.admin}
+	viewer := map[string]string{"X-API-Key": env.viewer}
+
+	var newKeyID string
+	exchanges := []struct {
+		method, target, body string
+		header               map[string]string
+		wantStatus           int
+	}{
+		{method: http.MethodGet, target: "/api/v1/health", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/openapi.json", wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/workflows", wantStatus: http.StatusUnauthorized},
+		{method: http.MethodGet, target: "/api/v1/workflows?tenant=acme&limit=10", header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/workflows?min_delta=lots", header: admin, wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/v1/workflows?tenant=globex", header: viewer, wantStatus: http.StatusNotFound},
+		{method: http.MethodGet, target: "/api/v1/workflows/wf-1", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/workflows/wf-1/ui", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/workflows/wf-1/stream", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/api/v1/workflows/wf-1/approve", body: `{}`, header: viewer, wantStatus: http.StatusForbidden},
+		{method: http.MethodPost, target: "/api/v1/workflows/wf-1/approve", body: `{}`, header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/api/v1/workflows/wf-1/deny", body: `{"reason":"not now"}`, header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/api/v1/workflows/wf-1/deny", body: `{`, header: admin, wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/v1/savings", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/savings?group_by=service", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/savings?format=csv", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/savings?from=March", header: viewer, wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/v1/anomalies", header: viewer, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/anomalies?limit=0", header: viewer, wantStatus: http.StatusBadRequest},
+		{method: http.MethodPost, target: "/api/v1/anomalies", body: strings.Replace(anomalyBody, "{", `{"tenant":"acme",`, 1), header: admin, wantStatus: http.StatusCreated},
+		{method: http.MethodPost, target: "/api/v1/anomalies", body: strings.Replace(anomalyBody, "{", `{"tenant":"acme",`, 1), header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodPost, target: "/api/v1/anomalies", body: anomalyBody, header: admin, wantStatus: http.StatusBadRequest},
+		{method: http.MethodPost, target: "/api/v1/sweeps", body: `{"tenant":"acme","accounts":[{"account_id":"123456789012","region":"us-east-1"}]}`,
+			header: map[string]string{"X-API-Key": env.admin, "Idempotency-Key": "nightly"}, wantStatus: http.StatusCreated},
+		{method: http.MethodPost, target: "/api/v1/sweeps", body: `{"tenant":"acme","accounts":[{"account_id":"123456789012","region":"eu-west-1"}]}`,
+			header: map[string]string{"X-API-Key": env.admin, "Idempotency-Key": "nightly"}, wantStatus: http.StatusConflict},
+		{method: http.MethodGet, target: "/api/v1/audit?limit=1", header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodGet, target: "/api/v1/audit", header: viewer, wantStatus: http.StatusForbidden},
+		{method: http.MethodPost, target: "/api/v1/apikeys", body: `{"name":"ci","tenant":"acme","roles":["analyst"],"expires_in":"24h"}`, header: admin, wantStatus: http.StatusCreated},
+		{method: http.MethodPost, target: "/api/v1/apikeys", body: `{"name":"ci","tenant":"acme","roles":["root"]}`, header: admin, wantStatus: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/api/v1/apikeys", header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodDelete, target: "/api/v1/apikeys/{new}", header: admin, wantStatus: http.StatusOK},
+		{method: http.MethodDelete, target: "/api/v1/apikeys/missing", header: admin, wantStatus: http.StatusNotFound},
+	}
+
+	exercised := map[string]bool{}
+	for _, ex := range exchanges {
+		target := strings.Replace(ex.target, "{new}", newKeyID, 1)
+		name := ex.method + " " + target
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(ex.method, target, strings.NewReader(ex.body))
+			for k, v := range ex.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			env.srv.ServeHTTP(rec, req)
+			require.Equal(t, ex.wantStatus, rec.Code, rec.Body.String())
+
+			op, ok := s.find(ex.method, req.URL.Path)
+			require.True(t, ok, "no operation for %s", name)
+			resp, ok := op.Responses[fmt.Sprint(rec.Code)]
+			require.True(t, ok, "%s answers %d, which is not documented", op.OperationID, rec.Code)
+			media, _, _ := strings.Cut(rec.Header().Get("Content-Type"), ";")
+			content, ok := resp.Content[media]
+			require.True(t, ok, "%s %d answers %s, which is not documented", op.OperationID, rec.Code, media)
+
+			switch media {
+			case "application/json":
+				assert.NoError(t, s.validate(content.Schema, rec.Body.Bytes()), rec.Body.String())
+			case ProblemContentType:
+				assert.NoError(t, s.validate(content.Schema, rec.Body.Bytes()), rec.Body.String())
+				var p Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+				assert.Equal(t, rec.Header().Get("X-Request-ID"), p.RequestID)
+			case "text/event-stream":
+				events := 0
+				sc := bufio.NewScanner(rec.Body)
+				sc.Buffer(nil, 1<<20)
+				for sc.Scan() {
+					if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+						events++
+						assert.NoError(t, s.validate(content.Schema, []byte(data)), data)
+					}
+				}
+				assert.Positive(t, events)
+			}
+			if rec.Code < 300 {
+				exercised[op.OperationID] = true
+				// An accepted body is one the spec allows.
+				if ex.body != "" {
+					require.NotNil(t, op.RequestBody, "%s takes no body", op.OperationID)
+					assert.NoError(t, s.validate(op.RequestBody.Content["application/json"].Schema, []byte(ex.body)))
+				}
+			}
+			if op.OperationID == "create_api_key" && rec.Code == http.StatusCreated {
+				var created CreateAPIKeyResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+				newKeyID = created.Key.ID
+			}
+		})
+	}
+
+	for op := range operationDocs {
+		assert.True(t, exercised[op], "no successful call to %s was checked against the spec", op)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+type recordingAuditor struct{ denials []rbac.Denial }
+
+func (a *recordingAuditor) RecordDenial(_ context.Context, d rbac.Denial) {
+	a.denials = append(a.denials, d)
+}
+
+func TestRBAC_Endpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		roles      []rbac.Role
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "viewer lists", roles: []rbac.Role{rbac.Viewer}, method: http.MethodGet, path: "/api/v1/workflows", wantStatus: http.StatusOK},
+		{name: "viewer reads", roles: []rbac.Role{rbac.Viewer}, method: http.MethodGet, path: "/api/v1/workflows/wf-1", wantStatus: http.StatusOK},
+		{name: "viewer cannot approve", roles: []rbac.Role{rbac.Viewer}, method: http.MethodPost, path: "/api/v1/workflows/wf-1/approve", body: `{"by":"alice"}`, wantStatus: http.StatusForbidden},
+		{name: "analyst cannot deny", roles: []rbac.Role{rbac.Analyst}, method: http.MethodPost, path: "/api/v1/workflows/wf-1/deny", body: `{"by":"alice"}`, wantStatus: http.StatusForbidden},
+		{name: "approver approves", roles: []rbac.Role{rbac.Approver}, method: http.MethodPost, path: "/api/v1/workflows/wf-1/approve", body: `{"by":"alice"}`, wantStatus: http.StatusOK},
+		{name: "executor-admin denies", roles: []rbac.Role{rbac.ExecutorAdmin}, method: http.MethodPost, path: "/api/v1/workflows/wf-1/deny", body: `{"by":"alice"}`, wantStatus: http.StatusOK},
+		{name: "no roles cannot read", roles: []rbac.Role{}, method: http.MethodGet, path: "/api/v1/workflows", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &tenantQuerier{tenant: "acme"}
+			srv := newTenancyServer(t, q)
+			a := &recordingAuditor{}
+			srv.SetAuditor(a)
+
+			rec := caller{tenant: "acme", roles: tt.roles}.do(t, srv, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusForbidden {
+				assert.Empty(t, a.denials)
+				return
+			}
+			assert.False(t, q.submitted)
+			require.Len(t, a.denials, 1)
+			assert.Equal(t, "api", a.denials[0].Surface)
+			assert.Equal(t, "acme", a.denials[0].TenantID)
+		})
+	}
+}
@@ -3,15 +3,65 @@ package api
 
 import (
 	"encoding/json"
+	"log/slog"
 	"net/http"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
 )
 
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of every error response: RFC 7807 problem details,
+// extended with a stable error code and the request's ID, which matches
+// the X-Request-ID header and the server's logs.
+type Problem struct {
+	// Type identifies the kind of problem: "urn:finops:error:" + Code.
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request path.
+	Instance  string      `json:"instance,omitempty"`
+	Code      apperr.Code `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// ResultResponse reports the outcome of an action.
+type ResultResponse struct {
+	Result string `json:"result"`
+}
+
+// HealthResponse is the body of GET /api/v1/health.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-func writeError(w http.ResponseWriter, status int, msg string) {
-	writeJSON(w, status, map[string]string{"error": msg})
+// writeError answers with err as a problem. The status and code come from
+// apperr's classification; server errors are logged in full and answered
+// with a generic detail, so internal messages do not leak.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	code, detail := apperr.Public(err)
+	status := code.HTTPStatus()
+	id := RequestIDFromContext(r.Context())
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "request_id", id, "path", r.URL.Path, "code", code, "error", err)
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:      "urn:finops:error:" + string(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: id,
+	})
 }
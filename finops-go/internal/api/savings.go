@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
+)
+
+// SetSavings enables GET /api/v1/savings backed by the given ledger.
+func (s *Server) SetSavings(repo savings.Repository) {
+	s.savings = repo
+}
+
+// SavingsResponse is the JSON body of GET /api/v1/savings. Entries is set
+// without group_by, Groups with it.
+type SavingsResponse struct {
+	GroupBy []savings.Dimension `json:"group_by,omitempty"`
+	Groups  []savings.Group     `json:"groups,omitempty"`
+	Entries []savings.Entry     `json:"entries,omitempty"`
+	Totals  savings.Totals      `json:"totals"`
+}
+
+// handleSavings reports the savings ledger. Query parameters: tenant, team,
+// account, service, action_type, from and to (inclusive YYYY-MM-DD),
+// group_by (comma-separated dimensions), and format=csv. Callers confined
+// to a tenant only see its entries.
+func (s *Server) handleSavings(w http.ResponseWriter, r *http.Request) {
+	if s.savings == nil {
+		writeError(w, r, apperr.New(apperr.Unavailable, "savings ledger not configured"))
+		return
+	}
+
+	q := r.URL.Query()
+	tenant, ok := scopeTenant(w, r, q.Get("tenant"))
+	if !ok {
+		return
+	}
+	filter := savings.Filter{
+		TenantID:   tenant,
+		Team:       q.Get("team"),
+		AccountID:  q.Get("account"),
+		Service:    q.Get("service"),
+		ActionType: q.Get("action_type"),
+		From:       q.Get("from"),
+		To:         q.Get("to"),
+	}
+	for _, d := range []string{filter.From, filter.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			writeError(w, r, apperr.New(apperr.InvalidArgument, "from/to must be YYYY-MM-DD"))
+			return
+		}
+	}
+	dims, err := savings.ParseDimensions(q.Get("group_by"))
+	if err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, err.Error()))
+		return
+	}
+
+	entries, err := s.savings.List(filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	groups, totals := savings.Summarize(entries, dims)
+
+	if q.Get("format") == "csv" || r.Header.Get("Accept") == "text/csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="savings.csv"`)
+		w.WriteHeader(http.StatusOK)
+		if len(dims) > 0 {
+			_ = savings.WriteGroupsCSV(w, dims, groups)
+		} else {
+			_ = savings.WriteEntriesCSV(w, entries)
+		}
+		return
+	}
+
+	resp := SavingsResponse{Totals: totals}
+	if len(dims) > 0 {
+		resp.GroupBy, resp.Groups = dims, groups
+	} else {
+		resp.Entries = entries
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,114 @@
+package api_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
+)
+
+func newSavingsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	store, err := savings.OpenFileStore(filepath.Join(t.TempDir(), "savings.jsonl"))
+	require.NoError(t, err)
+	for _, e := range []savings.Entry{
+		{ID: "wf-1/a", TenantID: "acme", Team: "platform", Service: "EC2", ActionType: "delete_volume",
+			Status: savings.StatusVerified, EstimatedMonthly: 300, RealizedMonthly: 250, ExecutedAt: "2026-02-10T22:00:00Z"},
+		{ID: "wf-2/a", TenantID: "acme", Team: "data", Service: "S3", ActionType: "lifecycle",
+			Status: savings.StatusExecuted, EstimatedMonthly: 100, ExecutedAt: "2026-03-05T22:00:00Z"},
+		{ID: "wf-3/a", TenantID: "globex", Team: "platform", Service: "EC2", ActionType: "rightsize",
+			Status: savings.StatusVerified, EstimatedMonthly: 80, RealizedMonthly: 90, ExecutedAt: "2026-03-06T22:00:00Z"},
+	} {
+		require.NoError(t, store.Upsert(e))
+	}
+
+	srv, err := api.New(&stubQuerier{}, []string{"*"}, api.OIDCConfig{})
+	require.NoError(t, err)
+	srv.SetSavings(store)
+	return httptest.NewServer(srv)
+}
+
+func getSavings(t *testing.T, url string) map[string]any {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+func TestSavings_Entries(t *testing.T) {
+	ts := newSavingsServer(t)
+	defer ts.Close()
+
+	body := getSavings(t, ts.URL+"/api/v1/savings?tenant=acme&from=2026-03-01&to=2026-03-31")
+	entries := body["entries"].([]any)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "wf-2/a", entries[0].(map[string]any)["id"])
+	totals := body["totals"].(map[string]any)
+	assert.Equal(t, 100.0, totals["estimated_monthly"])
+	assert.Equal(t, 0.0, totals["realized_monthly"])
+}
+
+func TestSavings_GroupBy(t *testing.T) {
+	ts := newSavingsServer(t)
+	defer ts.Close()
+
+	body := getSavings(t, ts.URL+"/api/v1/savings?group_by=team,month")
+	groups := body["groups"].([]any)
+	require.Len(t, groups, 3)
+	first := groups[0].(map[string]any)
+	assert.Equal(t, map[string]any{"team": "data", "month": "2026-03"}, first["key"])
+	totals := body["totals"].(map[string]any)
+	assert.Equal(t, 340.0, totals["realized_monthly"])
+	assert.Equal(t, 3.0, totals["actions"])
+}
+
+func TestSavings_CSV(t *testing.T) {
+	ts := newSavingsServer(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/savings?group_by=service&format=csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"service", "actions", "executed", "verified", "estimated_monthly", "executed_estimated_monthly", "realized_monthly"}, rows[0])
+	assert.Equal(t, []string{"EC2", "2", "2", "2", "380.00", "380.00", "340.00"}, rows[1])
+}
+
+func TestSavings_BadRequests(t *testing.T) {
+	ts := newSavingsServer(t)
+	defer ts.Close()
+
+	for _, q := range []string{"group_by=region", "from=March"} {
+		resp, err := http.Get(ts.URL + "/api/v1/savings?" + q)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, q)
+	}
+}
+
+func TestSavings_NotConfigured(t *testing.T) {
+	ts := newTestServer(t, &stubQuerier{})
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/v1/savings")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
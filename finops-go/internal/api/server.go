@@ -5,31 +5,54 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 
 	"github.com/finops-claw-gang/finops-go/internal/agui"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 )
 
 // Server is the HTTP API server for the FinOps Generative UI.
 type Server struct {
-	querier querier.WorkflowQuerier
-	mux     *http.ServeMux
-	handler http.Handler
+	querier   querier.WorkflowQuerier
+	savings   savings.Repository // nil = savings endpoint unavailable
+	history   history.Repository // nil = anomalies endpoint unavailable
+	audit     audit.Log          // nil = no audit trail
+	auditor   rbac.Auditor
+	keys      apikey.Store           // nil = API keys not accepted
+	metrics   *observability.Metrics // nil = no request metrics
+	premium   map[string]bool        // tenants with dedicated task queues
+	weights   map[string]float64     // nil = every tenant weighs 1
+	polledMu  sync.Mutex
+	polled    map[string]queueCheck // premium tenants' queues, whether workers poll them and when that was checked
+	oidc      bool
+	mux       *http.ServeMux
+	endpoints []endpoint
+	openAPI   func() ([]byte, error) // the encoded OpenAPI document, built once
+	app       http.Handler           // handler before authentication
+	handler   http.Handler
 }
 
 // New creates a Server with the given querier, CORS origins, and optional OIDC config.
 // Returns an error if OIDC is enabled but the provider cannot be reached.
 func New(q querier.WorkflowQuerier, corsOrigins []string, oidcCfg OIDCConfig) (*Server, error) {
-	s := &Server{querier: q, mux: http.NewServeMux()}
+	s := &Server{querier: q, auditor: rbac.LogAuditor{}, mux: http.NewServeMux()}
 	s.routes()
+	s.openAPI = sync.OnceValues(s.encodeOpenAPI)
 
 	var handler http.Handler = s.mux
 	handler = cors(corsOrigins, handler)
-	handler = logging(handler)
-	handler = requestID(handler)
+	handler = s.logging(handler)
+	s.app = handler
 
 	if oidcCfg.Enabled {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -39,6 +62,7 @@ func New(q querier.WorkflowQuerier, corsOrigins []string, oidcCfg OIDCConfig) (*
 			return nil, fmt.Errorf("oidc provider %s: %w", oidcCfg.IssuerURL, err)
 		}
 		handler = oidcAuth(provider, oidcCfg.Audience)(handler)
+		s.oidc = true
 		slog.Info("OIDC authentication enabled", "issuer", oidcCfg.IssuerURL)
 	}
 
@@ -46,17 +70,84 @@ func New(q querier.WorkflowQuerier, corsOrigins []string, oidcCfg OIDCConfig) (*
 	return s, nil
 }
 
-// ServeHTTP implements http.Handler.
+// ServeHTTP implements http.Handler. With API keys enabled, a request
+// carrying a key is authenticated by it; any other goes through OIDC.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = withRequestID(w, r)
+	if s.keys != nil {
+		if key, ok := apiKeyFromRequest(r); ok {
+			s.serveWithKey(w, r, key)
+			return
+		}
+		if !s.oidc && !isPublic(r.URL.Path) {
+			writeError(w, r, apperr.New(apperr.Unauthenticated, "missing API key"))
+			return
+		}
+	}
 	s.handler.ServeHTTP(w, r)
 }
 
+// SetMetrics records request durations by route in m.
+func (s *Server) SetMetrics(m *observability.Metrics) {
+	s.metrics = m
+}
+
+// SetAuditor replaces where permission denials are recorded. The default
+// logs them.
+func (s *Server) SetAuditor(a rbac.Auditor) {
+	s.auditor = a
+}
+
+// endpoint is a registered route: its mux pattern, the operation name
+// used in the OpenAPI document and permission audits, and the permission
+// it requires ("" for public endpoints).
+type endpoint struct {
+	Pattern   string
+	Operation string
+	Perm      rbac.Permission
+}
+
 func (s *Server) routes() {
-	s.mux.HandleFunc("GET /api/v1/health", s.handleHealth)
-	s.mux.HandleFunc("GET /api/v1/workflows", s.handleListWorkflows)
-	s.mux.HandleFunc("GET /api/v1/workflows/{id}", s.handleGetWorkflow)
-	s.mux.HandleFunc("GET /api/v1/workflows/{id}/ui", s.handleGetWorkflowUI)
-	s.mux.HandleFunc("POST /api/v1/workflows/{id}/approve", s.handleApprove)
-	s.mux.HandleFunc("POST /api/v1/workflows/{id}/deny", s.handleDeny)
-	s.mux.HandleFunc("GET /api/v1/workflows/{id}/stream", agui.StreamHandler(s.querier, agui.DefaultConfig()))
+	s.handle("GET /api/v1/health", "health", "", s.handleHealth)
+	s.handle("GET /api/v1/openapi.json", "get_openapi", "", s.handleOpenAPI)
+	s.handle("GET /api/v1/workflows", "list_workflows", rbac.PermRead, s.handleListWorkflows)
+	s.handle("GET /api/v1/workflows/{id}", "get_workflow", rbac.PermRead, s.handleGetWorkflow)
+	s.handle("GET /api/v1/workflows/{id}/ui", "get_workflow_ui", rbac.PermRead, s.handleGetWorkflowUI)
+	s.handle("POST /api/v1/workflows/{id}/approve", "approve", rbac.PermApprove, s.handleApprove)
+	s.handle("POST /api/v1/workflows/{id}/deny", "deny", rbac.PermApprove, s.handleDeny)
+	s.handle("GET /api/v1/savings", "get_savings", rbac.PermRead, s.handleSavings)
+	s.handle("GET /api/v1/anomalies", "list_anomalies", rbac.PermRead, s.handleListAnomalies)
+	s.handle("POST /api/v1/anomalies", "trigger_anomaly", rbac.PermTrigger, s.handleTriggerAnomaly)
+	s.handle("POST /api/v1/sweeps", "trigger_sweep", rbac.PermTrigger, s.handleTriggerSweep)
+	// The trail includes caller IPs and token IDs, so reading it is an
+	// admin operation.
+	s.handle("GET /api/v1/audit", "get_audit", rbac.PermTenantAdmin, s.handleAudit)
+	s.handle("POST /api/v1/apikeys", "create_api_key", rbac.PermTenantAdmin, s.handleCreateAPIKey)
+	s.handle("GET /api/v1/apikeys", "list_api_keys", rbac.PermTenantAdmin, s.handleListAPIKeys)
+	s.handle("DELETE /api/v1/apikeys/{id}", "revoke_api_key", rbac.PermTenantAdmin, s.handleRevokeAPIKey)
+	s.handle("GET /api/v1/workflows/{id}/stream", "stream_workflow", rbac.PermRead,
+		s.tenantScoped(agui.StreamHandler(s.querier, agui.DefaultConfig())))
+}
+
+// handle registers an endpoint, guarded by perm unless it is public.
+func (s *Server) handle(pattern, operation string, perm rbac.Permission, h http.HandlerFunc) {
+	s.endpoints = append(s.endpoints, endpoint{Pattern: pattern, Operation: operation, Perm: perm})
+	if perm != "" {
+		h = s.require(perm, operation, h)
+	}
+	s.mux.HandleFunc(pattern, h)
+}
+
+// require guards a handler with a permission check. Denials get 403 and
+// are audited. With authentication off every request is allowed.
+func (s *Server) require(perm rbac.Permission, operation string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := principalFromContext(r.Context()); ok {
+			if err := rbac.Check(r.Context(), s.auditor, p, perm, "api", operation); err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+		next(w, r)
+	}
 }
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// tenantScope returns the tenant an authenticated caller is confined to.
+// scoped is false for admins, and for every caller when authentication is
+// off.
+func tenantScope(ctx context.Context) (tenant string, scoped bool) {
+	if authed, _ := ctx.Value(ctxAuthenticated).(bool); !authed || IsAdminFromContext(ctx) {
+		return "", false
+	}
+	return TenantFromContext(ctx), true
+}
+
+// scopeTenant resolves the tenant filter of a list request from the
+// requested one. A scoped caller always gets their own tenant; asking for
+// another is answered like an unknown tenant. ok is false once an error
+// response has been written.
+func scopeTenant(w http.ResponseWriter, r *http.Request, requested string) (tenant string, ok bool) {
+	own, scoped := tenantScope(r.Context())
+	switch {
+	case !scoped:
+		return requested, true
+	case own == "":
+		writeError(w, r, apperr.New(apperr.Forbidden, "token has no tenant_id claim"))
+		return "", false
+	case requested != "" && requested != own:
+		writeError(w, r, apperr.New(apperr.NotFound, "tenant not found"))
+		return "", false
+	}
+	return own, true
+}
+
+// loadWorkflow reads the state of the workflow named in the path and
+// checks the caller may see it. Another tenant's workflow is reported as
+// not found, so its existence does not leak. ok is false once an error
+// response has been written.
+func (s *Server) loadWorkflow(w http.ResponseWriter, r *http.Request) (*workflows.WorkflowResult, bool) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "workflow id required"))
+		return nil, false
+	}
+
+	result, err := s.querier.GetWorkflowState(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return nil, false
+	}
+	if own, scoped := tenantScope(r.Context()); scoped && (own == "" || result.State.Tenant.TenantID != own) {
+		writeError(w, r, apperr.New(apperr.NotFound, "workflow not found"))
+		return nil, false
+	}
+	return result, true
+}
+
+// tenantScoped guards a per-workflow handler, such as the AG-UI stream,
+// with loadWorkflow.
+func (s *Server) tenantScoped(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.loadWorkflow(w, r); !ok {
+			return
+		}
+		next(w, r)
+	}
+}
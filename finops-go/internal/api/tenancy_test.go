@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// tenantQuerier serves one workflow owned by tenant and records calls.
+type tenantQuerier struct {
+	tenant    string
+	listOpts  *querier.ListOptions
+	submitted bool
+	resp      activities.ApprovalResponse
+}
+
+func (q *tenantQuerier) ListWorkflows(_ context.Context, opts querier.ListOptions) ([]querier.WorkflowSummary, error) {
+	q.listOpts = &opts
+	return nil, nil
+}
+
+func (q *tenantQuerier) GetWorkflowState(_ context.Context, _ string) (*workflows.WorkflowResult, error) {
+	return &workflows.WorkflowResult{State: domain.NewFinOpsState(domain.NewTenantContext(q.tenant))}, nil
+}
+
+func (q *tenantQuerier) DescribeWorkflow(_ context.Context, id string) (*querier.WorkflowDescription, error) {
+	return &querier.WorkflowDescription{WorkflowSummary: querier.WorkflowSummary{WorkflowID: id}}, nil
+}
+
+func (q *tenantQuerier) SubmitApproval(_ context.Context, _ string, resp activities.ApprovalResponse) (string, error) {
+	q.submitted = true
+	q.resp = resp
+	return "approved", nil
+}
+
+// caller is an authenticated principal; tenant "" means no tenant claim.
+// roles defaults to approver, which every endpoint here allows, and the
+// subject to "alice" unless the context names one.
+type caller struct {
+	tenant string
+	admin  bool
+	roles  []rbac.Role
+}
+
+func (c caller) do(t *testing.T, srv http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	return c.doCtx(t, context.Background(), srv, method, target, body)
+}
+
+// doCtx is do with extra context values, such as the user or token ID.
+func (c caller) doCtx(t *testing.T, ctx context.Context, srv http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	ctx = context.WithValue(ctx, ctxAuthenticated, true)
+	if UserFromContext(ctx) == "" {
+		ctx = context.WithValue(ctx, ctxUserID, "alice")
+	}
+	if c.tenant != "" {
+		ctx = context.WithValue(ctx, ctxTenantID, c.tenant)
+	}
+	if c.admin {
+		ctx = context.WithValue(ctx, ctxAdmin, true)
+	}
+	roles := c.roles
+	if roles == nil {
+		roles = []rbac.Role{rbac.Approver}
+	}
+	ctx = context.WithValue(ctx, ctxRoles, roles)
+	req := httptest.NewRequestWithContext(ctx, method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTenancyServer(t *testing.T, q *tenantQuerier) *Server {
+	t.Helper()
+	srv, err := New(q, nil, OIDCConfig{})
+	require.NoError(t, err)
+	return srv
+}
+
+func TestTenancy_ListWorkflows(t *testing.T) {
+	tests := []struct {
+		name       string
+		caller     caller
+		query      string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "pinned to own tenant", caller: caller{tenant: "acme"}, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "own tenant requested", caller: caller{tenant: "acme"}, query: "?tenant=acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "other tenant requested", caller: caller{tenant: "acme"}, query: "?tenant=globex", wantStatus: http.StatusNotFound},
+		{name: "no tenant claim", caller: caller{}, wantStatus: http.StatusForbidden},
+		{name: "admin any tenant", caller: caller{tenant: "acme", admin: true}, query: "?tenant=globex", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "admin all tenants", caller: caller{admin: true}, wantStatus: http.StatusOK, wantTenant: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &tenantQuerier{tenant: "acme"}
+			rec := tt.caller.do(t, newTenancyServer(t, q), http.MethodGet, "/api/v1/workflows"+tt.query, "")
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus != http.StatusOK {
+				assert.Nil(t, q.listOpts)
+				return
+			}
+			require.NotNil(t, q.listOpts)
+			assert.Equal(t, tt.wantTenant, q.listOpts.TenantID)
+		})
+	}
+}
+
+func TestTenancy_WorkflowAccess(t *testing.T) {
+	paths := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, "/api/v1/workflows/wf-1", ""},
+		{http.MethodGet, "/api/v1/workflows/wf-1/ui", ""},
+		{http.MethodGet, "/api/v1/workflows/wf-1/stream", ""},
+		{http.MethodPost, "/api/v1/workflows/wf-1/approve", `{"by":"alice"}`},
+		{http.MethodPost, "/api/v1/workflows/wf-1/deny", `{"by":"alice"}`},
+	}
+	for _, p := range paths {
+		t.Run(p.method+" "+p.path, func(t *testing.T) {
+			q := &tenantQuerier{tenant: "acme"}
+			srv := newTenancyServer(t, q)
+
+			rec := caller{tenant: "globex"}.do(t, srv, p.method, p.path, p.body)
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.False(t, q.submitted, "cross-tenant approval must not reach the workflow")
+
+			// The stream runs until its request context ends.
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			rec = caller{tenant: "globex", admin: true}.doCtx(t, ctx, srv, p.method, p.path, p.body)
+			assert.NotEqual(t, http.StatusNotFound, rec.Code)
+		})
+	}
+
+	q := &tenantQuerier{tenant: "acme"}
+	rec := caller{tenant: "acme"}.do(t, newTenancyServer(t, q), http.MethodPost, "/api/v1/workflows/wf-1/approve", `{"by":"alice"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, q.submitted)
+}
+
+func TestTenancy_Unauthenticated(t *testing.T) {
+	// With authentication off there is no caller to scope by.
+	q := &tenantQuerier{tenant: "acme"}
+	srv := newTenancyServer(t, q)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/workflows/wf-1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// MaxSweepAccounts caps the accounts one sweep request may scan.
+const MaxSweepAccounts = 100
+
+// maxIdempotencyKey bounds the Idempotency-Key header.
+const maxIdempotencyKey = 255
+
+var (
+	accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+	servicePattern   = regexp.MustCompile(`^[a-zA-Z0-9 _.-]+$`)
+	regionPattern    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d$`)
+)
+
+// TriggerAnomalyRequest is the body of POST /api/v1/anomalies.
+type TriggerAnomalyRequest struct {
+	Tenant      string             `json:"tenant,omitempty"`
+	Anomaly     domain.CostAnomaly `json:"anomaly"`
+	WindowStart string             `json:"window_start"`
+	WindowEnd   string             `json:"window_end"`
+}
+
+// TriggerSweepRequest is the body of POST /api/v1/sweeps. Accounts are
+// scanned with the worker's own credentials: per-account AWS profiles are
+// not accepted over the API.
+type TriggerSweepRequest struct {
+	Tenant   string         `json:"tenant,omitempty"`
+	Accounts []SweepAccount `json:"accounts"`
+}
+
+// SweepAccount is one account of a sweep request.
+type SweepAccount struct {
+	AccountID string `json:"account_id"`
+	Region    string `json:"region"`
+}
+
+// premiumCheckTTL is how long the API trusts that workers poll a premium
+// tenant's dedicated queues before checking again. premiumMissTTL is how
+// long it trusts that they do not: shorter, so a tenant is moved onto its
+// queues soon after workers start polling them.
+const (
+	premiumCheckTTL = time.Minute
+	premiumMissTTL  = 10 * time.Second
+)
+
+// queueCheck is the cached result of checking a premium tenant's queues.
+type queueCheck struct {
+	at     time.Time
+	polled bool
+}
+
+// SetTenantScheduling sets how started workflows are scheduled: premium
+// tenants run on their dedicated task queues, while workers poll them, and
+// weights set tenants' shares of the shared queues.
+func (s *Server) SetTenantScheduling(premium []string, weights map[string]float64) {
+	s.premium = make(map[string]bool, len(premium))
+	for _, tenant := range premium {
+		s.premium[tenant] = true
+	}
+	s.weights = weights
+	s.polled = make(map[string]queueCheck)
+}
+
+// tenantContext returns the context workflows of tenant are started with.
+func (s *Server) tenantContext(ctx context.Context, tenant string) domain.TenantContext {
+	tc := domain.NewTenantContext(tenant)
+	tc.Premium = s.premium[tenant] && s.premiumQueuesPolled(ctx, tenant)
+	tc.Weight = s.weights[tenant]
+	return tc
+}
+
+// premiumQueuesPolled reports whether workers poll the dedicated queues of
+// a premium tenant. When they do not, for example because the workers'
+// FINOPS_PREMIUM_TENANTS lacks the tenant, the tenant's workflows run on
+// the shared queues rather than wait for a worker that never comes, and
+// each such fallback is counted. A querier that cannot check is trusted.
+func (s *Server) premiumQueuesPolled(ctx context.Context, tenant string) bool {
+	qc, ok := s.querier.(querier.QueueChecker)
+	if !ok {
+		return true
+	}
+	s.polledMu.Lock()
+	check, ok := s.polled[tenant]
+	s.polledMu.Unlock()
+	ttl := premiumMissTTL
+	if check.polled {
+		ttl = premiumCheckTTL
+	}
+	if !ok || time.Since(check.at) >= ttl {
+		check = queueCheck{at: time.Now(), polled: s.checkPremiumQueues(ctx, qc, tenant)}
+		s.polledMu.Lock()
+		s.polled[tenant] = check
+		s.polledMu.Unlock()
+	}
+	if !check.polled && s.metrics != nil {
+		s.metrics.RecordPremiumFallback(ctx, tenant)
+	}
+	return check.polled
+}
+
+// checkPremiumQueues asks Temporal whether workers poll both of tenant's
+// dedicated queues, and logs the first one they do not.
+func (s *Server) checkPremiumQueues(ctx context.Context, qc querier.QueueChecker, tenant string) bool {
+	for _, base := range []string{versioning.QueueAnomaly, versioning.QueueExec} {
+		queue := versioning.TenantQueue(base, tenant)
+		polled, err := qc.TaskQueuePolled(ctx, queue)
+		if err != nil || !polled {
+			slog.WarnContext(ctx, "no worker polls premium tenant queue, using shared queues",
+				"tenant", tenant, "queue", queue, "error", err)
+			return false
+		}
+	}
+	return true
+}
+
+// handleTriggerAnomaly validates a cost anomaly and starts its lifecycle
+// workflow.
+func (s *Server) handleTriggerAnomaly(w http.ResponseWriter, r *http.Request) {
+	starter, ok := s.starter(w, r)
+	if !ok {
+		return
+	}
+	var body TriggerAnomalyRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "invalid request body"))
+		return
+	}
+	tenant, ok := s.triggerTenant(w, r, body.Tenant)
+	if !ok {
+		return
+	}
+	body.Tenant = tenant
+	if err := validateAnomalyRequest(body); err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, err.Error()))
+		return
+	}
+	key, fingerprint, ok := idempotency(w, r, tenant, body)
+	if !ok {
+		return
+	}
+
+	id := "finops-anomaly-" + tenant + "-" + key
+	anomaly := body.Anomaly
+	if anomaly.AnomalyID == "" {
+		anomaly.AnomalyID = key[:8]
+	}
+	if anomaly.DetectedAt == "" {
+		anomaly.DetectedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	if anomaly.LookbackDays == 0 {
+		anomaly.LookbackDays = domain.NewCostAnomaly().LookbackDays
+	}
+	res, err := starter.StartAnomaly(r.Context(), id, fingerprint, workflows.WorkflowInput{
+		Tenant:      s.tenantContext(r.Context(), tenant),
+		Anomaly:     &anomaly,
+		WindowStart: body.WindowStart,
+		WindowEnd:   body.WindowEnd,
+	})
+	s.writeStarted(w, r, tenant, "anomaly", res, err)
+}
+
+// handleTriggerSweep starts an aws-doctor waste sweep over the accounts.
+func (s *Server) handleTriggerSweep(w http.ResponseWriter, r *http.Request) {
+	starter, ok := s.starter(w, r)
+	if !ok {
+		return
+	}
+	var body TriggerSweepRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "invalid request body"))
+		return
+	}
+	tenant, ok := s.triggerTenant(w, r, body.Tenant)
+	if !ok {
+		return
+	}
+	body.Tenant = tenant
+	if err := validateSweepRequest(body); err != nil {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, err.Error()))
+		return
+	}
+	key, fingerprint, ok := idempotency(w, r, tenant, body)
+	if !ok {
+		return
+	}
+
+	tc := s.tenantContext(r.Context(), tenant)
+	input := workflows.SweepInput{Tenant: tenant, Premium: tc.Premium, Weight: tc.Weight}
+	for _, a := range body.Accounts {
+		input.Accounts = append(input.Accounts, workflows.SweepAccount{AccountID: a.AccountID, Region: a.Region})
+	}
+	res, err := starter.StartSweep(r.Context(), "finops-sweep-"+tenant+"-"+key, fingerprint, input)
+	s.writeStarted(w, r, tenant, "sweep", res, err)
+}
+
+// starter returns the querier's start capability, or answers 501.
+func (s *Server) starter(w http.ResponseWriter, r *http.Request) (querier.WorkflowStarter, bool) {
+	st, ok := s.querier.(querier.WorkflowStarter)
+	if !ok {
+		writeError(w, r, apperr.New(apperr.Unimplemented, "starting workflows is not supported"))
+	}
+	return st, ok
+}
+
+// triggerTenant resolves the tenant a workflow is started for. Scoped
+// callers start workflows for their own tenant; others must name one.
+func (s *Server) triggerTenant(w http.ResponseWriter, r *http.Request, requested string) (string, bool) {
+	tenant, ok := scopeTenant(w, r, requested)
+	if !ok {
+		return "", false
+	}
+	if tenant == "" {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, "'tenant' is required"))
+		return "", false
+	}
+	return tenant, true
+}
+
+// idempotency derives the workflow ID suffix and the request fingerprint.
+// The fingerprint is a hash of the validated request. The ID suffix hashes
+// the tenant with the Idempotency-Key header, or with the fingerprint when
+// there is none, so identical retries map to the same workflow either way.
+func idempotency(w http.ResponseWriter, r *http.Request, tenant string, body any) (key, fingerprint string, ok bool) {
+	canonical, err := json.Marshal(body)
+	if err != nil {
+		writeError(w, r, err)
+		return "", "", false
+	}
+	sum := sha256.Sum256(canonical)
+	fingerprint = hex.EncodeToString(sum[:])
+
+	idem := r.Header.Get("Idempotency-Key")
+	if len(idem) > maxIdempotencyKey {
+		writeError(w, r, apperr.New(apperr.InvalidArgument, fmt.Sprintf("Idempotency-Key longer than %d bytes", maxIdempotencyKey)))
+		return "", "", false
+	}
+	if idem == "" {
+		idem = fingerprint
+	}
+	sum = sha256.Sum256([]byte(tenant + "\x00" + idem))
+	return hex.EncodeToString(sum[:8]), fingerprint, true
+}
+
+// writeStarted answers a start: 201 for a new workflow, 200 when the same
+// request already started it, and 409 when another request holds its ID.
+func (s *Server) writeStarted(w http.ResponseWriter, r *http.Request, tenant, kind string, res querier.StartResult, err error) {
+	if errors.Is(err, querier.ErrIdempotencyConflict) {
+		writeError(w, r, apperr.New(apperr.Conflict, "idempotency key already used with a different request"))
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if res.Existing {
+		status = http.StatusOK
+	} else {
+		e := auditEvent(r, audit.ActionTriggered, res.WorkflowID)
+		e.TenantID = tenant
+		e.Resource = kind
+		e.Outcome = "started"
+		audit.Record(r.Context(), s.audit, e)
+	}
+	writeJSON(w, status, res)
+}
+
+func validateAnomalyRequest(b TriggerAnomalyRequest) error {
+	a := b.Anomaly
+	if !servicePattern.MatchString(a.Service) {
+		return errors.New("anomaly.service is required and must be alphanumeric")
+	}
+	if !accountIDPattern.MatchString(a.AccountID) {
+		return errors.New("anomaly.account_id must be 12 digits")
+	}
+	if a.Region != "" && !regionPattern.MatchString(a.Region) {
+		return fmt.Errorf("anomaly.region %q is not an AWS region", a.Region)
+	}
+	if a.DeltaDollars == 0 {
+		return errors.New("anomaly.delta_dollars is required")
+	}
+	if a.LookbackDays < 0 {
+		return errors.New("anomaly.lookback_days must not be negative")
+	}
+	if len(a.AnomalyID) > 64 {
+		return errors.New("anomaly.anomaly_id is longer than 64 characters")
+	}
+	start, err := time.Parse(time.DateOnly, b.WindowStart)
+	if err != nil {
+		return errors.New("window_start must be YYYY-MM-DD")
+	}
+	end, err := time.Parse(time.DateOnly, b.WindowEnd)
+	if err != nil {
+		return errors.New("window_end must be YYYY-MM-DD")
+	}
+	if !start.Before(end) {
+		return errors.New("window_start must be before window_end")
+	}
+	return nil
+}
+
+func validateSweepRequest(b TriggerSweepRequest) error {
+	if len(b.Accounts) == 0 {
+		return errors.New("at least one account is required")
+	}
+	if len(b.Accounts) > MaxSweepAccounts {
+		return fmt.Errorf("at most %d accounts per sweep", MaxSweepAccounts)
+	}
+	for i, a := range b.Accounts {
+		if !accountIDPattern.MatchString(a.AccountID) {
+			return fmt.Errorf("accounts[%d].account_id must be 12 digits", i)
+		}
+		if !regionPattern.MatchString(a.Region) {
+			return fmt.Errorf("accounts[%d].region %q is not an AWS region", i, a.Region)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// startQuerier is a tenantQuerier that can start workflows, keeping the
+// fingerprint each ID was started with as Temporal's memo would.
+type startQuerier struct {
+	tenantQuerier
+	started   map[string]string
+	anomalies []workflows.WorkflowInput
+	sweeps    []workflows.SweepInput
+}
+
+func (q *startQuerier) start(id, fingerprint string) (querier.StartResult, error) {
+	if prev, ok := q.started[id]; ok {
+		if prev != fingerprint {
+			return querier.StartResult{}, querier.ErrIdempotencyConflict
+		}
+		return querier.StartResult{WorkflowID: id, RunID: "run-1", Existing: true}, nil
+	}
+	q.started[id] = fingerprint
+	return querier.StartResult{WorkflowID: id, RunID: "run-1"}, nil
+}
+
+func (q *startQuerier) StartAnomaly(_ context.Context, id, fingerprint string, in workflows.WorkflowInput) (querier.StartResult, error) {
+	q.anomalies = append(q.anomalies, in)
+	return q.start(id, fingerprint)
+}
+
+func (q *startQuerier) StartSweep(_ context.Context, id, fingerprint string, in workflows.SweepInput) (querier.StartResult, error) {
+	q.sweeps = append(q.sweeps, in)
+	return q.start(id, fingerprint)
+}
+
+func newStartServer(t *testing.T) (*Server, *startQuerier) {
+	t.Helper()
+	q := &startQuerier{tenantQuerier: tenantQuerier{tenant: "acme"}, started: map[string]string{}}
+	srv, err := New(q, nil, OIDCConfig{})
+	require.NoError(t, err)
+	return srv, q
+}
+
+const anomalyBody = `{"anomaly":{"service":"EC2","account_id":"123456789012","delta_dollars":420},` +
+	`"window_start":"2026-03-01","window_end":"2026-03-15"}`
+
+func trigger(t *testing.T, srv *Server, c caller, path, body, idemKey string) (int, querier.StartResult) {
+	t.Helper()
+	rec := c.doCtx(t, context.Background(), &idemServer{srv, idemKey}, http.MethodPost, path, body)
+	var res querier.StartResult
+	if rec.Code < 300 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	}
+	return rec.Code, res
+}
+
+func TestTriggerAnomaly(t *testing.T) {
+	srv, q := newStartServer(t)
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+
+	code, first := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "")
+	require.Equal(t, http.StatusCreated, code)
+	assert.True(t, strings.HasPrefix(first.WorkflowID, "finops-anomaly-acme-"), first.WorkflowID)
+	require.Len(t, q.anomalies, 1)
+	in := q.anomalies[0]
+	assert.Equal(t, "acme", in.Tenant.TenantID)
+	assert.NotEmpty(t, in.Anomaly.AnomalyID)
+	assert.Equal(t, "2026-03-01", in.WindowStart)
+
+	// A retry of the same request is the same workflow.
+	code, again := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, first.WorkflowID, again.WorkflowID)
+	assert.True(t, again.Existing)
+
+	// An idempotency key names the workflow; reusing it for another
+	// request conflicts.
+	code, keyed := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "deploy-42")
+	assert.Equal(t, http.StatusCreated, code)
+	assert.NotEqual(t, first.WorkflowID, keyed.WorkflowID)
+	other := strings.Replace(anomalyBody, "420", "421", 1)
+	code, _ = trigger(t, srv, analyst, "/api/v1/anomalies", other, "deploy-42")
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+func TestTriggerAnomaly_Rejected(t *testing.T) {
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+	tests := []struct {
+		name       string
+		c          caller
+		body       string
+		wantStatus int
+	}{
+		{name: "viewer", c: caller{tenant: "acme", roles: []rbac.Role{rbac.Viewer}}, body: anomalyBody, wantStatus: http.StatusForbidden},
+		{name: "other tenant", c: analyst, body: strings.Replace(anomalyBody, `{"anomaly"`, `{"tenant":"globex","anomaly"`, 1), wantStatus: http.StatusNotFound},
+		{name: "admin without tenant", c: caller{admin: true}, body: anomalyBody, wantStatus: http.StatusBadRequest},
+		{name: "bad account", c: analyst, body: strings.Replace(anomalyBody, "123456789012", "1234", 1), wantStatus: http.StatusBadRequest},
+		{name: "no service", c: analyst, body: strings.Replace(anomalyBody, `"EC2"`, `""`, 1), wantStatus: http.StatusBadRequest},
+		{name: "no delta", c: analyst, body: strings.Replace(anomalyBody, "420", "0", 1), wantStatus: http.StatusBadRequest},
+		{name: "window reversed", c: analyst, body: strings.Replace(anomalyBody, "2026-03-15", "2026-02-15", 1), wantStatus: http.StatusBadRequest},
+		{name: "bad json", c: analyst, body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, q := newStartServer(t)
+			code, _ := trigger(t, srv, tt.c, "/api/v1/anomalies", tt.body, "")
+			assert.Equal(t, tt.wantStatus, code)
+			assert.Empty(t, q.anomalies)
+		})
+	}
+}
+
+func TestTrigger_TenantScheduling(t *testing.T) {
+	srv, q := newStartServer(t)
+	srv.SetTenantScheduling([]string{"acme"}, map[string]float64{"acme": 3})
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+
+	code, _ := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "")
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, q.anomalies, 1)
+	assert.True(t, q.anomalies[0].Tenant.Premium)
+	assert.Equal(t, 3.0, q.anomalies[0].Tenant.Weight)
+
+	code, _ = trigger(t, srv, analyst, "/api/v1/sweeps", `{"accounts":[{"account_id":"123456789012","region":"us-east-1"}]}`, "")
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, q.sweeps, 1)
+	assert.True(t, q.sweeps[0].Premium)
+	assert.Equal(t, 3.0, q.sweeps[0].Weight)
+}
+
+// pollingQuerier is a startQuerier that reports which task queues workers
+// poll.
+type pollingQuerier struct {
+	*startQuerier
+	polled map[string]bool
+	checks int
+}
+
+func (q *pollingQuerier) TaskQueuePolled(_ context.Context, queue string) (bool, error) {
+	q.checks++
+	return q.polled[queue], nil
+}
+
+func TestTrigger_PremiumQueuesNotPolled(t *testing.T) {
+	_, sq := newStartServer(t)
+	q := &pollingQuerier{startQuerier: sq, polled: map[string]bool{"finops-anomaly-acme": true}}
+	srv, err := New(q, nil, OIDCConfig{})
+	require.NoError(t, err)
+	srv.SetTenantScheduling([]string{"acme"}, nil)
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+
+	code, _ := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "")
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, sq.anomalies, 1)
+	assert.False(t, sq.anomalies[0].Tenant.Premium, "no worker polls finops-exec-acme")
+
+	q.polled["finops-exec-acme"] = true
+	srv.polled["acme"] = queueCheck{at: time.Now().Add(-premiumMissTTL)}
+	code, _ = trigger(t, srv, analyst, "/api/v1/sweeps", `{"accounts":[{"account_id":"123456789012","region":"us-east-1"}]}`, "")
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, sq.sweeps, 1)
+	assert.True(t, sq.sweeps[0].Premium)
+
+	checks := q.checks
+	code, _ = trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "other-key")
+	require.Equal(t, http.StatusCreated, code)
+	assert.True(t, sq.anomalies[1].Tenant.Premium)
+	assert.Equal(t, checks, q.checks, "polled queues are not checked again within the TTL")
+}
+
+func TestTrigger_PremiumQueuesMissCached(t *testing.T) {
+	_, sq := newStartServer(t)
+	q := &pollingQuerier{startQuerier: sq, polled: map[string]bool{}}
+	srv, err := New(q, nil, OIDCConfig{})
+	require.NoError(t, err)
+	srv.SetTenantScheduling([]string{"acme"}, nil)
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+
+	for _, key := range []string{"k-1", "k-2"} {
+		code, _ := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, key)
+		require.Equal(t, http.StatusCreated, code)
+	}
+	assert.Equal(t, 1, q.checks, "an unpolled queue is not checked again within the miss TTL")
+
+	q.polled["finops-anomaly-acme"], q.polled["finops-exec-acme"] = true, true
+	srv.polled["acme"] = queueCheck{at: time.Now().Add(-premiumMissTTL)}
+	code, _ := trigger(t, srv, analyst, "/api/v1/anomalies", anomalyBody, "k-3")
+	require.Equal(t, http.StatusCreated, code)
+	require.Len(t, sq.anomalies, 3)
+	assert.False(t, sq.anomalies[1].Tenant.Premium)
+	assert.True(t, sq.anomalies[2].Tenant.Premium, "rechecked once the miss expires")
+}
+
+func TestTriggerSweep(t *testing.T) {
+	srv, q := newStartServer(t)
+	analyst := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}
+
+	code, res := trigger(t, srv, analyst, "/api/v1/sweeps",
+		`{"accounts":[{"account_id":"123456789012","region":"us-east-1"}]}`, "nightly-2026-03-01")
+	require.Equal(t, http.StatusCreated, code)
+	assert.True(t, strings.HasPrefix(res.WorkflowID, "finops-sweep-acme-"), res.WorkflowID)
+	require.Len(t, q.sweeps, 1)
+	assert.Equal(t, "acme", q.sweeps[0].Tenant)
+	assert.Equal(t, []workflows.SweepAccount{{AccountID: "123456789012", Region: "us-east-1"}}, q.sweeps[0].Accounts)
+
+	for name, body := range map[string]string{
+		"no accounts": `{"accounts":[]}`,
+		"bad region":  `{"accounts":[{"account_id":"123456789012","region":"mars"}]}`,
+	} {
+		code, _ := trigger(t, srv, analyst, "/api/v1/sweeps", body, "")
+		assert.Equal(t, http.StatusBadRequest, code, name)
+	}
+}
+
+func TestTrigger_NotSupported(t *testing.T) {
+	rec := caller{tenant: "acme", roles: []rbac.Role{rbac.Analyst}}.do(t, newTenancyServer(t, &tenantQuerier{tenant: "acme"}),
+		http.MethodPost, "/api/v1/anomalies", anomalyBody)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
+
+// idemServer sets the Idempotency-Key header on requests to srv.
+type idemServer struct {
+	srv *Server
+	key string
+}
+
+func (s *idemServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.key != "" {
+		r.Header.Set("Idempotency-Key", s.key)
+	}
+	s.srv.ServeHTTP(w, r)
+}
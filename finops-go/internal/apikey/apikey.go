@@ -0,0 +1,194 @@
+// Package apikey issues and checks API keys for callers that cannot run an
+// interactive OIDC flow, such as CI jobs and bots. A key is scoped to a
+// tenant and a set of roles, may expire, and is stored only as a hash.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+// Prefix starts every key, so keys are recognisable in headers and in
+// secret scanners.
+const Prefix = "fok_"
+
+// TouchInterval is how stale LastUsedAt may get before a use is written
+// back, bounding the writes a busy key causes.
+const TouchInterval = time.Minute
+
+var (
+	// ErrInvalid is returned for a malformed or unknown key, or a wrong
+	// secret. Callers should not tell these apart to the client.
+	ErrInvalid = apperr.NewSentinel(apperr.Unauthenticated, "apikey: invalid key")
+	ErrExpired = apperr.NewSentinel(apperr.Unauthenticated, "apikey: key expired")
+	ErrRevoked = apperr.NewSentinel(apperr.Unauthenticated, "apikey: key revoked")
+	// ErrNotFound is returned by Store lookups for an unknown ID.
+	ErrNotFound = apperr.NewSentinel(apperr.NotFound, "apikey: key not found")
+)
+
+// Key is an issued key's record. The secret itself is never stored; Hash
+// is the SHA-256 of the full key string.
+type Key struct {
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	TenantID string      `json:"tenant_id,omitempty"`
+	Roles    []rbac.Role `json:"roles"`
+	// Admin keys hold every permission in every tenant.
+	Admin bool   `json:"admin,omitempty"`
+	Hash  string `json:"hash,omitempty"`
+
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+	RevokedAt  time.Time `json:"revoked_at,omitzero"`
+}
+
+// Subject is the principal subject a key authenticates as.
+func (k Key) Subject() string {
+	return "apikey:" + k.ID
+}
+
+// Principal is the caller a key authenticates as.
+func (k Key) Principal() rbac.Principal {
+	return rbac.Principal{
+		Subject:  k.Subject(),
+		TenantID: k.TenantID,
+		Roles:    k.Roles,
+		Admin:    k.Admin,
+	}
+}
+
+// Active reports whether the key may be used at now.
+func (k Key) Active(now time.Time) bool {
+	return k.RevokedAt.IsZero() && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
+
+// Redacted returns the key without its hash, for listing.
+func (k Key) Redacted() Key {
+	k.Hash = ""
+	return k
+}
+
+// Store persists keys.
+type Store interface {
+	// Create adds a new key; its ID must be unused.
+	Create(k Key) error
+	// Get returns the key with id, or ErrNotFound.
+	Get(id string) (Key, error)
+	// List returns the keys of tenant, or every key for "", oldest first.
+	List(tenant string) ([]Key, error)
+	// Revoke marks the key revoked at at. Revoking twice keeps the first
+	// time.
+	Revoke(id string, at time.Time) error
+	// Touch records a use of the key at at. Stores may skip writes within
+	// TouchInterval of the last recorded use.
+	Touch(id string, at time.Time) error
+}
+
+// Issue creates a key from spec, which supplies the name, scope, expiry
+// and creator, and returns the key string to hand to the caller. It is
+// shown once: only its hash is kept.
+func Issue(s Store, spec Key, now time.Time) (string, Key, error) {
+	if spec.Name == "" {
+		return "", Key{}, errors.New("apikey: name is required")
+	}
+	if !spec.Admin && spec.TenantID == "" {
+		return "", Key{}, errors.New("apikey: tenant is required for non-admin keys")
+	}
+	if len(spec.Roles) == 0 {
+		return "", Key{}, errors.New("apikey: at least one role is required")
+	}
+	for _, r := range spec.Roles {
+		if _, ok := rbac.Matrix[r]; !ok {
+			return "", Key{}, fmt.Errorf("apikey: unknown role %q", r)
+		}
+	}
+
+	id, err := randomString(9)
+	if err != nil {
+		return "", Key{}, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", Key{}, err
+	}
+	token := Prefix + id + "_" + secret
+
+	k := spec
+	k.ID = id
+	k.Hash = hash(token)
+	k.CreatedAt = now.UTC()
+	k.LastUsedAt, k.RevokedAt = time.Time{}, time.Time{}
+	if err := s.Create(k); err != nil {
+		return "", Key{}, err
+	}
+	return token, k, nil
+}
+
+// Authenticate checks a key string and returns its record. Uses are
+// recorded with Touch; a failure to record one is not an error.
+func Authenticate(s Store, token string, now time.Time) (Key, error) {
+	id, ok := parseID(token)
+	if !ok {
+		return Key{}, ErrInvalid
+	}
+	k, err := s.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return Key{}, ErrInvalid
+	}
+	if err != nil {
+		return Key{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash(token)), []byte(k.Hash)) != 1 {
+		return Key{}, ErrInvalid
+	}
+	if !k.RevokedAt.IsZero() {
+		return Key{}, ErrRevoked
+	}
+	if !k.Active(now) {
+		return Key{}, ErrExpired
+	}
+	_ = s.Touch(id, now)
+	return k, nil
+}
+
+// IsKey reports whether a credential looks like an API key rather than,
+// say, a JWT.
+func IsKey(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+func parseID(token string) (string, bool) {
+	rest, ok := strings.CutPrefix(token, Prefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	return id, ok && id != "" && secret != ""
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomString returns n random bytes as unpadded base64url without '_',
+// so it cannot be confused with the key's separator.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("apikey: random: %w", err)
+	}
+	return strings.ReplaceAll(base64.RawURLEncoding.EncodeToString(b), "_", "-"), nil
+}
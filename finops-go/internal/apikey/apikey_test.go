@@ -0,0 +1,137 @@
+package apikey
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+func openStore(t *testing.T) *FileStore {
+	t.Helper()
+	s, err := OpenFileStore(filepath.Join(t.TempDir(), "keys.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestIssueAndAuthenticate(t *testing.T) {
+	s := openStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	token, k, err := Issue(s, Key{
+		Name: "ci", TenantID: "acme", Roles: []rbac.Role{rbac.Analyst},
+		CreatedBy: "alice", ExpiresAt: now.Add(24 * time.Hour),
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsKey(token) || strings.Contains(k.Hash, token) || k.Hash == "" {
+		t.Fatalf("token %q, hash %q", token, k.Hash)
+	}
+
+	got, err := Authenticate(s, token, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != k.ID || got.TenantID != "acme" {
+		t.Errorf("Authenticate = %+v", got)
+	}
+	p := got.Principal()
+	if p.Subject != "apikey:"+k.ID || !p.Can(rbac.PermTrigger) || p.Can(rbac.PermApprove) {
+		t.Errorf("Principal = %+v", p)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+		want  error
+	}{
+		{name: "wrong secret", token: token[:len(token)-2] + "xx", at: now, want: ErrInvalid},
+		{name: "unknown id", token: Prefix + "nope_secret", at: now, want: ErrInvalid},
+		{name: "not a key", token: "eyJhbGciOi", at: now, want: ErrInvalid},
+		{name: "expired", token: token, at: now.Add(25 * time.Hour), want: ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Authenticate(s, tt.token, tt.at); !errors.Is(err, tt.want) {
+				t.Errorf("Authenticate err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := s.Revoke(k.ID, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Authenticate(s, token, now.Add(3*time.Hour)); !errors.Is(err, ErrRevoked) {
+		t.Errorf("after revoke err = %v, want ErrRevoked", err)
+	}
+}
+
+func TestIssue_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Key
+	}{
+		{name: "no name", spec: Key{TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}}},
+		{name: "no tenant", spec: Key{Name: "ci", Roles: []rbac.Role{rbac.Viewer}}},
+		{name: "no roles", spec: Key{Name: "ci", TenantID: "acme"}},
+		{name: "unknown role", spec: Key{Name: "ci", TenantID: "acme", Roles: []rbac.Role{"root"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Issue(openStore(t), tt.spec, time.Now()); err == nil {
+				t.Error("Issue succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestFileStore_TouchAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.jsonl")
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	token, k, err := Issue(s, Key{Name: "bot", TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Issue(s, Key{Name: "other", TenantID: "globex", Roles: []rbac.Role{rbac.Viewer}}, now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, at := range []time.Time{now, now.Add(10 * time.Second), now.Add(2 * time.Minute)} {
+		if _, err := Authenticate(s, token, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Another process sees the latest version of each key.
+	other, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := other.Get(k.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(2 * time.Minute); !got.LastUsedAt.Equal(want) {
+		t.Errorf("LastUsedAt = %v, want %v (uses within TouchInterval are not written)", got.LastUsedAt, want)
+	}
+	if keys, err := other.List("acme"); err != nil || len(keys) != 1 || keys[0].ID != k.ID {
+		t.Errorf("List(acme) = %v, %v", keys, err)
+	}
+	if keys, _ := other.List(""); len(keys) != 2 {
+		t.Errorf("List() = %d keys, want 2", len(keys))
+	}
+	if _, err := other.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,204 @@
+package apikey
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileStore is a Store backed by an append-only JSON-lines file, shared by
+// the API server and the CLI. Each change appends the key's new version
+// and the latest line for an ID wins. The parsed file is cached until it
+// changes on disk, so authenticating a request does not re-read it.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex
+	keys    map[string]Key
+	size    int64
+	modTime time.Time
+}
+
+// OpenFileStore opens (creating if needed) the key file at path. The file
+// holds only hashes, but is still created owner-only.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("apikey: create key dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("apikey: open key file %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileStore{path: path}, nil
+}
+
+// Create implements Store.
+func (s *FileStore) Create(k Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := keys[k.ID]; ok {
+		return fmt.Errorf("apikey: key %s already exists", k.ID)
+	}
+	return s.append(k)
+}
+
+// Get implements Store.
+func (s *FileStore) Get(id string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, err := s.load()
+	if err != nil {
+		return Key{}, err
+	}
+	k, ok := keys[id]
+	if !ok {
+		return Key{}, ErrNotFound
+	}
+	return k, nil
+}
+
+// List implements Store.
+func (s *FileStore) List(tenant string) ([]Key, error) {
+	s.mu.Lock()
+	keys, err := s.load()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Key, 0, len(keys))
+	for _, k := range keys {
+		if tenant == "" || k.TenantID == tenant {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// Revoke implements Store.
+func (s *FileStore) Revoke(id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	k, ok := keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if !k.RevokedAt.IsZero() {
+		return nil
+	}
+	k.RevokedAt = at.UTC()
+	return s.append(k)
+}
+
+// Touch implements Store. Uses within TouchInterval of the recorded one
+// are not written.
+func (s *FileStore) Touch(id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, err := s.load()
+	if err != nil {
+		return err
+	}
+	k, ok := keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if !k.LastUsedAt.IsZero() && at.Sub(k.LastUsedAt) < TouchInterval {
+		return nil
+	}
+	k.LastUsedAt = at.UTC()
+	return s.append(k)
+}
+
+// append writes k's new version and updates the cache. Callers hold mu
+// and have loaded the file.
+func (s *FileStore) append(k Key) error {
+	line, err := json.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("apikey: encode key %s: %w", k.ID, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("apikey: open key file %s: %w", s.path, err)
+	}
+	// One write per line keeps appends from another process (the CLI)
+	// from interleaving.
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("apikey: append key %s: %w", k.ID, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("apikey: close key file %s: %w", s.path, err)
+	}
+	s.keys[k.ID] = k
+	return nil
+}
+
+// load returns the latest version of each key, replaying the file only
+// when its size or modification time changed. Lines that do not decode
+// are skipped. Callers hold mu.
+func (s *FileStore) load() (map[string]Key, error) {
+	st, err := os.Stat(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.keys, s.size, s.modTime = map[string]Key{}, 0, time.Time{}
+		return s.keys, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("apikey: stat key file %s: %w", s.path, err)
+	}
+	if s.keys != nil && st.Size() == s.size && st.ModTime().Equal(s.modTime) {
+		return s.keys, nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("apikey: open key file %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	keys := make(map[string]Key)
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var k Key
+		if err := json.Unmarshal(sc.Bytes(), &k); err != nil {
+			slog.Warn("apikey: skipping corrupt key line", "path", s.path, "line", n, "error", err)
+			continue
+		}
+		keys[k.ID] = k
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("apikey: read key file %s: %w", s.path, err)
+	}
+	s.keys, s.size, s.modTime = keys, st.Size(), st.ModTime()
+	return s.keys, nil
+}
@@ -0,0 +1,233 @@
+// Package apperr is the error model shared by the HTTP API, the MCP server
+// and the CLI: a small set of stable error codes, the HTTP status of each,
+// and the classification of errors. Other packages declare their sentinel
+// errors with NewSentinel, so apperr depends on none of them.
+package apperr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Code is a stable, machine-readable error code. Clients may switch on it;
+// codes are never renamed.
+type Code string
+
+const (
+	// InvalidArgument: the request is malformed.
+	InvalidArgument Code = "invalid_argument"
+	// Unauthenticated: no valid credentials.
+	Unauthenticated Code = "unauthenticated"
+	// Forbidden: the caller may not do this.
+	Forbidden Code = "forbidden"
+	// NotFound: the resource does not exist, or is not the caller's.
+	NotFound Code = "not_found"
+	// Conflict: the request clashes with an earlier one.
+	Conflict Code = "conflict"
+	// InvalidState: the resource is not in a state that allows this, e.g.
+	// approving a workflow that is not at its approval gate.
+	InvalidState Code = "invalid_state"
+	// BudgetExceeded: the tenant has used up its budget; retry later.
+	BudgetExceeded Code = "budget_exceeded"
+	// Unimplemented: the server does not support this.
+	Unimplemented Code = "unimplemented"
+	// Unavailable: the feature is not configured on this server.
+	Unavailable Code = "unavailable"
+	// UpstreamUnavailable: Temporal or a cloud API did not answer.
+	UpstreamUnavailable Code = "upstream_unavailable"
+	// Internal: anything else.
+	Internal Code = "internal"
+)
+
+var statuses = map[Code]int{
+	InvalidArgument:     http.StatusBadRequest,
+	Unauthenticated:     http.StatusUnauthorized,
+	Forbidden:           http.StatusForbidden,
+	NotFound:            http.StatusNotFound,
+	Conflict:            http.StatusConflict,
+	InvalidState:        http.StatusConflict,
+	BudgetExceeded:      http.StatusTooManyRequests,
+	Unimplemented:       http.StatusNotImplemented,
+	Unavailable:         http.StatusServiceUnavailable,
+	UpstreamUnavailable: http.StatusServiceUnavailable,
+	Internal:            http.StatusInternalServerError,
+}
+
+// Codes lists every code.
+func Codes() []Code {
+	return []Code{
+		InvalidArgument, Unauthenticated, Forbidden, NotFound, Conflict, InvalidState,
+		BudgetExceeded, Unimplemented, Unavailable, UpstreamUnavailable, Internal,
+	}
+}
+
+// HTTPStatus is the HTTP status code the API answers with.
+func (c Code) HTTPStatus() int {
+	if s, ok := statuses[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is an error with a code. Message is shown to callers.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+// New returns an error with a code and a caller-facing message.
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, Message: msg}
+}
+
+// Wrap attaches a code and a caller-facing message to err.
+func Wrap(code Code, msg string, err error) *Error {
+	return &Error{Code: code, Message: msg, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Coded is implemented by errors that carry their own code, such as the
+// sentinels other packages declare with NewSentinel.
+type Coded interface {
+	error
+	ErrorCode() Code
+}
+
+type sentinel struct {
+	code Code
+	msg  string
+}
+
+func (s *sentinel) Error() string   { return s.msg }
+func (s *sentinel) ErrorCode() Code { return s.code }
+
+// NewSentinel returns a sentinel error with the text msg, classified as
+// code. Compare it with errors.Is, as any sentinel.
+func NewSentinel(code Code, msg string) error {
+	return &sentinel{code: code, msg: msg}
+}
+
+// TypeBudgetExceeded is the Temporal application error type of activities
+// refused by a tenant budget, so the refusal keeps its code once Temporal
+// carries it back from a workflow.
+const TypeBudgetExceeded = "BudgetExceeded"
+
+// CodeOf classifies err: an *Error's own code, a Coded error's code, a
+// Temporal service or application error, or Internal. nil has no code.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	if code, ok := sentinelCode(err); ok {
+		return code
+	}
+	if code, ok := temporalCode(err); ok {
+		return code
+	}
+	return Internal
+}
+
+// sentinelCode classifies Coded errors, and context deadlines as upstream
+// timeouts.
+func sentinelCode(err error) (Code, bool) {
+	var c Coded
+	if errors.As(err, &c) {
+		return c.ErrorCode(), true
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return UpstreamUnavailable, true
+	}
+	return "", false
+}
+
+// temporalCode classifies errors from the Temporal frontend and failures
+// carried back from workflows and activities.
+func temporalCode(err error) (Code, bool) {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) && appErr.Type() == TypeBudgetExceeded {
+		return BudgetExceeded, true
+	}
+	var (
+		notFound       *serviceerror.NotFound
+		nsNotFound     *serviceerror.NamespaceNotFound
+		alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+		alreadyExists  *serviceerror.AlreadyExists
+		precondition   *serviceerror.FailedPrecondition
+		notReady       *serviceerror.WorkflowNotReady
+		queryFailed    *serviceerror.QueryFailed
+		denied         *serviceerror.PermissionDenied
+		invalid        *serviceerror.InvalidArgument
+		unavailable    *serviceerror.Unavailable
+		deadline       *serviceerror.DeadlineExceeded
+		exhausted      *serviceerror.ResourceExhausted
+	)
+	switch {
+	case errors.As(err, &nsNotFound):
+		// The server's own namespace is missing: a misconfiguration, not
+		// something the caller asked for.
+		return UpstreamUnavailable, true
+	case errors.As(err, &notFound):
+		return NotFound, true
+	case errors.As(err, &alreadyStarted), errors.As(err, &alreadyExists):
+		return Conflict, true
+	case errors.As(err, &precondition), errors.As(err, &notReady), errors.As(err, &queryFailed):
+		return InvalidState, true
+	case errors.As(err, &denied):
+		return Forbidden, true
+	case errors.As(err, &invalid):
+		return InvalidArgument, true
+	case errors.As(err, &unavailable), errors.As(err, &deadline), errors.As(err, &exhausted):
+		return UpstreamUnavailable, true
+	}
+	return "", false
+}
+
+// genericMessages are shown for errors whose own message may carry
+// internal detail.
+var genericMessages = map[Code]string{
+	InvalidArgument:     "invalid argument",
+	Unauthenticated:     "unauthenticated",
+	Forbidden:           "permission denied",
+	NotFound:            "not found",
+	Conflict:            "conflict",
+	InvalidState:        "the resource is not in a state that allows this",
+	BudgetExceeded:      "budget exceeded",
+	Unimplemented:       "not implemented",
+	Unavailable:         "unavailable",
+	UpstreamUnavailable: "upstream service unavailable",
+	Internal:            "internal error",
+}
+
+// Public returns err's code and a message safe to show callers. *Error
+// messages are written for callers and shown as they are, as are caller
+// errors (4xx) classified by a Coded error, which explain what to fix. Everything else, including errors Temporal returned about the
+// server's own namespace or credentials, gets a generic message for its
+// code. Log the full error instead.
+func Public(err error) (Code, string) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, e.Message
+	}
+	code := CodeOf(err)
+	if sc, ok := sentinelCode(err); ok && sc.HTTPStatus() < http.StatusInternalServerError {
+		return code, err.Error()
+	}
+	return code, genericMessages[code]
+}
@@ -0,0 +1,93 @@
+package apperr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want apperr.Code
+	}{
+		{"nil", nil, ""},
+		{"coded", apperr.New(apperr.NotFound, "tenant not found"), apperr.NotFound},
+		{"wrapped coded", fmt.Errorf("load: %w", apperr.New(apperr.Conflict, "taken")), apperr.Conflict},
+		{"sentinel", fmt.Errorf("load: %w", apperr.NewSentinel(apperr.NotFound, "pkg: missing")), apperr.NotFound},
+		{"already decided", fmt.Errorf("get approval result: %w", querier.ErrAlreadyDecided), apperr.Conflict},
+		{"not running", fmt.Errorf("workflow wf-1: %w", querier.ErrNotRunning), apperr.InvalidState},
+		{"idempotency", querier.ErrIdempotencyConflict, apperr.Conflict},
+		{"budget", fmt.Errorf("tenant acme: %w", ratelimit.ErrBudgetExceeded), apperr.BudgetExceeded},
+		{"budget from activity", temporal.NewApplicationError("exceeded", activities.ErrTypeBudgetExceeded), apperr.BudgetExceeded},
+		{"denied", fmt.Errorf("%w: approve", rbac.ErrDenied), apperr.Forbidden},
+		{"deadline", context.DeadlineExceeded, apperr.UpstreamUnavailable},
+		{"temporal not found", fmt.Errorf("describe workflow: %w", serviceerror.NewNotFound("gone")), apperr.NotFound},
+		{"temporal namespace not found", serviceerror.NewNamespaceNotFound("finops"), apperr.UpstreamUnavailable},
+		{"temporal unavailable", serviceerror.NewUnavailable("refused"), apperr.UpstreamUnavailable},
+		{"temporal exhausted", serviceerror.NewResourceExhausted(0, "rps"), apperr.UpstreamUnavailable},
+		{"temporal precondition", serviceerror.NewFailedPrecondition("closed"), apperr.InvalidState},
+		{"temporal already started", serviceerror.NewWorkflowExecutionAlreadyStarted("started", "", "run"), apperr.Conflict},
+		{"other application error", temporal.NewApplicationError("boom", "Other"), apperr.Internal},
+		{"plain", errors.New("boom"), apperr.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apperr.CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode apperr.Code
+		wantMsg  string
+	}{
+		{"coded", apperr.Wrap(apperr.Internal, "API key lookup failed", errors.New("disk full")), apperr.Internal, "API key lookup failed"},
+		{"caller error passes through", fmt.Errorf("submit: %w", querier.ErrAlreadyDecided), apperr.Conflict, "submit: querier: approval already decided"},
+		{"upstream hidden", serviceerror.NewUnavailable("dial tcp 10.0.0.7:7233"), apperr.UpstreamUnavailable, "upstream service unavailable"},
+		{"temporal caller error hidden", serviceerror.NewPermissionDenied("role finops-api may not SignalWorkflow in namespace prod", ""), apperr.Forbidden, "permission denied"},
+		{"temporal invalid argument hidden", serviceerror.NewInvalidArgument("mTLS cert for api.internal expired"), apperr.InvalidArgument, "invalid argument"},
+		{"temporal not found hidden", serviceerror.NewNotFound("workflow wf-1 not found in shard 12"), apperr.NotFound, "not found"},
+		{"deadline hidden", fmt.Errorf("query 10.0.0.7: %w", context.DeadlineExceeded), apperr.UpstreamUnavailable, "upstream service unavailable"},
+		{"internal hidden", errors.New("open /var/lib/finops: permission denied"), apperr.Internal, "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := apperr.Public(tt.err)
+			if code != tt.wantCode || msg != tt.wantMsg {
+				t.Errorf("Public() = %q, %q, want %q, %q", code, msg, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCodes_HaveStatuses(t *testing.T) {
+	for _, c := range apperr.Codes() {
+		if c != apperr.Internal && c.HTTPStatus() == http.StatusInternalServerError {
+			t.Errorf("code %s has no HTTP status", c)
+		}
+	}
+	if got := apperr.Code("unknown").HTTPStatus(); got != http.StatusInternalServerError {
+		t.Errorf("unknown code status = %d", got)
+	}
+	if got := apperr.BudgetExceeded.HTTPStatus(); got != http.StatusTooManyRequests {
+		t.Errorf("budget_exceeded status = %d", got)
+	}
+}
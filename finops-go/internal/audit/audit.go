@@ -0,0 +1,100 @@
+// Package audit is an append-only, tamper-evident trail of human and system
+// decisions: who triggered, approved, denied, edited, rolled back or muted
+// what, when, from where and why, plus every automated policy decision and
+// executor action.
+//
+// Events are chained: each carries the SHA-256 of the previous event, and
+// its own hash covers that link. Editing, inserting, reordering or deleting
+// an event breaks every later hash, which Verify detects.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// Actions recorded in the trail.
+const (
+	ActionTriggered        = "triggered"
+	ActionApproved         = "approved"
+	ActionDenied           = "denied"
+	ActionEdited           = "edited"
+	ActionRolledBack       = "rolled_back"
+	ActionMuted            = "muted"
+	ActionPolicyDecision   = "policy_decision"
+	ActionApprovalTimeout  = "approval_timed_out"
+	ActionExecuted         = "executed"
+	ActionPermissionDenied = "permission_denied"
+	ActionKeyCreated       = "api_key_created"
+	ActionKeyRevoked       = "api_key_revoked"
+)
+
+// Sources that write events.
+const (
+	SourceAPI      = "api"
+	SourceMCP      = "mcp"
+	SourceWorkflow = "workflow"
+	SourceActivity = "activity"
+)
+
+// SystemActor is the actor of automated decisions.
+const SystemActor = "system"
+
+// Event is one audited decision. Seq, PrevHash and Hash are assigned by
+// the log on append.
+type Event struct {
+	Seq  int64  `json:"seq"`
+	Time string `json:"time"` // RFC3339Nano, UTC
+
+	Actor    string `json:"actor"`            // authenticated subject, or SystemActor
+	Email    string `json:"email,omitempty"`  // verified email, when known
+	Issuer   string `json:"issuer,omitempty"` // token issuer, when known
+	Source   string `json:"source"`
+	IP       string `json:"ip,omitempty"`
+	TokenID  string `json:"token_id,omitempty"` // jti or API key ID
+	TenantID string `json:"tenant_id,omitempty"`
+
+	Action     string            `json:"action"`
+	WorkflowID string            `json:"workflow_id,omitempty"`
+	Resource   string            `json:"resource,omitempty"`
+	Outcome    string            `json:"outcome,omitempty"`
+	Reason     string            `json:"reason,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// computeHash returns the hex SHA-256 of the event's JSON encoding with
+// Hash empty. PrevHash is inside the encoding, which links the chain.
+func (e Event) computeHash() (string, error) {
+	e.Hash = ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("audit: encode event %d: %w", e.Seq, err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Logger appends events to the trail.
+type Logger interface {
+	// Append chains e after the last event and stores it, returning the
+	// stored event.
+	Append(e Event) (Event, error)
+}
+
+// Record appends e to l, logging rather than returning a failure so that
+// callers on a request path can audit best effort. A nil l does nothing.
+func Record(ctx context.Context, l Logger, e Event) {
+	if l == nil {
+		return
+	}
+	if _, err := l.Append(e); err != nil {
+		slog.ErrorContext(ctx, "audit: append failed", "action", e.Action, "actor", e.Actor, "error", err)
+	}
+}
@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func openTestLog(t *testing.T) *FileLog {
+	t.Helper()
+	l, err := OpenFileLog(filepath.Join(t.TempDir(), "audit", "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func appendN(t *testing.T, l Logger, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := l.Append(Event{
+			Actor:      fmt.Sprintf("user-%d", i%2),
+			Source:     SourceAPI,
+			TenantID:   "acme",
+			Action:     ActionApproved,
+			WorkflowID: fmt.Sprintf("wf-%d", i),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAppendChains(t *testing.T) {
+	l := openTestLog(t)
+
+	first, err := l.Append(Event{Actor: "alice", Source: SourceAPI, Action: ActionApproved})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := l.Append(Event{Actor: SystemActor, Source: SourceWorkflow, Action: ActionPolicyDecision})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Errorf("seqs = %d, %d; want 1, 2", first.Seq, second.Seq)
+	}
+	if first.PrevHash != "" || second.PrevHash != first.Hash {
+		t.Errorf("second.PrevHash = %q, want %q", second.PrevHash, first.Hash)
+	}
+	if first.Time == "" {
+		t.Error("time not stamped")
+	}
+
+	rep, err := Verify(l.Path())
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if rep.Events != 2 || rep.Head != second.Hash {
+		t.Errorf("report = %+v", rep)
+	}
+}
+
+func TestAppendAcrossWriters(t *testing.T) {
+	// Two FileLogs on one file stand in for the worker and API processes.
+	a := openTestLog(t)
+	b, err := OpenFileLog(a.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, l := range []*FileLog{a, b} {
+		wg.Add(1)
+		go func(l *FileLog) {
+			defer wg.Done()
+			appendN(t, l, 20)
+		}(l)
+	}
+	wg.Wait()
+
+	rep, err := Verify(a.Path())
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if rep.Events != 40 {
+		t.Errorf("events = %d, want 40", rep.Events)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		want   string
+	}{
+		{
+			name: "edited field",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"actor":"user-1"`, `"actor":"mallory"`, 1)
+				return lines
+			},
+			want: "line 2: seq 2 hash mismatch",
+		},
+		{
+			name:   "deleted event",
+			tamper: func(lines []string) []string { return append(lines[:1], lines[2:]...) },
+			want:   "line 2: seq 3 follows 1",
+		},
+		{
+			name: "reordered events",
+			tamper: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			want: "line 2: seq 3 follows 1",
+		},
+		{
+			name: "truncated line",
+			tamper: func(lines []string) []string {
+				lines[2] = lines[2][:len(lines[2])/2]
+				return lines
+			},
+			want: "line 3: malformed event",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := openTestLog(t)
+			appendN(t, l, 4)
+			raw, err := os.ReadFile(l.Path())
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := tt.tamper(strings.Split(strings.TrimSpace(string(raw)), "\n"))
+			if err := os.WriteFile(l.Path(), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = Verify(l.Path())
+			if !errors.Is(err, ErrTampered) {
+				t.Fatalf("Verify err = %v, want ErrTampered", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Verify err = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendAfterPartialLine(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 1)
+	f, err := os.OpenFile(l.Path(), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"seq":2,"act`)
+	_ = f.Close()
+
+	e, err := l.Append(Event{Actor: "alice", Source: SourceAPI, Action: ActionDenied})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Seq != 2 {
+		t.Errorf("seq = %d, want 2 (chained after the last good event)", e.Seq)
+	}
+	// The crash is still visible to Verify.
+	if _, err := Verify(l.Path()); !errors.Is(err, ErrTampered) {
+		t.Errorf("Verify err = %v, want ErrTampered", err)
+	}
+	events, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Errorf("queried %d events, want 2", len(events))
+	}
+}
+
+func TestQuery(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 5)
+	if _, err := l.Append(Event{Actor: SystemActor, Source: SourceActivity, TenantID: "globex", Action: ActionExecuted}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		wantSeqs []int64
+	}{
+		{name: "all newest first", filter: Filter{}, wantSeqs: []int64{6, 5, 4, 3, 2, 1}},
+		{name: "tenant", filter: Filter{TenantID: "globex"}, wantSeqs: []int64{6}},
+		{name: "actor", filter: Filter{Actor: "user-1"}, wantSeqs: []int64{4, 2}},
+		{name: "action and source", filter: Filter{Action: ActionApproved, Source: SourceAPI}, wantSeqs: []int64{5, 4, 3, 2, 1}},
+		{name: "workflow", filter: Filter{WorkflowID: "wf-3"}, wantSeqs: []int64{4}},
+		{name: "limit", filter: Filter{Limit: 2}, wantSeqs: []int64{6, 5}},
+		{name: "next page", filter: Filter{Limit: 2, AfterSeq: 5}, wantSeqs: []int64{4, 3}},
+		{name: "date bounds", filter: Filter{From: "2000-01-01", To: "2000-12-31"}, wantSeqs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := l.Query(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var seqs []int64
+			for _, e := range events {
+				seqs = append(seqs, e.Seq)
+			}
+			if fmt.Sprint(seqs) != fmt.Sprint(tt.wantSeqs) {
+				t.Errorf("seqs = %v, want %v", seqs, tt.wantSeqs)
+			}
+		})
+	}
+}
+
+func TestOversizedEvent(t *testing.T) {
+	l := openTestLog(t)
+	appendN(t, l, 1)
+	if _, err := l.Append(Event{
+		Actor: "alice", Source: SourceAPI, Action: ActionDenied,
+		Reason: strings.Repeat("x", 2<<20), // beyond any line buffer
+	}); err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, l, 1)
+
+	events, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	rep, err := Verify(l.Path())
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if rep.Events != 3 {
+		t.Errorf("verified %d events, want 3", rep.Events)
+	}
+}
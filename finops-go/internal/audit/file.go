@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileLog is a Logger backed by an append-only JSON-lines file. Several
+// processes (worker, API, MCP server) may append to the same file: each
+// append holds an exclusive file lock while it reads the chain head and
+// writes the next event.
+type FileLog struct {
+	path string
+
+	mu sync.Mutex
+	// head is the last event read from the file, and offset how much of
+	// the file has been read, so appends only scan what others added.
+	head   Event
+	offset int64
+}
+
+// OpenFileLog opens (creating if needed) the audit file at path.
+func OpenFileLog(path string) (*FileLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("audit: create dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileLog{path: path}, nil
+}
+
+// Path returns the file's path.
+func (l *FileLog) Path() string { return l.path }
+
+// Append implements Logger.
+func (l *FileLog) Append(e Event) (Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return Event{}, fmt.Errorf("audit: open %s: %w", l.path, err)
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return Event{}, fmt.Errorf("audit: lock %s: %w", l.path, err)
+	}
+	defer unlockFile(f)
+
+	partial, err := l.advance(f)
+	if err != nil {
+		return Event{}, err
+	}
+
+	if e.Time == "" {
+		e.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	e.Seq = l.head.Seq + 1
+	e.PrevHash = l.head.Hash
+	if e.Hash, err = e.computeHash(); err != nil {
+		return Event{}, err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Event{}, fmt.Errorf("audit: encode event %d: %w", e.Seq, err)
+	}
+	buf := append(line, '\n')
+	if partial {
+		// Close off a line left partial by a crashed writer; Verify will
+		// still report it.
+		buf = append([]byte{'\n'}, buf...)
+	}
+	n, err := f.Write(buf)
+	if err != nil {
+		return Event{}, fmt.Errorf("audit: append event %d: %w", e.Seq, err)
+	}
+	l.head = e
+	l.offset += int64(n)
+	return e, nil
+}
+
+// advance reads events appended since the last call to find the chain
+// head. partial reports a trailing line without a newline. Callers hold mu
+// and the file lock.
+func (l *FileLog) advance(f *os.File) (partial bool, err error) {
+	st, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("audit: stat %s: %w", l.path, err)
+	}
+	if st.Size() < l.offset {
+		// Truncated or replaced: start over, so appends chain onto what is
+		// actually there and Verify reports the gap.
+		l.head, l.offset = Event{}, 0
+	}
+	if st.Size() == l.offset {
+		return false, nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(f, l.offset, st.Size()-l.offset))
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			l.offset += int64(len(line))
+			return len(line) > 0, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("audit: read %s: %w", l.path, err)
+		}
+		l.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			slog.Error("audit: corrupt line", "path", l.path, "offset", l.offset, "error", err)
+			continue
+		}
+		l.head = e
+	}
+}
+
+// Query implements Reader.
+func (l *FileLog) Query(filter Filter) ([]Event, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("audit: open %s: %w", l.path, err)
+	}
+	defer f.Close()
+
+	var out []Event
+	err = readLines(f, func(_ int, line []byte) error {
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil // Verify reports it
+		}
+		if filter.Match(e) {
+			out = append(out, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("audit: read %s: %w", l.path, err)
+	}
+	return filter.page(out), nil
+}
+
+// readLines calls fn with each non-blank line of r, trimmed, and its line
+// number. Lines have no length limit, so one oversized event cannot make
+// the rest of the log unreadable. An error from fn stops the read and is
+// returned as is.
+func readLines(r io.Reader, fn func(n int, line []byte) error) error {
+	rd := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := rd.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if ferr := fn(n, line); ferr != nil {
+				return ferr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package audit
+
+import "os"
+
+// Without flock, appends are only serialized within one process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) {}
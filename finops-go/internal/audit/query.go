@@ -0,0 +1,70 @@
+package audit
+
+// DefaultLimit and MaxLimit bound Filter.Limit.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Filter selects events. Empty fields match everything. From and To are
+// inclusive RFC3339 or YYYY-MM-DD bounds on Time, compared as strings.
+// Results are newest first; AfterSeq pages backwards from a previous
+// page's last Seq.
+type Filter struct {
+	TenantID   string
+	Actor      string
+	Action     string
+	Source     string
+	WorkflowID string
+	From       string
+	To         string
+
+	AfterSeq int64
+	Limit    int
+}
+
+// Match reports whether e satisfies the filter's criteria (ignoring
+// paging).
+func (f Filter) Match(e Event) bool {
+	switch {
+	case f.TenantID != "" && e.TenantID != f.TenantID,
+		f.Actor != "" && e.Actor != f.Actor,
+		f.Action != "" && e.Action != f.Action,
+		f.Source != "" && e.Source != f.Source,
+		f.WorkflowID != "" && e.WorkflowID != f.WorkflowID,
+		f.From != "" && e.Time < f.From,
+		f.To != "" && e.Time[:min(len(e.Time), len(f.To))] > f.To:
+		return false
+	}
+	return true
+}
+
+// page orders matches newest first and applies AfterSeq and Limit.
+func (f Filter) page(events []Event) []Event {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	out := make([]Event, 0, min(limit, len(events)))
+	for i := len(events) - 1; i >= 0 && len(out) < limit; i-- {
+		if f.AfterSeq > 0 && events[i].Seq >= f.AfterSeq {
+			continue
+		}
+		out = append(out, events[i])
+	}
+	return out
+}
+
+// Reader queries the trail.
+type Reader interface {
+	Query(f Filter) ([]Event, error)
+}
+
+// Log is a trail that can be both appended to and queried.
+type Log interface {
+	Logger
+	Reader
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+)
+
+// RBACAuditor records permission denials in the trail as well as the log.
+type RBACAuditor struct {
+	Log Logger
+}
+
+// RecordDenial implements rbac.Auditor.
+func (a RBACAuditor) RecordDenial(ctx context.Context, d rbac.Denial) {
+	rbac.LogAuditor{}.RecordDenial(ctx, d)
+
+	roles := make([]string, len(d.Roles))
+	for i, r := range d.Roles {
+		roles[i] = string(r)
+	}
+	Record(ctx, a.Log, Event{
+		Actor:    d.Subject,
+		Source:   d.Surface,
+		TenantID: d.TenantID,
+		Action:   ActionPermissionDenied,
+		Resource: d.Operation,
+		Outcome:  "denied",
+		Details:  map[string]string{"permission": string(d.Permission), "roles": strings.Join(roles, ",")},
+	})
+}
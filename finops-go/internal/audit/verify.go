@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrTampered is returned (wrapped) by Verify when the chain is broken.
+var ErrTampered = errors.New("audit: chain broken")
+
+// Report summarizes a verified chain. Head is the last event's hash;
+// anchoring it elsewhere (a ticket, a WORM bucket) also detects a
+// truncated tail.
+type Report struct {
+	Events int64  `json:"events"`
+	Head   string `json:"head"`
+}
+
+// Verify re-computes every hash in the file at path and checks that each
+// event links to its predecessor with consecutive sequence numbers. It
+// returns the first break, wrapping ErrTampered, with its line number.
+func Verify(path string) (Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Report{}, fmt.Errorf("audit: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var (
+		rep  Report
+		prev Event
+	)
+	err = readLines(f, func(n int, line []byte) error {
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("%w: line %d: malformed event: %v", ErrTampered, n, err)
+		}
+		if e.Seq != prev.Seq+1 {
+			return fmt.Errorf("%w: line %d: seq %d follows %d", ErrTampered, n, e.Seq, prev.Seq)
+		}
+		if e.PrevHash != prev.Hash {
+			return fmt.Errorf("%w: line %d: seq %d does not link to its predecessor", ErrTampered, n, e.Seq)
+		}
+		want, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if e.Hash != want {
+			return fmt.Errorf("%w: line %d: seq %d hash mismatch", ErrTampered, n, e.Seq)
+		}
+		prev = e
+		rep.Events++
+		rep.Head = e.Hash
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrTampered) {
+			return rep, err
+		}
+		return rep, fmt.Errorf("audit: read %s: %w", path, err)
+	}
+	return rep, nil
+}
@@ -0,0 +1,292 @@
+// Package calendar models change windows and freeze periods for the
+// executor. It is pure logic: callers pass the current time explicitly.
+//
+// Semantics:
+//   - A freeze whose scope matches blocks changes for its whole duration.
+//   - If any recurring window matches the scope, changes are only allowed
+//     inside one of the matching windows.
+//   - If no window matches the scope, changes are allowed at any time
+//     outside freezes.
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchHorizon bounds how far ahead NextAllowed looks for an open window.
+const SearchHorizon = 90 * 24 * time.Hour
+
+var (
+	// ErrFrozen is returned by Check when a freeze covers the given time.
+	ErrFrozen = errors.New("calendar: change freeze in effect")
+	// ErrOutsideWindow is returned by Check when no matching window is open.
+	ErrOutsideWindow = errors.New("calendar: outside change window")
+	// ErrNoWindow is returned by NextAllowed when nothing opens within SearchHorizon.
+	ErrNoWindow = errors.New("calendar: no change window within search horizon")
+)
+
+// Scope restricts a window or freeze to particular tenants and resources.
+// An empty scope matches everything. Tags must all match; a value of "*"
+// matches any value for that key.
+type Scope struct {
+	Tenants []string          `json:"tenants,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// Matches reports whether the scope covers the tenant and resource tags.
+func (s Scope) Matches(tenantID string, tags map[string]string) bool {
+	if len(s.Tenants) > 0 {
+		found := false
+		for _, t := range s.Tenants {
+			if t == tenantID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, want := range s.Tags {
+		got, ok := tags[k]
+		if !ok || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Window is a recurring weekly maintenance window. Start and End are
+// "HH:MM" in Timezone; an End at or before Start crosses midnight.
+type Window struct {
+	Name     string   `json:"name"`
+	Scope    Scope    `json:"scope"`
+	Days     []string `json:"days"` // mon, tue, wed, thu, fri, sat, sun
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Timezone string   `json:"timezone,omitempty"` // IANA name; default UTC
+
+	days  [7]bool
+	start int // minutes after midnight
+	end   int
+	loc   *time.Location
+}
+
+// Freeze is a one-off period during which no changes are allowed.
+type Freeze struct {
+	Name  string    `json:"name"`
+	Scope Scope     `json:"scope"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Calendar is a set of recurring windows and one-off freezes.
+// A nil *Calendar allows every change.
+type Calendar struct {
+	Windows []Window `json:"windows"`
+	Freezes []Freeze `json:"freezes"`
+}
+
+// Load reads a JSON calendar from path.
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: read %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates a JSON calendar.
+func Parse(data []byte) (*Calendar, error) {
+	var c Calendar
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("calendar: decode: %w", err)
+	}
+	if err := c.Compile(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Compile validates the calendar and prepares windows for evaluation.
+// It must be called after building a Calendar by hand; Parse calls it.
+func (c *Calendar) Compile() error {
+	for i := range c.Windows {
+		if err := c.Windows[i].compile(); err != nil {
+			return fmt.Errorf("calendar: window %q: %w", c.Windows[i].Name, err)
+		}
+	}
+	for _, f := range c.Freezes {
+		if !f.End.After(f.Start) {
+			return fmt.Errorf("calendar: freeze %q: end must be after start", f.Name)
+		}
+	}
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func (w *Window) compile() error {
+	if len(w.Days) == 0 {
+		return errors.New("at least one day is required")
+	}
+	w.days = [7]bool{}
+	for _, d := range w.Days {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return fmt.Errorf("invalid day %q", d)
+		}
+		w.days[wd] = true
+	}
+	var err error
+	if w.start, err = parseClock(w.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if w.end, err = parseClock(w.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	w.loc = time.UTC
+	if w.Timezone != "" {
+		if w.loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+	return nil
+}
+
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	h, err1 := strconv.Atoi(hh)
+	m, err2 := strconv.Atoi(mm)
+	if err1 != nil || err2 != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return h*60 + m, nil
+}
+
+// contains reports whether t falls inside an occurrence of the window.
+func (w *Window) contains(t time.Time) bool {
+	lt := t.In(w.loc)
+	m := lt.Hour()*60 + lt.Minute()
+	wd := lt.Weekday()
+	if w.start < w.end {
+		return w.days[wd] && m >= w.start && m < w.end
+	}
+	// Overnight: today's occurrence after start, or yesterday's before end.
+	return (w.days[wd] && m >= w.start) || (w.days[(wd+6)%7] && m < w.end)
+}
+
+// starts returns occurrence start times in [from, to).
+func (w *Window) starts(from, to time.Time) []time.Time {
+	var out []time.Time
+	lf := from.In(w.loc)
+	day := time.Date(lf.Year(), lf.Month(), lf.Day(), 0, 0, 0, 0, w.loc)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		s := day.Add(time.Duration(w.start) * time.Minute)
+		if !s.Before(from) && s.Before(to) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Check returns nil if a change to a resource with the given tags, owned by
+// tenantID, is allowed at t. Otherwise it returns an error wrapping
+// ErrFrozen or ErrOutsideWindow.
+func (c *Calendar) Check(t time.Time, tenantID string, tags map[string]string) error {
+	if c == nil {
+		return nil
+	}
+	for _, f := range c.Freezes {
+		if f.Scope.Matches(tenantID, tags) && !t.Before(f.Start) && t.Before(f.End) {
+			return fmt.Errorf("%w: %q until %s", ErrFrozen, f.Name, f.End.UTC().Format(time.RFC3339))
+		}
+	}
+	scoped := false
+	for i := range c.Windows {
+		w := &c.Windows[i]
+		if !w.Scope.Matches(tenantID, tags) {
+			continue
+		}
+		scoped = true
+		if w.contains(t) {
+			return nil
+		}
+	}
+	if scoped {
+		return fmt.Errorf("%w at %s", ErrOutsideWindow, t.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// NextAllowed returns the earliest time at or after from when a change is
+// allowed for the tenant and tags, or ErrNoWindow if none falls within
+// SearchHorizon.
+func (c *Calendar) NextAllowed(from time.Time, tenantID string, tags map[string]string) (time.Time, error) {
+	if c.Check(from, tenantID, tags) == nil {
+		return from, nil
+	}
+	to := from.Add(SearchHorizon)
+
+	// Allowed periods can only begin at a window start or a freeze end.
+	var candidates []time.Time
+	for i := range c.Windows {
+		if c.Windows[i].Scope.Matches(tenantID, tags) {
+			candidates = append(candidates, c.Windows[i].starts(from, to)...)
+		}
+	}
+	for _, f := range c.Freezes {
+		if f.Scope.Matches(tenantID, tags) && f.End.After(from) && f.End.Before(to) {
+			candidates = append(candidates, f.End)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, t := range candidates {
+		if c.Check(t, tenantID, tags) == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrNoWindow
+}
+
+// NextAllowedAll returns the earliest time at or after from when changes to
+// every resource in tagSets are allowed at once.
+func (c *Calendar) NextAllowedAll(from time.Time, tenantID string, tagSets []map[string]string) (time.Time, error) {
+	if len(tagSets) == 0 {
+		tagSets = []map[string]string{nil}
+	}
+	limit := from.Add(SearchHorizon)
+	t := from
+	for !t.After(limit) {
+		moved := false
+		for _, tags := range tagSets {
+			next, err := c.NextAllowed(t, tenantID, tags)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if next.After(t) {
+				t, moved = next, true
+			}
+		}
+		if !moved {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrNoWindow
+}
@@ -0,0 +1,172 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, js string) *Calendar {
+	t.Helper()
+	c, err := Parse([]byte(js))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return c
+}
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// 2026-03-02 is a Monday.
+const testCalendar = `{
+	"windows": [
+		{"name": "prod-weeknights", "scope": {"tags": {"env": "prod"}},
+		 "days": ["mon", "tue", "wed", "thu"], "start": "22:00", "end": "02:00"},
+		{"name": "acme-weekend", "scope": {"tenants": ["acme"]},
+		 "days": ["sat"], "start": "09:00", "end": "17:00"}
+	],
+	"freezes": [
+		{"name": "quarter-close", "start": "2026-03-30T00:00:00Z", "end": "2026-04-03T00:00:00Z"},
+		{"name": "holiday", "scope": {"tags": {"env": "*"}},
+		 "start": "2026-03-03T23:00:00Z", "end": "2026-03-04T01:00:00Z"}
+	]
+}`
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	c := mustParse(t, testCalendar)
+	prod := map[string]string{"env": "prod"}
+	dev := map[string]string{"env": "dev"}
+
+	tests := []struct {
+		name    string
+		at      string
+		tenant  string
+		tags    map[string]string
+		wantErr error
+	}{
+		{name: "prod inside window", at: "2026-03-02T23:00:00Z", tenant: "t1", tags: prod},
+		{name: "prod after midnight carries over", at: "2026-03-03T01:30:00Z", tenant: "t1", tags: prod},
+		{name: "prod outside window", at: "2026-03-02T12:00:00Z", tenant: "t1", tags: prod, wantErr: ErrOutsideWindow},
+		{name: "prod friday night not a window day", at: "2026-03-06T23:00:00Z", tenant: "t1", tags: prod, wantErr: ErrOutsideWindow},
+		{name: "unscoped resource always allowed", at: "2026-03-02T12:00:00Z", tenant: "t1", tags: dev},
+		{name: "tenant window closed", at: "2026-03-02T12:00:00Z", tenant: "acme", tags: dev, wantErr: ErrOutsideWindow},
+		{name: "tenant window open", at: "2026-03-07T10:00:00Z", tenant: "acme", tags: dev},
+		{name: "global freeze", at: "2026-03-31T12:00:00Z", tenant: "t1", tags: dev, wantErr: ErrFrozen},
+		{name: "tag freeze inside window", at: "2026-03-03T23:30:00Z", tenant: "t1", tags: prod, wantErr: ErrFrozen},
+		{name: "tag freeze ignores untagged", at: "2026-03-03T23:30:00Z", tenant: "t1", tags: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := c.Check(at(tt.at), tt.tenant, tt.tags)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Check() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextAllowed(t *testing.T) {
+	t.Parallel()
+	c := mustParse(t, testCalendar)
+	prod := map[string]string{"env": "prod"}
+
+	tests := []struct {
+		name   string
+		from   string
+		tenant string
+		tags   map[string]string
+		want   string
+	}{
+		{name: "already open", from: "2026-03-02T23:00:00Z", tenant: "t1", tags: prod, want: "2026-03-02T23:00:00Z"},
+		{name: "waits for window start", from: "2026-03-02T12:00:00Z", tenant: "t1", tags: prod, want: "2026-03-02T22:00:00Z"},
+		{name: "skips to monday after friday", from: "2026-03-06T12:00:00Z", tenant: "t1", tags: prod, want: "2026-03-09T22:00:00Z"},
+		{name: "resumes when freeze ends mid-window", from: "2026-03-03T23:30:00Z", tenant: "t1", tags: prod, want: "2026-03-04T01:00:00Z"},
+		{name: "global freeze end", from: "2026-03-31T12:00:00Z", tenant: "t1", tags: nil, want: "2026-04-03T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := c.NextAllowed(at(tt.from), tt.tenant, tt.tags)
+			if err != nil {
+				t.Fatalf("NextAllowed: %v", err)
+			}
+			if !got.Equal(at(tt.want)) {
+				t.Errorf("NextAllowed() = %s, want %s", got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestNextAllowedAll(t *testing.T) {
+	t.Parallel()
+	c := mustParse(t, testCalendar)
+
+	// An acme prod resource matches both windows; either one being open is enough.
+	got, err := c.NextAllowedAll(at("2026-03-06T12:00:00Z"), "acme", []map[string]string{{"env": "prod"}})
+	if err != nil {
+		t.Fatalf("NextAllowedAll: %v", err)
+	}
+	if !got.Equal(at("2026-03-07T09:00:00Z")) {
+		t.Errorf("NextAllowedAll() = %s", got)
+	}
+
+	long := mustParse(t, `{"freezes": [{"name": "forever", "start": "2026-01-01T00:00:00Z", "end": "2027-01-01T00:00:00Z"}]}`)
+	if _, err := long.NextAllowedAll(at("2026-03-02T12:00:00Z"), "t1", nil); !errors.Is(err, ErrNoWindow) {
+		t.Fatalf("expected ErrNoWindow, got %v", err)
+	}
+
+	got, err = c.NextAllowedAll(at("2026-03-02T12:00:00Z"), "t1", []map[string]string{nil, {"env": "prod"}})
+	if err != nil {
+		t.Fatalf("NextAllowedAll: %v", err)
+	}
+	if !got.Equal(at("2026-03-02T22:00:00Z")) {
+		t.Errorf("NextAllowedAll() = %s", got)
+	}
+}
+
+func TestNilCalendarAllowsEverything(t *testing.T) {
+	t.Parallel()
+	var c *Calendar
+	now := at("2026-03-02T12:00:00Z")
+	if err := c.Check(now, "t1", nil); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	got, err := c.NextAllowedAll(now, "t1", nil)
+	if err != nil || !got.Equal(now) {
+		t.Fatalf("NextAllowedAll() = %s, %v", got, err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		js   string
+	}{
+		{name: "bad json", js: `{`},
+		{name: "no days", js: `{"windows": [{"name": "w", "start": "01:00", "end": "02:00"}]}`},
+		{name: "bad day", js: `{"windows": [{"name": "w", "days": ["funday"], "start": "01:00", "end": "02:00"}]}`},
+		{name: "bad clock", js: `{"windows": [{"name": "w", "days": ["mon"], "start": "25:00", "end": "02:00"}]}`},
+		{name: "bad timezone", js: `{"windows": [{"name": "w", "days": ["mon"], "start": "01:00", "end": "02:00", "timezone": "Mars/Base"}]}`},
+		{name: "inverted freeze", js: `{"freezes": [{"name": "f", "start": "2026-03-02T00:00:00Z", "end": "2026-03-01T00:00:00Z"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Parse([]byte(tt.js)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Package client is a Go client for the FinOps HTTP API (/api/v1). The
+// request and response types are the API's own, so the client follows the
+// OpenAPI document the server publishes at /api/v1/openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+	"github.com/finops-claw-gang/finops-go/internal/uischema"
+)
+
+// Client calls the API at a base URL such as http://localhost:8080.
+type Client struct {
+	base   string
+	http   *http.Client
+	apiKey string
+	token  string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithAPIKey authenticates requests with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithToken authenticates requests with an OIDC bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient replaces http.DefaultClient.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) { c.http = h }
+}
+
+// New creates a Client for the API at baseURL.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{base: strings.TrimRight(baseURL, "/"), http: http.DefaultClient}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// Error is an error response from the API. Code is one of apperr's
+// stable codes; RequestID matches the server's logs.
+type Error struct {
+	StatusCode int
+	Code       apperr.Code
+	Message    string
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("api: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	if e.Code != "" {
+		msg = fmt.Sprintf("api: %s (%d): %s", e.Code, e.StatusCode, e.Message)
+	}
+	if e.RequestID != "" {
+		msg += " [request " + e.RequestID + "]"
+	}
+	return msg
+}
+
+// CodeOf returns the API error code of err, or "" if err is not an API
+// error.
+func CodeOf(err error) apperr.Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// WorkflowFilter selects workflows to list. Zero fields do not filter.
+type WorkflowFilter struct {
+	Status         string
+	Tenant         string
+	Service        string
+	Account        string
+	Category       string
+	Severity       string
+	Phase          string
+	ApprovalStatus string
+	MinDelta       *float64
+	Limit          int
+}
+
+func (f WorkflowFilter) values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("status", f.Status)
+	set("tenant", f.Tenant)
+	set("service", f.Service)
+	set("account", f.Account)
+	set("category", f.Category)
+	set("severity", f.Severity)
+	set("phase", f.Phase)
+	set("approval_status", f.ApprovalStatus)
+	if f.MinDelta != nil {
+		v.Set("min_delta", strconv.FormatFloat(*f.MinDelta, 'f', -1, 64))
+	}
+	if f.Limit > 0 {
+		v.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return v
+}
+
+// Health checks the server is up.
+func (c *Client) Health(ctx context.Context) error {
+	var out api.HealthResponse
+	return c.do(ctx, http.MethodGet, "/api/v1/health", nil, nil, nil, &out)
+}
+
+// ListWorkflows lists anomaly lifecycle workflows.
+func (c *Client) ListWorkflows(ctx context.Context, f WorkflowFilter) ([]querier.WorkflowSummary, error) {
+	var out []querier.WorkflowSummary
+	err := c.do(ctx, http.MethodGet, "/api/v1/workflows", f.values(), nil, nil, &out)
+	return out, err
+}
+
+// GetWorkflow returns a workflow's state.
+func (c *Client) GetWorkflow(ctx context.Context, id string) (*workflows.WorkflowResult, error) {
+	var out workflows.WorkflowResult
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/"+url.PathEscape(id), nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWorkflowUI returns the UI schema for a workflow's state.
+func (c *Client) GetWorkflowUI(ctx context.Context, id string) (*uischema.UISchema, error) {
+	var out uischema.UISchema
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workflows/"+url.PathEscape(id)+"/ui", nil, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Approve approves a workflow's pending actions and returns the
+// workflow's answer. With authentication on, by may be empty.
+func (c *Client) Approve(ctx context.Context, id, by string) (string, error) {
+	return c.approval(ctx, id, "approve", api.ApprovalRequest{By: by})
+}
+
+// Deny denies a workflow's pending actions and returns the workflow's
+// answer. With authentication on, by may be empty.
+func (c *Client) Deny(ctx context.Context, id, by, reason string) (string, error) {
+	return c.approval(ctx, id, "deny", api.ApprovalRequest{By: by, Reason: reason})
+}
+
+func (c *Client) approval(ctx context.Context, id, action string, body api.ApprovalRequest) (string, error) {
+	var out api.ResultResponse
+	err := c.do(ctx, http.MethodPost, "/api/v1/workflows/"+url.PathEscape(id)+"/"+action, nil, nil, body, &out)
+	return out.Result, err
+}
+
+// TriggerAnomaly starts an anomaly lifecycle workflow. A non-empty
+// idempotencyKey names it; retries with the same key return it.
+func (c *Client) TriggerAnomaly(ctx context.Context, req api.TriggerAnomalyRequest, idempotencyKey string) (querier.StartResult, error) {
+	var out querier.StartResult
+	err := c.do(ctx, http.MethodPost, "/api/v1/anomalies", nil, idempotency(idempotencyKey), req, &out)
+	return out, err
+}
+
+// TriggerSweep starts a waste sweep. A non-empty idempotencyKey names it.
+func (c *Client) TriggerSweep(ctx context.Context, req api.TriggerSweepRequest, idempotencyKey string) (querier.StartResult, error) {
+	var out querier.StartResult
+	err := c.do(ctx, http.MethodPost, "/api/v1/sweeps", nil, idempotency(idempotencyKey), req, &out)
+	return out, err
+}
+
+// ListAnomalies searches the anomaly history. The query parameters are
+// those of GET /api/v1/anomalies.
+func (c *Client) ListAnomalies(ctx context.Context, query url.Values) (history.Page, error) {
+	var out history.Page
+	err := c.do(ctx, http.MethodGet, "/api/v1/anomalies", query, nil, nil, &out)
+	return out, err
+}
+
+// Savings reports the savings ledger. The query parameters are those of
+// GET /api/v1/savings, other than format.
+func (c *Client) Savings(ctx context.Context, query url.Values) (api.SavingsResponse, error) {
+	var out api.SavingsResponse
+	err := c.do(ctx, http.MethodGet, "/api/v1/savings", query, nil, nil, &out)
+	return out, err
+}
+
+// Audit queries the audit trail. The query parameters are those of
+// GET /api/v1/audit.
+func (c *Client) Audit(ctx context.Context, query url.Values) (api.AuditResponse, error) {
+	var out api.AuditResponse
+	err := c.do(ctx, http.MethodGet, "/api/v1/audit", query, nil, nil, &out)
+	return out, err
+}
+
+// CreateAPIKey issues an API key. The token is returned only here.
+func (c *Client) CreateAPIKey(ctx context.Context, req api.CreateAPIKeyRequest) (api.CreateAPIKeyResponse, error) {
+	var out api.CreateAPIKeyResponse
+	err := c.do(ctx, http.MethodPost, "/api/v1/apikeys", nil, nil, req, &out)
+	return out, err
+}
+
+// ListAPIKeys lists API keys, without their hashes. An empty tenant lists
+// the caller's own, or every tenant's for admins.
+func (c *Client) ListAPIKeys(ctx context.Context, tenant string) ([]apikey.Key, error) {
+	query := url.Values{}
+	if tenant != "" {
+		query.Set("tenant", tenant)
+	}
+	var out api.APIKeyList
+	err := c.do(ctx, http.MethodGet, "/api/v1/apikeys", query, nil, nil, &out)
+	return out.Keys, err
+}
+
+// RevokeAPIKey revokes an API key.
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) error {
+	var out api.ResultResponse
+	return c.do(ctx, http.MethodDelete, "/api/v1/apikeys/"+url.PathEscape(id), nil, nil, nil, &out)
+}
+
+func idempotency(key string) http.Header {
+	if key == "" {
+		return nil
+	}
+	return http.Header{"Idempotency-Key": {key}}
+}
+
+// do sends a request with an optional JSON body and decodes a JSON
+// response into out. Non-2xx responses are returned as *Error, decoded
+// from the problem body.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	target := c.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("client: encode %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json, "+api.ProblemContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("X-API-Key", c.apiKey)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var p api.Problem
+		if json.Unmarshal(data, &p) == nil && p.Code != "" {
+			apiErr.Code, apiErr.Message, apiErr.RequestID = p.Code, p.Detail, p.RequestID
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Header.Get("X-Request-ID")
+		}
+		return apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode %s %s: %w", method, path, err)
+	}
+	return nil
+}
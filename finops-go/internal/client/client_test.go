@@ -0,0 +1,183 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/api"
+	"github.com/finops-claw-gang/finops-go/internal/apikey"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/client"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+)
+
+// stubQuerier serves workflow wf-1 of tenant acme and starts workflows.
+type stubQuerier struct {
+	listOpts querier.ListOptions
+	resp     activities.ApprovalResponse
+	started  map[string]bool
+}
+
+func (q *stubQuerier) ListWorkflows(_ context.Context, opts querier.ListOptions) ([]querier.WorkflowSummary, error) {
+	q.listOpts = opts
+	return []querier.WorkflowSummary{{WorkflowID: "wf-1", Status: "Running"}}, nil
+}
+
+func (q *stubQuerier) GetWorkflowState(_ context.Context, id string) (*workflows.WorkflowResult, error) {
+	state := domain.NewFinOpsState(domain.NewTenantContext("acme"))
+	state.WorkflowID = id
+	state.CurrentPhase = "hil_gate"
+	return &workflows.WorkflowResult{State: state}, nil
+}
+
+func (q *stubQuerier) DescribeWorkflow(_ context.Context, id string) (*querier.WorkflowDescription, error) {
+	return &querier.WorkflowDescription{WorkflowSummary: querier.WorkflowSummary{WorkflowID: id}}, nil
+}
+
+func (q *stubQuerier) SubmitApproval(_ context.Context, _ string, resp activities.ApprovalResponse) (string, error) {
+	q.resp = resp
+	return "approved", nil
+}
+
+func (q *stubQuerier) StartAnomaly(_ context.Context, id, _ string, _ workflows.WorkflowInput) (querier.StartResult, error) {
+	existing := q.started[id]
+	q.started[id] = true
+	return querier.StartResult{WorkflowID: id, RunID: "run-1", Existing: existing}, nil
+}
+
+func (q *stubQuerier) StartSweep(_ context.Context, id, _ string, _ workflows.SweepInput) (querier.StartResult, error) {
+	return querier.StartResult{WorkflowID: id, RunID: "run-1"}, nil
+}
+
+func newServer(t *testing.T) (*httptest.Server, *stubQuerier, string) {
+	t.Helper()
+	q := &stubQuerier{started: map[string]bool{}}
+	srv, err := api.New(q, nil, api.OIDCConfig{})
+	require.NoError(t, err)
+	keys, err := apikey.OpenFileStore(filepath.Join(t.TempDir(), "keys.jsonl"))
+	require.NoError(t, err)
+	srv.SetAPIKeys(keys)
+	token, _, err := apikey.Issue(keys, apikey.Key{
+		Name: "cli", TenantID: "acme", Roles: []rbac.Role{rbac.Approver, rbac.Analyst, rbac.TenantAdmin},
+	}, time.Now())
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts, q, token
+}
+
+func TestClient_Workflows(t *testing.T) {
+	ts, q, token := newServer(t)
+	c := client.New(ts.URL+"/", client.WithAPIKey(token))
+	ctx := context.Background()
+
+	require.NoError(t, c.Health(ctx))
+
+	minDelta := 100.0
+	list, err := c.ListWorkflows(ctx, client.WorkflowFilter{Severity: "high", MinDelta: &minDelta, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "high", q.listOpts.Severity)
+	assert.Equal(t, "acme", q.listOpts.TenantID)
+	assert.Equal(t, 5, q.listOpts.PageSize)
+	require.NotNil(t, q.listOpts.MinDeltaDollars)
+	assert.InDelta(t, 100, *q.listOpts.MinDeltaDollars, 0.001)
+
+	wf, err := c.GetWorkflow(ctx, "wf-1")
+	require.NoError(t, err)
+	assert.Equal(t, "hil_gate", wf.State.CurrentPhase)
+
+	ui, err := c.GetWorkflowUI(ctx, "wf-1")
+	require.NoError(t, err)
+	assert.Equal(t, "wf-1", ui.WorkflowID)
+
+	// The approver is the key, without naming it.
+	result, err := c.Approve(ctx, "wf-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "approved", result)
+	assert.Contains(t, q.resp.By, "apikey:")
+}
+
+func TestClient_Trigger(t *testing.T) {
+	ts, _, token := newServer(t)
+	c := client.New(ts.URL, client.WithAPIKey(token))
+	ctx := context.Background()
+
+	req := api.TriggerAnomalyRequest{
+		Anomaly:     domain.CostAnomaly{Service: "EC2", AccountID: "123456789012", DeltaDollars: 420},
+		WindowStart: "2026-03-01",
+		WindowEnd:   "2026-03-15",
+	}
+	first, err := c.TriggerAnomaly(ctx, req, "deploy-42")
+	require.NoError(t, err)
+	assert.False(t, first.Existing)
+	again, err := c.TriggerAnomaly(ctx, req, "deploy-42")
+	require.NoError(t, err)
+	assert.True(t, again.Existing)
+	assert.Equal(t, first.WorkflowID, again.WorkflowID)
+
+	created, err := c.CreateAPIKey(ctx, api.CreateAPIKeyRequest{Name: "bot", Roles: []string{"viewer"}})
+	require.NoError(t, err)
+	keys, err := c.ListAPIKeys(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	require.NoError(t, c.RevokeAPIKey(ctx, created.Key.ID))
+}
+
+func TestClient_Errors(t *testing.T) {
+	ts, _, token := newServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		c          *client.Client
+		call       func(*client.Client) error
+		wantStatus int
+		wantCode   apperr.Code
+	}{
+		{
+			name:       "no key",
+			c:          client.New(ts.URL),
+			call:       func(c *client.Client) error { _, err := c.GetWorkflow(ctx, "wf-1"); return err },
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   apperr.Unauthenticated,
+		},
+		{
+			name:       "invalid body",
+			c:          client.New(ts.URL, client.WithAPIKey(token)),
+			call:       func(c *client.Client) error { _, err := c.TriggerSweep(ctx, api.TriggerSweepRequest{}, ""); return err },
+			wantStatus: http.StatusBadRequest,
+			wantCode:   apperr.InvalidArgument,
+		},
+		{
+			name:       "other tenant",
+			c:          client.New(ts.URL, client.WithAPIKey(token)),
+			call:       func(c *client.Client) error { _, err := c.ListAPIKeys(ctx, "globex"); return err },
+			wantStatus: http.StatusNotFound,
+			wantCode:   apperr.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(tt.c)
+			var apiErr *client.Error
+			require.True(t, errors.As(err, &apiErr), "err = %v", err)
+			assert.Equal(t, tt.wantStatus, apiErr.StatusCode)
+			assert.Equal(t, tt.wantCode, client.CodeOf(err))
+			assert.NotEmpty(t, apiErr.Message)
+			assert.NotEmpty(t, apiErr.RequestID)
+		})
+	}
+}
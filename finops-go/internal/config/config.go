@@ -7,6 +7,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/policy"
 )
 
 // Mode determines whether the worker uses stub fixtures or real AWS connectors.
@@ -33,6 +36,45 @@ type Config struct {
 	// Worker settings.
 	WorkerQueues string // comma-separated queue list (env FINOPS_WORKER_QUEUES)
 
+	// PremiumTenants get dedicated anomaly and exec task queues. The API
+	// routes their lifecycles there and workers poll them.
+	PremiumTenants []string
+	// TenantWeights are tenants' shares of the shared queues relative to
+	// the default weight of 1.
+	TenantWeights map[string]float64
+
+	// ChangeCalendarPath is a JSON change-window/freeze calendar. Empty
+	// means actions may execute at any time.
+	ChangeCalendarPath string
+
+	// Executor blast-radius limits (zero = unlimited) and the kill switch
+	// directory (empty = no kill switch).
+	BlastRadius   policy.BlastRadiusLimits
+	KillSwitchDir string
+	// ExecutionLedgerPath keeps the executor's idempotency ledger in a
+	// file, so retries after a restart or on another worker find earlier
+	// attempts. Empty keeps it in memory, per worker.
+	ExecutionLedgerPath string
+
+	// ProtectionRulesPath is a JSON protection ruleset added to the
+	// built-in do-not-modify/manual-only rules.
+	ProtectionRulesPath string
+
+	// SavingsLedgerPath is the savings ledger file shared by the worker
+	// (writer) and API (reader). Empty disables the ledger.
+	SavingsLedgerPath string
+
+	// HistoryStorePath is the anomaly history projection file, written by
+	// the worker and searched by the API. Empty disables it.
+	HistoryStorePath string
+	// AuditLogPath is the hash-chained audit trail, appended to by the
+	// worker, API and MCP server and read by the API. Empty disables it.
+	AuditLogPath string
+
+	// APIKeysPath is the API key file, read by the API server and managed
+	// with the CLI. Empty disables API keys.
+	APIKeysPath string
+
 	// API server settings.
 	APIPort     string
 	CORSOrigins []string
@@ -41,9 +83,20 @@ type Config struct {
 	OIDCIssuer   string
 	OIDCAudience string
 
+	// MCP caller identity. With no roles the MCP tools are unrestricted.
+	MCPSubject  string
+	MCPTenantID string
+	MCPRoles    []string
+	// MCPToken is an OIDC ID token for the MCP caller. When set, it is
+	// verified against the OIDC settings and replaces the fields above.
+	MCPToken string
+
 	// Observability.
 	LogLevel    string
 	OTelEnabled bool
+	// MetricsPort is where the API server and the worker serve Prometheus
+	// metrics on /metrics. "0" disables the listener.
+	MetricsPort string
 
 	AWSDocBinaryPath string
 	SweepAccounts    string
@@ -52,40 +105,119 @@ type Config struct {
 	ShadowPythonPath string
 
 	// Rate limits (requests per second). Zero means use default.
-	RateLimitCE     float64
-	RateLimitAthena float64
-	RateLimitCW     float64
-	RateLimitSTS    float64
+	RateLimitCE         float64
+	RateLimitAthena     float64
+	RateLimitCW         float64
+	RateLimitSTS        float64
+	RateLimitCodeDeploy float64
+	RateLimitTagging    float64
+	RateLimitELB        float64
+	RateLimitKubeCost   float64
+	// RateLimitTenantShare is the fraction of each rate one tenant or AWS
+	// account may use. 1 disables per-tenant limits.
+	RateLimitTenantShare float64
+	// RateLimitStatePath keeps rate-limiter buckets and activity budgets in
+	// a file shared by worker replicas. Empty keeps them in memory, per
+	// replica.
+	RateLimitStatePath string
+
+	// ActivityBudgetMax caps the calls of each activity per tenant within
+	// ActivityBudgetWindow. Zero disables activity budgets.
+	ActivityBudgetMax    int
+	ActivityBudgetWindow time.Duration
+
+	// ToolingBudgetMonthly caps each tenant's monthly spend, in dollars, on
+	// the desk's own AWS API calls. Zero tracks spend without a cap.
+	ToolingBudgetMonthly float64
+	// ToolingBudgetTenants overrides ToolingBudgetMonthly per tenant ID.
+	ToolingBudgetTenants map[string]float64
+
+	// CostCacheEntries is the capacity of the in-memory cache of Cost
+	// Explorer and Athena responses. Zero disables the cache.
+	CostCacheEntries int
+	// CostCacheDir keeps cached responses on disk across restarts. Empty
+	// means memory only.
+	CostCacheDir string
 }
 
 // LoadFromEnv reads configuration from environment variables with sensible defaults.
 func LoadFromEnv() (Config, error) {
 	cfg := Config{
-		Mode:             Mode(envOr("FINOPS_MODE", "stub")),
-		FixturesDir:      os.Getenv("FIXTURES_DIR"),
-		AWSRegion:        envOr("AWS_REGION", "us-east-1"),
-		AWSProfile:       os.Getenv("AWS_PROFILE"),
-		CrossAccountRole: os.Getenv("FINOPS_CROSS_ACCOUNT_ROLE"),
-		CURDatabase:      os.Getenv("FINOPS_CUR_DATABASE"),
-		CURTable:         os.Getenv("FINOPS_CUR_TABLE"),
-		CURWorkgroup:     envOr("FINOPS_CUR_WORKGROUP", "primary"),
-		CUROutputBucket:  os.Getenv("FINOPS_CUR_OUTPUT_BUCKET"),
-		KubeCostEndpoint: os.Getenv("FINOPS_KUBECOST_ENDPOINT"),
-		WorkerQueues:     os.Getenv("FINOPS_WORKER_QUEUES"),
-		APIPort:          envOr("FINOPS_API_PORT", "8080"),
-		CORSOrigins:      parseCORSOrigins(os.Getenv("FINOPS_CORS_ORIGINS")),
-		OIDCIssuer:       os.Getenv("FINOPS_OIDC_ISSUER"),
-		OIDCAudience:     os.Getenv("FINOPS_OIDC_AUDIENCE"),
-		LogLevel:         envOr("FINOPS_LOG_LEVEL", "info"),
-		OTelEnabled:      os.Getenv("FINOPS_OTEL_ENABLED") == "true",
-		AWSDocBinaryPath: envOr("FINOPS_AWSDOC_BINARY", "aws-doctor"),
-		SweepAccounts:    os.Getenv("FINOPS_SWEEP_ACCOUNTS"),
-		ShadowPythonPath: envOr("FINOPS_SHADOW_PYTHON", "python"),
-		RateLimitCE:      envFloat("FINOPS_RATELIMIT_CE", 5),
-		RateLimitAthena:  envFloat("FINOPS_RATELIMIT_ATHENA", 5),
-		RateLimitCW:      envFloat("FINOPS_RATELIMIT_CW", 20),
-		RateLimitSTS:     envFloat("FINOPS_RATELIMIT_STS", 10),
+		Mode:                 Mode(envOr("FINOPS_MODE", "stub")),
+		FixturesDir:          os.Getenv("FIXTURES_DIR"),
+		AWSRegion:            envOr("AWS_REGION", "us-east-1"),
+		AWSProfile:           os.Getenv("AWS_PROFILE"),
+		CrossAccountRole:     os.Getenv("FINOPS_CROSS_ACCOUNT_ROLE"),
+		CURDatabase:          os.Getenv("FINOPS_CUR_DATABASE"),
+		CURTable:             os.Getenv("FINOPS_CUR_TABLE"),
+		CURWorkgroup:         envOr("FINOPS_CUR_WORKGROUP", "primary"),
+		CUROutputBucket:      os.Getenv("FINOPS_CUR_OUTPUT_BUCKET"),
+		KubeCostEndpoint:     os.Getenv("FINOPS_KUBECOST_ENDPOINT"),
+		WorkerQueues:         os.Getenv("FINOPS_WORKER_QUEUES"),
+		PremiumTenants:       parseList(os.Getenv("FINOPS_PREMIUM_TENANTS")),
+		ChangeCalendarPath:   os.Getenv("FINOPS_CHANGE_CALENDAR"),
+		APIPort:              envOr("FINOPS_API_PORT", "8080"),
+		CORSOrigins:          parseCORSOrigins(os.Getenv("FINOPS_CORS_ORIGINS")),
+		OIDCIssuer:           os.Getenv("FINOPS_OIDC_ISSUER"),
+		OIDCAudience:         os.Getenv("FINOPS_OIDC_AUDIENCE"),
+		MCPSubject:           os.Getenv("FINOPS_MCP_SUBJECT"),
+		MCPTenantID:          os.Getenv("FINOPS_MCP_TENANT"),
+		MCPRoles:             parseList(os.Getenv("FINOPS_MCP_ROLES")),
+		MCPToken:             os.Getenv("FINOPS_MCP_TOKEN"),
+		LogLevel:             envOr("FINOPS_LOG_LEVEL", "info"),
+		OTelEnabled:          os.Getenv("FINOPS_OTEL_ENABLED") == "true",
+		MetricsPort:          envOr("FINOPS_METRICS_PORT", "9464"),
+		AWSDocBinaryPath:     envOr("FINOPS_AWSDOC_BINARY", "aws-doctor"),
+		SweepAccounts:        os.Getenv("FINOPS_SWEEP_ACCOUNTS"),
+		ShadowPythonPath:     envOr("FINOPS_SHADOW_PYTHON", "python"),
+		RateLimitCE:          envFloat("FINOPS_RATELIMIT_CE", 5),
+		RateLimitAthena:      envFloat("FINOPS_RATELIMIT_ATHENA", 5),
+		RateLimitCW:          envFloat("FINOPS_RATELIMIT_CW", 20),
+		RateLimitSTS:         envFloat("FINOPS_RATELIMIT_STS", 10),
+		RateLimitCodeDeploy:  envFloat("FINOPS_RATELIMIT_CODEDEPLOY", 5),
+		RateLimitTagging:     envFloat("FINOPS_RATELIMIT_TAGGING", 5),
+		RateLimitELB:         envFloat("FINOPS_RATELIMIT_ELB", 10),
+		RateLimitKubeCost:    envFloat("FINOPS_RATELIMIT_KUBECOST", 10),
+		RateLimitTenantShare: envFloat("FINOPS_RATELIMIT_TENANT_SHARE", 0.5),
+		RateLimitStatePath:   os.Getenv("FINOPS_RATELIMIT_STATE"),
+		ActivityBudgetMax:    envInt("FINOPS_ACTIVITY_BUDGET", 0),
+		ActivityBudgetWindow: envDuration("FINOPS_ACTIVITY_BUDGET_WINDOW", time.Hour),
+		ToolingBudgetMonthly: envFloat("FINOPS_TOOLING_BUDGET", 0),
+		CostCacheEntries:     envInt("FINOPS_COST_CACHE_ENTRIES", 1024),
+		CostCacheDir:         os.Getenv("FINOPS_COST_CACHE_DIR"),
+		KillSwitchDir:        os.Getenv("FINOPS_KILL_SWITCH_DIR"),
+		ExecutionLedgerPath:  os.Getenv("FINOPS_EXECUTION_LEDGER"),
+		ProtectionRulesPath:  os.Getenv("FINOPS_PROTECTION_RULES"),
+		SavingsLedgerPath:    os.Getenv("FINOPS_SAVINGS_LEDGER"),
+		HistoryStorePath:     os.Getenv("FINOPS_HISTORY_STORE"),
+		AuditLogPath:         os.Getenv("FINOPS_AUDIT_LOG"),
+		APIKeysPath:          os.Getenv("FINOPS_API_KEYS"),
+	}
+
+	blast := policy.DefaultBlastRadiusLimits()
+	cfg.BlastRadius = policy.BlastRadiusLimits{
+		MaxActionsPerWorkflow:     envInt("FINOPS_BLAST_MAX_ACTIONS_PER_WORKFLOW", blast.MaxActionsPerWorkflow),
+		MaxActionsPerTenantPerDay: envInt("FINOPS_BLAST_MAX_ACTIONS_PER_TENANT_DAY", blast.MaxActionsPerTenantPerDay),
+		MaxActionsPerResourceType: envInt("FINOPS_BLAST_MAX_ACTIONS_PER_RESOURCE_TYPE", blast.MaxActionsPerResourceType),
+		MaxMonthlySpendAffected:   envFloat("FINOPS_BLAST_MAX_MONTHLY_SPEND", blast.MaxMonthlySpendAffected),
+	}
+
+	tenantBudgets, err := parseTenantValues(os.Getenv("FINOPS_TOOLING_BUDGET_TENANTS"), "dollars", "amount")
+	if err != nil {
+		return Config{}, fmt.Errorf("config: FINOPS_TOOLING_BUDGET_TENANTS: %w", err)
 	}
+	cfg.ToolingBudgetTenants = tenantBudgets
+
+	weights, err := parseTenantValues(os.Getenv("FINOPS_TENANT_WEIGHTS"), "weight", "weight")
+	if err != nil {
+		return Config{}, fmt.Errorf("config: FINOPS_TENANT_WEIGHTS: %w", err)
+	}
+	for tenant, w := range weights {
+		if w <= 0 {
+			return Config{}, fmt.Errorf("config: FINOPS_TENANT_WEIGHTS: weight for tenant %s must be positive", tenant)
+		}
+	}
+	cfg.TenantWeights = weights
 
 	if cfg.Mode != ModeStub && cfg.Mode != ModeProduction {
 		return Config{}, fmt.Errorf("config: invalid FINOPS_MODE %q (must be stub or production)", cfg.Mode)
@@ -131,18 +263,66 @@ func envFloat(key string, fallback float64) float64 {
 	return f
 }
 
-func parseCORSOrigins(raw string) []string {
-	if raw == "" {
-		return []string{"*"}
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
 	}
-	var origins []string
-	for _, o := range strings.Split(raw, ",") {
-		if t := strings.TrimSpace(o); t != "" {
-			origins = append(origins, t)
-		}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		slog.Warn("ignoring invalid env var", "key", key, "value", v, "fallback", fallback)
+		return fallback
 	}
+	return n
+}
+
+func parseCORSOrigins(raw string) []string {
+	origins := parseList(raw)
 	if len(origins) == 0 {
 		return []string{"*"}
 	}
 	return origins
 }
+
+// parseList splits a comma-separated value, dropping empty items.
+func parseList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if t := strings.TrimSpace(item); t != "" {
+			items = append(items, t)
+		}
+	}
+	return items
+}
+
+// parseTenantValues parses "tenant=value" pairs separated by commas. unit
+// and name describe the value in error messages.
+func parseTenantValues(raw, unit, name string) (map[string]float64, error) {
+	values := make(map[string]float64)
+	for _, item := range parseList(raw) {
+		tenant, value, ok := strings.Cut(item, "=")
+		tenant = strings.TrimSpace(tenant)
+		if !ok || tenant == "" {
+			return nil, fmt.Errorf("invalid entry %q (want tenant=%s)", item, unit)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s for tenant %s: %w", name, tenant, err)
+		}
+		values[tenant] = v
+	}
+	return values, nil
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("ignoring invalid env var", "key", key, "value", v, "fallback", fallback)
+		return fallback
+	}
+	return d
+}
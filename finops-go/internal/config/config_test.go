@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +16,29 @@ func TestLoadFromEnv_Defaults(t *testing.T) {
 	assert.Equal(t, ModeStub, cfg.Mode)
 	assert.Equal(t, "us-east-1", cfg.AWSRegion)
 	assert.Equal(t, "primary", cfg.CURWorkgroup)
+	assert.Equal(t, 1024, cfg.CostCacheEntries)
+	assert.Empty(t, cfg.CostCacheDir)
+	assert.Empty(t, cfg.RateLimitStatePath)
+	assert.Zero(t, cfg.ActivityBudgetMax)
+	assert.Equal(t, time.Hour, cfg.ActivityBudgetWindow)
+}
+
+func TestLoadFromEnv_ActivityBudget(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("FINOPS_ACTIVITY_BUDGET", "50")
+	t.Setenv("FINOPS_ACTIVITY_BUDGET_WINDOW", "15m")
+	t.Setenv("FINOPS_RATELIMIT_STATE", "/shared/limits.json")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.ActivityBudgetMax)
+	assert.Equal(t, 15*time.Minute, cfg.ActivityBudgetWindow)
+	assert.Equal(t, "/shared/limits.json", cfg.RateLimitStatePath)
+
+	t.Setenv("FINOPS_ACTIVITY_BUDGET_WINDOW", "soon")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.ActivityBudgetWindow, "invalid value falls back to default")
 }
 
 func TestLoadFromEnv_ProductionValid(t *testing.T) {
@@ -48,12 +72,99 @@ func TestLoadFromEnv_InvalidMode(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid FINOPS_MODE")
 }
 
+func TestLoadFromEnv_BlastRadius(t *testing.T) {
+	clearEnv(t)
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 25, cfg.BlastRadius.MaxActionsPerWorkflow)
+
+	t.Setenv("FINOPS_BLAST_MAX_ACTIONS_PER_WORKFLOW", "0")
+	t.Setenv("FINOPS_BLAST_MAX_ACTIONS_PER_TENANT_DAY", "not-a-number")
+	t.Setenv("FINOPS_BLAST_MAX_MONTHLY_SPEND", "1234.5")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.BlastRadius.MaxActionsPerWorkflow)
+	assert.Equal(t, 200, cfg.BlastRadius.MaxActionsPerTenantPerDay, "invalid value falls back to default")
+	assert.Equal(t, 1234.5, cfg.BlastRadius.MaxMonthlySpendAffected)
+}
+
+func TestLoadFromEnv_ToolingBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenants string
+		want    map[string]float64
+		wantErr string
+	}{
+		{name: "none", want: map[string]float64{}},
+		{name: "overrides", tenants: "acme=50, globex=0", want: map[string]float64{"acme": 50, "globex": 0}},
+		{name: "missing amount", tenants: "acme", wantErr: `invalid entry "acme"`},
+		{name: "bad amount", tenants: "acme=lots", wantErr: "invalid amount for tenant acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("FINOPS_TOOLING_BUDGET", "25")
+			t.Setenv("FINOPS_TOOLING_BUDGET_TENANTS", tt.tenants)
+
+			cfg, err := LoadFromEnv()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 25.0, cfg.ToolingBudgetMonthly)
+			assert.Equal(t, tt.want, cfg.ToolingBudgetTenants)
+		})
+	}
+}
+
+func TestLoadFromEnv_TenantScheduling(t *testing.T) {
+	tests := []struct {
+		name    string
+		premium string
+		weights string
+		want    []string
+		wantW   map[string]float64
+		wantErr string
+	}{
+		{name: "none", wantW: map[string]float64{}},
+		{name: "premium and weights", premium: "acme, globex", weights: "acme=3,initech=0.5",
+			want: []string{"acme", "globex"}, wantW: map[string]float64{"acme": 3, "initech": 0.5}},
+		{name: "bad weight", weights: "acme=heavy", wantErr: "invalid weight for tenant acme"},
+		{name: "zero weight", weights: "acme=0", wantErr: "weight for tenant acme must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("FINOPS_PREMIUM_TENANTS", tt.premium)
+			t.Setenv("FINOPS_TENANT_WEIGHTS", tt.weights)
+
+			cfg, err := LoadFromEnv()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.PremiumTenants)
+			assert.Equal(t, tt.wantW, cfg.TenantWeights)
+		})
+	}
+}
+
 func clearEnv(t *testing.T) {
 	t.Helper()
 	for _, key := range []string{
 		"FINOPS_MODE", "FIXTURES_DIR", "AWS_REGION", "AWS_PROFILE",
 		"FINOPS_CROSS_ACCOUNT_ROLE", "FINOPS_CUR_DATABASE", "FINOPS_CUR_TABLE",
 		"FINOPS_CUR_WORKGROUP", "FINOPS_CUR_OUTPUT_BUCKET", "FINOPS_KUBECOST_ENDPOINT",
+		"FINOPS_BLAST_MAX_ACTIONS_PER_WORKFLOW", "FINOPS_BLAST_MAX_ACTIONS_PER_TENANT_DAY",
+		"FINOPS_BLAST_MAX_ACTIONS_PER_RESOURCE_TYPE", "FINOPS_BLAST_MAX_MONTHLY_SPEND",
+		"FINOPS_TOOLING_BUDGET", "FINOPS_TOOLING_BUDGET_TENANTS",
+		"FINOPS_COST_CACHE_ENTRIES", "FINOPS_COST_CACHE_DIR",
+		"FINOPS_RATELIMIT_STATE", "FINOPS_ACTIVITY_BUDGET", "FINOPS_ACTIVITY_BUDGET_WINDOW",
+		"FINOPS_PREMIUM_TENANTS", "FINOPS_TENANT_WEIGHTS",
 	} {
 		// t.Setenv saves the current value and restores it on cleanup.
 		// Setting to "" then unsetting ensures the key is absent during the test.
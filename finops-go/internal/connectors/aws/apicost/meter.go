@@ -0,0 +1,152 @@
+// Package apicost estimates what the desk's own AWS API calls cost and
+// attributes the spend to tenants. A Meter attaches to an aws.Config as SDK
+// middleware, so every client built from that config, including paginated
+// and retried calls, is metered without changes to the connectors.
+package apicost
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	ath "github.com/aws/aws-sdk-go-v2/service/athena"
+	athtypes "github.com/aws/aws-sdk-go-v2/service/athena/types"
+	cw "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	ce "github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+)
+
+// Prices are the list prices the Meter charges, in dollars.
+type Prices struct {
+	CostExplorerRequest float64 // per Cost Explorer API request
+	AthenaPerTB         float64 // per TB (2^40 bytes) scanned
+	AthenaMinBytes      int64   // billed minimum per query
+	// CloudWatchPerThousand is charged per 1,000 GetMetricStatistics
+	// requests and per 1,000 metrics requested through GetMetricData.
+	CloudWatchPerThousand float64
+}
+
+// DefaultPrices returns AWS us-east-1 list prices.
+func DefaultPrices() Prices {
+	return Prices{
+		CostExplorerRequest:   0.01,
+		AthenaPerTB:           5.00,
+		AthenaMinBytes:        10 << 20,
+		CloudWatchPerThousand: 0.01,
+	}
+}
+
+// Unattributed is the tenant label of calls made without WithTenant.
+const Unattributed = "unattributed"
+
+type tenantKey struct{}
+
+// WithTenant returns ctx whose AWS calls are charged to tenantID.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant AWS calls on ctx are charged to,
+// or "" if none.
+func TenantFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	return id
+}
+
+// Meter prices successful AWS API calls, records the spend in the
+// finops.api_cost.dollars metric and charges it to the calling tenant's
+// CostBudget.
+type Meter struct {
+	prices Prices
+	budget *ratelimit.CostBudget // nil = spend is not budgeted
+}
+
+// NewMeter creates a Meter charging prices.
+func NewMeter(prices Prices) *Meter {
+	return &Meter{prices: prices}
+}
+
+// SetBudget charges metered spend to b.
+func (m *Meter) SetBudget(b *ratelimit.CostBudget) {
+	m.budget = b
+}
+
+// Attach adds the metering middleware to cfg. cfg's option slice is copied
+// first, so configs shared with other clients are not affected.
+func (m *Meter) Attach(cfg *aws.Config) {
+	cfg.APIOptions = append(slices.Clone(cfg.APIOptions), func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("FinOpsAPICost", m.handleInitialize), middleware.After)
+	})
+}
+
+// handleInitialize runs once per operation, around all of its retries.
+func (m *Meter) handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	out, md, err := next.HandleInitialize(ctx, in)
+	if err != nil {
+		// AWS does not bill failed requests.
+		return out, md, err
+	}
+	service := awsmiddleware.GetServiceID(ctx)
+	if dollars := m.Estimate(service, in.Parameters, out.Result); dollars > 0 {
+		m.record(ctx, service, awsmiddleware.GetOperationName(ctx), dollars)
+	}
+	return out, md, err
+}
+
+// Estimate returns the price of one successful call to service with the
+// given input and output.
+func (m *Meter) Estimate(service string, params, result any) float64 {
+	switch service {
+	case ath.ServiceID:
+		// Queries are billed by the scan reported once they finish;
+		// failed queries are not billed.
+		out, ok := result.(*ath.GetQueryExecutionOutput)
+		if !ok || out.QueryExecution == nil || out.QueryExecution.Status == nil || out.QueryExecution.Statistics == nil {
+			return 0
+		}
+		switch out.QueryExecution.Status.State {
+		case athtypes.QueryExecutionStateSucceeded, athtypes.QueryExecutionStateCancelled:
+			return m.athenaScan(aws.ToInt64(out.QueryExecution.Statistics.DataScannedInBytes))
+		}
+		return 0
+	case cw.ServiceID:
+		switch in := params.(type) {
+		case *cw.GetMetricStatisticsInput:
+			return m.prices.CloudWatchPerThousand / 1000
+		case *cw.GetMetricDataInput:
+			var metrics int
+			for _, q := range in.MetricDataQueries {
+				if q.MetricStat != nil {
+					metrics++
+				}
+			}
+			return float64(metrics) * m.prices.CloudWatchPerThousand / 1000
+		}
+		return 0
+	case ce.ServiceID:
+		return m.prices.CostExplorerRequest
+	}
+	return 0
+}
+
+// athenaScan prices a scan of n bytes: rounded up to the megabyte, with a
+// per-query minimum.
+func (m *Meter) athenaScan(n int64) float64 {
+	const mb = 1 << 20
+	n = max((n+mb-1)/mb*mb, m.prices.AthenaMinBytes)
+	return float64(n) / (1 << 40) * m.prices.AthenaPerTB
+}
+
+func (m *Meter) record(ctx context.Context, service, operation string, dollars float64) {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == "" {
+		tenantID = Unattributed
+	} else if m.budget != nil {
+		m.budget.Record(tenantID, dollars)
+	}
+	observability.Default().RecordAPICost(ctx, tenantID, service, operation, dollars)
+}
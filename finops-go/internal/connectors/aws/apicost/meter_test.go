@@ -0,0 +1,123 @@
+package apicost
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ath "github.com/aws/aws-sdk-go-v2/service/athena"
+	athtypes "github.com/aws/aws-sdk-go-v2/service/athena/types"
+	cw "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	ce "github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	cetypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+)
+
+func queryExecution(state athtypes.QueryExecutionState, scanned int64) *ath.GetQueryExecutionOutput {
+	return &ath.GetQueryExecutionOutput{QueryExecution: &athtypes.QueryExecution{
+		Status:     &athtypes.QueryExecutionStatus{State: state},
+		Statistics: &athtypes.QueryExecutionStatistics{DataScannedInBytes: aws.Int64(scanned)},
+	}}
+}
+
+func TestEstimate(t *testing.T) {
+	m := NewMeter(DefaultPrices())
+	const tb = 1 << 40
+
+	tests := []struct {
+		name    string
+		service string
+		params  any
+		result  any
+		want    float64
+	}{
+		{"cost explorer request", ce.ServiceID, &ce.GetCostAndUsageInput{}, &ce.GetCostAndUsageOutput{}, 0.01},
+		{"athena succeeded", ath.ServiceID, nil, queryExecution(athtypes.QueryExecutionStateSucceeded, tb), 5.00},
+		{"athena minimum", ath.ServiceID, nil, queryExecution(athtypes.QueryExecutionStateSucceeded, 1), 5.00 * (10 << 20) / tb},
+		{"athena cancelled", ath.ServiceID, nil, queryExecution(athtypes.QueryExecutionStateCancelled, tb/2), 2.50},
+		{"athena failed", ath.ServiceID, nil, queryExecution(athtypes.QueryExecutionStateFailed, tb), 0},
+		{"athena running", ath.ServiceID, nil, queryExecution(athtypes.QueryExecutionStateRunning, tb), 0},
+		{"athena start", ath.ServiceID, &ath.StartQueryExecutionInput{}, &ath.StartQueryExecutionOutput{}, 0},
+		{"cloudwatch statistics", cw.ServiceID, &cw.GetMetricStatisticsInput{}, &cw.GetMetricStatisticsOutput{}, 0.00001},
+		{"cloudwatch metric data", cw.ServiceID, &cw.GetMetricDataInput{MetricDataQueries: []cwtypes.MetricDataQuery{
+			{MetricStat: &cwtypes.MetricStat{}},
+			{MetricStat: &cwtypes.MetricStat{}},
+			{Expression: aws.String("m1 + m2")},
+		}}, &cw.GetMetricDataOutput{}, 0.00002},
+		{"cloudwatch alarms", cw.ServiceID, &cw.DescribeAlarmsInput{}, &cw.DescribeAlarmsOutput{}, 0},
+		{"other service", "Resource Groups Tagging API", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, m.Estimate(tt.service, tt.params, tt.result), 1e-12)
+		})
+	}
+}
+
+// stubHTTP answers every request with body.
+type stubHTTP struct {
+	body string
+}
+
+func (s *stubHTTP) Do(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(s.body)),
+	}, nil
+}
+
+func TestMeter_Attach(t *testing.T) {
+	budget := ratelimit.NewCostBudget(0)
+	m := NewMeter(DefaultPrices())
+	m.SetBudget(budget)
+
+	newConfig := func(body string) aws.Config {
+		cfg := aws.Config{
+			Region:      "us-east-1",
+			Credentials: aws.AnonymousCredentials{},
+			HTTPClient:  &stubHTTP{body: body},
+		}
+		m.Attach(&cfg)
+		return cfg
+	}
+	ctx := WithTenant(context.Background(), "t-1")
+
+	ceClient := ce.NewFromConfig(newConfig(`{}`))
+	for range 3 {
+		_, err := ceClient.GetCostAndUsage(ctx, &ce.GetCostAndUsageInput{
+			TimePeriod:  &cetypes.DateInterval{Start: aws.String("2026-02-01"), End: aws.String("2026-02-16")},
+			Granularity: cetypes.GranularityDaily,
+			Metrics:     []string{"UnblendedCost"},
+		})
+		require.NoError(t, err)
+	}
+
+	athClient := ath.NewFromConfig(newConfig(`{"QueryExecution":{"Status":{"State":"SUCCEEDED"},"Statistics":{"DataScannedInBytes":1099511627776}}}`))
+	_, err := athClient.GetQueryExecution(ctx, &ath.GetQueryExecutionInput{QueryExecutionId: aws.String("q-1")})
+	require.NoError(t, err)
+
+	assert.InDelta(t, 3*0.01+5.00, budget.Spent("t-1"), 1e-9)
+
+	// Calls without a tenant are metered but charged to no budget.
+	_, err = athClient.GetQueryExecution(context.Background(), &ath.GetQueryExecutionInput{QueryExecutionId: aws.String("q-2")})
+	require.NoError(t, err)
+	assert.InDelta(t, 3*0.01+5.00, budget.Spent("t-1"), 1e-9)
+	assert.Zero(t, budget.Spent(Unattributed))
+}
+
+func TestMeter_AttachCopiesOptions(t *testing.T) {
+	shared := aws.Config{APIOptions: make([]func(*middleware.Stack) error, 0, 4)}
+	a, b := shared, shared
+	NewMeter(DefaultPrices()).Attach(&a)
+	assert.Len(t, a.APIOptions, 1)
+	assert.Nil(t, b.APIOptions[:1][0], "the shared backing array is untouched")
+}
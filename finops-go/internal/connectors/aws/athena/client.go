@@ -4,6 +4,8 @@ package athena
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"time"
@@ -11,6 +13,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ath "github.com/aws/aws-sdk-go-v2/service/athena"
 	athtypes "github.com/aws/aws-sdk-go-v2/service/athena/types"
+
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 )
 
 const (
@@ -25,6 +29,10 @@ type API interface {
 	GetQueryResults(ctx context.Context, params *ath.GetQueryResultsInput, optFns ...func(*ath.Options)) (*ath.GetQueryResultsOutput, error)
 }
 
+// CallWrapper runs one Athena API call made for accountID, for example
+// under a rate limiter with retries. It may call call more than once.
+type CallWrapper func(ctx context.Context, accountID string, call func(context.Context) error) error
+
 // Querier queries CUR data via Athena.
 type Querier struct {
 	api       API
@@ -32,6 +40,7 @@ type Querier struct {
 	table     string
 	workgroup string
 	outputLoc string
+	wrap      CallWrapper // nil = each call runs once
 }
 
 // New creates a Querier from an AWS config and CUR table configuration.
@@ -56,20 +65,43 @@ func NewFromAPI(api API, database, table, workgroup, outputLoc string) *Querier
 	}
 }
 
+// SetCallWrapper runs each Athena API call through w. Retrying the calls
+// one at a time, rather than the whole query, means a failed poll never
+// starts the query again and pays for a second scan.
+func (q *Querier) SetCallWrapper(w CallWrapper) {
+	q.wrap = w
+}
+
+func (q *Querier) do(ctx context.Context, accountID string, call func(context.Context) error) error {
+	if q.wrap == nil {
+		return call(ctx)
+	}
+	return q.wrap(ctx, accountID, call)
+}
+
+// newRequestToken returns a client request token for StartQueryExecution.
+// Athena starts one query per token, so retries of a start that reached
+// Athena return the query it already started.
+func newRequestToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // GetCURLineItems queries the CUR table and returns line items as []map[string]any
 // matching the fixture shape with keys: line_item_line_item_type, line_item_product_code,
 // line_item_usage_type, product_product_name, unblended_cost.
-func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string) ([]map[string]any, error) {
+func (q *Querier) GetCURLineItems(ctx context.Context, accountID, startDate, endDate, service string) ([]map[string]any, error) {
 	sql, err := buildCURQuery(q.table, accountID, startDate, endDate, service)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.TODO(), pollTimeout)
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
 	defer cancel()
 
 	// Start the query.
-	startOut, err := q.api.StartQueryExecution(ctx, &ath.StartQueryExecutionInput{
+	input := &ath.StartQueryExecutionInput{
 		QueryString: aws.String(sql),
 		QueryExecutionContext: &athtypes.QueryExecutionContext{
 			Database: aws.String(q.database),
@@ -78,8 +110,13 @@ func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string)
 		ResultConfiguration: &athtypes.ResultConfiguration{
 			OutputLocation: aws.String(q.outputLoc),
 		},
-	})
-	if err != nil {
+		ClientRequestToken: aws.String(newRequestToken()),
+	}
+	var startOut *ath.StartQueryExecutionOutput
+	if err := q.do(ctx, accountID, func(ctx context.Context) (err error) {
+		startOut, err = q.api.StartQueryExecution(ctx, input)
+		return err
+	}); err != nil {
 		return nil, fmt.Errorf("athena: start query: %w", err)
 	}
 
@@ -90,10 +127,13 @@ func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string)
 	defer ticker.Stop()
 
 	for {
-		execOut, err := q.api.GetQueryExecution(ctx, &ath.GetQueryExecutionInput{
-			QueryExecutionId: queryID,
-		})
-		if err != nil {
+		var execOut *ath.GetQueryExecutionOutput
+		if err := q.do(ctx, accountID, func(ctx context.Context) (err error) {
+			execOut, err = q.api.GetQueryExecution(ctx, &ath.GetQueryExecutionInput{
+				QueryExecutionId: queryID,
+			})
+			return err
+		}); err != nil {
 			return nil, fmt.Errorf("athena: get query execution: %w", err)
 		}
 
@@ -101,7 +141,10 @@ func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string)
 		switch state {
 		case athtypes.QueryExecutionStateSucceeded:
 			// Proceed to get results.
+			q.recordScanned(ctx, execOut.QueryExecution)
 		case athtypes.QueryExecutionStateFailed:
+			// Failed queries scan data too, though Athena does not bill it.
+			q.recordScanned(ctx, execOut.QueryExecution)
 			reason := ""
 			if execOut.QueryExecution.Status.StateChangeReason != nil {
 				reason = *execOut.QueryExecution.Status.StateChangeReason
@@ -119,10 +162,13 @@ func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string)
 		}
 
 		// Fetch results.
-		resultsOut, err := q.api.GetQueryResults(ctx, &ath.GetQueryResultsInput{
-			QueryExecutionId: queryID,
-		})
-		if err != nil {
+		var resultsOut *ath.GetQueryResultsOutput
+		if err := q.do(ctx, accountID, func(ctx context.Context) (err error) {
+			resultsOut, err = q.api.GetQueryResults(ctx, &ath.GetQueryResultsInput{
+				QueryExecutionId: queryID,
+			})
+			return err
+		}); err != nil {
 			return nil, fmt.Errorf("athena: get query results: %w", err)
 		}
 
@@ -130,6 +176,14 @@ func (q *Querier) GetCURLineItems(accountID, startDate, endDate, service string)
 	}
 }
 
+// recordScanned records the bytes a finished query scanned.
+func (q *Querier) recordScanned(ctx context.Context, qe *athtypes.QueryExecution) {
+	if qe.Statistics == nil || qe.Statistics.DataScannedInBytes == nil {
+		return
+	}
+	observability.Default().RecordAthenaBytesScanned(ctx, q.workgroup, *qe.Statistics.DataScannedInBytes)
+}
+
 // transformResults converts Athena ResultSet rows to []map[string]any.
 // The first row is the header; remaining rows are data.
 func transformResults(out *ath.GetQueryResultsOutput) []map[string]any {
@@ -19,13 +19,22 @@ type mockAthenaAPI struct {
 	execErr  error
 	resOut   *ath.GetQueryResultsOutput
 	resErr   error
+
+	starts   []*ath.StartQueryExecutionInput
+	execErrs []error // returned by the first GetQueryExecution calls
 }
 
-func (m *mockAthenaAPI) StartQueryExecution(_ context.Context, _ *ath.StartQueryExecutionInput, _ ...func(*ath.Options)) (*ath.StartQueryExecutionOutput, error) {
+func (m *mockAthenaAPI) StartQueryExecution(_ context.Context, in *ath.StartQueryExecutionInput, _ ...func(*ath.Options)) (*ath.StartQueryExecutionOutput, error) {
+	m.starts = append(m.starts, in)
 	return m.startOut, m.startErr
 }
 
 func (m *mockAthenaAPI) GetQueryExecution(_ context.Context, _ *ath.GetQueryExecutionInput, _ ...func(*ath.Options)) (*ath.GetQueryExecutionOutput, error) {
+	if len(m.execErrs) > 0 {
+		err := m.execErrs[0]
+		m.execErrs = m.execErrs[1:]
+		return nil, err
+	}
 	return m.execOut, m.execErr
 }
 
@@ -94,7 +103,7 @@ func TestGetCURLineItems(t *testing.T) {
 	}
 
 	q := NewFromAPI(mock, "cur_db", "cur_table", "primary", "s3://output")
-	items, err := q.GetCURLineItems("123456789012", "2024-01-01", "2024-01-31", "EC2")
+	items, err := q.GetCURLineItems(context.Background(), "123456789012", "2024-01-01", "2024-01-31", "EC2")
 	require.NoError(t, err)
 	require.Len(t, items, 1)
 
@@ -119,7 +128,7 @@ func TestGetCURLineItems_QueryFailed(t *testing.T) {
 	}
 
 	q := NewFromAPI(mock, "db", "tbl", "primary", "s3://out")
-	_, err := q.GetCURLineItems("123456789012", "2024-01-01", "2024-01-31", "EC2")
+	_, err := q.GetCURLineItems(context.Background(), "123456789012", "2024-01-01", "2024-01-31", "EC2")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "query failed")
 }
@@ -130,7 +139,38 @@ func TestGetCURLineItems_StartError(t *testing.T) {
 	}
 
 	q := NewFromAPI(mock, "db", "tbl", "primary", "s3://out")
-	_, err := q.GetCURLineItems("123456789012", "2024-01-01", "2024-01-31", "EC2")
+	_, err := q.GetCURLineItems(context.Background(), "123456789012", "2024-01-01", "2024-01-31", "EC2")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "start query")
 }
+
+func TestGetCURLineItems_RetriesEachCall(t *testing.T) {
+	mock := &mockAthenaAPI{
+		startOut: &ath.StartQueryExecutionOutput{QueryExecutionId: aws.String("query-123")},
+		execOut: &ath.GetQueryExecutionOutput{
+			QueryExecution: &athtypes.QueryExecution{
+				Status: &athtypes.QueryExecutionStatus{State: athtypes.QueryExecutionStateSucceeded},
+			},
+		},
+		resOut:   &ath.GetQueryResultsOutput{},
+		execErrs: []error{fmt.Errorf("throttled")},
+	}
+
+	q := NewFromAPI(mock, "db", "tbl", "primary", "s3://out")
+	var calls int
+	q.SetCallWrapper(func(ctx context.Context, accountID string, call func(context.Context) error) error {
+		assert.Equal(t, "123456789012", accountID)
+		calls++
+		if err := call(ctx); err != nil {
+			calls++
+			return call(ctx)
+		}
+		return nil
+	})
+	_, err := q.GetCURLineItems(context.Background(), "123456789012", "2024-01-01", "2024-01-31", "EC2")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, calls, "start, failed poll, retried poll, results")
+	require.Len(t, mock.starts, 1, "a failed poll does not restart the query")
+	assert.Len(t, aws.ToString(mock.starts[0].ClientRequestToken), 32)
+}
@@ -1,5 +1,6 @@
 // Package cloudwatch wraps the AWS CloudWatch API to satisfy
-// the CloudWatchMetrics portion of triage.InfraQuerier.
+// the CloudWatchMetrics portion of triage.InfraQuerier and to gather
+// alarm and metric signals for post-execution health verification.
 package cloudwatch
 
 import (
@@ -10,11 +11,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	cw "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
 )
 
 // API is the subset of the CloudWatch client used by this package.
 type API interface {
 	GetMetricStatistics(ctx context.Context, params *cw.GetMetricStatisticsInput, optFns ...func(*cw.Options)) (*cw.GetMetricStatisticsOutput, error)
+	DescribeAlarms(ctx context.Context, params *cw.DescribeAlarmsInput, optFns ...func(*cw.Options)) (*cw.DescribeAlarmsOutput, error)
 }
 
 // Client wraps the CloudWatch API.
@@ -36,17 +40,17 @@ func NewFromAPI(api API) *Client {
 // {"baseline": float64, "current": float64}
 // Baseline: average over 8d ago to 1d ago (7 full days).
 // Current: average over 1d ago to now.
-func (c *Client) CloudWatchMetrics(resourceID, metricName, namespace string) (map[string]any, error) {
+func (c *Client) CloudWatchMetrics(ctx context.Context, resourceID, metricName, namespace string) (map[string]any, error) {
 	now := time.Now().UTC()
 	oneDayAgo := now.Add(-24 * time.Hour)
 	eightDaysAgo := now.Add(-8 * 24 * time.Hour)
 
-	baseline, err := c.getAverage(resourceID, metricName, namespace, eightDaysAgo, oneDayAgo)
+	baseline, err := c.getAverage(ctx, resourceID, metricName, namespace, eightDaysAgo, oneDayAgo)
 	if err != nil {
 		return nil, fmt.Errorf("cloudwatch: baseline: %w", err)
 	}
 
-	current, err := c.getAverage(resourceID, metricName, namespace, oneDayAgo, now)
+	current, err := c.getAverage(ctx, resourceID, metricName, namespace, oneDayAgo, now)
 	if err != nil {
 		return nil, fmt.Errorf("cloudwatch: current: %w", err)
 	}
@@ -57,8 +61,8 @@ func (c *Client) CloudWatchMetrics(resourceID, metricName, namespace string) (ma
 	}, nil
 }
 
-func (c *Client) getAverage(resourceID, metricName, namespace string, start, end time.Time) (float64, error) {
-	out, err := c.api.GetMetricStatistics(context.TODO(), &cw.GetMetricStatisticsInput{
+func (c *Client) getAverage(ctx context.Context, resourceID, metricName, namespace string, start, end time.Time) (float64, error) {
+	out, err := c.api.GetMetricStatistics(ctx, &cw.GetMetricStatisticsInput{
 		Namespace:  aws.String(namespace),
 		MetricName: aws.String(metricName),
 		StartTime:  aws.Time(start),
@@ -88,3 +92,164 @@ func (c *Client) getAverage(resourceID, metricName, namespace string, start, end
 	}
 	return sum / float64(len(out.Datapoints)), nil
 }
+
+// maxAlarmPages bounds the DescribeAlarms scan in accounts with many alarms.
+const maxAlarmPages = 20
+
+// AlarmsByDimension returns metric alarms with a dimension whose value is a
+// key of resources. resources maps dimension values (e.g. "i-0abc",
+// "app/web/50dc6c495c0c9188") to the resource they identify, which is
+// reported as the alarm's resource.
+func (c *Client) AlarmsByDimension(ctx context.Context, resources map[string]string) ([]domain.AlarmSignal, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	var out []domain.AlarmSignal
+	var token *string
+	for page := 0; page < maxAlarmPages; page++ {
+		resp, err := c.api.DescribeAlarms(ctx, &cw.DescribeAlarmsInput{
+			AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm},
+			NextToken:  token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cloudwatch: describe alarms: %w", err)
+		}
+		for _, a := range resp.MetricAlarms {
+			for _, d := range a.Dimensions {
+				if res, ok := resources[aws.ToString(d.Value)]; ok {
+					out = append(out, domain.AlarmSignal{
+						Name:     aws.ToString(a.AlarmName),
+						Resource: res,
+						State:    string(a.StateValue),
+					})
+					break
+				}
+			}
+		}
+		token = resp.NextToken
+		if token == nil {
+			break
+		}
+	}
+	return out, nil
+}
+
+// AlarmsByName returns the state of the named alarms, metric or composite.
+// names maps alarm names to the resource each one watches.
+func (c *Client) AlarmsByName(ctx context.Context, names map[string]string) ([]domain.AlarmSignal, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	list := make([]string, 0, len(names))
+	for n := range names {
+		list = append(list, n)
+	}
+	var out []domain.AlarmSignal
+	// DescribeAlarms accepts at most 100 names per call.
+	for start := 0; start < len(list); start += 100 {
+		end := min(start+100, len(list))
+		resp, err := c.api.DescribeAlarms(ctx, &cw.DescribeAlarmsInput{
+			AlarmNames: list[start:end],
+			AlarmTypes: []cwtypes.AlarmType{cwtypes.AlarmTypeMetricAlarm, cwtypes.AlarmTypeCompositeAlarm},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cloudwatch: describe alarms: %w", err)
+		}
+		for _, a := range resp.MetricAlarms {
+			name := aws.ToString(a.AlarmName)
+			out = append(out, domain.AlarmSignal{Name: name, Resource: names[name], State: string(a.StateValue)})
+		}
+		for _, a := range resp.CompositeAlarms {
+			name := aws.ToString(a.AlarmName)
+			out = append(out, domain.AlarmSignal{Name: name, Resource: names[name], State: string(a.StateValue)})
+		}
+	}
+	return out, nil
+}
+
+// MetricQuery identifies one metric series.
+type MetricQuery struct {
+	Namespace  string
+	MetricName string
+	Dimensions map[string]string
+	// Statistic is Sum for counts and Average for latencies.
+	Statistic cwtypes.Statistic
+}
+
+// Window summarises a metric over a time range at 5-minute resolution.
+type Window struct {
+	// Value is the mean of the per-period statistic.
+	Value float64
+	// Total is the sum of the per-period statistic.
+	Total   float64
+	Samples int
+}
+
+// healthPeriod is the resolution of before/after comparisons.
+const healthPeriod = 300
+
+// BeforeAfter summarises q over [at-span, at), the baseline before a change
+// at at, and over the span ending at until, starting no earlier than at and
+// truncated to now. until = at+span compares the first span after the
+// change; a later until compares a trailing window.
+func (c *Client) BeforeAfter(ctx context.Context, q MetricQuery, at, until time.Time, span time.Duration) (before, after Window, err error) {
+	before, err = c.window(ctx, q, at.Add(-span), at)
+	if err != nil {
+		return Window{}, Window{}, fmt.Errorf("cloudwatch: %s before: %w", q.MetricName, err)
+	}
+	start, end := until.Add(-span), until
+	if start.Before(at) {
+		start = at
+	}
+	if now := time.Now().UTC(); end.After(now) {
+		end = now
+	}
+	if !end.After(start) {
+		return before, Window{}, nil
+	}
+	after, err = c.window(ctx, q, start, end)
+	if err != nil {
+		return Window{}, Window{}, fmt.Errorf("cloudwatch: %s after: %w", q.MetricName, err)
+	}
+	return before, after, nil
+}
+
+func (c *Client) window(ctx context.Context, q MetricQuery, start, end time.Time) (Window, error) {
+	dims := make([]cwtypes.Dimension, 0, len(q.Dimensions))
+	for k, v := range q.Dimensions {
+		dims = append(dims, cwtypes.Dimension{Name: aws.String(k), Value: aws.String(v)})
+	}
+	out, err := c.api.GetMetricStatistics(ctx, &cw.GetMetricStatisticsInput{
+		Namespace:  aws.String(q.Namespace),
+		MetricName: aws.String(q.MetricName),
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(healthPeriod),
+		Statistics: []cwtypes.Statistic{q.Statistic},
+		Dimensions: dims,
+	})
+	if err != nil {
+		return Window{}, err
+	}
+	var w Window
+	for _, dp := range out.Datapoints {
+		var v *float64
+		switch q.Statistic {
+		case cwtypes.StatisticSum:
+			v = dp.Sum
+		case cwtypes.StatisticMaximum:
+			v = dp.Maximum
+		default:
+			v = dp.Average
+		}
+		if v == nil {
+			continue
+		}
+		w.Total += *v
+		w.Samples++
+	}
+	if w.Samples > 0 {
+		w.Value = w.Total / float64(w.Samples)
+	}
+	return w, nil
+}
@@ -2,25 +2,46 @@ package cloudwatch
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	cw "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
 )
 
 type mockCWAPI struct {
-	calls int
-	outs  []*cw.GetMetricStatisticsOutput
-	err   error
+	calls  int
+	outs   []*cw.GetMetricStatisticsOutput
+	err    error
+	inputs []*cw.GetMetricStatisticsInput
+
+	alarmPages  []*cw.DescribeAlarmsOutput
+	alarmInputs []*cw.DescribeAlarmsInput
+}
+
+func (m *mockCWAPI) DescribeAlarms(_ context.Context, in *cw.DescribeAlarmsInput, _ ...func(*cw.Options)) (*cw.DescribeAlarmsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	idx := len(m.alarmInputs)
+	m.alarmInputs = append(m.alarmInputs, in)
+	if idx >= len(m.alarmPages) {
+		return &cw.DescribeAlarmsOutput{}, nil
+	}
+	return m.alarmPages[idx], nil
 }
 
-func (m *mockCWAPI) GetMetricStatistics(_ context.Context, _ *cw.GetMetricStatisticsInput, _ ...func(*cw.Options)) (*cw.GetMetricStatisticsOutput, error) {
+func (m *mockCWAPI) GetMetricStatistics(_ context.Context, in *cw.GetMetricStatisticsInput, _ ...func(*cw.Options)) (*cw.GetMetricStatisticsOutput, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.inputs = append(m.inputs, in)
 	idx := m.calls
 	if idx >= len(m.outs) {
 		idx = len(m.outs) - 1
@@ -45,7 +66,7 @@ func TestCloudWatchMetrics(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.CloudWatchMetrics("i-1234", "CPUUtilization", "AWS/EC2")
+	result, err := client.CloudWatchMetrics(context.Background(), "i-1234", "CPUUtilization", "AWS/EC2")
 	require.NoError(t, err)
 
 	// Baseline: (1000 + 1100) / 2 = 1050
@@ -63,9 +84,136 @@ func TestCloudWatchMetrics_NoDatapoints(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.CloudWatchMetrics("i-1234", "CPUUtilization", "AWS/EC2")
+	result, err := client.CloudWatchMetrics(context.Background(), "i-1234", "CPUUtilization", "AWS/EC2")
 	require.NoError(t, err)
 
 	assert.Equal(t, 0.0, result["baseline"].(float64))
 	assert.Equal(t, 0.0, result["current"].(float64))
 }
+
+func TestAlarmsByDimension(t *testing.T) {
+	mock := &mockCWAPI{
+		alarmPages: []*cw.DescribeAlarmsOutput{
+			{
+				MetricAlarms: []cwtypes.MetricAlarm{
+					{
+						AlarmName:  aws.String("web-5xx"),
+						StateValue: cwtypes.StateValueAlarm,
+						Dimensions: []cwtypes.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String("app/web/abc")}},
+					},
+					{
+						AlarmName:  aws.String("other"),
+						StateValue: cwtypes.StateValueAlarm,
+						Dimensions: []cwtypes.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-other")}},
+					},
+				},
+				NextToken: aws.String("page2"),
+			},
+			{
+				MetricAlarms: []cwtypes.MetricAlarm{
+					{
+						AlarmName:  aws.String("cpu"),
+						StateValue: cwtypes.StateValueOk,
+						Dimensions: []cwtypes.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-1")}},
+					},
+				},
+			},
+		},
+	}
+
+	client := NewFromAPI(mock)
+	alarms, err := client.AlarmsByDimension(context.Background(), map[string]string{
+		"app/web/abc": "arn:lb",
+		"i-1":         "arn:i-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.AlarmSignal{
+		{Name: "web-5xx", Resource: "arn:lb", State: "ALARM"},
+		{Name: "cpu", Resource: "arn:i-1", State: "OK"},
+	}, alarms)
+	require.Len(t, mock.alarmInputs, 2)
+	assert.Equal(t, "page2", aws.ToString(mock.alarmInputs[1].NextToken))
+}
+
+func TestAlarmsByName(t *testing.T) {
+	mock := &mockCWAPI{
+		alarmPages: []*cw.DescribeAlarmsOutput{{
+			MetricAlarms:    []cwtypes.MetricAlarm{{AlarmName: aws.String("latency"), StateValue: cwtypes.StateValueOk}},
+			CompositeAlarms: []cwtypes.CompositeAlarm{{AlarmName: aws.String("svc-health"), StateValue: cwtypes.StateValueAlarm}},
+		}},
+	}
+
+	client := NewFromAPI(mock)
+	alarms, err := client.AlarmsByName(context.Background(), map[string]string{"latency": "arn:a", "svc-health": "arn:a"})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []domain.AlarmSignal{
+		{Name: "latency", Resource: "arn:a", State: "OK"},
+		{Name: "svc-health", Resource: "arn:a", State: "ALARM"},
+	}, alarms)
+}
+
+func TestAlarms_Error(t *testing.T) {
+	client := NewFromAPI(&mockCWAPI{err: errors.New("denied")})
+	_, err := client.AlarmsByDimension(context.Background(), map[string]string{"i-1": "arn"})
+	assert.Error(t, err)
+}
+
+func TestBeforeAfter(t *testing.T) {
+	mock := &mockCWAPI{
+		outs: []*cw.GetMetricStatisticsOutput{
+			{Datapoints: []cwtypes.Datapoint{{Sum: aws.Float64(2)}, {Sum: aws.Float64(4)}}},
+			{Datapoints: []cwtypes.Datapoint{{Sum: aws.Float64(30)}}},
+		},
+	}
+	at := time.Now().UTC().Add(-2 * time.Hour)
+
+	client := NewFromAPI(mock)
+	before, after, err := client.BeforeAfter(context.Background(), MetricQuery{
+		Namespace:  "AWS/ApplicationELB",
+		MetricName: "HTTPCode_Target_5XX_Count",
+		Dimensions: map[string]string{"LoadBalancer": "app/web/abc"},
+		Statistic:  cwtypes.StatisticSum,
+	}, at, at.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, Window{Value: 3, Total: 6, Samples: 2}, before)
+	assert.Equal(t, Window{Value: 30, Total: 30, Samples: 1}, after)
+	require.Len(t, mock.inputs, 2)
+	assert.Equal(t, at, aws.ToTime(mock.inputs[0].EndTime))
+	assert.Equal(t, at, aws.ToTime(mock.inputs[1].StartTime))
+	assert.Equal(t, "LoadBalancer", aws.ToString(mock.inputs[0].Dimensions[0].Name))
+}
+
+func TestBeforeAfter_Trailing(t *testing.T) {
+	mock := &mockCWAPI{outs: []*cw.GetMetricStatisticsOutput{{}, {}}}
+	at := time.Now().UTC().Add(-72 * time.Hour)
+	until := at.Add(48 * time.Hour)
+
+	client := NewFromAPI(mock)
+	_, _, err := client.BeforeAfter(context.Background(), MetricQuery{Namespace: "AWS/Lambda", MetricName: "Errors"}, at, until, time.Hour)
+	require.NoError(t, err)
+
+	require.Len(t, mock.inputs, 2)
+	assert.Equal(t, at.Add(-time.Hour), aws.ToTime(mock.inputs[0].StartTime), "baseline stays before the change")
+	assert.Equal(t, at, aws.ToTime(mock.inputs[0].EndTime))
+	assert.Equal(t, until.Add(-time.Hour), aws.ToTime(mock.inputs[1].StartTime), "after window trails until")
+	assert.Equal(t, until, aws.ToTime(mock.inputs[1].EndTime))
+}
+
+func TestBeforeAfter_NoElapsedTime(t *testing.T) {
+	mock := &mockCWAPI{
+		outs: []*cw.GetMetricStatisticsOutput{
+			{Datapoints: []cwtypes.Datapoint{{Average: aws.Float64(0.2)}}},
+		},
+	}
+
+	client := NewFromAPI(mock)
+	_, after, err := client.BeforeAfter(context.Background(), MetricQuery{Namespace: "AWS/Lambda", MetricName: "Duration"},
+		time.Now().UTC().Add(time.Minute), time.Now().UTC().Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+
+	assert.Equal(t, Window{}, after)
+	assert.Equal(t, 1, mock.calls)
+}
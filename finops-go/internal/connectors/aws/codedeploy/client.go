@@ -34,11 +34,11 @@ func NewFromAPI(api API) *Client {
 
 // RecentDeploys returns deployments from the past 7 days in the fixture-compatible shape:
 // []map[string]any with "id" field.
-func (c *Client) RecentDeploys(service string) ([]map[string]any, error) {
+func (c *Client) RecentDeploys(ctx context.Context, service string) ([]map[string]any, error) {
 	now := time.Now().UTC()
 	sevenDaysAgo := now.Add(-7 * 24 * time.Hour)
 
-	out, err := c.api.ListDeployments(context.TODO(), &cd.ListDeploymentsInput{
+	out, err := c.api.ListDeployments(ctx, &cd.ListDeploymentsInput{
 		ApplicationName: aws.String(service),
 		CreateTimeRange: &cdtypes.TimeRange{
 			Start: aws.Time(sevenDaysAgo),
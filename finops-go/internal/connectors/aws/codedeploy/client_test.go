@@ -26,7 +26,7 @@ func TestRecentDeploys(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	deploys, err := client.RecentDeploys("my-app")
+	deploys, err := client.RecentDeploys(context.Background(), "my-app")
 	require.NoError(t, err)
 	require.Len(t, deploys, 2)
 	assert.Equal(t, "d-ABC123", deploys[0]["id"])
@@ -41,7 +41,7 @@ func TestRecentDeploys_Empty(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	deploys, err := client.RecentDeploys("my-app")
+	deploys, err := client.RecentDeploys(context.Background(), "my-app")
 	require.NoError(t, err)
 	assert.Empty(t, deploys)
 }
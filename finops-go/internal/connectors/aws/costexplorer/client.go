@@ -35,7 +35,7 @@ func NewFromAPI(api API) *Client {
 
 // GetCostTimeseries returns daily cost data for a service/account in the fixture-compatible shape:
 // {"observed_savings_daily": float64, "points": []map[string]any}
-func (c *Client) GetCostTimeseries(service, accountID, startDate, endDate string) (map[string]any, error) {
+func (c *Client) GetCostTimeseries(ctx context.Context, service, accountID, startDate, endDate string) (map[string]any, error) {
 	input := &ce.GetCostAndUsageInput{
 		TimePeriod: &cetypes.DateInterval{
 			Start: aws.String(startDate),
@@ -57,7 +57,7 @@ func (c *Client) GetCostTimeseries(service, accountID, startDate, endDate string
 		},
 	}
 
-	out, err := c.api.GetCostAndUsage(context.TODO(), input)
+	out, err := c.api.GetCostAndUsage(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("costexplorer: get cost timeseries: %w", err)
 	}
@@ -67,7 +67,7 @@ func (c *Client) GetCostTimeseries(service, accountID, startDate, endDate string
 
 // GetRICoverage returns RI coverage delta in the fixture-compatible shape:
 // {"coverage_delta": float64}
-func (c *Client) GetRICoverage(accountID, startDate, endDate string) (map[string]any, error) {
+func (c *Client) GetRICoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	input := &ce.GetReservationCoverageInput{
 		TimePeriod: &cetypes.DateInterval{
 			Start: aws.String(startDate),
@@ -82,7 +82,7 @@ func (c *Client) GetRICoverage(accountID, startDate, endDate string) (map[string
 		Granularity: cetypes.GranularityDaily,
 	}
 
-	out, err := c.api.GetReservationCoverage(context.TODO(), input)
+	out, err := c.api.GetReservationCoverage(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("costexplorer: get ri coverage: %w", err)
 	}
@@ -92,7 +92,7 @@ func (c *Client) GetRICoverage(accountID, startDate, endDate string) (map[string
 
 // GetSPCoverage returns Savings Plans coverage delta in the fixture-compatible shape:
 // {"coverage_delta": float64}
-func (c *Client) GetSPCoverage(accountID, startDate, endDate string) (map[string]any, error) {
+func (c *Client) GetSPCoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	input := &ce.GetSavingsPlansCoverageInput{
 		TimePeriod: &cetypes.DateInterval{
 			Start: aws.String(startDate),
@@ -107,7 +107,7 @@ func (c *Client) GetSPCoverage(accountID, startDate, endDate string) (map[string
 		Granularity: cetypes.GranularityDaily,
 	}
 
-	out, err := c.api.GetSavingsPlansCoverage(context.TODO(), input)
+	out, err := c.api.GetSavingsPlansCoverage(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("costexplorer: get sp coverage: %w", err)
 	}
@@ -59,7 +59,7 @@ func TestGetCostTimeseries(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.GetCostTimeseries("EC2", "123456789012", "2024-01-01", "2024-01-03")
+	result, err := client.GetCostTimeseries(context.Background(), "EC2", "123456789012", "2024-01-01", "2024-01-03")
 	require.NoError(t, err)
 
 	savings, ok := result["observed_savings_daily"].(float64)
@@ -91,7 +91,7 @@ func TestGetCostTimeseries_SinglePoint(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.GetCostTimeseries("EC2", "123456789012", "2024-01-01", "2024-01-02")
+	result, err := client.GetCostTimeseries(context.Background(), "EC2", "123456789012", "2024-01-01", "2024-01-02")
 	require.NoError(t, err)
 
 	savings := result["observed_savings_daily"].(float64)
@@ -117,7 +117,7 @@ func TestGetRICoverage(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.GetRICoverage("123456789012", "2024-01-01", "2024-01-08")
+	result, err := client.GetRICoverage(context.Background(), "123456789012", "2024-01-01", "2024-01-08")
 	require.NoError(t, err)
 
 	delta := result["coverage_delta"].(float64)
@@ -138,7 +138,7 @@ func TestGetRICoverage_SinglePeriod(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.GetRICoverage("123456789012", "2024-01-01", "2024-01-02")
+	result, err := client.GetRICoverage(context.Background(), "123456789012", "2024-01-01", "2024-01-02")
 	require.NoError(t, err)
 	assert.Equal(t, 0.0, result["coverage_delta"].(float64))
 }
@@ -158,7 +158,7 @@ func TestGetSPCoverage(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	result, err := client.GetSPCoverage("123456789012", "2024-01-01", "2024-01-08")
+	result, err := client.GetSPCoverage(context.Background(), "123456789012", "2024-01-01", "2024-01-08")
 	require.NoError(t, err)
 
 	delta := result["coverage_delta"].(float64)
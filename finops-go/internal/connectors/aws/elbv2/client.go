@@ -0,0 +1,83 @@
+// Package elbv2 wraps the Elastic Load Balancing v2 API to report target
+// health for post-execution verification.
+package elbv2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// API is the subset of the ELBv2 client used by this package.
+type API interface {
+	DescribeTargetGroups(ctx context.Context, params *elb.DescribeTargetGroupsInput, optFns ...func(*elb.Options)) (*elb.DescribeTargetGroupsOutput, error)
+	DescribeTargetHealth(ctx context.Context, params *elb.DescribeTargetHealthInput, optFns ...func(*elb.Options)) (*elb.DescribeTargetHealthOutput, error)
+}
+
+// Client wraps the ELBv2 API.
+type Client struct {
+	api API
+}
+
+// New creates an ELBv2 client from an AWS config.
+func New(cfg aws.Config) *Client {
+	return &Client{api: elb.NewFromConfig(cfg)}
+}
+
+// NewFromAPI creates a Client from an explicit API implementation (for testing).
+func NewFromAPI(api API) *Client {
+	return &Client{api: api}
+}
+
+// TargetGroups returns the ARNs of the target groups attached to a load balancer.
+func (c *Client) TargetGroups(ctx context.Context, loadBalancerARN string) ([]string, error) {
+	var arns []string
+	var marker *string
+	for {
+		out, err := c.api.DescribeTargetGroups(ctx, &elb.DescribeTargetGroupsInput{
+			LoadBalancerArn: aws.String(loadBalancerARN),
+			Marker:          marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("elbv2: describe target groups: %w", err)
+		}
+		for _, tg := range out.TargetGroups {
+			if tg.TargetGroupArn != nil {
+				arns = append(arns, *tg.TargetGroupArn)
+			}
+		}
+		if aws.ToString(out.NextMarker) == "" {
+			return arns, nil
+		}
+		marker = out.NextMarker
+	}
+}
+
+// TargetHealth counts healthy and unhealthy targets in a target group.
+// Targets that are registering, draining, or unused count as neither.
+func (c *Client) TargetHealth(ctx context.Context, targetGroupARN string) (domain.TargetSignal, error) {
+	out, err := c.api.DescribeTargetHealth(ctx, &elb.DescribeTargetHealthInput{
+		TargetGroupArn: aws.String(targetGroupARN),
+	})
+	if err != nil {
+		return domain.TargetSignal{}, fmt.Errorf("elbv2: describe target health: %w", err)
+	}
+	sig := domain.TargetSignal{TargetGroup: targetGroupARN}
+	for _, d := range out.TargetHealthDescriptions {
+		if d.TargetHealth == nil {
+			continue
+		}
+		switch d.TargetHealth.State {
+		case elbtypes.TargetHealthStateEnumHealthy:
+			sig.Healthy++
+		case elbtypes.TargetHealthStateEnumUnhealthy, elbtypes.TargetHealthStateEnumUnavailable:
+			sig.Unhealthy++
+		}
+	}
+	return sig, nil
+}
@@ -0,0 +1,79 @@
+package elbv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+type mockELBAPI struct {
+	groups  []*elb.DescribeTargetGroupsOutput
+	health  *elb.DescribeTargetHealthOutput
+	err     error
+	markers []*string
+}
+
+func (m *mockELBAPI) DescribeTargetGroups(_ context.Context, in *elb.DescribeTargetGroupsInput, _ ...func(*elb.Options)) (*elb.DescribeTargetGroupsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	idx := len(m.markers)
+	m.markers = append(m.markers, in.Marker)
+	return m.groups[idx], nil
+}
+
+func (m *mockELBAPI) DescribeTargetHealth(_ context.Context, _ *elb.DescribeTargetHealthInput, _ ...func(*elb.Options)) (*elb.DescribeTargetHealthOutput, error) {
+	return m.health, m.err
+}
+
+func targetIn(state elbtypes.TargetHealthStateEnum) elbtypes.TargetHealthDescription {
+	return elbtypes.TargetHealthDescription{TargetHealth: &elbtypes.TargetHealth{State: state}}
+}
+
+func TestTargetHealth(t *testing.T) {
+	mock := &mockELBAPI{
+		health: &elb.DescribeTargetHealthOutput{
+			TargetHealthDescriptions: []elbtypes.TargetHealthDescription{
+				targetIn(elbtypes.TargetHealthStateEnumHealthy),
+				targetIn(elbtypes.TargetHealthStateEnumHealthy),
+				targetIn(elbtypes.TargetHealthStateEnumUnhealthy),
+				targetIn(elbtypes.TargetHealthStateEnumDraining),
+				{},
+			},
+		},
+	}
+
+	client := NewFromAPI(mock)
+	sig, err := client.TargetHealth(context.Background(), "arn:tg")
+	require.NoError(t, err)
+	assert.Equal(t, domain.TargetSignal{TargetGroup: "arn:tg", Healthy: 2, Unhealthy: 1}, sig)
+}
+
+func TestTargetGroups_Paginates(t *testing.T) {
+	mock := &mockELBAPI{
+		groups: []*elb.DescribeTargetGroupsOutput{
+			{TargetGroups: []elbtypes.TargetGroup{{TargetGroupArn: aws.String("arn:tg1")}}, NextMarker: aws.String("m")},
+			{TargetGroups: []elbtypes.TargetGroup{{TargetGroupArn: aws.String("arn:tg2")}}},
+		},
+	}
+
+	client := NewFromAPI(mock)
+	arns, err := client.TargetGroups(context.Background(), "arn:lb")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arn:tg1", "arn:tg2"}, arns)
+	assert.Equal(t, "m", aws.ToString(mock.markers[1]))
+}
+
+func TestTargetHealth_Error(t *testing.T) {
+	client := NewFromAPI(&mockELBAPI{err: errors.New("denied")})
+	_, err := client.TargetHealth(context.Background(), "arn:tg")
+	assert.Error(t, err)
+}
@@ -1,5 +1,6 @@
 // Package tagging wraps the AWS Resource Groups Tagging API to satisfy
-// the ResourceTags portion of executor.TagFetcher.
+// the ResourceTags portion of executor.TagFetcher and to find resources
+// by tag.
 package tagging
 
 import (
@@ -8,6 +9,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	tag "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	tagtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 )
 
 // API is the subset of the Tagging API client used by this package.
@@ -31,8 +33,8 @@ func NewFromAPI(api API) *Client {
 }
 
 // ResourceTags returns tags for the given resource ARN as map[string]string.
-func (c *Client) ResourceTags(resourceARN string) (map[string]string, error) {
-	out, err := c.api.GetResources(context.TODO(), &tag.GetResourcesInput{
+func (c *Client) ResourceTags(ctx context.Context, resourceARN string) (map[string]string, error) {
+	out, err := c.api.GetResources(ctx, &tag.GetResourcesInput{
 		ResourceARNList: []string{resourceARN},
 	})
 	if err != nil {
@@ -52,3 +54,29 @@ func (c *Client) ResourceTags(resourceARN string) (map[string]string, error) {
 	}
 	return tags, nil
 }
+
+// TaggedResources returns the ARNs of resources of resourceType (e.g.
+// "cloudwatch:alarm") carrying the tag key=value.
+func (c *Client) TaggedResources(ctx context.Context, resourceType, key, value string) ([]string, error) {
+	var arns []string
+	var token *string
+	for {
+		out, err := c.api.GetResources(ctx, &tag.GetResourcesInput{
+			ResourceTypeFilters: []string{resourceType},
+			TagFilters:          []tagtypes.TagFilter{{Key: aws.String(key), Values: []string{value}}},
+			PaginationToken:     token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("tagging: get resources: %w", err)
+		}
+		for _, mapping := range out.ResourceTagMappingList {
+			if mapping.ResourceARN != nil {
+				arns = append(arns, *mapping.ResourceARN)
+			}
+		}
+		if aws.ToString(out.PaginationToken) == "" {
+			return arns, nil
+		}
+		token = out.PaginationToken
+	}
+}
@@ -12,11 +12,18 @@ import (
 )
 
 type mockTagAPI struct {
-	out *tag.GetResourcesOutput
-	err error
+	out    *tag.GetResourcesOutput
+	pages  []*tag.GetResourcesOutput
+	err    error
+	inputs []*tag.GetResourcesInput
 }
 
-func (m *mockTagAPI) GetResources(_ context.Context, _ *tag.GetResourcesInput, _ ...func(*tag.Options)) (*tag.GetResourcesOutput, error) {
+func (m *mockTagAPI) GetResources(_ context.Context, in *tag.GetResourcesInput, _ ...func(*tag.Options)) (*tag.GetResourcesOutput, error) {
+	idx := len(m.inputs)
+	m.inputs = append(m.inputs, in)
+	if idx < len(m.pages) {
+		return m.pages[idx], m.err
+	}
 	return m.out, m.err
 }
 
@@ -37,7 +44,7 @@ func TestResourceTags(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	tags, err := client.ResourceTags(arn)
+	tags, err := client.ResourceTags(context.Background(), arn)
 	require.NoError(t, err)
 	assert.Equal(t, "prod", tags["env"])
 	assert.Equal(t, "platform", tags["team"])
@@ -51,7 +58,33 @@ func TestResourceTags_NotFound(t *testing.T) {
 	}
 
 	client := NewFromAPI(mock)
-	tags, err := client.ResourceTags("arn:aws:ec2:us-east-1:123456789012:instance/i-missing")
+	tags, err := client.ResourceTags(context.Background(), "arn:aws:ec2:us-east-1:123456789012:instance/i-missing")
 	require.NoError(t, err)
 	assert.Empty(t, tags)
 }
+
+func TestTaggedResources(t *testing.T) {
+	mock := &mockTagAPI{
+		pages: []*tag.GetResourcesOutput{
+			{
+				ResourceTagMappingList: []tagtypes.ResourceTagMapping{{ResourceARN: aws.String("arn:aws:cloudwatch:us-east-1:123:alarm:a")}},
+				PaginationToken:        aws.String("next"),
+			},
+			{
+				ResourceTagMappingList: []tagtypes.ResourceTagMapping{{ResourceARN: aws.String("arn:aws:cloudwatch:us-east-1:123:alarm:b")}},
+			},
+		},
+	}
+
+	client := NewFromAPI(mock)
+	arns, err := client.TaggedResources(context.Background(), "cloudwatch:alarm", "finops:resource", "arn:x")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"arn:aws:cloudwatch:us-east-1:123:alarm:a",
+		"arn:aws:cloudwatch:us-east-1:123:alarm:b",
+	}, arns)
+	require.Len(t, mock.inputs, 2)
+	assert.Equal(t, []string{"cloudwatch:alarm"}, mock.inputs[0].ResourceTypeFilters)
+	assert.Equal(t, "finops:resource", aws.ToString(mock.inputs[0].TagFilters[0].Key))
+	assert.Equal(t, "next", aws.ToString(mock.inputs[1].PaginationToken))
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 )
 
 // Runner is the interface for invoking aws-doctor.
@@ -36,7 +37,7 @@ func (r *BinaryRunner) Waste(ctx context.Context, opts RunOpts) (WasteReport, er
 	args := []string{"--waste", "--output", "json"}
 	args = appendProfileRegion(args, opts)
 
-	out, err := r.run(ctx, args)
+	out, err := r.run(ctx, "Waste", args)
 	if err != nil {
 		return WasteReport{}, fmt.Errorf("aws-doctor --waste: %w", err)
 	}
@@ -53,7 +54,7 @@ func (r *BinaryRunner) Trend(ctx context.Context, opts RunOpts) (TrendReport, er
 	args := []string{"--trend", "--output", "json"}
 	args = appendProfileRegion(args, opts)
 
-	out, err := r.run(ctx, args)
+	out, err := r.run(ctx, "Trend", args)
 	if err != nil {
 		return TrendReport{}, fmt.Errorf("aws-doctor --trend: %w", err)
 	}
@@ -65,7 +66,10 @@ func (r *BinaryRunner) Trend(ctx context.Context, opts RunOpts) (TrendReport, er
 	return report, nil
 }
 
-func (r *BinaryRunner) run(ctx context.Context, args []string) ([]byte, error) {
+func (r *BinaryRunner) run(ctx context.Context, operation string, args []string) (_ []byte, err error) {
+	ctx, call := observability.StartConnectorCall(ctx, "aws-doctor", operation)
+	defer func() { call.End(err) }()
+
 	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
@@ -4,126 +4,160 @@ package connectors
 
 import (
 	"context"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/athena"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/cloudwatch"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/codedeploy"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/costexplorer"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/elbv2"
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/tagging"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
 )
 
-// rateLimitTimeout is a safety net for rate limiter waits.
-// The interface methods don't carry context, so we use a fixed timeout
-// to prevent indefinite blocking if the limiter is severely constrained.
-const rateLimitTimeout = 30 * time.Second
+// scope is the rate-limiter scope of a call for accountID made on behalf
+// of the tenant on ctx.
+func scope(ctx context.Context, accountID string) ratelimit.Scope {
+	return ratelimit.Scope{TenantID: apicost.TenantFromContext(ctx), AccountID: accountID}
+}
+
+// traced runs fn inside a connector span for system and operation. The
+// span and the recorded duration cover the rate limiter wait as well as
+// the API call, including retries.
+func traced[T any](ctx context.Context, system, operation string, fn func(context.Context) (T, error), attrs ...attribute.KeyValue) (T, error) {
+	ctx, call := observability.StartConnectorCall(ctx, system, operation, attrs...)
+	out, err := fn(ctx)
+	call.End(err)
+	return out, err
+}
+
+func accountAttr(accountID string) attribute.KeyValue {
+	return attribute.String("aws.account_id", accountID)
+}
 
 // AWSCostClient satisfies activities.CostDeps by composing Cost Explorer and Athena clients.
 type AWSCostClient struct {
 	ce      *costexplorer.Client
 	ath     *athena.Querier
 	limiter *ratelimit.ServiceLimiter // nil = no limiting
+	retry   ratelimit.RetryPolicy
 }
 
 // NewAWSCostClient creates an AWSCostClient from an AWS config and Athena CUR configuration.
+// The clients use ratelimit.SDKRetryer in place of cfg's retryer.
 func NewAWSCostClient(cfg aws.Config, curDatabase, curTable, curWorkgroup, curOutputBucket string) *AWSCostClient {
-	return &AWSCostClient{
-		ce:  costexplorer.New(cfg),
-		ath: athena.New(cfg, curDatabase, curTable, curWorkgroup, curOutputBucket),
+	cfg.Retryer = ratelimit.SDKRetryer
+	c := &AWSCostClient{
+		ce:    costexplorer.New(cfg),
+		ath:   athena.New(cfg, curDatabase, curTable, curWorkgroup, curOutputBucket),
+		retry: ratelimit.DefaultRetryPolicy(),
 	}
+	c.ath.SetCallWrapper(c.athenaCall)
+	return c
 }
 
-// SetLimiter attaches a rate limiter to the client.
+// SetLimiter attaches a rate limiter to the client. Calls wait on it per
+// tenant and account, and throttling responses slow it down.
 func (c *AWSCostClient) SetLimiter(sl *ratelimit.ServiceLimiter) {
 	c.limiter = sl
 }
 
-func (c *AWSCostClient) waitCE() error {
-	if c.limiter != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), rateLimitTimeout)
-		defer cancel()
-		return c.limiter.Wait(ctx, "CostExplorer")
-	}
-	return nil
-}
-
-func (c *AWSCostClient) waitAthena() error {
-	if c.limiter != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), rateLimitTimeout)
-		defer cancel()
-		return c.limiter.Wait(ctx, "Athena")
-	}
-	return nil
+func (c *AWSCostClient) GetRICoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return traced(ctx, "CostExplorer", "GetReservationCoverage", func(ctx context.Context) (map[string]any, error) {
+		return ratelimit.Do(ctx, c.limiter, "CostExplorer", scope(ctx, accountID), c.retry, func(ctx context.Context) (map[string]any, error) {
+			return c.ce.GetRICoverage(ctx, accountID, startDate, endDate)
+		})
+	}, accountAttr(accountID))
 }
 
-func (c *AWSCostClient) GetRICoverage(accountID, startDate, endDate string) (map[string]any, error) {
-	if err := c.waitCE(); err != nil {
-		return nil, err
-	}
-	return c.ce.GetRICoverage(accountID, startDate, endDate)
+func (c *AWSCostClient) GetSPCoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return traced(ctx, "CostExplorer", "GetSavingsPlansCoverage", func(ctx context.Context) (map[string]any, error) {
+		return ratelimit.Do(ctx, c.limiter, "CostExplorer", scope(ctx, accountID), c.retry, func(ctx context.Context) (map[string]any, error) {
+			return c.ce.GetSPCoverage(ctx, accountID, startDate, endDate)
+		})
+	}, accountAttr(accountID))
 }
 
-func (c *AWSCostClient) GetSPCoverage(accountID, startDate, endDate string) (map[string]any, error) {
-	if err := c.waitCE(); err != nil {
-		return nil, err
-	}
-	return c.ce.GetSPCoverage(accountID, startDate, endDate)
+func (c *AWSCostClient) GetCostTimeseries(ctx context.Context, service, accountID, startDate, endDate string) (map[string]any, error) {
+	return traced(ctx, "CostExplorer", "GetCostAndUsage", func(ctx context.Context) (map[string]any, error) {
+		return ratelimit.Do(ctx, c.limiter, "CostExplorer", scope(ctx, accountID), c.retry, func(ctx context.Context) (map[string]any, error) {
+			return c.ce.GetCostTimeseries(ctx, service, accountID, startDate, endDate)
+		})
+	}, accountAttr(accountID), attribute.String("finops.service", service))
 }
 
-func (c *AWSCostClient) GetCostTimeseries(service, accountID, startDate, endDate string) (map[string]any, error) {
-	if err := c.waitCE(); err != nil {
-		return nil, err
-	}
-	return c.ce.GetCostTimeseries(service, accountID, startDate, endDate)
+// GetCURLineItems runs a CUR query. The limiter and retries apply to each
+// Athena call of the query rather than to the query as a whole; see
+// athenaCall.
+func (c *AWSCostClient) GetCURLineItems(ctx context.Context, accountID, startDate, endDate, service string) ([]map[string]any, error) {
+	return traced(ctx, "Athena", "GetCURLineItems", func(ctx context.Context) ([]map[string]any, error) {
+		return c.ath.GetCURLineItems(ctx, accountID, startDate, endDate, service)
+	}, accountAttr(accountID), attribute.String("finops.service", service))
 }
 
-func (c *AWSCostClient) GetCURLineItems(accountID, startDate, endDate, service string) ([]map[string]any, error) {
-	if err := c.waitAthena(); err != nil {
-		return nil, err
-	}
-	return c.ath.GetCURLineItems(accountID, startDate, endDate, service)
+// athenaCall runs one Athena API call under the limiter and retry policy.
+func (c *AWSCostClient) athenaCall(ctx context.Context, accountID string, call func(context.Context) error) error {
+	_, err := ratelimit.Do(ctx, c.limiter, "Athena", scope(ctx, accountID), c.retry, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
 }
 
-// AWSInfraClient satisfies activities.InfraDeps by composing CloudWatch, Tagging, and CodeDeploy clients.
+// AWSInfraClient satisfies activities.InfraDeps by composing CloudWatch, Tagging,
+// CodeDeploy, and ELBv2 clients.
 type AWSInfraClient struct {
 	cw      *cloudwatch.Client
 	tg      *tagging.Client
 	cd      *codedeploy.Client
+	elb     *elbv2.Client
 	limiter *ratelimit.ServiceLimiter // nil = no limiting
+	retry   ratelimit.RetryPolicy
 }
 
-// NewAWSInfraClient creates an AWSInfraClient from an AWS config.
+// NewAWSInfraClient creates an AWSInfraClient from an AWS config. The
+// clients use ratelimit.SDKRetryer in place of cfg's retryer.
 func NewAWSInfraClient(cfg aws.Config) *AWSInfraClient {
+	cfg.Retryer = ratelimit.SDKRetryer
 	return &AWSInfraClient{
-		cw: cloudwatch.New(cfg),
-		tg: tagging.New(cfg),
-		cd: codedeploy.New(cfg),
+		cw:    cloudwatch.New(cfg),
+		tg:    tagging.New(cfg),
+		cd:    codedeploy.New(cfg),
+		elb:   elbv2.New(cfg),
+		retry: ratelimit.DefaultRetryPolicy(),
 	}
 }
 
-// SetLimiter attaches a rate limiter to the client.
+// SetLimiter attaches a rate limiter to the client. Calls wait on it per
+// tenant and account, and throttling responses slow it down.
 func (c *AWSInfraClient) SetLimiter(sl *ratelimit.ServiceLimiter) {
 	c.limiter = sl
 }
 
-func (c *AWSInfraClient) RecentDeploys(service string) ([]map[string]any, error) {
-	return c.cd.RecentDeploys(service)
+func (c *AWSInfraClient) RecentDeploys(ctx context.Context, service string) ([]map[string]any, error) {
+	return traced(ctx, "CodeDeploy", "ListDeployments", func(ctx context.Context) ([]map[string]any, error) {
+		return ratelimit.Do(ctx, c.limiter, "CodeDeploy", scope(ctx, ""), c.retry, func(ctx context.Context) ([]map[string]any, error) {
+			return c.cd.RecentDeploys(ctx, service)
+		})
+	}, attribute.String("finops.service", service))
 }
 
-func (c *AWSInfraClient) CloudWatchMetrics(resourceID, metricName, namespace string) (map[string]any, error) {
-	if c.limiter != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), rateLimitTimeout)
-		defer cancel()
-		if err := c.limiter.Wait(ctx, "CloudWatch"); err != nil {
-			return nil, err
-		}
-	}
-	return c.cw.CloudWatchMetrics(resourceID, metricName, namespace)
+func (c *AWSInfraClient) CloudWatchMetrics(ctx context.Context, resourceID, metricName, namespace string) (map[string]any, error) {
+	return traced(ctx, "CloudWatch", "GetMetricStatistics", func(ctx context.Context) (map[string]any, error) {
+		return ratelimit.Do(ctx, c.limiter, "CloudWatch", scope(ctx, ""), c.retry, func(ctx context.Context) (map[string]any, error) {
+			return c.cw.CloudWatchMetrics(ctx, resourceID, metricName, namespace)
+		})
+	}, attribute.String("cloudwatch.namespace", namespace), attribute.String("cloudwatch.metric", metricName))
 }
 
-func (c *AWSInfraClient) ResourceTags(resourceARN string) (map[string]string, error) {
-	return c.tg.ResourceTags(resourceARN)
+func (c *AWSInfraClient) ResourceTags(ctx context.Context, resourceARN string) (map[string]string, error) {
+	r, _ := parseARN(resourceARN)
+	return traced(ctx, "Tagging", "GetResources", func(ctx context.Context) (map[string]string, error) {
+		return ratelimit.Do(ctx, c.limiter, "Tagging", scope(ctx, r.account), c.retry, func(ctx context.Context) (map[string]string, error) {
+			return c.tg.ResourceTags(ctx, resourceARN)
+		})
+	}, attribute.String("aws.resource_arn", resourceARN))
 }
@@ -0,0 +1,307 @@
+package connectors
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// CacheTTLs sets how long cached cost data stays fresh, by how recent the
+// queried window is. Cost Explorer and the CUR revise the last few days
+// several times a day, and the month's data until it closes.
+type CacheTTLs struct {
+	Recent  time.Duration // window ends within the last 3 days
+	Current time.Duration // window ends within the last 35 days
+	Settled time.Duration // older windows
+}
+
+const (
+	recentAge  = 3 * 24 * time.Hour
+	currentAge = 35 * 24 * time.Hour
+)
+
+// DefaultCacheTTLs returns TTLs matching how often AWS refreshes cost data.
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Recent:  time.Hour,
+		Current: 6 * time.Hour,
+		Settled: 7 * 24 * time.Hour,
+	}
+}
+
+// ttl returns the TTL of a window ending on end (YYYY-MM-DD). An
+// unparseable end gets the shortest TTL.
+func (t CacheTTLs) ttl(end string, now time.Time) time.Duration {
+	e, err := time.Parse(time.DateOnly, end)
+	if err != nil {
+		return t.Recent
+	}
+	switch age := now.Sub(e); {
+	case age < recentAge:
+		return t.Recent
+	case age < currentAge:
+		return t.Current
+	}
+	return t.Settled
+}
+
+// CacheOptions configures a CachedCostClient.
+type CacheOptions struct {
+	Entries int    // in-memory LRU capacity; <= 0 = 1024
+	Dir     string // on-disk tier; "" = memory only
+	TTLs    CacheTTLs
+}
+
+// CachedCostClient decorates a cost client with a cache, so triage,
+// planning and verification of one anomaly query Cost Explorer and Athena
+// once per account and window. Entries are keyed by tenant (from
+// apicost.WithTenant), operation and query arguments. Lookups go to an
+// in-memory LRU, then to the optional disk tier, which survives worker
+// restarts. Concurrent identical misses share one upstream call. Errors
+// are not cached.
+//
+// Cached values are shared between callers and must not be modified.
+type CachedCostClient struct {
+	next  activities.CostDeps
+	ttls  CacheTTLs
+	mem   *lruCache
+	disk  *diskCache // nil = memory only
+	group singleflight.Group
+	now   func() time.Time
+}
+
+// Compile-time check.
+var _ activities.CostDeps = (*CachedCostClient)(nil)
+
+// NewCachedCostClient wraps next in a cache configured by opts.
+func NewCachedCostClient(next activities.CostDeps, opts CacheOptions) (*CachedCostClient, error) {
+	if opts.Entries <= 0 {
+		opts.Entries = 1024
+	}
+	c := &CachedCostClient{
+		next: next,
+		ttls: opts.TTLs,
+		mem:  newLRUCache(opts.Entries),
+		now:  time.Now,
+	}
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+			return nil, fmt.Errorf("connectors: create cache dir: %w", err)
+		}
+		c.disk = &diskCache{dir: opts.Dir}
+	}
+	return c, nil
+}
+
+func (c *CachedCostClient) GetRICoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return cachedCall(ctx, c, "GetRICoverage", endDate, func(ctx context.Context) (map[string]any, error) {
+		return c.next.GetRICoverage(ctx, accountID, startDate, endDate)
+	}, accountID, startDate, endDate)
+}
+
+func (c *CachedCostClient) GetSPCoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return cachedCall(ctx, c, "GetSPCoverage", endDate, func(ctx context.Context) (map[string]any, error) {
+		return c.next.GetSPCoverage(ctx, accountID, startDate, endDate)
+	}, accountID, startDate, endDate)
+}
+
+func (c *CachedCostClient) GetCostTimeseries(ctx context.Context, service, accountID, startDate, endDate string) (map[string]any, error) {
+	return cachedCall(ctx, c, "GetCostTimeseries", endDate, func(ctx context.Context) (map[string]any, error) {
+		return c.next.GetCostTimeseries(ctx, service, accountID, startDate, endDate)
+	}, service, accountID, startDate, endDate)
+}
+
+func (c *CachedCostClient) GetCURLineItems(ctx context.Context, accountID, startDate, endDate, service string) ([]map[string]any, error) {
+	return cachedCall(ctx, c, "GetCURLineItems", endDate, func(ctx context.Context) ([]map[string]any, error) {
+		return c.next.GetCURLineItems(ctx, accountID, startDate, endDate, service)
+	}, accountID, startDate, endDate, service)
+}
+
+// cacheResult is what a lookup was answered from, as reported in
+// finops.cache.requests. Callers that waited on another caller's
+// identical miss report "shared".
+type cacheResult struct {
+	value  any
+	source string
+}
+
+// cachedCall answers operation from the cache or, on a miss, from fetch.
+// end is the last day of the queried window and picks the entry's TTL.
+func cachedCall[T any](ctx context.Context, c *CachedCostClient, operation, end string, fetch func(context.Context) (T, error), args ...string) (T, error) {
+	key := strings.Join(append([]string{apicost.TenantFromContext(ctx), operation}, args...), "|")
+	now := c.now()
+	if v, ok := c.mem.get(key, now); ok {
+		observability.Default().RecordCacheLookup(ctx, "cost", operation, "memory")
+		return v.(T), nil
+	}
+
+	leader := false
+	v, err, _ := c.group.Do(key, func() (any, error) {
+		leader = true
+		if c.disk != nil {
+			var out T
+			if expires, ok := c.disk.get(key, now, &out); ok {
+				c.mem.add(key, out, expires)
+				return cacheResult{out, "disk"}, nil
+			}
+		}
+		out, err := fetch(ctx)
+		if err != nil {
+			return cacheResult{source: "miss"}, err
+		}
+		expires := now.Add(c.ttls.ttl(end, now))
+		c.mem.add(key, out, expires)
+		if c.disk != nil {
+			if err := c.disk.put(key, out, expires); err != nil {
+				slog.WarnContext(ctx, "cost cache: disk write failed", "operation", operation, "error", err)
+			}
+		}
+		return cacheResult{out, "miss"}, nil
+	})
+	res := v.(cacheResult)
+	if !leader {
+		res.source = "shared"
+	}
+	observability.Default().RecordCacheLookup(ctx, "cost", operation, res.source)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return res.value.(T), nil
+}
+
+// lruCache is a size-bounded map evicting the least recently used entry.
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // front = most recently used
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{size: size, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (l *lruCache) get(key string, now time.Time) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !now.Before(e.expires) {
+		l.order.Remove(el)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (l *lruCache) add(key string, value any, expires time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		el.Value = &lruEntry{key: key, value: value, expires: expires}
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// diskCache keeps one JSON file per entry, named by the hash of its key.
+// Unreadable or expired files count as misses.
+type diskCache struct {
+	dir string
+}
+
+type diskEntry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+func (d *diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get decodes the entry for key into out and returns its expiry.
+func (d *diskCache) get(key string, now time.Time, out any) (time.Time, bool) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("cost cache: disk read failed", "path", path, "error", err)
+		}
+		return time.Time{}, false
+	}
+	var e diskEntry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return time.Time{}, false
+	}
+	if !now.Before(e.Expires) {
+		_ = os.Remove(path)
+		return time.Time{}, false
+	}
+	if err := json.Unmarshal(e.Value, out); err != nil {
+		return time.Time{}, false
+	}
+	return e.Expires, true
+}
+
+// put writes the entry through a temporary file, so readers never see a
+// partial entry.
+func (d *diskCache) put(key string, value any, expires time.Time) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	data, err := json.Marshal(diskEntry{Key: key, Expires: expires, Value: raw})
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), d.path(key))
+}
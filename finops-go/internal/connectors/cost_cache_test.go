@@ -0,0 +1,214 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
+)
+
+// countingCost answers every query with its arguments and counts calls.
+// Calls block on release when it is set.
+type countingCost struct {
+	calls   atomic.Int64
+	err     error
+	release chan struct{}
+}
+
+func (c *countingCost) answer(args ...string) (map[string]any, error) {
+	c.calls.Add(1)
+	if c.release != nil {
+		<-c.release
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return map[string]any{"args": args, "total": 12.5}, nil
+}
+
+func (c *countingCost) GetRICoverage(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return c.answer("ri", accountID, startDate, endDate)
+}
+
+func (c *countingCost) GetSPCoverage(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
+	return c.answer("sp", accountID, startDate, endDate)
+}
+
+func (c *countingCost) GetCostTimeseries(_ context.Context, service, accountID, startDate, endDate string) (map[string]any, error) {
+	return c.answer("ts", service, accountID, startDate, endDate)
+}
+
+func (c *countingCost) GetCURLineItems(_ context.Context, accountID, startDate, endDate, service string) ([]map[string]any, error) {
+	m, err := c.answer("cur", accountID, startDate, endDate, service)
+	if err != nil {
+		return nil, err
+	}
+	return []map[string]any{m}, nil
+}
+
+var cacheNow = time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+
+func newTestCache(t *testing.T, next *countingCost, opts CacheOptions) *CachedCostClient {
+	t.Helper()
+	if opts.TTLs == (CacheTTLs{}) {
+		opts.TTLs = DefaultCacheTTLs()
+	}
+	c, err := NewCachedCostClient(next, opts)
+	require.NoError(t, err)
+	c.now = func() time.Time { return cacheNow }
+	return c
+}
+
+func TestCacheTTLs(t *testing.T) {
+	ttls := DefaultCacheTTLs()
+	tests := []struct {
+		end  string
+		want time.Duration
+	}{
+		{"2026-03-21", time.Hour},
+		{"2026-03-18", time.Hour},
+		{"2026-03-01", 6 * time.Hour},
+		{"2026-01-31", 7 * 24 * time.Hour},
+		{"not-a-date", time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.end, func(t *testing.T) {
+			assert.Equal(t, tt.want, ttls.ttl(tt.end, cacheNow))
+		})
+	}
+}
+
+func TestCachedCostClient_Memory(t *testing.T) {
+	next := &countingCost{}
+	c := newTestCache(t, next, CacheOptions{})
+	ctx := context.Background()
+
+	first, err := c.GetCostTimeseries(ctx, "AmazonEC2", "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	second, err := c.GetCostTimeseries(ctx, "AmazonEC2", "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.EqualValues(t, 1, next.calls.Load())
+
+	_, err = c.GetCostTimeseries(ctx, "AmazonRDS", "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	_, err = c.GetRICoverage(ctx, "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, next.calls.Load(), "query and operation are part of the key")
+
+	// The window ended a day ago, so the entry lives an hour.
+	c.now = func() time.Time { return cacheNow.Add(time.Hour) }
+	_, err = c.GetCostTimeseries(ctx, "AmazonEC2", "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, next.calls.Load(), "expired entries are refetched")
+}
+
+func TestCachedCostClient_TenantKeying(t *testing.T) {
+	next := &countingCost{}
+	c := newTestCache(t, next, CacheOptions{})
+
+	for _, tenant := range []string{"t-1", "t-2", "t-1"} {
+		ctx := apicost.WithTenant(context.Background(), tenant)
+		_, err := c.GetCURLineItems(ctx, "123", "2026-03-01", "2026-03-19", "AmazonEC2")
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 2, next.calls.Load())
+}
+
+func TestCachedCostClient_Eviction(t *testing.T) {
+	next := &countingCost{}
+	c := newTestCache(t, next, CacheOptions{Entries: 2})
+	ctx := context.Background()
+
+	get := func(account string) {
+		t.Helper()
+		_, err := c.GetSPCoverage(ctx, account, "2026-01-01", "2026-01-31")
+		require.NoError(t, err)
+	}
+	get("a")
+	get("b")
+	get("a") // a is now the most recently used
+	get("c") // evicts b
+	assert.EqualValues(t, 3, next.calls.Load())
+
+	get("a")
+	assert.EqualValues(t, 3, next.calls.Load())
+	get("b")
+	assert.EqualValues(t, 4, next.calls.Load())
+}
+
+func TestCachedCostClient_ErrorsNotCached(t *testing.T) {
+	next := &countingCost{err: errors.New("throttled")}
+	c := newTestCache(t, next, CacheOptions{})
+	ctx := context.Background()
+
+	_, err := c.GetRICoverage(ctx, "123", "2026-03-01", "2026-03-19")
+	require.Error(t, err)
+
+	next.err = nil
+	got, err := c.GetRICoverage(ctx, "123", "2026-03-01", "2026-03-19")
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.EqualValues(t, 2, next.calls.Load())
+}
+
+func TestCachedCostClient_Disk(t *testing.T) {
+	dir := t.TempDir()
+	ctx := apicost.WithTenant(context.Background(), "t-1")
+
+	warm := &countingCost{}
+	_, err := newTestCache(t, warm, CacheOptions{Dir: dir}).GetCURLineItems(ctx, "123", "2026-01-01", "2026-01-31", "AmazonEC2")
+	require.NoError(t, err)
+
+	// A new client, as after a restart, is served from disk.
+	cold := &countingCost{}
+	c := newTestCache(t, cold, CacheOptions{Dir: dir})
+	items, err := c.GetCURLineItems(ctx, "123", "2026-01-01", "2026-01-31", "AmazonEC2")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 12.5, items[0]["total"])
+	assert.Zero(t, cold.calls.Load())
+
+	// Settled windows live a week, on disk as in memory.
+	c = newTestCache(t, cold, CacheOptions{Dir: dir})
+	c.now = func() time.Time { return cacheNow.Add(8 * 24 * time.Hour) }
+	_, err = c.GetCURLineItems(ctx, "123", "2026-01-01", "2026-01-31", "AmazonEC2")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, cold.calls.Load())
+}
+
+func TestCachedCostClient_Singleflight(t *testing.T) {
+	next := &countingCost{release: make(chan struct{})}
+	c := newTestCache(t, next, CacheOptions{})
+	ctx := context.Background()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]map[string]any, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := c.GetCostTimeseries(ctx, "AmazonEC2", "123", "2026-03-01", "2026-03-19")
+			assert.NoError(t, err)
+			results[i] = res
+		}()
+	}
+	require.Eventually(t, func() bool { return next.calls.Load() == 1 }, time.Second, time.Millisecond)
+	// Let the other callers reach the in-flight call before releasing it.
+	time.Sleep(10 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, next.calls.Load())
+	for _, res := range results {
+		assert.Equal(t, results[0], res)
+	}
+}
@@ -0,0 +1,313 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/cloudwatch"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+	"github.com/finops-claw-gang/finops-go/internal/verifier"
+)
+
+// HealthAlarmTag associates a CloudWatch alarm with a resource whose metrics
+// it does not carry as a dimension (for example a service-level composite
+// alarm). The tag value is the resource ARN.
+const HealthAlarmTag = "finops:resource"
+
+// arnResource is the parsed service and resource part of an ARN.
+type arnResource struct {
+	arn      string
+	service  string
+	account  string
+	resource string
+}
+
+func parseARN(arn string) (arnResource, bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return arnResource{}, false
+	}
+	return arnResource{arn: arn, service: parts[2], account: parts[4], resource: parts[5]}, true
+}
+
+// dimensionValue is the value CloudWatch uses to identify the resource in
+// metric dimensions: "app/name/id" for load balancers, "targetgroup/name/id"
+// for target groups, and the trailing ID or name for everything else.
+func (r arnResource) dimensionValue() string {
+	if r.service == "elasticloadbalancing" {
+		if v, ok := strings.CutPrefix(r.resource, "loadbalancer/"); ok {
+			return v
+		}
+		return r.resource
+	}
+	if i := strings.LastIndexAny(r.resource, "/:"); i >= 0 {
+		return r.resource[i+1:]
+	}
+	return r.resource
+}
+
+// HealthSignals implements verifier.HealthChecker. For each changed resource
+// it collects alarms that reference it by dimension or by HealthAlarmTag,
+// before/after error, 5xx, and latency metrics for load balancers, Lambda
+// functions, and EC2 instances, and target health for load balancers and
+// target groups.
+func (c *AWSInfraClient) HealthSignals(ctx context.Context, resourceARNs []string, executedAt, until time.Time) (domain.HealthSignals, error) {
+	var sig domain.HealthSignals
+
+	byDimension := make(map[string]string)
+	var resources []arnResource
+	for _, arn := range resourceARNs {
+		r, ok := parseARN(arn)
+		if !ok {
+			continue
+		}
+		resources = append(resources, r)
+		byDimension[r.dimensionValue()] = arn
+	}
+	if len(resources) == 0 {
+		return sig, nil
+	}
+
+	alarms, err := traced(ctx, "CloudWatch", "DescribeAlarms", func(ctx context.Context) ([]domain.AlarmSignal, error) {
+		return ratelimit.Do(ctx, c.limiter, "CloudWatch", scope(ctx, ""), c.retry, func(ctx context.Context) ([]domain.AlarmSignal, error) {
+			return c.cw.AlarmsByDimension(ctx, byDimension)
+		})
+	})
+	if err != nil {
+		return sig, err
+	}
+	sig.Alarms = alarms
+
+	tagged, err := c.taggedAlarms(ctx, resources)
+	if err != nil {
+		return sig, err
+	}
+	seen := make(map[string]bool, len(alarms))
+	for _, a := range alarms {
+		seen[a.Name] = true
+	}
+	for name := range tagged {
+		if seen[name] {
+			delete(tagged, name)
+		}
+	}
+	if len(tagged) > 0 {
+		named, err := traced(ctx, "CloudWatch", "DescribeAlarms", func(ctx context.Context) ([]domain.AlarmSignal, error) {
+			return ratelimit.Do(ctx, c.limiter, "CloudWatch", scope(ctx, ""), c.retry, func(ctx context.Context) ([]domain.AlarmSignal, error) {
+				return c.cw.AlarmsByName(ctx, tagged)
+			})
+		})
+		if err != nil {
+			return sig, err
+		}
+		sig.Alarms = append(sig.Alarms, named...)
+	}
+
+	for _, r := range resources {
+		metrics, err := c.metricSignals(ctx, r, executedAt, until)
+		if err != nil {
+			return sig, err
+		}
+		sig.Metrics = append(sig.Metrics, metrics...)
+
+		targets, err := c.targetSignals(ctx, r)
+		if err != nil {
+			return sig, err
+		}
+		sig.Targets = append(sig.Targets, targets...)
+	}
+	return sig, nil
+}
+
+// taggedAlarms maps the names of alarms tagged with HealthAlarmTag to the
+// resource they watch.
+func (c *AWSInfraClient) taggedAlarms(ctx context.Context, resources []arnResource) (map[string]string, error) {
+	names := make(map[string]string)
+	for _, r := range resources {
+		arns, err := traced(ctx, "Tagging", "GetResources", func(ctx context.Context) ([]string, error) {
+			return ratelimit.Do(ctx, c.limiter, "Tagging", scope(ctx, r.account), c.retry, func(ctx context.Context) ([]string, error) {
+				return c.tg.TaggedResources(ctx, "cloudwatch:alarm", HealthAlarmTag, r.arn)
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, alarmARN := range arns {
+			if _, name, ok := strings.Cut(alarmARN, ":alarm:"); ok {
+				names[name] = r.arn
+			}
+		}
+	}
+	return names, nil
+}
+
+// serviceMetrics describes the metrics compared for one kind of resource.
+// Counts share the request metric's sample count because CloudWatch omits
+// zero-valued error datapoints.
+type serviceMetrics struct {
+	namespace string
+	dimension string
+	requests  string // Sum; empty when the service has no request count
+	errors    string // Sum, or Maximum for status checks
+	fiveXX    bool   // errors are HTTP 5xx counts
+	latency   string // Average
+}
+
+func metricsFor(r arnResource) (serviceMetrics, bool) {
+	switch {
+	case r.service == "elasticloadbalancing" && strings.HasPrefix(r.resource, "loadbalancer/app/"):
+		return serviceMetrics{
+			namespace: "AWS/ApplicationELB",
+			dimension: "LoadBalancer",
+			requests:  "RequestCount",
+			errors:    "HTTPCode_Target_5XX_Count",
+			fiveXX:    true,
+			latency:   "TargetResponseTime",
+		}, true
+	case r.service == "lambda" && strings.HasPrefix(r.resource, "function:"):
+		return serviceMetrics{
+			namespace: "AWS/Lambda",
+			dimension: "FunctionName",
+			requests:  "Invocations",
+			errors:    "Errors",
+			latency:   "Duration",
+		}, true
+	case r.service == "ec2" && strings.HasPrefix(r.resource, "instance/"):
+		return serviceMetrics{
+			namespace: "AWS/EC2",
+			dimension: "InstanceId",
+			errors:    "StatusCheckFailed",
+		}, true
+	}
+	return serviceMetrics{}, false
+}
+
+func (c *AWSInfraClient) metricSignals(ctx context.Context, r arnResource, executedAt, until time.Time) ([]domain.MetricSignal, error) {
+	m, ok := metricsFor(r)
+	if !ok {
+		return nil, nil
+	}
+	dims := map[string]string{m.dimension: r.dimensionValue()}
+	query := func(name string, stat cwtypes.Statistic) (cloudwatch.Window, cloudwatch.Window, error) {
+		w, err := traced(ctx, "CloudWatch", "GetMetricStatistics", func(ctx context.Context) ([2]cloudwatch.Window, error) {
+			return ratelimit.Do(ctx, c.limiter, "CloudWatch", scope(ctx, r.account), c.retry, func(ctx context.Context) ([2]cloudwatch.Window, error) {
+				before, after, err := c.cw.BeforeAfter(ctx, cloudwatch.MetricQuery{
+					Namespace:  m.namespace,
+					MetricName: name,
+					Dimensions: dims,
+					Statistic:  stat,
+				}, executedAt, until, verifier.HealthSpan)
+				return [2]cloudwatch.Window{before, after}, err
+			})
+		}, attribute.String("cloudwatch.namespace", m.namespace), attribute.String("cloudwatch.metric", name))
+		return w[0], w[1], err
+	}
+
+	var out []domain.MetricSignal
+
+	if m.requests == "" {
+		// Status checks: the mean of per-period maxima is the failing fraction.
+		before, after, err := query(m.errors, cwtypes.StatisticMaximum)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, domain.MetricSignal{
+			Resource: r.arn, Name: m.errors, Kind: domain.MetricErrorRate,
+			Before: before.Value, After: after.Value,
+			BeforeSamples: before.Samples, AfterSamples: after.Samples,
+		})
+		return out, nil
+	}
+
+	reqBefore, reqAfter, err := query(m.requests, cwtypes.StatisticSum)
+	if err != nil {
+		return nil, err
+	}
+	errBefore, errAfter, err := query(m.errors, cwtypes.StatisticSum)
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, domain.MetricSignal{
+		Resource: r.arn, Name: m.errors + "/" + m.requests, Kind: domain.MetricErrorRate,
+		Before: ratio(errBefore.Total, reqBefore.Total), After: ratio(errAfter.Total, reqAfter.Total),
+		BeforeSamples: reqBefore.Samples, AfterSamples: reqAfter.Samples,
+	})
+	if m.fiveXX {
+		// Per-period means keep windows of different lengths comparable.
+		out = append(out, domain.MetricSignal{
+			Resource: r.arn, Name: m.errors, Kind: domain.Metric5xx,
+			Before: perPeriod(errBefore.Total, reqBefore.Samples), After: perPeriod(errAfter.Total, reqAfter.Samples),
+			BeforeSamples: reqBefore.Samples, AfterSamples: reqAfter.Samples,
+		})
+	}
+
+	latBefore, latAfter, err := query(m.latency, cwtypes.StatisticAverage)
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, domain.MetricSignal{
+		Resource: r.arn, Name: m.latency, Kind: domain.MetricLatency,
+		Before: latBefore.Value, After: latAfter.Value,
+		BeforeSamples: latBefore.Samples, AfterSamples: latAfter.Samples,
+	})
+	return out, nil
+}
+
+func (c *AWSInfraClient) targetSignals(ctx context.Context, r arnResource) ([]domain.TargetSignal, error) {
+	if r.service != "elasticloadbalancing" {
+		return nil, nil
+	}
+	var groups []string
+	switch {
+	case strings.HasPrefix(r.resource, "targetgroup/"):
+		groups = []string{r.arn}
+	case strings.HasPrefix(r.resource, "loadbalancer/"):
+		var err error
+		groups, err = traced(ctx, "ELBv2", "DescribeTargetGroups", func(ctx context.Context) ([]string, error) {
+			return ratelimit.Do(ctx, c.limiter, "ELBv2", scope(ctx, r.account), c.retry, func(ctx context.Context) ([]string, error) {
+				return c.elb.TargetGroups(ctx, r.arn)
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	sort.Strings(groups)
+
+	out := make([]domain.TargetSignal, 0, len(groups))
+	for _, tg := range groups {
+		s, err := traced(ctx, "ELBv2", "DescribeTargetHealth", func(ctx context.Context) (domain.TargetSignal, error) {
+			return ratelimit.Do(ctx, c.limiter, "ELBv2", scope(ctx, r.account), c.retry, func(ctx context.Context) (domain.TargetSignal, error) {
+				return c.elb.TargetHealth(ctx, tg)
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("target health for %s: %w", r.arn, err)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func ratio(num, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+func perPeriod(total float64, samples int) float64 {
+	if samples == 0 {
+		return 0
+	}
+	return total / float64(samples)
+}
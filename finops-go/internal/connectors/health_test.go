@@ -0,0 +1,192 @@
+package connectors
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cw "github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	tag "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	tagtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/cloudwatch"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/elbv2"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/tagging"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/verifier"
+)
+
+const (
+	testLB = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/abc"
+	testTG = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/web/def"
+)
+
+// fakeCW serves alarms and per-metric datapoints. Datapoints before the
+// execution time come from before, after it from after.
+type fakeCW struct {
+	alarms     []cwtypes.MetricAlarm
+	named      []cwtypes.CompositeAlarm
+	executedAt time.Time
+	before     map[string][]cwtypes.Datapoint
+	after      map[string][]cwtypes.Datapoint
+}
+
+func (f *fakeCW) GetMetricStatistics(_ context.Context, in *cw.GetMetricStatisticsInput, _ ...func(*cw.Options)) (*cw.GetMetricStatisticsOutput, error) {
+	src := f.after
+	if aws.ToTime(in.StartTime).Before(f.executedAt) {
+		src = f.before
+	}
+	return &cw.GetMetricStatisticsOutput{Datapoints: src[aws.ToString(in.MetricName)]}, nil
+}
+
+func (f *fakeCW) DescribeAlarms(_ context.Context, in *cw.DescribeAlarmsInput, _ ...func(*cw.Options)) (*cw.DescribeAlarmsOutput, error) {
+	if len(in.AlarmNames) > 0 {
+		return &cw.DescribeAlarmsOutput{CompositeAlarms: f.named}, nil
+	}
+	return &cw.DescribeAlarmsOutput{MetricAlarms: f.alarms}, nil
+}
+
+type fakeTagging struct{ alarmARNs []string }
+
+func (f *fakeTagging) GetResources(_ context.Context, _ *tag.GetResourcesInput, _ ...func(*tag.Options)) (*tag.GetResourcesOutput, error) {
+	out := &tag.GetResourcesOutput{}
+	for _, arn := range f.alarmARNs {
+		out.ResourceTagMappingList = append(out.ResourceTagMappingList, tagtypes.ResourceTagMapping{ResourceARN: aws.String(arn)})
+	}
+	return out, nil
+}
+
+type fakeELB struct{ unhealthy int }
+
+func (f *fakeELB) DescribeTargetGroups(_ context.Context, _ *elb.DescribeTargetGroupsInput, _ ...func(*elb.Options)) (*elb.DescribeTargetGroupsOutput, error) {
+	return &elb.DescribeTargetGroupsOutput{TargetGroups: []elbtypes.TargetGroup{{TargetGroupArn: aws.String(testTG)}}}, nil
+}
+
+func (f *fakeELB) DescribeTargetHealth(_ context.Context, _ *elb.DescribeTargetHealthInput, _ ...func(*elb.Options)) (*elb.DescribeTargetHealthOutput, error) {
+	out := &elb.DescribeTargetHealthOutput{}
+	for i := 0; i < 3; i++ {
+		state := elbtypes.TargetHealthStateEnumHealthy
+		if i < f.unhealthy {
+			state = elbtypes.TargetHealthStateEnumUnhealthy
+		}
+		out.TargetHealthDescriptions = append(out.TargetHealthDescriptions,
+			elbtypes.TargetHealthDescription{TargetHealth: &elbtypes.TargetHealth{State: state}})
+	}
+	return out, nil
+}
+
+func sums(n int, v float64) []cwtypes.Datapoint {
+	out := make([]cwtypes.Datapoint, n)
+	for i := range out {
+		out[i] = cwtypes.Datapoint{Sum: aws.Float64(v), Average: aws.Float64(v)}
+	}
+	return out
+}
+
+func TestHealthSignals_LoadBalancer(t *testing.T) {
+	executedAt := time.Now().UTC().Add(-2 * time.Hour)
+	cwAPI := &fakeCW{
+		executedAt: executedAt,
+		alarms: []cwtypes.MetricAlarm{{
+			AlarmName:  aws.String("web-5xx"),
+			StateValue: cwtypes.StateValueOk,
+			Dimensions: []cwtypes.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String("app/web/abc")}},
+		}},
+		named: []cwtypes.CompositeAlarm{{AlarmName: aws.String("checkout-slo"), StateValue: cwtypes.StateValueAlarm}},
+		before: map[string][]cwtypes.Datapoint{
+			"RequestCount":              sums(12, 1000),
+			"HTTPCode_Target_5XX_Count": sums(2, 6),
+			"TargetResponseTime":        sums(12, 0.2),
+		},
+		after: map[string][]cwtypes.Datapoint{
+			"RequestCount":              sums(12, 1000),
+			"HTTPCode_Target_5XX_Count": sums(12, 100),
+			"TargetResponseTime":        sums(12, 0.2),
+		},
+	}
+	c := &AWSInfraClient{
+		cw:  cloudwatch.NewFromAPI(cwAPI),
+		tg:  tagging.NewFromAPI(&fakeTagging{alarmARNs: []string{"arn:aws:cloudwatch:us-east-1:123456789012:alarm:checkout-slo"}}),
+		elb: elbv2.NewFromAPI(&fakeELB{unhealthy: 1}),
+	}
+
+	sig, err := c.HealthSignals(context.Background(), []string{testLB}, executedAt, executedAt.Add(verifier.HealthSpan))
+	if err != nil {
+		t.Fatalf("HealthSignals: %v", err)
+	}
+
+	wantAlarms := []domain.AlarmSignal{
+		{Name: "web-5xx", Resource: testLB, State: "OK"},
+		{Name: "checkout-slo", Resource: testLB, State: "ALARM"},
+	}
+	if len(sig.Alarms) != len(wantAlarms) {
+		t.Fatalf("alarms = %+v, want %+v", sig.Alarms, wantAlarms)
+	}
+	for i, a := range wantAlarms {
+		if sig.Alarms[i] != a {
+			t.Errorf("alarm %d = %+v, want %+v", i, sig.Alarms[i], a)
+		}
+	}
+
+	byKind := map[domain.MetricKind]domain.MetricSignal{}
+	for _, m := range sig.Metrics {
+		byKind[m.Kind] = m
+	}
+	rate := byKind[domain.MetricErrorRate]
+	if !near(rate.Before, 0.001) || !near(rate.After, 0.1) || rate.BeforeSamples != 12 {
+		t.Errorf("error rate = %+v", rate)
+	}
+	fiveXX := byKind[domain.Metric5xx]
+	if !near(fiveXX.Before, 1) || !near(fiveXX.After, 100) {
+		t.Errorf("5xx per period = %+v", fiveXX)
+	}
+	if lat := byKind[domain.MetricLatency]; !near(lat.Before, 0.2) || !near(lat.After, 0.2) {
+		t.Errorf("latency = %+v", lat)
+	}
+
+	if len(sig.Targets) != 1 || sig.Targets[0] != (domain.TargetSignal{TargetGroup: testTG, Healthy: 2, Unhealthy: 1}) {
+		t.Errorf("targets = %+v", sig.Targets)
+	}
+}
+
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
+
+func TestHealthSignals_SkipsNonARNs(t *testing.T) {
+	c := &AWSInfraClient{}
+	sig, err := c.HealthSignals(context.Background(), []string{"budget:EC2:123456789012"}, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("HealthSignals: %v", err)
+	}
+	if len(sig.Alarms)+len(sig.Metrics)+len(sig.Targets) != 0 {
+		t.Errorf("expected no signals, got %+v", sig)
+	}
+}
+
+func TestDimensionValue(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want string
+	}{
+		{testLB, "app/web/abc"},
+		{testTG, "targetgroup/web/def"},
+		{"arn:aws:lambda:us-east-1:123456789012:function:resize", "resize"},
+		{"arn:aws:ec2:us-east-1:123456789012:instance/i-0abc", "i-0abc"},
+		{"arn:aws:rds:us-east-1:123456789012:db:orders", "orders"},
+	}
+	for _, tt := range tests {
+		r, ok := parseARN(tt.arn)
+		if !ok {
+			t.Fatalf("parseARN(%q) failed", tt.arn)
+		}
+		if got := r.dimensionValue(); got != tt.want {
+			t.Errorf("dimensionValue(%q) = %q, want %q", tt.arn, got, tt.want)
+		}
+	}
+}
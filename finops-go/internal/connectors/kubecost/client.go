@@ -3,17 +3,41 @@
 package kubecost
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
 )
 
 // Client queries the KubeCost allocation API.
 type Client struct {
 	endpoint   string
 	httpClient *http.Client
+	limiter    *ratelimit.ServiceLimiter // nil = no limiting
+	retry      ratelimit.RetryPolicy
+}
+
+// StatusError is returned when KubeCost answers with a status other than
+// 200 OK.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("kubecost: unexpected status %d", e.StatusCode)
+}
+
+// HTTPStatusCode lets ratelimit classify throttling and server errors.
+func (e *StatusError) HTTPStatusCode() int {
+	return e.StatusCode
 }
 
 // New creates a KubeCost client with the given endpoint URL.
@@ -23,6 +47,7 @@ func New(endpoint string) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		retry: ratelimit.DefaultRetryPolicy(),
 	}
 }
 
@@ -31,11 +56,29 @@ func NewWithHTTPClient(endpoint string, httpClient *http.Client) *Client {
 	return &Client{
 		endpoint:   endpoint,
 		httpClient: httpClient,
+		retry:      ratelimit.DefaultRetryPolicy(),
 	}
 }
 
+// SetLimiter attaches a rate limiter to the client. Calls wait on it per
+// tenant, and 429 responses slow it down.
+func (c *Client) SetLimiter(sl *ratelimit.ServiceLimiter) {
+	c.limiter = sl
+}
+
 // Allocation queries the KubeCost /model/allocation endpoint.
-func (c *Client) Allocation(window, aggregate string) (map[string]any, error) {
+func (c *Client) Allocation(ctx context.Context, window, aggregate string) (_ map[string]any, err error) {
+	ctx, call := observability.StartConnectorCall(ctx, "KubeCost", "Allocation",
+		attribute.String("kubecost.window", window), attribute.String("kubecost.aggregate", aggregate))
+	defer func() { call.End(err) }()
+
+	scope := ratelimit.Scope{TenantID: apicost.TenantFromContext(ctx)}
+	return ratelimit.Do(ctx, c.limiter, "KubeCost", scope, c.retry, func(ctx context.Context) (map[string]any, error) {
+		return c.allocation(ctx, window, aggregate)
+	})
+}
+
+func (c *Client) allocation(ctx context.Context, window, aggregate string) (map[string]any, error) {
 	u, err := url.Parse(c.endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("kubecost: invalid endpoint: %w", err)
@@ -46,14 +89,18 @@ func (c *Client) Allocation(window, aggregate string) (map[string]any, error) {
 	q.Set("aggregate", aggregate)
 	u.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Get(u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("kubecost: build request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("kubecost: request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("kubecost: unexpected status %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var result map[string]any
@@ -1,13 +1,24 @@
 package kubecost
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
 )
 
 func TestAllocation(t *testing.T) {
@@ -30,7 +41,7 @@ func TestAllocation(t *testing.T) {
 	defer srv.Close()
 
 	client := NewWithHTTPClient(srv.URL, srv.Client())
-	result, err := client.Allocation("7d", "namespace")
+	result, err := client.Allocation(context.Background(), "7d", "namespace")
 	require.NoError(t, err)
 
 	allocs, ok := result["allocations"].(map[string]any)
@@ -40,14 +51,69 @@ func TestAllocation(t *testing.T) {
 	assert.InDelta(t, 120.5, ns["totalCost"].(float64), 0.01)
 }
 
+// fastRetry keeps retry tests quick.
+var fastRetry = ratelimit.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
 func TestAllocation_ServerError(t *testing.T) {
+	var calls atomic.Int32
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer srv.Close()
 
 	client := NewWithHTTPClient(srv.URL, srv.Client())
-	_, err := client.Allocation("7d", "namespace")
+	client.retry = fastRetry
+	_, err := client.Allocation(context.Background(), "7d", "namespace")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unexpected status 500")
+	assert.EqualValues(t, 3, calls.Load(), "server errors are retried")
+}
+
+func TestAllocation_Throttled(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"allocations":{}}`))
+	}))
+	defer srv.Close()
+
+	sl := ratelimit.NewServiceLimiter(ratelimit.ServiceRates{KubeCost: 100})
+	client := NewWithHTTPClient(srv.URL, srv.Client())
+	client.retry = fastRetry
+	client.SetLimiter(sl)
+
+	ctx := apicost.WithTenant(context.Background(), "t-1")
+	result, err := client.Allocation(ctx, "7d", "namespace")
+	require.NoError(t, err)
+	assert.Contains(t, result, "allocations")
+	assert.EqualValues(t, 2, calls.Load())
+	assert.Less(t, sl.Rate("KubeCost", ratelimit.Scope{TenantID: "t-1"}), 50.0, "the 429 slowed the tenant's bucket")
+}
+
+func TestAllocation_Span(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := NewWithHTTPClient(srv.URL, srv.Client())
+	client.retry = fastRetry
+	_, err := client.Allocation(context.Background(), "24h", "namespace")
+	require.Error(t, err)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "KubeCost Allocation", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/finops-claw-gang/finops-go/internal/connectors/aws"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 )
@@ -16,6 +17,7 @@ type TenantClientFactory struct {
 	curTable        string
 	curWorkgroup    string
 	curOutputBucket string
+	meter           *apicost.Meter // nil = API calls not metered
 }
 
 // Compile-time check.
@@ -35,12 +37,20 @@ func NewTenantClientFactory(
 	}
 }
 
+// SetCostMeter meters the AWS API calls of the clients the factory creates.
+func (f *TenantClientFactory) SetCostMeter(m *apicost.Meter) {
+	f.meter = m
+}
+
 // CostClient creates a per-tenant AWSCostClient.
 func (f *TenantClientFactory) CostClient(ctx context.Context, tenant domain.TenantContext) (activities.CostDeps, error) {
 	cfg, err := f.provider.ForTenant(ctx, tenant.TenantID, tenant.IAMRoleARN, tenant.DefaultRegion)
 	if err != nil {
 		return nil, err
 	}
+	if f.meter != nil {
+		f.meter.Attach(&cfg)
+	}
 	return NewAWSCostClient(cfg, f.curDatabase, f.curTable, f.curWorkgroup, f.curOutputBucket), nil
 }
 
@@ -50,5 +60,8 @@ func (f *TenantClientFactory) InfraClient(ctx context.Context, tenant domain.Ten
 	if err != nil {
 		return nil, err
 	}
+	if f.meter != nil {
+		f.meter.Attach(&cfg)
+	}
 	return NewAWSInfraClient(cfg), nil
 }
@@ -122,3 +122,63 @@ func (v VerificationRecommendation) Valid() bool {
 	}
 	return false
 }
+
+// ExecutionOutcome records what happened to a single action during execution.
+type ExecutionOutcome string
+
+const (
+	OutcomeSucceeded      ExecutionOutcome = "succeeded"
+	OutcomeAlreadyApplied ExecutionOutcome = "already_applied"
+	OutcomeFailed         ExecutionOutcome = "failed"
+	OutcomeSkipped        ExecutionOutcome = "skipped"
+	OutcomeRolledBack     ExecutionOutcome = "rolled_back"
+)
+
+func (o ExecutionOutcome) Valid() bool {
+	switch o {
+	case OutcomeSucceeded, OutcomeAlreadyApplied, OutcomeFailed, OutcomeSkipped, OutcomeRolledBack:
+		return true
+	}
+	return false
+}
+
+// FailurePolicy decides what the lifecycle does with the remaining actions
+// after one of them fails.
+type FailurePolicy string
+
+const (
+	// FailureContinue executes the remaining actions anyway.
+	FailureContinue FailurePolicy = "continue"
+	// FailureSkipRemaining leaves completed actions in place and skips the rest.
+	FailureSkipRemaining FailurePolicy = "skip_remaining"
+	// FailureRollback skips the rest and rolls back completed actions in reverse order.
+	FailureRollback FailurePolicy = "rollback"
+)
+
+func (f FailurePolicy) Valid() bool {
+	switch f {
+	case FailureContinue, FailureSkipRemaining, FailureRollback:
+		return true
+	}
+	return false
+}
+
+// MetricKind classifies a health metric so the right threshold applies.
+type MetricKind string
+
+const (
+	// MetricErrorRate is a ratio in [0, 1] (errors / requests).
+	MetricErrorRate MetricKind = "error_rate"
+	// MetricLatency is a response time in any consistent unit.
+	MetricLatency MetricKind = "latency"
+	// Metric5xx is a count of server errors per window.
+	Metric5xx MetricKind = "5xx"
+)
+
+func (m MetricKind) Valid() bool {
+	switch m {
+	case MetricErrorRate, MetricLatency, Metric5xx:
+		return true
+	}
+	return false
+}
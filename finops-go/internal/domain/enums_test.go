@@ -228,3 +228,49 @@ func TestVerificationRecommendationValid(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutionOutcomeValid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		outcome ExecutionOutcome
+		valid   bool
+	}{
+		{name: "succeeded", outcome: OutcomeSucceeded, valid: true},
+		{name: "already_applied", outcome: OutcomeAlreadyApplied, valid: true},
+		{name: "failed", outcome: OutcomeFailed, valid: true},
+		{name: "skipped", outcome: OutcomeSkipped, valid: true},
+		{name: "rolled_back", outcome: OutcomeRolledBack, valid: true},
+		{name: "bogus", outcome: ExecutionOutcome("bogus"), valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.outcome.Valid(); got != tt.valid {
+				t.Errorf("ExecutionOutcome(%q).Valid() = %v, want %v", tt.outcome, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestFailurePolicyValid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		policy FailurePolicy
+		valid  bool
+	}{
+		{name: "continue", policy: FailureContinue, valid: true},
+		{name: "skip_remaining", policy: FailureSkipRemaining, valid: true},
+		{name: "rollback", policy: FailureRollback, valid: true},
+		{name: "empty", policy: FailurePolicy(""), valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.policy.Valid(); got != tt.valid {
+				t.Errorf("FailurePolicy(%q).Valid() = %v, want %v", tt.policy, got, tt.valid)
+			}
+		})
+	}
+}
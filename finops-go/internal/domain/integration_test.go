@@ -3,6 +3,7 @@ package domain_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/finops-claw-gang/finops-go/internal/analysis"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
@@ -18,6 +19,7 @@ import (
 // This is the Go equivalent of the Python graph's auto-approve path.
 func TestEndToEndPipeline(t *testing.T) {
 	t.Parallel()
+	ctx := context.Background()
 	goldenDir := testutil.GoldenDir()
 	cost := &testutil.StubCost{FixturesDir: goldenDir}
 	infra := &testutil.StubInfra{FixturesDir: goldenDir}
@@ -40,7 +42,7 @@ func TestEndToEndPipeline(t *testing.T) {
 	}
 
 	// 2. Triage
-	triageResult, err := triage.Triage(context.Background(), anomaly, cost, infra, kube, nil, "", "")
+	triageResult, err := triage.Triage(ctx, anomaly, cost, infra, kube, nil, "", "")
 	if err != nil {
 		t.Fatalf("triage: %v", err)
 	}
@@ -57,7 +59,7 @@ func TestEndToEndPipeline(t *testing.T) {
 
 	// 3. Analysis
 	analysisResult, err := analysis.AnalyzeAndRecommend(
-		anomaly.AccountID, anomaly.Service, "2026-02-01", "2026-02-16", cost,
+		ctx, anomaly.AccountID, anomaly.Service, "2026-02-01", "2026-02-16", cost,
 	)
 	if err != nil {
 		t.Fatalf("analysis: %v", err)
@@ -84,7 +86,7 @@ func TestEndToEndPipeline(t *testing.T) {
 	tagsByARN := make(map[string]map[string]string)
 	for _, a := range analysisResult.RecommendedActions {
 		if a.TargetResource != "" {
-			tags, err := infra.ResourceTags(a.TargetResource)
+			tags, err := infra.ResourceTags(ctx, a.TargetResource)
 			if err != nil {
 				t.Fatalf("resource tags: %v", err)
 			}
@@ -93,7 +95,7 @@ func TestEndToEndPipeline(t *testing.T) {
 	}
 
 	exec := executor.NewExecutor(infra)
-	execResults, err := exec.ExecuteActions(decision.Approval, analysisResult.RecommendedActions, tagsByARN)
+	execResults, err := exec.ExecuteActions(ctx, "wf-integration", "tenant-1", decision.Approval, analysisResult.RecommendedActions, tagsByARN)
 	if err != nil {
 		t.Fatalf("executor: %v", err)
 	}
@@ -109,7 +111,14 @@ func TestEndToEndPipeline(t *testing.T) {
 	}
 
 	// 6. Verify
-	verifyResult, err := verifier.Verify(anomaly.Service, anomaly.AccountID, cost, "2026-02-01", "2026-02-16")
+	var changed []string
+	for _, a := range analysisResult.RecommendedActions {
+		if a.TargetResource != "" {
+			changed = append(changed, a.TargetResource)
+		}
+	}
+	verifyResult, err := verifier.Verify(ctx, anomaly.Service, anomaly.AccountID, cost, infra,
+		verifier.Change{Resources: changed, ExecutedAt: time.Now().UTC()}, "2026-02-01", "2026-02-16")
 	if err != nil {
 		t.Fatalf("verifier: %v", err)
 	}
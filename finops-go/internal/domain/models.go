@@ -126,14 +126,18 @@ type AnalysisResult struct {
 }
 
 // ExecutionResult records the outcome of executing an action.
+// IdempotencyKey is derived from the workflow ID and ActionID so a retried
+// or replayed execution can be recognised as the same logical attempt.
 type ExecutionResult struct {
-	ActionID           string         `json:"action_id"`
-	ExecutedAt         string         `json:"executed_at"`
-	Success            bool           `json:"success"`
-	Details            string         `json:"details"`
-	RollbackAvailable  bool           `json:"rollback_available"`
-	PreActionSnapshot  map[string]any `json:"pre_action_snapshot"`
-	PostActionSnapshot map[string]any `json:"post_action_snapshot"`
+	ActionID           string           `json:"action_id"`
+	IdempotencyKey     string           `json:"idempotency_key,omitempty"`
+	ExecutedAt         string           `json:"executed_at"`
+	Success            bool             `json:"success"`
+	Outcome            ExecutionOutcome `json:"outcome,omitempty"`
+	Details            string           `json:"details"`
+	RollbackAvailable  bool             `json:"rollback_available"`
+	PreActionSnapshot  map[string]any   `json:"pre_action_snapshot"`
+	PostActionSnapshot map[string]any   `json:"post_action_snapshot"`
 }
 
 // VerificationResult records the outcome of post-execution verification.
@@ -144,6 +148,93 @@ type VerificationResult struct {
 	ServiceHealthOK       bool                       `json:"service_health_ok"`
 	HealthCheckDetails    string                     `json:"health_check_details"`
 	Recommendation        VerificationRecommendation `json:"recommendation"`
+	// SavingsChecks are the delayed savings verifications, oldest first.
+	SavingsChecks []SavingsVerification `json:"savings_checks,omitempty"`
+}
+
+// SavingsVerification compares mean daily spend after execution with the
+// pre-execution baseline. SavingsDaily is baseline minus post (positive
+// means savings) with a 95% confidence interval; Significant means the
+// interval excludes zero.
+type SavingsVerification struct {
+	CheckedAt            string                     `json:"checked_at"`
+	DaysAfter            int                        `json:"days_after"`
+	BaselineDays         int                        `json:"baseline_days"`
+	PostDays             int                        `json:"post_days"`
+	BaselineDailyMean    float64                    `json:"baseline_daily_mean"`
+	PostDailyMean        float64                    `json:"post_daily_mean"`
+	SavingsDaily         float64                    `json:"savings_daily"`
+	CILow                float64                    `json:"ci_low"`
+	CIHigh               float64                    `json:"ci_high"`
+	Significant          bool                       `json:"significant"`
+	ExpectedSavingsDaily float64                    `json:"expected_savings_daily"`
+	RealizationPct       float64                    `json:"realization_pct"`
+	Details              string                     `json:"details"`
+	Recommendation       VerificationRecommendation `json:"recommendation"`
+}
+
+// MonitoringStatus tracks daily post-change monitoring. Day is the current
+// monitoring day (1..TotalDays); its check runs at StartedAt plus Day days.
+// The workflow carries the status across continue-as-new runs.
+type MonitoringStatus struct {
+	Day                    int      `json:"day"`
+	TotalDays              int      `json:"total_days"`
+	StartedAt              string   `json:"started_at"`
+	ExecutedAt             string   `json:"executed_at"`
+	NextCheckAt            string   `json:"next_check_at,omitempty"`
+	Resources              []string `json:"resources,omitempty"`
+	ExpectedSavingsMonthly float64  `json:"expected_savings_monthly"`
+	// InconclusiveChecks counts consecutive health checks that could not
+	// tell whether the changed resources are healthy.
+	InconclusiveChecks int `json:"inconclusive_checks,omitempty"`
+	// Outcome explains why monitoring ended; empty while it is running.
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// Label renders progress for display, e.g. "monitoring day 3/14".
+func (m MonitoringStatus) Label() string {
+	return fmt.Sprintf("monitoring day %d/%d", m.Day, m.TotalDays)
+}
+
+// CloudWatch alarm states reported in AlarmSignal.State.
+const (
+	AlarmStateOK               = "OK"
+	AlarmStateAlarm            = "ALARM"
+	AlarmStateInsufficientData = "INSUFFICIENT_DATA"
+)
+
+// AlarmSignal is the current state of an alarm associated with a changed
+// resource, either through its metric dimensions or through a tag.
+type AlarmSignal struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	State    string `json:"state"`
+}
+
+// MetricSignal compares a metric before and after execution. Samples are
+// the number of datapoints behind each value.
+type MetricSignal struct {
+	Resource      string     `json:"resource"`
+	Name          string     `json:"name"`
+	Kind          MetricKind `json:"kind"`
+	Before        float64    `json:"before"`
+	After         float64    `json:"after"`
+	BeforeSamples int        `json:"before_samples"`
+	AfterSamples  int        `json:"after_samples"`
+}
+
+// TargetSignal is the registered-target health of a load balancer target group.
+type TargetSignal struct {
+	TargetGroup string `json:"target_group"`
+	Healthy     int    `json:"healthy"`
+	Unhealthy   int    `json:"unhealthy"`
+}
+
+// HealthSignals is everything observed about the health of changed resources.
+type HealthSignals struct {
+	Alarms  []AlarmSignal  `json:"alarms,omitempty"`
+	Metrics []MetricSignal `json:"metrics,omitempty"`
+	Targets []TargetSignal `json:"targets,omitempty"`
 }
 
 // TenantContext identifies a tenant and their cloud accounts.
@@ -153,6 +244,13 @@ type TenantContext struct {
 	DefaultRegion          string `json:"default_region"`
 	IAMRoleARN             string `json:"iam_role_arn"`
 	KubecostBaseURL        string `json:"kubecost_base_url"`
+
+	// Premium tenants run their lifecycle activities on dedicated task
+	// queues (versioning.TenantQueue) instead of the shared ones.
+	Premium bool `json:"premium,omitempty"`
+	// Weight is the tenant's share of the shared queues relative to other
+	// tenants. Zero means 1.
+	Weight float64 `json:"weight,omitempty"`
 }
 
 // NewTenantContext creates a TenantContext with sensible defaults.
@@ -163,6 +261,18 @@ func NewTenantContext(tenantID string) TenantContext {
 	}
 }
 
+// Approver records who answered the approval gate. By is the approver's
+// name. Subject, Email and Issuer are the caller's authenticated identity,
+// and By equals Subject when they are set; without authentication only By
+// is known, as the caller gave it. Issuer is empty for an MCP caller
+// configured rather than verified by token.
+type Approver struct {
+	By      string `json:"by"`
+	Subject string `json:"subject,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Issuer  string `json:"issuer,omitempty"`
+}
+
 // FinOpsState is the top-level workflow state, analogous to the LangGraph state schema.
 type FinOpsState struct {
 	WorkflowID string `json:"workflow_id"`
@@ -175,10 +285,19 @@ type FinOpsState struct {
 
 	Approval        ApprovalStatus `json:"approval"`
 	ApprovalDetails string         `json:"approval_details"`
+	Approver        *Approver      `json:"approver,omitempty"`
+
+	// ScheduledFor is set (RFC3339) while approved actions wait for the
+	// next change window.
+	ScheduledFor string `json:"scheduled_for,omitempty"`
 
 	Executions   []ExecutionResult   `json:"executions"`
 	Verification *VerificationResult `json:"verification"`
 
+	// Monitoring is set once a "monitor" recommendation enters daily
+	// post-change monitoring.
+	Monitoring *MonitoringStatus `json:"monitoring,omitempty"`
+
 	CurrentPhase    string  `json:"current_phase"`
 	ShouldTerminate bool    `json:"should_terminate"`
 	Error           *string `json:"error"`
@@ -1,29 +1,118 @@
 package executor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/finops-claw-gang/finops-go/internal/calendar"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/policy"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
 )
 
+// ErrRefused wraps safety-gate refusals. Callers use errors.Is to tell a
+// refusal (never retry) from a transient handler failure (safe to retry).
+var ErrRefused = errors.New("executor: refused by safety gate")
+
 // Executor performs deterministic action execution. It takes pre/post
 // snapshots and calls the policy safety gate before any action.
 type Executor struct {
-	tags TagFetcher
+	tags     TagFetcher
+	handlers map[string]ActionHandler
+	fallback ActionHandler
+	ledger   Ledger
+	calendar *calendar.Calendar
+	kill     KillSwitch
+	limits   policy.BlastRadiusLimits
+	rules    policy.ProtectionRuleset
+	now      func() time.Time
+	daily    ratelimit.Store // actions applied per tenant and UTC day
+}
+
+// ExecuteRequest describes one action to execute.
+type ExecuteRequest struct {
+	IdempotencyKey string
+	TenantID       string
+	Approval       domain.ApprovalStatus
+	Action         domain.RecommendedAction
+	// Plan is every action in the owning workflow, used for plan-level
+	// blast-radius limits. Nil means Action alone.
+	Plan         []domain.RecommendedAction
+	ResourceTags map[string]string
 }
 
 // NewExecutor creates an Executor backed by the given TagFetcher.
+// Action types without a registered handler use a stub handler, and
+// completed executions are recorded in an in-memory ledger.
 func NewExecutor(tags TagFetcher) *Executor {
-	return &Executor{tags: tags}
+	return &Executor{
+		tags:     tags,
+		handlers: make(map[string]ActionHandler),
+		fallback: stubHandler{},
+		ledger:   NewMemoryLedger(),
+		now:      time.Now,
+		daily:    ratelimit.NewMemoryStore(),
+	}
+}
+
+// RegisterHandler installs the handler for an action type.
+func (e *Executor) RegisterHandler(actionType string, h ActionHandler) {
+	e.handlers[actionType] = h
+}
+
+// SetLedger replaces the idempotency ledger.
+func (e *Executor) SetLedger(l Ledger) {
+	e.ledger = l
+}
+
+// SetKillSwitch installs the kill switch checked before every action.
+func (e *Executor) SetKillSwitch(k KillSwitch) {
+	e.kill = k
+}
+
+// SetBlastRadius sets the blast-radius limits enforced by the safety gate.
+// The zero value (the default) is unlimited.
+func (e *Executor) SetBlastRadius(l policy.BlastRadiusLimits) {
+	e.limits = l
+}
+
+// SetProtectionRules sets the protection rules the safety gate enforces
+// in addition to the built-in ones.
+func (e *Executor) SetProtectionRules(rs policy.ProtectionRuleset) {
+	e.rules = rs
+}
+
+// SetStore keeps the per-tenant daily action counts in s, so that replicas
+// sharing it enforce MaxActionsPerTenantPerDay across the fleet and keep
+// the counts across restarts. The default store is in memory.
+func (e *Executor) SetStore(s ratelimit.Store) {
+	e.daily = s
+}
+
+// SetCalendar installs the change calendar consulted by the safety gate.
+// A nil calendar allows changes at any time.
+func (e *Executor) SetCalendar(c *calendar.Calendar) {
+	e.calendar = c
+}
+
+func (e *Executor) handlerFor(actionType string) ActionHandler {
+	if h, ok := e.handlers[actionType]; ok {
+		return h
+	}
+	return e.fallback
+}
+
+func (e *Executor) timestamp() string {
+	return e.now().UTC().Format(time.RFC3339)
 }
 
 // Snapshot captures the pre- or post-action state for the given action.
 // If the action has a target resource, the snapshot includes its tags.
-func (e *Executor) Snapshot(action domain.RecommendedAction) (map[string]any, error) {
+func (e *Executor) Snapshot(ctx context.Context, action domain.RecommendedAction) (map[string]any, error) {
 	if action.TargetResource != "" {
-		tags, err := e.tags.ResourceTags(action.TargetResource)
+		tags, err := e.tags.ResourceTags(ctx, action.TargetResource)
 		if err != nil {
 			return nil, fmt.Errorf("executor: snapshot tags for %s: %w", action.TargetResource, err)
 		}
@@ -32,35 +121,270 @@ func (e *Executor) Snapshot(action domain.RecommendedAction) (map[string]any, er
 	return map[string]any{}, nil
 }
 
-// ExecuteActions runs each approved action sequentially, taking pre/post
-// snapshots and enforcing the policy safety gate up front.
-// Execution stops on the first failure.
+// ExecuteAction runs a single approved action exactly once per idempotency key.
+//
+// The gate runs first, in order: kill switch, policy safety checks, change
+// calendar, then blast-radius limits for the plan and the tenant's daily
+// count. A refusal is returned wrapped in ErrRefused. A previously recorded
+// successful result for the key is returned unchanged. Otherwise the
+// handler's Applied check runs before Apply, so re-running an action whose
+// effect is already in place records OutcomeAlreadyApplied instead of
+// acting twice. Handler errors are returned unwrapped and are safe to retry.
+//
+// Before Apply, an in-progress entry with the pre-action snapshot is
+// recorded for the key. A retry that finds it, and finds the effect in
+// place, completes the earlier attempt: the result is OutcomeSucceeded
+// against the original snapshot, with rollback available, and is not
+// counted against the tenant's daily limit again.
+func (e *Executor) ExecuteAction(ctx context.Context, req ExecuteRequest) (domain.ExecutionResult, error) {
+	idempotencyKey, action := req.IdempotencyKey, req.Action
+
+	if e.kill != nil {
+		engaged, reason, err := e.kill.Engaged(req.TenantID)
+		if err != nil {
+			// Fail closed: an unreadable kill switch halts execution.
+			return domain.ExecutionResult{}, fmt.Errorf("%w: kill switch unavailable: %v", ErrRefused, err)
+		}
+		if engaged {
+			return domain.ExecutionResult{}, fmt.Errorf("%w: kill switch engaged: %s", ErrRefused, reason)
+		}
+	}
+
+	tagsByARN := map[string]map[string]string{}
+	if action.TargetResource != "" && req.ResourceTags != nil {
+		tagsByARN[action.TargetResource] = req.ResourceTags
+	}
+	if err := policy.EnforceExecutorSafety(req.Approval, []domain.RecommendedAction{action}, tagsByARN, e.rules); err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("%w: %v", ErrRefused, err)
+	}
+	if err := e.calendar.Check(e.now(), req.TenantID, req.ResourceTags); err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("%w: %v", ErrRefused, err)
+	}
+
+	prior, ok, err := e.ledger.Get(idempotencyKey)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: ledger get %s: %w", idempotencyKey, err)
+	}
+	if ok && prior.Success {
+		return prior, nil
+	}
+
+	h := e.handlerFor(action.ActionType)
+
+	if ok && prior.Outcome == outcomeInProgress {
+		done, err := h.Applied(ctx, action)
+		if err != nil {
+			return domain.ExecutionResult{}, fmt.Errorf("executor: check state for %s: %w", action.ActionID, err)
+		}
+		if done {
+			// An earlier attempt applied the action and stopped before
+			// recording it, so the change is this key's to roll back.
+			result := prior
+			result.Success = true
+			result.Outcome = domain.OutcomeSucceeded
+			result.RollbackAvailable = true
+			result.Details = fmt.Sprintf("%s on %s applied by an earlier attempt", action.ActionType, action.TargetResource)
+			return e.complete(ctx, action, result)
+		}
+	}
+
+	plan := req.Plan
+	if len(plan) == 0 {
+		plan = []domain.RecommendedAction{action}
+	}
+	unreserve, err := e.reserveAction(req.TenantID, plan)
+	if err != nil {
+		return domain.ExecutionResult{}, err
+	}
+	// The reservation stands only if this run changes something.
+	applied := false
+	defer func() {
+		if !applied {
+			unreserve()
+		}
+	}()
+
+	pre, err := e.Snapshot(ctx, action)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: pre-snapshot: %w", err)
+	}
+
+	alreadyApplied, err := h.Applied(ctx, action)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: check state for %s: %w", action.ActionID, err)
+	}
+
+	result := domain.ExecutionResult{
+		ActionID:          action.ActionID,
+		IdempotencyKey:    idempotencyKey,
+		Success:           true,
+		RollbackAvailable: !alreadyApplied,
+		PreActionSnapshot: pre,
+	}
+	if alreadyApplied {
+		// This run changed nothing, so it has nothing to roll back: undoing
+		// the effect would undo whoever did apply it.
+		result.Outcome = domain.OutcomeAlreadyApplied
+		result.Details = fmt.Sprintf("%s on %s already applied; no change made", action.ActionType, action.TargetResource)
+	} else {
+		inProgress := domain.ExecutionResult{
+			ActionID:          action.ActionID,
+			IdempotencyKey:    idempotencyKey,
+			Outcome:           outcomeInProgress,
+			PreActionSnapshot: pre,
+		}
+		if err := e.ledger.Put(idempotencyKey, inProgress); err != nil {
+			return domain.ExecutionResult{}, fmt.Errorf("executor: ledger put %s: %w", idempotencyKey, err)
+		}
+		details, err := h.Apply(ctx, idempotencyKey, action)
+		if err != nil {
+			return domain.ExecutionResult{}, fmt.Errorf("executor: apply %s: %w", action.ActionID, err)
+		}
+		applied = true
+		result.Outcome = domain.OutcomeSucceeded
+		result.Details = details
+	}
+	return e.complete(ctx, action, result)
+}
+
+// complete takes the post-action snapshot and records result in the ledger.
+func (e *Executor) complete(ctx context.Context, action domain.RecommendedAction, result domain.ExecutionResult) (domain.ExecutionResult, error) {
+	result.ExecutedAt = e.timestamp()
+
+	post, err := e.Snapshot(ctx, action)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: post-snapshot: %w", err)
+	}
+	result.PostActionSnapshot = post
+
+	if err := e.ledger.Put(result.IdempotencyKey, result); err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: ledger put %s: %w", result.IdempotencyKey, err)
+	}
+	return result, nil
+}
+
+// reserveAction checks the plan against the blast-radius limits and counts
+// the action against the tenant's daily limit in one store update, so
+// replicas sharing the store cannot all pass the check before any of them
+// counts. The returned func gives the place back when nothing was applied.
+// An unavailable store refuses the action: the limit is a safety gate.
+func (e *Executor) reserveAction(tenantID string, plan []domain.RecommendedAction) (unreserve func(), err error) {
+	now := e.now().UTC()
+	key := "blast|" + tenantID + "|" + now.Format("2006-01-02")
+	var limitErr error
+	var newDay bool
+	err = e.daily.UpdateCounter(key, func(c *ratelimit.Counter) {
+		newDay = c.WindowEnd.IsZero()
+		c.WindowEnd = now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if limitErr = policy.EnforceBlastRadius(e.limits, plan, c.Count); limitErr == nil {
+			c.Count++
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: daily action count unavailable: %v", ErrRefused, err)
+	}
+	if newDay {
+		// The tenant's first action of the day: drop earlier days' counts.
+		_ = e.daily.Prune(now)
+	}
+	if limitErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRefused, limitErr)
+	}
+	return func() {
+		_ = e.daily.UpdateCounter(key, func(c *ratelimit.Counter) {
+			c.Count = max(0, c.Count-1)
+		})
+	}, nil
+}
+
+// RollbackAction reverses a previously executed action. A rollback already
+// recorded for the key is returned unchanged. Rollbacks are remediation and
+// are not subject to the change calendar.
+func (e *Executor) RollbackAction(
+	ctx context.Context,
+	idempotencyKey string,
+	action domain.RecommendedAction,
+	pre map[string]any,
+) (domain.ExecutionResult, error) {
+	prior, ok, err := e.ledger.Get(idempotencyKey)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: ledger get %s: %w", idempotencyKey, err)
+	}
+	if ok && prior.Outcome == domain.OutcomeRolledBack {
+		return prior, nil
+	}
+
+	details, err := e.handlerFor(action.ActionType).Rollback(ctx, idempotencyKey, action, pre)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: rollback %s: %w", action.ActionID, err)
+	}
+
+	post, err := e.Snapshot(ctx, action)
+	if err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: post-rollback snapshot: %w", err)
+	}
+
+	result := domain.ExecutionResult{
+		ActionID:           action.ActionID,
+		IdempotencyKey:     idempotencyKey,
+		ExecutedAt:         e.timestamp(),
+		Success:            false,
+		Outcome:            domain.OutcomeRolledBack,
+		Details:            details,
+		RollbackAvailable:  false,
+		PreActionSnapshot:  pre,
+		PostActionSnapshot: post,
+	}
+	if err := e.ledger.Put(idempotencyKey, result); err != nil {
+		return domain.ExecutionResult{}, fmt.Errorf("executor: ledger put %s: %w", idempotencyKey, err)
+	}
+	return result, nil
+}
+
+// ExecuteActions runs the approved actions of workflowID in order, each
+// through ExecuteAction and so through the full safety gate, keyed by
+// IdempotencyKey(workflowID, action ID). Execution stops on the first
+// failure, returning the results so far with the error.
+//
+// Retained for lifecycle workflows started before per-action execution;
+// new code should call ExecuteAction once per action.
 func (e *Executor) ExecuteActions(
+	ctx context.Context,
+	workflowID, tenantID string,
 	approval domain.ApprovalStatus,
 	actions []domain.RecommendedAction,
 	resourceTagsByARN map[string]map[string]string,
 ) ([]domain.ExecutionResult, error) {
-	if err := policy.EnforceExecutorSafety(approval, actions, resourceTagsByARN); err != nil {
-		return nil, err
-	}
-
 	results := make([]domain.ExecutionResult, 0, len(actions))
 	for _, a := range actions {
-		pre, err := e.Snapshot(a)
+		res, err := e.ExecuteAction(ctx, ExecuteRequest{
+			IdempotencyKey: IdempotencyKey(workflowID, a.ActionID),
+			TenantID:       tenantID,
+			Approval:       approval,
+			Action:         a,
+			Plan:           actions,
+			ResourceTags:   resourceTagsByARN[a.TargetResource],
+		})
 		if err != nil {
-			return nil, fmt.Errorf("executor: pre-snapshot: %w", err)
+			return results, err
 		}
-
-		// TODO: production impl should deep-copy pre into post and capture real state
-		results = append(results, domain.ExecutionResult{
-			ActionID:           a.ActionID,
-			ExecutedAt:         time.Now().UTC().Format(time.RFC3339),
-			Success:            true,
-			Details:            fmt.Sprintf("stub executed %s on %s", a.ActionType, a.TargetResource),
-			RollbackAvailable:  true,
-			PreActionSnapshot:  pre,
-			PostActionSnapshot: pre,
-		})
+		results = append(results, res)
 	}
 	return results, nil
 }
+
+// stubHandler is the fallback for action types without a real handler.
+// It never reports prior application and performs no infrastructure changes.
+type stubHandler struct{}
+
+func (stubHandler) Applied(context.Context, domain.RecommendedAction) (bool, error) {
+	return false, nil
+}
+
+func (stubHandler) Apply(_ context.Context, _ string, a domain.RecommendedAction) (string, error) {
+	return fmt.Sprintf("stub executed %s on %s", a.ActionType, a.TargetResource), nil
+}
+
+func (stubHandler) Rollback(_ context.Context, _ string, a domain.RecommendedAction, _ map[string]any) (string, error) {
+	return fmt.Sprintf("stub rolled back %s on %s", a.ActionType, a.TargetResource), nil
+}
@@ -1,9 +1,18 @@
 package executor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/finops-claw-gang/finops-go/internal/calendar"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/policy"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
 	"github.com/finops-claw-gang/finops-go/internal/testutil"
 )
 
@@ -76,7 +85,7 @@ func TestExecuteActions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			exec := NewExecutor(infra)
-			results, err := exec.ExecuteActions(tt.approval, tt.actions, tt.tags)
+			results, err := exec.ExecuteActions(context.Background(), "wf-1", "t1", tt.approval, tt.actions, tt.tags)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("ExecuteActions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -113,7 +122,7 @@ func TestSnapshot(t *testing.T) {
 			action := domain.NewRecommendedAction("test", "test", domain.RiskLow, "rollback")
 			action.TargetResource = tt.target
 
-			snap, err := exec.Snapshot(action)
+			snap, err := exec.Snapshot(context.Background(), action)
 			if err != nil {
 				t.Fatalf("Snapshot: %v", err)
 			}
@@ -127,3 +136,507 @@ func TestSnapshot(t *testing.T) {
 		})
 	}
 }
+
+// fakeHandler records calls and reports a configurable prior state.
+type fakeHandler struct {
+	applied   bool
+	applies   int
+	rollbacks int
+}
+
+func (f *fakeHandler) Applied(context.Context, domain.RecommendedAction) (bool, error) {
+	return f.applied, nil
+}
+
+func (f *fakeHandler) Apply(context.Context, string, domain.RecommendedAction) (string, error) {
+	f.applies++
+	return "applied", nil
+}
+
+func (f *fakeHandler) Rollback(context.Context, string, domain.RecommendedAction, map[string]any) (string, error) {
+	f.rollbacks++
+	return "rolled back", nil
+}
+
+func TestExecuteAction(t *testing.T) {
+	t.Parallel()
+	infra := &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+
+	newAction := func(target string) domain.RecommendedAction {
+		a := domain.NewRecommendedAction("tag resource", "tag", domain.RiskLow, "remove tag")
+		a.TargetResource = target
+		return a
+	}
+
+	tests := []struct {
+		name        string
+		approval    domain.ApprovalStatus
+		tags        map[string]string
+		calendar    string
+		applied     bool
+		runs        int
+		wantRefused bool
+		wantOutcome domain.ExecutionOutcome
+		wantApplies int
+	}{
+		{
+			name:        "applies once",
+			approval:    domain.ApprovalApproved,
+			runs:        1,
+			wantOutcome: domain.OutcomeSucceeded,
+			wantApplies: 1,
+		},
+		{
+			name:        "retry with same key does not reapply",
+			approval:    domain.ApprovalApproved,
+			runs:        3,
+			wantOutcome: domain.OutcomeSucceeded,
+			wantApplies: 1,
+		},
+		{
+			name:        "already applied is recorded without acting",
+			approval:    domain.ApprovalAutoApproved,
+			applied:     true,
+			runs:        1,
+			wantOutcome: domain.OutcomeAlreadyApplied,
+			wantApplies: 0,
+		},
+		{
+			name:        "pending approval refused",
+			approval:    domain.ApprovalPending,
+			runs:        1,
+			wantRefused: true,
+		},
+		{
+			name:        "change freeze refused",
+			approval:    domain.ApprovalApproved,
+			calendar:    `{"freezes": [{"name": "close", "start": "2026-03-30T00:00:00Z", "end": "2026-04-03T00:00:00Z"}]}`,
+			runs:        1,
+			wantRefused: true,
+		},
+		{
+			name:        "outside tenant window refused",
+			approval:    domain.ApprovalApproved,
+			calendar:    `{"windows": [{"name": "w", "scope": {"tenants": ["t1"]}, "days": ["sat"], "start": "09:00", "end": "17:00"}]}`,
+			runs:        1,
+			wantRefused: true,
+		},
+		{
+			name:        "other tenant's window ignored",
+			approval:    domain.ApprovalApproved,
+			calendar:    `{"windows": [{"name": "w", "scope": {"tenants": ["t2"]}, "days": ["sat"], "start": "09:00", "end": "17:00"}]}`,
+			runs:        1,
+			wantOutcome: domain.OutcomeSucceeded,
+			wantApplies: 1,
+		},
+		{
+			name:        "do-not-modify tag refused",
+			approval:    domain.ApprovalApproved,
+			tags:        map[string]string{"do-not-modify": "true"},
+			runs:        1,
+			wantRefused: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &fakeHandler{applied: tt.applied}
+			exec := NewExecutor(infra)
+			exec.RegisterHandler("tag", h)
+			// Tuesday during quarter close.
+			exec.now = func() time.Time { return time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC) }
+			if tt.calendar != "" {
+				cal, err := calendar.Parse([]byte(tt.calendar))
+				if err != nil {
+					t.Fatalf("calendar.Parse: %v", err)
+				}
+				exec.SetCalendar(cal)
+			}
+
+			action := newAction("arn:aws:ec2:us-east-1:123:instance/i-abc")
+			key := IdempotencyKey("wf-1", action.ActionID)
+
+			var res domain.ExecutionResult
+			var err error
+			for range tt.runs {
+				res, err = exec.ExecuteAction(context.Background(), ExecuteRequest{
+					IdempotencyKey: key,
+					TenantID:       "t1",
+					Approval:       tt.approval,
+					Action:         action,
+					ResourceTags:   tt.tags,
+				})
+			}
+			if tt.wantRefused {
+				if !errors.Is(err, ErrRefused) {
+					t.Fatalf("expected ErrRefused, got %v", err)
+				}
+				if h.applies != 0 {
+					t.Errorf("refused action applied %d times", h.applies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteAction: %v", err)
+			}
+			if res.Outcome != tt.wantOutcome {
+				t.Errorf("outcome = %q, want %q", res.Outcome, tt.wantOutcome)
+			}
+			if res.IdempotencyKey != key {
+				t.Errorf("idempotency key = %q, want %q", res.IdempotencyKey, key)
+			}
+			if h.applies != tt.wantApplies {
+				t.Errorf("applies = %d, want %d", h.applies, tt.wantApplies)
+			}
+			if want := tt.wantApplies > 0; res.RollbackAvailable != want {
+				t.Errorf("rollback available = %v, want %v", res.RollbackAvailable, want)
+			}
+		})
+	}
+}
+
+func TestRollbackAction(t *testing.T) {
+	t.Parallel()
+	infra := &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+	h := &fakeHandler{}
+	exec := NewExecutor(infra)
+	exec.RegisterHandler("tag", h)
+
+	action := domain.NewRecommendedAction("tag resource", "tag", domain.RiskLow, "remove tag")
+	key := IdempotencyKey("wf-1", action.ActionID)
+
+	done, err := exec.ExecuteAction(context.Background(), ExecuteRequest{
+		IdempotencyKey: key,
+		TenantID:       "t1",
+		Approval:       domain.ApprovalApproved,
+		Action:         action,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteAction: %v", err)
+	}
+	for range 2 {
+		res, err := exec.RollbackAction(context.Background(), key, action, done.PreActionSnapshot)
+		if err != nil {
+			t.Fatalf("RollbackAction: %v", err)
+		}
+		if res.Outcome != domain.OutcomeRolledBack || res.Success || res.RollbackAvailable {
+			t.Errorf("unexpected rollback result: %+v", res)
+		}
+	}
+	if h.rollbacks != 1 {
+		t.Errorf("rollbacks = %d, want 1", h.rollbacks)
+	}
+}
+
+// tagState is a TagFetcher over one resource's tags.
+type tagState struct {
+	tags map[string]string
+}
+
+func (s *tagState) ResourceTags(context.Context, string) (map[string]string, error) {
+	return maps.Clone(s.tags), nil
+}
+
+// taggingHandler applies an action by setting a tag on a tagState.
+type taggingHandler struct {
+	state   *tagState
+	applies int
+}
+
+func (h *taggingHandler) Applied(context.Context, domain.RecommendedAction) (bool, error) {
+	return h.state.tags["owner"] == "finops", nil
+}
+
+func (h *taggingHandler) Apply(context.Context, string, domain.RecommendedAction) (string, error) {
+	h.applies++
+	h.state.tags["owner"] = "finops"
+	return "tagged", nil
+}
+
+func (h *taggingHandler) Rollback(context.Context, string, domain.RecommendedAction, map[string]any) (string, error) {
+	delete(h.state.tags, "owner")
+	return "untagged", nil
+}
+
+func TestExecuteAction_ResumesInterruptedApply(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		fileLedger bool
+		// retryOn returns the executor the retry runs on, given the first.
+		retryOn func(t *testing.T, first *Executor, path string) *Executor
+	}{
+		{
+			name:    "same worker, memory ledger",
+			retryOn: func(_ *testing.T, first *Executor, _ string) *Executor { return first },
+		},
+		{
+			name:       "another worker sharing a file ledger",
+			fileLedger: true,
+			retryOn: func(t *testing.T, first *Executor, path string) *Executor {
+				l, err := OpenFileLedger(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				exec := NewExecutor(first.tags)
+				exec.handlers = first.handlers
+				exec.SetLedger(l)
+				exec.SetStore(first.daily)
+				exec.SetBlastRadius(first.limits)
+				return exec
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			state := &tagState{tags: map[string]string{"env": "dev"}}
+			h := &taggingHandler{state: state}
+			exec := NewExecutor(state)
+			exec.RegisterHandler("tag", h)
+			path := filepath.Join(t.TempDir(), "ledger.jsonl")
+			if tt.fileLedger {
+				ledger, err := OpenFileLedger(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				exec.SetLedger(ledger)
+			}
+			// The retry must not be counted again.
+			exec.SetBlastRadius(policy.BlastRadiusLimits{MaxActionsPerTenantPerDay: 1})
+
+			action := domain.NewRecommendedAction("tag resource", "tag", domain.RiskLow, "remove tag")
+			action.TargetResource = "arn:aws:ec2:us-east-1:123:volume/vol-1"
+			req := ExecuteRequest{
+				IdempotencyKey: IdempotencyKey("wf-1", action.ActionID),
+				TenantID:       "t1",
+				Approval:       domain.ApprovalApproved,
+				Action:         action,
+			}
+
+			// Pre-snapshot succeeds, Apply succeeds, post-snapshot fails.
+			exec.tags = &failAfter{TagFetcher: state, ok: 1}
+			if _, err := exec.ExecuteAction(context.Background(), req); err == nil {
+				t.Fatal("first attempt: want post-snapshot error")
+			}
+			exec.tags = state
+
+			res, err := tt.retryOn(t, exec, path).ExecuteAction(context.Background(), req)
+			if err != nil {
+				t.Fatalf("retry: %v", err)
+			}
+			if h.applies != 1 {
+				t.Errorf("applies = %d, want 1", h.applies)
+			}
+			if res.Outcome != domain.OutcomeSucceeded || !res.Success || !res.RollbackAvailable {
+				t.Errorf("retry result = %+v, want succeeded with rollback", res)
+			}
+			// A file ledger decodes the tags as map[string]any; both print alike.
+			if got := fmt.Sprint(res.PreActionSnapshot["tags"]); got != "map[env:dev]" {
+				t.Errorf("pre-action snapshot = %v, want the state before Apply", res.PreActionSnapshot)
+			}
+		})
+	}
+}
+
+// failAfter is a TagFetcher that fails every call after the first ok.
+type failAfter struct {
+	TagFetcher
+	ok int
+}
+
+func (f *failAfter) ResourceTags(ctx context.Context, arn string) (map[string]string, error) {
+	if f.ok == 0 {
+		return nil, errors.New("throttled")
+	}
+	f.ok--
+	return f.TagFetcher.ResourceTags(ctx, arn)
+}
+
+func TestExecuteAction_KillSwitch(t *testing.T) {
+	t.Parallel()
+	infra := &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+
+	tests := []struct {
+		name        string
+		scope       string
+		tenant      string
+		wantRefused bool
+	}{
+		{name: "released", tenant: "t1"},
+		{name: "tenant engaged", scope: "t1", tenant: "t1", wantRefused: true},
+		{name: "other tenant engaged", scope: "t2", tenant: "t1"},
+		{name: "global engaged", scope: KillSwitchGlobal, tenant: "t1", wantRefused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &fakeHandler{}
+			exec := NewExecutor(infra)
+			exec.RegisterHandler("tag", h)
+			ks := NewMemoryKillSwitch()
+			if tt.scope != "" {
+				ks.Engage(tt.scope, "incident 42")
+			}
+			exec.SetKillSwitch(ks)
+
+			action := domain.NewRecommendedAction("tag resource", "tag", domain.RiskLow, "remove tag")
+			_, err := exec.ExecuteAction(context.Background(), ExecuteRequest{
+				IdempotencyKey: IdempotencyKey("wf-1", action.ActionID),
+				TenantID:       tt.tenant,
+				Approval:       domain.ApprovalApproved,
+				Action:         action,
+			})
+			if tt.wantRefused {
+				if !errors.Is(err, ErrRefused) {
+					t.Fatalf("expected ErrRefused, got %v", err)
+				}
+				if h.applies != 0 {
+					t.Errorf("applied %d times while kill switch engaged", h.applies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExecuteAction: %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteAction_BlastRadius(t *testing.T) {
+	t.Parallel()
+	infra := &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+
+	newVolume := func(i int) domain.RecommendedAction {
+		a := domain.NewRecommendedAction("delete volume", "tag", domain.RiskLow, "restore snapshot")
+		a.TargetResource = "arn:aws:ec2:us-east-1:123456789012:volume/vol-" + string(rune('a'+i))
+		return a
+	}
+
+	t.Run("oversized plan refused before any action", func(t *testing.T) {
+		t.Parallel()
+		h := &fakeHandler{}
+		exec := NewExecutor(infra)
+		exec.RegisterHandler("tag", h)
+		exec.SetBlastRadius(policy.BlastRadiusLimits{MaxActionsPerWorkflow: 2})
+
+		plan := []domain.RecommendedAction{newVolume(0), newVolume(1), newVolume(2)}
+		_, err := exec.ExecuteAction(context.Background(), ExecuteRequest{
+			IdempotencyKey: IdempotencyKey("wf-1", plan[0].ActionID),
+			TenantID:       "t1",
+			Approval:       domain.ApprovalApproved,
+			Action:         plan[0],
+			Plan:           plan,
+		})
+		if !errors.Is(err, ErrRefused) {
+			t.Fatalf("expected ErrRefused, got %v", err)
+		}
+		if h.applies != 0 {
+			t.Errorf("applies = %d, want 0", h.applies)
+		}
+	})
+
+	t.Run("tenant daily cap counts applied actions only", func(t *testing.T) {
+		t.Parallel()
+		h := &fakeHandler{}
+		exec := NewExecutor(infra)
+		exec.RegisterHandler("tag", h)
+		exec.SetBlastRadius(policy.BlastRadiusLimits{MaxActionsPerTenantPerDay: 2})
+
+		run := func(tenant string, a domain.RecommendedAction) error {
+			_, err := exec.ExecuteAction(context.Background(), ExecuteRequest{
+				IdempotencyKey: IdempotencyKey("wf-"+tenant, a.ActionID),
+				TenantID:       tenant,
+				Approval:       domain.ApprovalApproved,
+				Action:         a,
+			})
+			return err
+		}
+		first := newVolume(0)
+		for _, a := range []domain.RecommendedAction{first, first, newVolume(1)} {
+			if err := run("t1", a); err != nil {
+				t.Fatalf("ExecuteAction: %v", err)
+			}
+		}
+		if err := run("t1", newVolume(2)); !errors.Is(err, ErrRefused) {
+			t.Fatalf("expected daily cap refusal, got %v", err)
+		}
+		if err := run("t2", newVolume(3)); err != nil {
+			t.Fatalf("other tenant should be unaffected: %v", err)
+		}
+	})
+
+	t.Run("tenant daily cap is shared through the store", func(t *testing.T) {
+		t.Parallel()
+		store := ratelimit.NewMemoryStore()
+		replicas := make([]*Executor, 2)
+		for i := range replicas {
+			replicas[i] = NewExecutor(infra)
+			replicas[i].RegisterHandler("tag", &fakeHandler{})
+			replicas[i].SetBlastRadius(policy.BlastRadiusLimits{MaxActionsPerTenantPerDay: 2})
+			replicas[i].SetStore(store)
+		}
+		for i, exec := range []*Executor{replicas[0], replicas[1], replicas[0]} {
+			a := newVolume(i)
+			_, err := exec.ExecuteAction(context.Background(), ExecuteRequest{
+				IdempotencyKey: IdempotencyKey("wf-1", a.ActionID),
+				TenantID:       "t1",
+				Approval:       domain.ApprovalApproved,
+				Action:         a,
+			})
+			if refused := errors.Is(err, ErrRefused); refused != (i == 2) {
+				t.Fatalf("action %d: err = %v", i, err)
+			}
+		}
+	})
+}
+
+func TestExecuteActions_Gate(t *testing.T) {
+	t.Parallel()
+	infra := &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+	action := domain.NewRecommendedAction("tag resource", "tag", domain.RiskLow, "remove tag")
+	action.TargetResource = "arn:aws:ec2:us-east-1:123:instance/i-abc"
+
+	tests := []struct {
+		name  string
+		setup func(*Executor)
+	}{
+		{name: "kill switch", setup: func(e *Executor) {
+			ks := NewMemoryKillSwitch()
+			ks.Engage(KillSwitchGlobal, "incident")
+			e.SetKillSwitch(ks)
+		}},
+		{name: "change freeze", setup: func(e *Executor) {
+			cal, err := calendar.Parse([]byte(`{"freezes": [{"name": "close", "start": "2026-03-30T00:00:00Z", "end": "2026-04-03T00:00:00Z"}]}`))
+			if err != nil {
+				t.Fatalf("calendar.Parse: %v", err)
+			}
+			e.SetCalendar(cal)
+		}},
+		{name: "blast radius", setup: func(e *Executor) {
+			e.SetBlastRadius(policy.BlastRadiusLimits{MaxActionsPerTenantPerDay: 1})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &fakeHandler{}
+			exec := NewExecutor(infra)
+			exec.RegisterHandler("tag", h)
+			exec.now = func() time.Time { return time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC) }
+			tt.setup(exec)
+
+			second := action
+			second.ActionID = "second"
+			second.TargetResource = "arn:aws:ec2:us-east-1:123:instance/i-def"
+			_, err := exec.ExecuteActions(context.Background(), "wf-1", "t1", domain.ApprovalApproved,
+				[]domain.RecommendedAction{action, second}, nil)
+			if !errors.Is(err, ErrRefused) {
+				t.Fatalf("expected ErrRefused, got %v", err)
+			}
+			if h.applies > 1 {
+				t.Errorf("applies = %d, want at most 1", h.applies)
+			}
+		})
+	}
+}
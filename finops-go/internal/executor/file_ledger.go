@@ -0,0 +1,150 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// Compile-time check.
+var _ Ledger = (*FileLedger)(nil)
+
+// FileLedger is a Ledger backed by an append-only JSON-lines file. Unlike
+// MemoryLedger it survives worker restarts, and workers that share the
+// file, for example on a shared volume, see each other's entries, so a
+// retry on another worker finds the earlier attempt. Each Put appends one
+// line while holding an exclusive lock on the file; the latest line for a
+// key wins. Entries are never removed: each action adds two or three.
+type FileLedger struct {
+	path string
+
+	mu sync.Mutex
+	// results holds the entries read so far, and offset how much of the
+	// file has been read, so lookups only scan what others added.
+	results map[string]domain.ExecutionResult
+	offset  int64
+}
+
+// ledgerEntry is one line of a FileLedger file.
+type ledgerEntry struct {
+	Key    string                 `json:"key"`
+	Result domain.ExecutionResult `json:"result"`
+}
+
+// OpenFileLedger opens (creating if needed) the ledger file at path.
+func OpenFileLedger(path string) (*FileLedger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("executor: create ledger dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("executor: open ledger %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileLedger{path: path, results: make(map[string]domain.ExecutionResult)}, nil
+}
+
+// Get returns the latest result recorded for key, if any.
+func (l *FileLedger) Get(key string) (domain.ExecutionResult, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return domain.ExecutionResult{}, false, fmt.Errorf("executor: open ledger %s: %w", l.path, err)
+	}
+	defer f.Close()
+	if _, err := l.advance(f); err != nil {
+		return domain.ExecutionResult{}, false, err
+	}
+	r, ok := l.results[key]
+	return r, ok, nil
+}
+
+// Put records the result for key, replacing any previous entry. The line
+// is synced to disk before Put returns.
+func (l *FileLedger) Put(key string, result domain.ExecutionResult) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	line, err := json.Marshal(ledgerEntry{Key: key, Result: result})
+	if err != nil {
+		return fmt.Errorf("executor: encode ledger entry %s: %w", key, err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("executor: open ledger %s: %w", l.path, err)
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("executor: lock ledger %s: %w", l.path, err)
+	}
+	defer unlockFile(f)
+
+	partial, err := l.advance(f)
+	if err != nil {
+		return err
+	}
+	buf := append(line, '\n')
+	if partial {
+		// Close off a line left partial by a crashed writer.
+		buf = append([]byte{'\n'}, buf...)
+	}
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("executor: append ledger entry %s: %w", key, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("executor: sync ledger %s: %w", l.path, err)
+	}
+	_, err = l.advance(f)
+	return err
+}
+
+// advance reads the complete lines appended since the last call. partial
+// reports a trailing line without a newline, which is left unread: without
+// the file lock it may be an append still in progress. Callers hold mu.
+func (l *FileLedger) advance(f *os.File) (partial bool, err error) {
+	st, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("executor: stat ledger %s: %w", l.path, err)
+	}
+	if st.Size() < l.offset {
+		// Truncated or replaced: start over.
+		clear(l.results)
+		l.offset = 0
+	}
+	if st.Size() == l.offset {
+		return false, nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(f, l.offset, st.Size()-l.offset))
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			return len(line) > 0, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("executor: read ledger %s: %w", l.path, err)
+		}
+		l.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var e ledgerEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			slog.Error("executor: corrupt ledger line", "path", l.path, "offset", l.offset, "error", err)
+			continue
+		}
+		l.results[e.Key] = e.Result
+	}
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func TestFileLedger(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "state", "ledger.jsonl")
+	a, err := OpenFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := OpenFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := a.Get("wf-1/a-1"); err != nil || ok {
+		t.Fatalf("Get on empty ledger = %v, %v", ok, err)
+	}
+	if err := a.Put("wf-1/a-1", domain.ExecutionResult{ActionID: "a-1", Outcome: outcomeInProgress}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put("wf-1/a-1", domain.ExecutionResult{ActionID: "a-1", Success: true, Outcome: domain.OutcomeSucceeded}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crashed writer leaves a partial line behind.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"key":"wf-1/a-2","res`)
+	_ = f.Close()
+
+	// Another process sees a's entries, latest first, and appends past the
+	// partial line.
+	got, ok, err := b.Get("wf-1/a-1")
+	if err != nil || !ok || got.Outcome != domain.OutcomeSucceeded {
+		t.Fatalf("b.Get(a-1) = %+v, %v, %v; want the succeeded entry", got, ok, err)
+	}
+	if err := b.Put("wf-1/a-3", domain.ExecutionResult{ActionID: "a-3", Outcome: domain.OutcomeRolledBack}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart reads everything back.
+	c, err := OpenFileLedger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key  string
+		ok   bool
+		want domain.ExecutionOutcome
+	}{
+		{key: "wf-1/a-1", ok: true, want: domain.OutcomeSucceeded},
+		{key: "wf-1/a-2"},
+		{key: "wf-1/a-3", ok: true, want: domain.OutcomeRolledBack},
+	}
+	for _, tt := range tests {
+		for name, l := range map[string]*FileLedger{"a": a, "c": c} {
+			got, ok, err := l.Get(tt.key)
+			if err != nil {
+				t.Fatalf("%s.Get(%s): %v", name, tt.key, err)
+			}
+			if ok != tt.ok || got.Outcome != tt.want {
+				t.Errorf("%s.Get(%s) = %q, %v; want %q, %v", name, tt.key, got.Outcome, ok, tt.want, tt.ok)
+			}
+		}
+	}
+}
@@ -1,6 +1,36 @@
 package executor
 
+import (
+	"context"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
 // TagFetcher provides resource tags for safety checks.
 type TagFetcher interface {
-	ResourceTags(resourceARN string) (map[string]string, error)
+	ResourceTags(ctx context.Context, resourceARN string) (map[string]string, error)
+}
+
+// ActionHandler performs one action type against real infrastructure.
+//
+// Handlers must tolerate being invoked more than once for the same
+// idempotency key: Applied is always consulted before Apply, and a true
+// result short-circuits execution. The key should be forwarded to AWS APIs
+// that accept a client token.
+type ActionHandler interface {
+	// Applied reports whether the action's desired end state is already in place.
+	Applied(ctx context.Context, action domain.RecommendedAction) (bool, error)
+	// Apply performs the action and returns a human-readable summary.
+	Apply(ctx context.Context, idempotencyKey string, action domain.RecommendedAction) (string, error)
+	// Rollback reverses a previously applied action using its pre-action snapshot.
+	Rollback(ctx context.Context, idempotencyKey string, action domain.RecommendedAction, pre map[string]any) (string, error)
+}
+
+// Ledger records executions by idempotency key so a retried activity
+// returns the original result instead of acting twice. Besides completed
+// results, it holds an entry with Outcome "in_progress" for an action whose
+// Apply has started but whose result is not yet recorded.
+type Ledger interface {
+	Get(key string) (domain.ExecutionResult, bool, error)
+	Put(key string, result domain.ExecutionResult) error
 }
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// KillSwitchGlobal is the scope that halts executions for every tenant.
+const KillSwitchGlobal = "_all"
+
+// KillSwitch halts executions for a tenant or globally. It is consulted
+// before every action, so engaging it stops in-flight workflows at their
+// next action rather than at their next start.
+type KillSwitch interface {
+	// Engaged reports whether executions are halted for tenantID, either
+	// for that tenant or globally, and the operator-supplied reason.
+	Engaged(tenantID string) (bool, string, error)
+}
+
+// MemoryKillSwitch is an in-process KillSwitch, useful for tests and for
+// embedding behind an admin endpoint.
+type MemoryKillSwitch struct {
+	mu      sync.RWMutex
+	engaged map[string]string
+}
+
+// NewMemoryKillSwitch creates a released MemoryKillSwitch.
+func NewMemoryKillSwitch() *MemoryKillSwitch {
+	return &MemoryKillSwitch{engaged: make(map[string]string)}
+}
+
+// Engage halts executions for scope (a tenant ID or KillSwitchGlobal).
+func (k *MemoryKillSwitch) Engage(scope, reason string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.engaged[scope] = reason
+}
+
+// Release resumes executions for scope.
+func (k *MemoryKillSwitch) Release(scope string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.engaged, scope)
+}
+
+// Engaged implements KillSwitch.
+func (k *MemoryKillSwitch) Engaged(tenantID string) (bool, string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if reason, ok := k.engaged[KillSwitchGlobal]; ok {
+		return true, reason, nil
+	}
+	if reason, ok := k.engaged[tenantID]; ok {
+		return true, reason, nil
+	}
+	return false, "", nil
+}
+
+// FileKillSwitch reads switches from a directory so operators can halt
+// executions across a fleet by writing a file (for example to a mounted
+// ConfigMap). A file named KillSwitchGlobal halts everything; a file named
+// after a tenant ID halts that tenant. The file contents are the reason.
+type FileKillSwitch struct {
+	Dir string
+}
+
+// Engaged implements KillSwitch.
+func (k FileKillSwitch) Engaged(tenantID string) (bool, string, error) {
+	for _, scope := range []string{KillSwitchGlobal, tenantID} {
+		if scope == "" || strings.ContainsAny(scope, `/\`) || scope == "." || scope == ".." {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(k.Dir, scope))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, "", fmt.Errorf("executor: read kill switch %s: %w", scope, err)
+		}
+		reason := strings.TrimSpace(string(data))
+		if reason == "" {
+			reason = "kill switch engaged"
+		}
+		return true, reason, nil
+	}
+	return false, "", nil
+}
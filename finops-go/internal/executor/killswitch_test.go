@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileKillSwitch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		files      map[string]string
+		tenant     string
+		wantOn     bool
+		wantReason string
+	}{
+		{name: "empty dir", tenant: "t1"},
+		{name: "tenant file", files: map[string]string{"t1": "bad deploy\n"}, tenant: "t1", wantOn: true, wantReason: "bad deploy"},
+		{name: "other tenant file", files: map[string]string{"t2": "x"}, tenant: "t1"},
+		{name: "global file", files: map[string]string{KillSwitchGlobal: ""}, tenant: "t1", wantOn: true, wantReason: "kill switch engaged"},
+		{name: "path-like tenant ignored", files: map[string]string{"t1": "x"}, tenant: "../t1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for name, body := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			on, reason, err := FileKillSwitch{Dir: dir}.Engaged(tt.tenant)
+			if err != nil {
+				t.Fatalf("Engaged: %v", err)
+			}
+			if on != tt.wantOn || reason != tt.wantReason {
+				t.Errorf("Engaged() = (%v, %q), want (%v, %q)", on, reason, tt.wantOn, tt.wantReason)
+			}
+		})
+	}
+}
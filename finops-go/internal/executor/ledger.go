@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// IdempotencyKey derives the per-action key from the owning workflow ID and
+// the action ID. The same (workflow, action) pair always yields the same key,
+// across activity retries and worker restarts.
+func IdempotencyKey(workflowID, actionID string) string {
+	return fmt.Sprintf("%s/%s", workflowID, actionID)
+}
+
+// outcomeInProgress marks the ledger entry written before Apply. It is
+// never returned to callers.
+const outcomeInProgress domain.ExecutionOutcome = "in_progress"
+
+// MemoryLedger is an in-process Ledger. It protects against activity retries
+// on the same worker, and is lost when the worker restarts: a retry on
+// another worker, or after a restart, finds no entry, and handlers' Applied
+// checks then record an action applied by the lost attempt as
+// OutcomeAlreadyApplied, with no rollback. Use FileLedger to keep entries.
+type MemoryLedger struct {
+	mu      sync.Mutex
+	results map[string]domain.ExecutionResult
+}
+
+// NewMemoryLedger creates an empty MemoryLedger.
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{results: make(map[string]domain.ExecutionResult)}
+}
+
+// Get returns the recorded result for key, if any.
+func (l *MemoryLedger) Get(key string) (domain.ExecutionResult, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.results[key]
+	return r, ok, nil
+}
+
+// Put records the result for key, replacing any previous entry.
+func (l *MemoryLedger) Put(key string, result domain.ExecutionResult) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.results[key] = result
+	return nil
+}
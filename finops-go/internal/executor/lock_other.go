@@ -0,0 +1,10 @@
+//go:build !unix
+
+package executor
+
+import "os"
+
+// Without flock, ledger appends are only serialized within one process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) {}
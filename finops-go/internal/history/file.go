@@ -0,0 +1,152 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is an embedded Repository: an append-only JSON-lines file of
+// record versions, replayed into a MemoryStore for indexed queries. Each
+// call first applies lines appended since the last one, so a reader in
+// another process (the API) follows the writer (the worker).
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	mem    *MemoryStore
+	offset int64 // bytes of the file applied to mem
+}
+
+// OpenFileStore opens (creating if needed) the history file at path and
+// loads it.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("history: create dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("history: open %s: %w", path, err)
+	}
+	_ = f.Close()
+
+	s := &FileStore{path: path, mem: NewMemoryStore()}
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Upsert implements Repository.
+func (s *FileStore) Upsert(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.refresh(); err != nil {
+		return err
+	}
+	if prev, ok, _ := s.mem.Get(r.WorkflowID); ok && prev.StartedAt != "" {
+		r.StartedAt = prev.StartedAt
+	}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("history: encode %s: %w", r.WorkflowID, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: open %s: %w", s.path, err)
+	}
+	buf := append(line, '\n')
+	// Close off a line left partial by a crashed writer, so it stays the
+	// only corrupt line.
+	if st, err := f.Stat(); err == nil && st.Size() > s.offset {
+		buf = append([]byte{'\n'}, buf...)
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("history: append %s: %w", r.WorkflowID, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("history: close %s: %w", s.path, err)
+	}
+	// Pick up our own line (and any appended concurrently) from disk.
+	return s.refresh()
+}
+
+// Get implements Repository.
+func (s *FileStore) Get(workflowID string) (Record, bool, error) {
+	s.mu.Lock()
+	err := s.refresh()
+	mem := s.mem
+	s.mu.Unlock()
+	if err != nil {
+		return Record{}, false, err
+	}
+	return mem.Get(workflowID)
+}
+
+// Query implements Repository.
+func (s *FileStore) Query(q Query) (Page, error) {
+	s.mu.Lock()
+	err := s.refresh()
+	mem := s.mem
+	s.mu.Unlock()
+	if err != nil {
+		return Page{}, err
+	}
+	return mem.Query(q)
+}
+
+// refresh applies complete lines appended since the last call. If the
+// file shrank (rotated or rewritten), it reloads from the start. Lines
+// that do not decode are skipped. Callers hold mu.
+func (s *FileStore) refresh() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("history: open %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("history: stat %s: %w", s.path, err)
+	}
+	if st.Size() < s.offset {
+		s.mem, s.offset = NewMemoryStore(), 0
+	}
+	if st.Size() == s.offset {
+		return nil
+	}
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("history: seek %s: %w", s.path, err)
+	}
+
+	rd := bufio.NewReader(f)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial line is still being written; retry next time.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("history: read %s: %w", s.path, err)
+		}
+		s.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			slog.Warn("history: skipping corrupt line", "path", s.path, "offset", s.offset, "error", err)
+			continue
+		}
+		_ = s.mem.Upsert(r)
+	}
+}
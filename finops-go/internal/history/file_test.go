@@ -0,0 +1,63 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore_FollowsWriter(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "history", "anomalies.jsonl")
+	writer, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	reader, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+
+	seed(t, writer)
+	if err := writer.Upsert(Record{WorkflowID: "wf-0", Service: "EC2", Phase: "monitoring", StartedAt: "2026-04-01T00:00:00Z"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	p, err := reader.Query(Query{Service: "EC2", Sort: "started_at"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(p.Records) != 3 {
+		t.Fatalf("records = %v", ids(p))
+	}
+	r, ok, err := reader.Get("wf-0")
+	if err != nil || !ok {
+		t.Fatalf("Get = %v, %v", ok, err)
+	}
+	if r.Phase != "monitoring" || r.StartedAt != "2026-03-01T10:00:00Z" {
+		t.Errorf("wf-0 = %+v", r)
+	}
+}
+
+func TestFileStore_SkipsPartialLine(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "anomalies.jsonl")
+	data := `{"workflow_id":"wf-1","phase":"triage"}` + "\n" + `{"workflow_id":"wf-2","pha`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	if err := s.Upsert(Record{WorkflowID: "wf-3", Phase: "triage"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	p, err := s.Query(Query{Phase: "triage", Sort: "started_at"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if got := ids(p); len(got) != 2 || got[0] != "wf-1" || got[1] != "wf-3" {
+		t.Errorf("records = %v", got)
+	}
+}
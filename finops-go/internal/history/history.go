@@ -0,0 +1,127 @@
+// Package history is a queryable projection of anomaly lifecycles: each
+// workflow's anomaly, triage, analysis, decisions and outcome, updated at
+// phase boundaries. It answers searches that Temporal visibility cannot,
+// such as by service, category, team, severity or dollar impact.
+package history
+
+import (
+	"fmt"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// Record is one workflow's projection. The flat fields are the indexed and
+// sortable ones; the nested ones carry full detail.
+type Record struct {
+	WorkflowID string `json:"workflow_id"`
+	TenantID   string `json:"tenant_id"`
+
+	Service      string                 `json:"service"`
+	AccountID    string                 `json:"account_id"`
+	Team         string                 `json:"team,omitempty"`
+	Category     domain.AnomalyCategory `json:"category,omitempty"`
+	Severity     domain.AnomalySeverity `json:"severity,omitempty"`
+	DeltaDollars float64                `json:"delta_dollars"`
+
+	Phase          string                            `json:"phase"`
+	Approval       domain.ApprovalStatus             `json:"approval,omitempty"`
+	Approver       *domain.Approver                  `json:"approver,omitempty"`
+	Reason         string                            `json:"reason,omitempty"` // termination reason; empty while running
+	Recommendation domain.VerificationRecommendation `json:"recommendation,omitempty"`
+
+	Anomaly      *domain.CostAnomaly        `json:"anomaly,omitempty"`
+	Triage       *domain.TriageResult       `json:"triage,omitempty"`
+	Analysis     *domain.AnalysisResult     `json:"analysis,omitempty"`
+	Executions   []domain.ExecutionResult   `json:"executions,omitempty"`
+	Verification *domain.VerificationResult `json:"verification,omitempty"`
+	Decisions    []Decision                 `json:"decisions,omitempty"`
+
+	StartedAt string `json:"started_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Decision is one step of the lifecycle's decision trail.
+type Decision struct {
+	Phase    string `json:"phase"`
+	Decision string `json:"decision"`
+	Details  string `json:"details,omitempty"`
+}
+
+// FromState projects workflow state. reason is the termination reason, or
+// empty while the workflow runs; at (RFC3339) stamps the update.
+func FromState(workflowID string, state domain.FinOpsState, reason, at string) Record {
+	r := Record{
+		WorkflowID:   workflowID,
+		TenantID:     state.Tenant.TenantID,
+		Phase:        state.CurrentPhase,
+		Approval:     state.Approval,
+		Approver:     state.Approver,
+		Reason:       reason,
+		Anomaly:      state.Anomaly,
+		Triage:       state.Triage,
+		Analysis:     state.Analysis,
+		Executions:   state.Executions,
+		Verification: state.Verification,
+		StartedAt:    state.StartedAt,
+		UpdatedAt:    at,
+	}
+	if a := state.Anomaly; a != nil {
+		r.Service, r.AccountID, r.Team, r.DeltaDollars = a.Service, a.AccountID, a.Team, a.DeltaDollars
+	}
+	if t := state.Triage; t != nil {
+		r.Category, r.Severity = t.Category, t.Severity
+		r.Decisions = append(r.Decisions, Decision{
+			Phase:    "triage",
+			Decision: string(t.Category),
+			Details:  fmt.Sprintf("%s severity, %.0f%% confidence", t.Severity, t.Confidence*100),
+		})
+	}
+	if a := state.Analysis; a != nil {
+		r.Decisions = append(r.Decisions, Decision{
+			Phase:    "analyst",
+			Decision: fmt.Sprintf("%d actions", len(a.RecommendedActions)),
+			Details:  fmt.Sprintf("estimated $%.2f/month", a.EstimatedMonthlySavings),
+		})
+	}
+	if state.Approval != "" {
+		r.Decisions = append(r.Decisions, Decision{Phase: "hil_gate", Decision: string(state.Approval), Details: state.ApprovalDetails})
+	}
+	if len(state.Executions) > 0 {
+		ok := 0
+		for _, e := range state.Executions {
+			if e.Success && e.Outcome != domain.OutcomeRolledBack {
+				ok++
+			}
+		}
+		r.Decisions = append(r.Decisions, Decision{
+			Phase:    "executor",
+			Decision: fmt.Sprintf("%d/%d applied", ok, len(state.Executions)),
+		})
+	}
+	if v := state.Verification; v != nil {
+		r.Recommendation = v.Recommendation
+		r.Decisions = append(r.Decisions, Decision{Phase: "verifier", Decision: string(v.Recommendation), Details: v.HealthCheckDetails})
+	}
+	if m := state.Monitoring; m != nil && m.Outcome != "" {
+		r.Decisions = append(r.Decisions, Decision{Phase: "monitoring", Decision: m.Label(), Details: m.Outcome})
+	}
+	if reason != "" {
+		d := Decision{Phase: "end", Decision: reason}
+		if state.Error != nil {
+			d.Details = *state.Error
+		}
+		r.Decisions = append(r.Decisions, d)
+	}
+	return r
+}
+
+// Repository stores projections, one per workflow.
+type Repository interface {
+	// Upsert replaces the projection for r.WorkflowID. StartedAt of an
+	// existing record is preserved.
+	Upsert(r Record) error
+	// Get returns the projection for workflowID, if any.
+	Get(workflowID string) (Record, bool, error)
+	// Query returns one page of matching projections.
+	Query(q Query) (Page, error)
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func TestFromState(t *testing.T) {
+	t.Parallel()
+	state := domain.NewFinOpsState(domain.NewTenantContext("acme"))
+	state.CurrentPhase = "completed"
+	state.Anomaly = &domain.CostAnomaly{Service: "EC2", AccountID: "123456789012", Team: "platform", DeltaDollars: 420}
+	state.Triage = &domain.TriageResult{Category: domain.CategoryResourceWaste, Severity: domain.SeverityHigh, Confidence: 0.9}
+	state.Analysis = &domain.AnalysisResult{RecommendedActions: make([]domain.RecommendedAction, 2), EstimatedMonthlySavings: 600}
+	state.Approval = domain.ApprovalApproved
+	state.Executions = []domain.ExecutionResult{{Success: true}, {Success: false}}
+	state.Verification = &domain.VerificationResult{Recommendation: domain.RecommendClose}
+
+	r := FromState("wf-1", state, "completed", "2026-03-02T10:00:00Z")
+	if r.TenantID != "acme" || r.Service != "EC2" || r.Team != "platform" || r.DeltaDollars != 420 {
+		t.Errorf("anomaly fields = %+v", r)
+	}
+	if r.Category != domain.CategoryResourceWaste || r.Severity != domain.SeverityHigh || r.Recommendation != domain.RecommendClose {
+		t.Errorf("decision fields = %+v", r)
+	}
+	want := []string{"triage", "analyst", "hil_gate", "executor", "verifier", "end"}
+	if len(r.Decisions) != len(want) {
+		t.Fatalf("decisions = %+v", r.Decisions)
+	}
+	for i, phase := range want {
+		if r.Decisions[i].Phase != phase {
+			t.Errorf("decision %d phase = %q, want %q", i, r.Decisions[i].Phase, phase)
+		}
+	}
+	if r.Decisions[3].Decision != "1/2 applied" {
+		t.Errorf("executor decision = %q", r.Decisions[3].Decision)
+	}
+}
+
+func seed(t *testing.T, repo Repository) {
+	t.Helper()
+	services := []string{"EC2", "S3", "RDS"}
+	for i := 0; i < 9; i++ {
+		r := Record{
+			WorkflowID:   fmt.Sprintf("wf-%d", i),
+			TenantID:     []string{"acme", "globex"}[i%2],
+			Service:      services[i%3],
+			Severity:     domain.SeverityMedium,
+			DeltaDollars: float64(100 * (i + 1)),
+			Phase:        "completed",
+			StartedAt:    fmt.Sprintf("2026-03-%02dT10:00:00Z", i+1),
+		}
+		if i == 4 {
+			r.Severity = domain.SeverityCritical
+		}
+		if err := repo.Upsert(r); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+}
+
+func ids(p Page) []string {
+	out := make([]string, len(p.Records))
+	for i, r := range p.Records {
+		out[i] = r.WorkflowID
+	}
+	return out
+}
+
+func TestMemoryStore_Query(t *testing.T) {
+	t.Parallel()
+	m := NewMemoryStore()
+	seed(t, m)
+	min := 300.0
+
+	tests := []struct {
+		name string
+		q    Query
+		want string
+	}{
+		{"default newest first", Query{Limit: 3}, "[wf-8 wf-7 wf-6]"},
+		{"service index", Query{Service: "EC2", Sort: "started_at"}, "[wf-0 wf-3 wf-6]"},
+		{"tenant and service", Query{TenantID: "acme", Service: "EC2"}, "[wf-6 wf-0]"},
+		{"severity", Query{Severity: domain.SeverityCritical}, "[wf-4]"},
+		{"min delta by dollars", Query{MinDelta: &min, Sort: "-delta_dollars", Limit: 2}, "[wf-8 wf-7]"},
+		{"date range", Query{From: "2026-03-02", To: "2026-03-03", Sort: "started_at"}, "[wf-1 wf-2]"},
+		{"no match", Query{Service: "Lambda"}, "[]"},
+	}
+	for _, tt := range tests {
+		p, err := m.Query(tt.q)
+		if err != nil {
+			t.Fatalf("%s: Query: %v", tt.name, err)
+		}
+		if got := fmt.Sprint(ids(p)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryStore_Pagination(t *testing.T) {
+	t.Parallel()
+	m := NewMemoryStore()
+	seed(t, m)
+
+	var all []string
+	q := Query{Sort: "delta_dollars", Limit: 4}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		p, err := m.Query(q)
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		all = append(all, ids(p)...)
+		if p.NextCursor == "" {
+			break
+		}
+		q.Cursor = p.NextCursor
+	}
+	if got := fmt.Sprint(all); got != "[wf-0 wf-1 wf-2 wf-3 wf-4 wf-5 wf-6 wf-7 wf-8]" {
+		t.Errorf("pages = %s", got)
+	}
+}
+
+func TestMemoryStore_UpsertReindexes(t *testing.T) {
+	t.Parallel()
+	m := NewMemoryStore()
+	_ = m.Upsert(Record{WorkflowID: "wf-1", Phase: "triage", StartedAt: "2026-03-01T10:00:00Z"})
+	_ = m.Upsert(Record{WorkflowID: "wf-1", Phase: "completed", StartedAt: "2026-03-05T10:00:00Z"})
+
+	if p, _ := m.Query(Query{Phase: "triage"}); len(p.Records) != 0 {
+		t.Errorf("stale phase index: %v", ids(p))
+	}
+	p, _ := m.Query(Query{Phase: "completed"})
+	if len(p.Records) != 1 || p.Records[0].StartedAt != "2026-03-01T10:00:00Z" {
+		t.Errorf("records = %+v, want StartedAt preserved", p.Records)
+	}
+}
+
+func TestQuery_Invalid(t *testing.T) {
+	t.Parallel()
+	m := NewMemoryStore()
+	for _, q := range []Query{{Sort: "severity"}, {Cursor: "not-a-cursor"}} {
+		if _, err := m.Query(q); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("Query(%+v) error = %v, want ErrInvalidQuery", q, err)
+		}
+	}
+}
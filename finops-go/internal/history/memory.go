@@ -0,0 +1,164 @@
+package history
+
+import (
+	"sort"
+	"sync"
+)
+
+// indexed lists the fields with secondary indexes, and how to read them
+// from a record and a query.
+var indexed = []struct {
+	name   string
+	record func(Record) string
+	query  func(Query) string
+}{
+	{"tenant", func(r Record) string { return r.TenantID }, func(q Query) string { return q.TenantID }},
+	{"service", func(r Record) string { return r.Service }, func(q Query) string { return q.Service }},
+	{"account", func(r Record) string { return r.AccountID }, func(q Query) string { return q.AccountID }},
+	{"team", func(r Record) string { return r.Team }, func(q Query) string { return q.Team }},
+	{"category", func(r Record) string { return string(r.Category) }, func(q Query) string { return string(q.Category) }},
+	{"severity", func(r Record) string { return string(r.Severity) }, func(q Query) string { return string(q.Severity) }},
+	{"phase", func(r Record) string { return r.Phase }, func(q Query) string { return q.Phase }},
+}
+
+// MemoryStore is an in-process Repository with secondary indexes on the
+// equality-filter fields. A query scans only the smallest matching index
+// set rather than every record.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]Record
+	// index[field][value] is the set of workflow IDs with that value.
+	index map[string]map[string]map[string]struct{}
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	m := &MemoryStore{
+		records: make(map[string]Record),
+		index:   make(map[string]map[string]map[string]struct{}, len(indexed)),
+	}
+	for _, f := range indexed {
+		m.index[f.name] = make(map[string]map[string]struct{})
+	}
+	return m
+}
+
+// Upsert implements Repository.
+func (m *MemoryStore) Upsert(r Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(r)
+	return nil
+}
+
+// put replaces r's record and index entries. Callers hold mu.
+func (m *MemoryStore) put(r Record) {
+	if prev, ok := m.records[r.WorkflowID]; ok {
+		if prev.StartedAt != "" {
+			r.StartedAt = prev.StartedAt
+		}
+		for _, f := range indexed {
+			vals := m.index[f.name]
+			v := f.record(prev)
+			delete(vals[v], prev.WorkflowID)
+			if len(vals[v]) == 0 {
+				delete(vals, v)
+			}
+		}
+	}
+	m.records[r.WorkflowID] = r
+	for _, f := range indexed {
+		vals := m.index[f.name]
+		v := f.record(r)
+		if vals[v] == nil {
+			vals[v] = make(map[string]struct{})
+		}
+		vals[v][r.WorkflowID] = struct{}{}
+	}
+}
+
+// Get implements Repository.
+func (m *MemoryStore) Get(workflowID string) (Record, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.records[workflowID]
+	return r, ok, nil
+}
+
+// Query implements Repository.
+func (m *MemoryStore) Query(q Query) (Page, error) {
+	o, err := parseSort(q.Sort)
+	if err != nil {
+		return Page{}, err
+	}
+	var after *sortKey
+	if q.Cursor != "" {
+		k, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		after = &k
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	m.mu.RLock()
+	var matched []Record
+	for _, id := range m.candidates(q) {
+		r := m.records[id]
+		if !q.Match(r) {
+			continue
+		}
+		if after != nil && !o.less(*after, o.key(r)) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool { return o.less(o.key(matched[i]), o.key(matched[j])) })
+	page := Page{Records: matched}
+	if len(matched) > limit {
+		page.Records = matched[:limit]
+		page.NextCursor = encodeCursor(o.key(matched[limit-1]))
+	}
+	if page.Records == nil {
+		page.Records = []Record{}
+	}
+	return page, nil
+}
+
+// candidates returns the IDs in the smallest index set named by the query's
+// equality filters, or every ID when it has none. Callers hold mu.
+func (m *MemoryStore) candidates(q Query) []string {
+	var best map[string]struct{}
+	filtered := false
+	for _, f := range indexed {
+		v := f.query(q)
+		if v == "" {
+			continue
+		}
+		set := m.index[f.name][v]
+		if !filtered || len(set) < len(best) {
+			best, filtered = set, true
+		}
+	}
+	var ids []string
+	if filtered {
+		ids = make([]string, 0, len(best))
+		for id := range best {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	ids = make([]string, 0, len(m.records))
+	for id := range m.records {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// ErrInvalidQuery wraps query validation errors.
+var ErrInvalidQuery = apperr.NewSentinel(apperr.InvalidArgument, "history: invalid query")
+
+// DefaultLimit and MaxLimit bound Query.Limit.
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Query selects projections. Empty fields match everything. From and To
+// are inclusive YYYY-MM-DD bounds on StartedAt. Sort is a field name
+// (started_at, updated_at, delta_dollars), prefixed with "-" for
+// descending; the default is "-started_at". Cursor is the NextCursor of
+// the previous page.
+type Query struct {
+	TenantID  string
+	Service   string
+	AccountID string
+	Team      string
+	Category  domain.AnomalyCategory
+	Severity  domain.AnomalySeverity
+	Phase     string
+	Reason    string
+	MinDelta  *float64
+	MaxDelta  *float64
+	From      string
+	To        string
+
+	Sort   string
+	Limit  int
+	Cursor string
+}
+
+// Page is one page of results. NextCursor is empty on the last page.
+type Page struct {
+	Records    []Record `json:"records"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Match reports whether r satisfies the query's filters.
+func (q Query) Match(r Record) bool {
+	switch {
+	case q.TenantID != "" && r.TenantID != q.TenantID,
+		q.Service != "" && r.Service != q.Service,
+		q.AccountID != "" && r.AccountID != q.AccountID,
+		q.Team != "" && r.Team != q.Team,
+		q.Category != "" && r.Category != q.Category,
+		q.Severity != "" && r.Severity != q.Severity,
+		q.Phase != "" && r.Phase != q.Phase,
+		q.Reason != "" && r.Reason != q.Reason,
+		q.MinDelta != nil && r.DeltaDollars < *q.MinDelta,
+		q.MaxDelta != nil && r.DeltaDollars > *q.MaxDelta:
+		return false
+	}
+	day := r.StartedAt
+	if len(day) > 10 {
+		day = day[:10]
+	}
+	if q.From != "" && day < q.From {
+		return false
+	}
+	if q.To != "" && day > q.To {
+		return false
+	}
+	return true
+}
+
+// order is a parsed Query.Sort.
+type order struct {
+	field string
+	desc  bool
+}
+
+func parseSort(s string) (order, error) {
+	if s == "" {
+		s = "-started_at"
+	}
+	o := order{field: strings.TrimPrefix(s, "-"), desc: strings.HasPrefix(s, "-")}
+	switch o.field {
+	case "started_at", "updated_at", "delta_dollars":
+		return o, nil
+	}
+	return order{}, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, o.field)
+}
+
+// sortKey is a record's position in an ordering: the sort value, with the
+// workflow ID breaking ties.
+type sortKey struct {
+	S  string  `json:"s,omitempty"`
+	N  float64 `json:"n,omitempty"`
+	ID string  `json:"id"`
+}
+
+func (o order) key(r Record) sortKey {
+	switch o.field {
+	case "updated_at":
+		return sortKey{S: r.UpdatedAt, ID: r.WorkflowID}
+	case "delta_dollars":
+		return sortKey{N: r.DeltaDollars, ID: r.WorkflowID}
+	default:
+		return sortKey{S: r.StartedAt, ID: r.WorkflowID}
+	}
+}
+
+// less reports whether a sorts before b.
+func (o order) less(a, b sortKey) bool {
+	c := 0
+	switch {
+	case a.N < b.N, a.S < b.S:
+		c = -1
+	case a.N > b.N, a.S > b.S:
+		c = 1
+	}
+	if o.desc {
+		c = -c
+	}
+	if c == 0 {
+		return a.ID < b.ID
+	}
+	return c < 0
+}
+
+func encodeCursor(k sortKey) string {
+	b, _ := json.Marshal(k)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (sortKey, error) {
+	var k sortKey
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &k)
+	}
+	if err != nil || k.ID == "" {
+		return sortKey{}, fmt.Errorf("%w: malformed cursor", ErrInvalidQuery)
+	}
+	return k, nil
+}
@@ -5,22 +5,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
 	"github.com/finops-claw-gang/finops-go/internal/uischema"
 )
 
-// RegisterTools registers all FinOps MCP tools on the given server.
-func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier) {
+// Access is who calls the tools and where their decisions are recorded.
+// A nil Principal is unrestricted, as for a trusted local stdio session.
+// Approvals, denials and permission denials go to Audit when set; a nil
+// Auditor records permission denials there, or logs them without Audit.
+type Access struct {
+	Principal *rbac.Principal
+	// Authenticate re-checks the caller's credentials before every tool
+	// call, failing once they expire. nil = the Principal never expires.
+	Authenticate func(ctx context.Context) error
+	Auditor      rbac.Auditor
+	Audit        audit.Logger
+}
+
+// RegisterTools registers all FinOps MCP tools on the given server. Each
+// tool checks the same permission as its HTTP API counterpart, and confines
+// a principal bound to a tenant to that tenant's workflows.
+func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier, access Access) {
+	if access.Auditor == nil && access.Audit != nil {
+		access.Auditor = audit.RBACAuditor{Log: access.Audit}
+	}
 	mcp.AddTool(server,
 		&mcp.Tool{
 			Name:        "list_anomalies",
 			Description: "List recent anomaly workflows with status, service, and cost delta",
 		},
-		listAnomaliesHandler(q),
+		guarded(access, rbac.PermRead, "list_anomalies", listAnomaliesHandler(q, access)),
 	)
 
 	mcp.AddTool(server,
@@ -28,7 +52,7 @@ func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier) {
 			Name:        "get_anomaly_state",
 			Description: "Get full state and evidence for a specific anomaly workflow",
 		},
-		getAnomalyStateHandler(q),
+		guarded(access, rbac.PermRead, "get_anomaly_state", getAnomalyStateHandler(q, access)),
 	)
 
 	mcp.AddTool(server,
@@ -36,7 +60,7 @@ func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier) {
 			Name:        "get_anomaly_ui",
 			Description: "Get UI schema (components + actions) for rendering an anomaly workflow",
 		},
-		getAnomalyUIHandler(q),
+		guarded(access, rbac.PermRead, "get_anomaly_ui", getAnomalyUIHandler(q, access)),
 	)
 
 	mcp.AddTool(server,
@@ -44,7 +68,7 @@ func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier) {
 			Name:        "approve_actions",
 			Description: "Approve pending workflow actions",
 		},
-		approveActionsHandler(q),
+		guarded(access, rbac.PermApprove, "approve_actions", approveActionsHandler(q, access)),
 	)
 
 	mcp.AddTool(server,
@@ -52,24 +76,107 @@ func RegisterTools(server *mcp.Server, q querier.WorkflowQuerier) {
 			Name:        "deny_actions",
 			Description: "Deny pending workflow actions",
 		},
-		denyActionsHandler(q),
+		guarded(access, rbac.PermApprove, "deny_actions", denyActionsHandler(q, access)),
 	)
 }
 
+// guarded checks the caller's credentials and the principal's permission
+// before running a tool. Denials are audited and returned as tool errors.
+func guarded[In any](access Access, perm rbac.Permission, tool string, h mcp.ToolHandlerFor[In, any]) mcp.ToolHandlerFor[In, any] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, any, error) {
+		if access.Authenticate != nil {
+			if err := access.Authenticate(ctx); err != nil {
+				return errorResult(ctx, apperr.Wrap(apperr.Unauthenticated, "caller credentials are no longer valid", err)), nil, nil
+			}
+		}
+		if access.Principal != nil {
+			if err := rbac.Check(ctx, access.Auditor, *access.Principal, perm, "mcp", tool); err != nil {
+				return errorResult(ctx, err), nil, nil
+			}
+		}
+		return h(ctx, req, input)
+	}
+}
+
+// tenantScope returns the tenant the principal is confined to. scoped is
+// false for admins, and without a principal.
+func (a Access) tenantScope() (tenant string, scoped bool) {
+	if a.Principal == nil || a.Principal.Admin {
+		return "", false
+	}
+	return a.Principal.TenantID, true
+}
+
+// scopeTenant resolves the tenant filter of a list from the requested one,
+// as the HTTP API does. A scoped principal always gets its own tenant;
+// asking for another is answered like an unknown tenant.
+func scopeTenant(access Access, requested string) (string, error) {
+	own, scoped := access.tenantScope()
+	switch {
+	case !scoped:
+		return requested, nil
+	case own == "":
+		return "", apperr.New(apperr.Forbidden, "principal has no tenant")
+	case requested != "" && requested != own:
+		return "", apperr.New(apperr.NotFound, "tenant not found")
+	}
+	return own, nil
+}
+
+// loadWorkflow reads the state of a workflow and checks the principal may
+// see it. Another tenant's workflow is reported as not found, so its
+// existence does not leak.
+func loadWorkflow(ctx context.Context, q querier.WorkflowQuerier, access Access, workflowID string) (*workflows.WorkflowResult, error) {
+	if workflowID == "" {
+		return nil, apperr.New(apperr.InvalidArgument, "workflow_id is required")
+	}
+	result, err := q.GetWorkflowState(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, apperr.New(apperr.NotFound, "workflow not found")
+	}
+	if own, scoped := access.tenantScope(); scoped && (own == "" || result.State.Tenant.TenantID != own) {
+		return nil, apperr.New(apperr.NotFound, "workflow not found")
+	}
+	return result, nil
+}
+
 type listAnomaliesInput struct {
-	Status string `json:"status,omitempty"`
+	Status          string   `json:"status,omitempty" jsonschema:"workflow status, e.g. Running or Completed"`
+	TenantID        string   `json:"tenant_id,omitempty"`
+	Service         string   `json:"service,omitempty"`
+	AccountID       string   `json:"account_id,omitempty"`
+	Category        string   `json:"category,omitempty"`
+	Severity        string   `json:"severity,omitempty" jsonschema:"low, medium, high or critical"`
+	Phase           string   `json:"phase,omitempty" jsonschema:"lifecycle phase, e.g. hil_gate or monitoring"`
+	ApprovalStatus  string   `json:"approval_status,omitempty" jsonschema:"e.g. pending, approved or denied"`
+	MinDeltaDollars *float64 `json:"min_delta_dollars,omitempty" jsonschema:"minimum daily cost delta in dollars"`
 }
 
-func listAnomaliesHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[listAnomaliesInput, any] {
+func listAnomaliesHandler(q querier.WorkflowQuerier, access Access) mcp.ToolHandlerFor[listAnomaliesInput, any] {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input listAnomaliesInput) (*mcp.CallToolResult, any, error) {
-		opts := querier.ListOptions{TaskQueue: "finops-anomaly"}
-		if input.Status != "" {
-			opts.StatusFilter = input.Status
+		tenant, err := scopeTenant(access, input.TenantID)
+		if err != nil {
+			return errorResult(ctx, err), nil, nil
+		}
+		opts := querier.ListOptions{
+			TaskQueue:       "finops-anomaly",
+			StatusFilter:    input.Status,
+			TenantID:        tenant,
+			Service:         input.Service,
+			AccountID:       input.AccountID,
+			Category:        input.Category,
+			Severity:        input.Severity,
+			Phase:           input.Phase,
+			ApprovalStatus:  input.ApprovalStatus,
+			MinDeltaDollars: input.MinDeltaDollars,
 		}
 
 		workflows, err := q.ListWorkflows(ctx, opts)
 		if err != nil {
-			return nil, nil, fmt.Errorf("list_anomalies: %w", err)
+			return errorResult(ctx, fmt.Errorf("list_anomalies: %w", err)), nil, nil
 		}
 
 		return textResult(workflows)
@@ -80,30 +187,22 @@ type workflowIDInput struct {
 	WorkflowID string `json:"workflow_id"`
 }
 
-func getAnomalyStateHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[workflowIDInput, any] {
+func getAnomalyStateHandler(q querier.WorkflowQuerier, access Access) mcp.ToolHandlerFor[workflowIDInput, any] {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input workflowIDInput) (*mcp.CallToolResult, any, error) {
-		if input.WorkflowID == "" {
-			return errorResult("workflow_id is required"), nil, nil
-		}
-
-		result, err := q.GetWorkflowState(ctx, input.WorkflowID)
+		result, err := loadWorkflow(ctx, q, access, input.WorkflowID)
 		if err != nil {
-			return nil, nil, fmt.Errorf("get_anomaly_state: %w", err)
+			return errorResult(ctx, fmt.Errorf("get_anomaly_state: %w", err)), nil, nil
 		}
 
 		return textResult(result)
 	}
 }
 
-func getAnomalyUIHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[workflowIDInput, any] {
+func getAnomalyUIHandler(q querier.WorkflowQuerier, access Access) mcp.ToolHandlerFor[workflowIDInput, any] {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input workflowIDInput) (*mcp.CallToolResult, any, error) {
-		if input.WorkflowID == "" {
-			return errorResult("workflow_id is required"), nil, nil
-		}
-
-		result, err := q.GetWorkflowState(ctx, input.WorkflowID)
+		result, err := loadWorkflow(ctx, q, access, input.WorkflowID)
 		if err != nil {
-			return nil, nil, fmt.Errorf("get_anomaly_ui: %w", err)
+			return errorResult(ctx, fmt.Errorf("get_anomaly_ui: %w", err)), nil, nil
 		}
 
 		schema := uischema.Build(result.State)
@@ -113,42 +212,100 @@ func getAnomalyUIHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[workflowI
 
 type approvalInput struct {
 	WorkflowID string `json:"workflow_id"`
-	By         string `json:"by"`
+	By         string `json:"by,omitempty"`
 	Reason     string `json:"reason,omitempty"`
 }
 
-func approveActionsHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[approvalInput, any] {
+func approveActionsHandler(q querier.WorkflowQuerier, access Access) mcp.ToolHandlerFor[approvalInput, any] {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input approvalInput) (*mcp.CallToolResult, any, error) {
-		if input.WorkflowID == "" || input.By == "" {
-			return errorResult("workflow_id and by are required"), nil, nil
+		resp, err := approvalResponse(access, input, true)
+		if err != nil {
+			return errorResult(ctx, err), nil, nil
+		}
+		if _, scoped := access.tenantScope(); scoped {
+			// Only the tenant's own workflows may be decided.
+			if _, err := loadWorkflow(ctx, q, access, input.WorkflowID); err != nil {
+				return errorResult(ctx, fmt.Errorf("approve_actions: %w", err)), nil, nil
+			}
 		}
 
-		resp := activities.ApprovalResponse{Approved: true, By: input.By, Reason: input.Reason}
 		result, err := q.SubmitApproval(ctx, input.WorkflowID, resp)
+		auditApproval(ctx, access, resp, input.WorkflowID, result, err)
 		if err != nil {
-			return nil, nil, fmt.Errorf("approve_actions: %w", err)
+			return errorResult(ctx, fmt.Errorf("approve_actions: %w", err)), nil, nil
 		}
 
 		return textResult(map[string]string{"result": result})
 	}
 }
 
-func denyActionsHandler(q querier.WorkflowQuerier) mcp.ToolHandlerFor[approvalInput, any] {
+func denyActionsHandler(q querier.WorkflowQuerier, access Access) mcp.ToolHandlerFor[approvalInput, any] {
 	return func(ctx context.Context, _ *mcp.CallToolRequest, input approvalInput) (*mcp.CallToolResult, any, error) {
-		if input.WorkflowID == "" || input.By == "" {
-			return errorResult("workflow_id and by are required"), nil, nil
+		resp, err := approvalResponse(access, input, false)
+		if err != nil {
+			return errorResult(ctx, err), nil, nil
+		}
+		if _, scoped := access.tenantScope(); scoped {
+			// Only the tenant's own workflows may be decided.
+			if _, err := loadWorkflow(ctx, q, access, input.WorkflowID); err != nil {
+				return errorResult(ctx, fmt.Errorf("deny_actions: %w", err)), nil, nil
+			}
 		}
 
-		resp := activities.ApprovalResponse{Approved: false, By: input.By, Reason: input.Reason}
 		result, err := q.SubmitApproval(ctx, input.WorkflowID, resp)
+		auditApproval(ctx, access, resp, input.WorkflowID, result, err)
 		if err != nil {
-			return nil, nil, fmt.Errorf("deny_actions: %w", err)
+			return errorResult(ctx, fmt.Errorf("deny_actions: %w", err)), nil, nil
 		}
 
 		return textResult(map[string]string{"result": result})
 	}
 }
 
+// approvalResponse builds the update for an approval tool call. With a
+// principal, the approver is the principal's subject and input.By may only
+// restate it; without one, input.By is trusted as given.
+func approvalResponse(access Access, input approvalInput, approved bool) (activities.ApprovalResponse, error) {
+	if input.WorkflowID == "" {
+		return activities.ApprovalResponse{}, apperr.New(apperr.InvalidArgument, "workflow_id is required")
+	}
+	resp := activities.ApprovalResponse{Approved: approved, By: input.By, Reason: input.Reason}
+	if p := access.Principal; p != nil {
+		by, err := p.ActingAs(input.By)
+		if err != nil {
+			return activities.ApprovalResponse{}, err
+		}
+		resp.By, resp.Subject, resp.Email, resp.Issuer = by, p.Subject, p.Email, p.Issuer
+	}
+	if resp.By == "" {
+		return activities.ApprovalResponse{}, apperr.New(apperr.InvalidArgument, "workflow_id and by are required")
+	}
+	return resp, nil
+}
+
+// auditApproval records an approval or denial submitted through MCP.
+func auditApproval(ctx context.Context, access Access, resp activities.ApprovalResponse, workflowID, result string, err error) {
+	e := audit.Event{
+		Actor:      "unauthenticated",
+		Source:     audit.SourceMCP,
+		Action:     audit.ActionDenied,
+		WorkflowID: workflowID,
+		Outcome:    result,
+		Reason:     resp.Reason,
+		Details:    map[string]string{"by": resp.By},
+	}
+	if resp.Approved {
+		e.Action = audit.ActionApproved
+	}
+	if p := access.Principal; p != nil {
+		e.Actor, e.TenantID, e.Email, e.Issuer = p.Subject, p.TenantID, p.Email, p.Issuer
+	}
+	if err != nil {
+		e.Outcome = "error: " + err.Error()
+	}
+	audit.Record(ctx, access.Audit, e)
+}
+
 func textResult(v any) (*mcp.CallToolResult, any, error) {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -161,11 +318,27 @@ func textResult(v any) (*mcp.CallToolResult, any, error) {
 	}, nil, nil
 }
 
-func errorResult(msg string) *mcp.CallToolResult {
+// ToolError is the structured content of a failed tool call. Code is one
+// of the API's stable error codes, so agents can branch on it as HTTP
+// clients do on problem bodies.
+type ToolError struct {
+	Code    apperr.Code `json:"code"`
+	Message string      `json:"message"`
+}
+
+// errorResult reports err as a tool error: "code: message" as text, and
+// as ToolError structured content. Server errors are logged in full and
+// reported generically, as the HTTP API does.
+func errorResult(ctx context.Context, err error) *mcp.CallToolResult {
+	code, msg := apperr.Public(err)
+	if code.HTTPStatus() >= http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "mcp tool failed", "code", code, "error", err)
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: msg},
+			&mcp.TextContent{Text: string(code) + ": " + msg},
 		},
-		IsError: true,
+		StructuredContent: ToolError{Code: code, Message: msg},
+		IsError:           true,
 	}
 }
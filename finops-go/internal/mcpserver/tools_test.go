@@ -2,13 +2,22 @@ package mcpserver_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/serviceerror"
 
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/mcpserver"
+	"github.com/finops-claw-gang/finops-go/internal/rbac"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
@@ -20,13 +29,19 @@ type stubQuerier struct {
 	desc      *querier.WorkflowDescription
 	approval  string
 	err       error
+	listOpts  querier.ListOptions
+	resp      *activities.ApprovalResponse
 }
 
-func (s *stubQuerier) ListWorkflows(_ context.Context, _ querier.ListOptions) ([]querier.WorkflowSummary, error) {
+func (s *stubQuerier) ListWorkflows(_ context.Context, opts querier.ListOptions) ([]querier.WorkflowSummary, error) {
+	s.listOpts = opts
 	return s.workflows, s.err
 }
 
 func (s *stubQuerier) GetWorkflowState(_ context.Context, _ string) (*workflows.WorkflowResult, error) {
+	if s.state != nil {
+		return s.state, nil // err is for the call under test
+	}
 	return s.state, s.err
 }
 
@@ -34,10 +49,16 @@ func (s *stubQuerier) DescribeWorkflow(_ context.Context, _ string) (*querier.Wo
 	return s.desc, s.err
 }
 
-func (s *stubQuerier) SubmitApproval(_ context.Context, _ string, _ activities.ApprovalResponse) (string, error) {
+func (s *stubQuerier) SubmitApproval(_ context.Context, _ string, resp activities.ApprovalResponse) (string, error) {
+	s.resp = &resp
 	return s.approval, s.err
 }
 
+// acmeState is the state of a workflow of tenant acme.
+func acmeState() *workflows.WorkflowResult {
+	return &workflows.WorkflowResult{State: domain.NewFinOpsState(domain.NewTenantContext("acme"))}
+}
+
 func TestRegisterTools(t *testing.T) {
 	q := &stubQuerier{
 		state: &workflows.WorkflowResult{
@@ -46,8 +67,333 @@ func TestRegisterTools(t *testing.T) {
 	}
 
 	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
-	mcpserver.RegisterTools(server, q)
+	mcpserver.RegisterTools(server, q, mcpserver.Access{})
 
 	// Verify it compiles and registers without panic.
 	assert.NotNil(t, server)
 }
+
+// connect registers the tools and returns an in-memory client session.
+func connect(t *testing.T, q querier.WorkflowQuerier, access mcpserver.Access) *mcp.ClientSession {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "v1"}, nil)
+	mcpserver.RegisterTools(server, q, access)
+
+	ctx := context.Background()
+	serverT, clientT := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverT, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "v1"}, nil)
+	cs, err := client.Connect(ctx, clientT, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func TestListAnomalies_Filters(t *testing.T) {
+	q := &stubQuerier{}
+	cs := connect(t, q, mcpserver.Access{})
+
+	ctx := context.Background()
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{
+		Name: "list_anomalies",
+		Arguments: map[string]any{
+			"tenant_id":         "acme",
+			"severity":          "critical",
+			"approval_status":   "pending",
+			"min_delta_dollars": 100,
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+
+	assert.Equal(t, "finops-anomaly", q.listOpts.TaskQueue)
+	assert.Equal(t, "acme", q.listOpts.TenantID)
+	assert.Equal(t, "critical", q.listOpts.Severity)
+	assert.Equal(t, "pending", q.listOpts.ApprovalStatus)
+	require.NotNil(t, q.listOpts.MinDeltaDollars)
+	assert.Equal(t, 100.0, *q.listOpts.MinDeltaDollars)
+}
+
+func TestTools_TenantScope(t *testing.T) {
+	viewer := &rbac.Principal{Subject: "agent", TenantID: "acme", Roles: []rbac.Role{rbac.Viewer}}
+	tests := []struct {
+		name       string
+		principal  *rbac.Principal
+		tool       string
+		args       map[string]any
+		wantCode   apperr.Code // "" = success
+		wantTenant string      // list filter passed to the querier
+	}{
+		{name: "own tenant's workflow", principal: viewer, tool: "get_anomaly_state", args: map[string]any{"workflow_id": "wf-acme"}},
+		{name: "other tenant's workflow", principal: viewer, tool: "get_anomaly_state", args: map[string]any{"workflow_id": "wf-globex"}, wantCode: apperr.NotFound},
+		{name: "other tenant's UI", principal: viewer, tool: "get_anomaly_ui", args: map[string]any{"workflow_id": "wf-globex"}, wantCode: apperr.NotFound},
+		{name: "list defaults to own tenant", principal: viewer, tool: "list_anomalies", args: map[string]any{}, wantTenant: "acme"},
+		{name: "list of other tenant", principal: viewer, tool: "list_anomalies", args: map[string]any{"tenant_id": "globex"}, wantCode: apperr.NotFound},
+		{
+			name: "admin reads any tenant", principal: &rbac.Principal{Subject: "root", Admin: true},
+			tool: "get_anomaly_state", args: map[string]any{"workflow_id": "wf-globex"},
+		},
+		{
+			name: "admin lists any tenant", principal: &rbac.Principal{Subject: "root", Admin: true},
+			tool: "list_anomalies", args: map[string]any{"tenant_id": "globex"}, wantTenant: "globex",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &tenantQuerier{}
+			cs := connect(t, q, mcpserver.Access{Principal: tt.principal})
+			res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			require.NoError(t, err)
+			if tt.wantCode == "" {
+				require.False(t, res.IsError, res.Content[0].(*mcp.TextContent).Text)
+				assert.Equal(t, tt.wantTenant, q.listOpts.TenantID)
+				return
+			}
+			require.True(t, res.IsError)
+			text := res.Content[0].(*mcp.TextContent).Text
+			assert.True(t, strings.HasPrefix(text, string(tt.wantCode)+": "), text)
+			assert.NotContains(t, text, "globex")
+		})
+	}
+}
+
+// tenantQuerier serves workflows "wf-<tenant>" owned by <tenant>.
+type tenantQuerier struct {
+	stubQuerier
+	submitted []string
+}
+
+func (q *tenantQuerier) GetWorkflowState(_ context.Context, id string) (*workflows.WorkflowResult, error) {
+	tenant := strings.TrimPrefix(id, "wf-")
+	return &workflows.WorkflowResult{State: domain.NewFinOpsState(domain.NewTenantContext(tenant))}, nil
+}
+
+func (q *tenantQuerier) SubmitApproval(_ context.Context, id string, _ activities.ApprovalResponse) (string, error) {
+	q.submitted = append(q.submitted, id)
+	return "approved", nil
+}
+
+func TestTools_CrossTenantApproval(t *testing.T) {
+	approver := &rbac.Principal{Subject: "agent", TenantID: "acme", Roles: []rbac.Role{rbac.Approver}}
+	for _, tool := range []string{"approve_actions", "deny_actions"} {
+		t.Run(tool, func(t *testing.T) {
+			q := &tenantQuerier{}
+			cs := connect(t, q, mcpserver.Access{Principal: approver})
+
+			res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+				Name: tool, Arguments: map[string]any{"workflow_id": "wf-globex"},
+			})
+			require.NoError(t, err)
+			require.True(t, res.IsError)
+			text := res.Content[0].(*mcp.TextContent).Text
+			assert.True(t, strings.HasPrefix(text, string(apperr.NotFound)+": "), text)
+			assert.Empty(t, q.submitted, "nothing is submitted to another tenant's workflow")
+
+			res, err = cs.CallTool(context.Background(), &mcp.CallToolParams{
+				Name: tool, Arguments: map[string]any{"workflow_id": "wf-acme"},
+			})
+			require.NoError(t, err)
+			require.False(t, res.IsError)
+			assert.Equal(t, []string{"wf-acme"}, q.submitted)
+		})
+	}
+}
+
+type recordingAuditor struct{ denials []rbac.Denial }
+
+func (a *recordingAuditor) RecordDenial(_ context.Context, d rbac.Denial) {
+	a.denials = append(a.denials, d)
+}
+
+func TestTools_RBAC(t *testing.T) {
+	tests := []struct {
+		name      string
+		roles     []rbac.Role
+		tool      string
+		wantError bool
+	}{
+		{name: "viewer reads", roles: []rbac.Role{rbac.Viewer}, tool: "get_anomaly_state"},
+		{name: "viewer cannot approve", roles: []rbac.Role{rbac.Viewer}, tool: "approve_actions", wantError: true},
+		{name: "analyst cannot deny", roles: []rbac.Role{rbac.Analyst}, tool: "deny_actions", wantError: true},
+		{name: "approver approves", roles: []rbac.Role{rbac.Approver}, tool: "approve_actions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &recordingAuditor{}
+			q := &stubQuerier{
+				approval: "approved",
+				state:    &workflows.WorkflowResult{State: domain.NewFinOpsState(domain.NewTenantContext("acme"))},
+			}
+			cs := connect(t, q, mcpserver.Access{
+				Principal: &rbac.Principal{Subject: "agent", TenantID: "acme", Roles: tt.roles},
+				Auditor:   a,
+			})
+
+			args := map[string]any{"workflow_id": "wf-1"}
+			if tt.tool != "get_anomaly_state" {
+				args["by"] = "agent"
+			}
+			res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tt.tool, Arguments: args})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantError, res.IsError)
+			if tt.wantError {
+				require.Len(t, a.denials, 1)
+				assert.Equal(t, "mcp", a.denials[0].Surface)
+				assert.Equal(t, tt.tool, a.denials[0].Operation)
+			} else {
+				assert.Empty(t, a.denials)
+			}
+		})
+	}
+}
+
+func TestTools_Audit(t *testing.T) {
+	log, err := audit.OpenFileLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	cs := connect(t, &stubQuerier{approval: "approved", state: acmeState()}, mcpserver.Access{
+		Principal: &rbac.Principal{Subject: "agent-1", TenantID: "acme", Roles: []rbac.Role{rbac.Approver, rbac.Viewer}},
+		Audit:     log,
+	})
+	ctx := context.Background()
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "approve_actions",
+		Arguments: map[string]any{"workflow_id": "wf-1", "by": "agent-1", "reason": "looks safe"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.IsError)
+
+	events, err := log.Query(audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, audit.ActionApproved, events[0].Action)
+	assert.Equal(t, audit.SourceMCP, events[0].Source)
+	assert.Equal(t, "agent-1", events[0].Actor)
+	assert.Equal(t, "acme", events[0].TenantID)
+	assert.Equal(t, "looks safe", events[0].Reason)
+
+	// Permission denials land in the same trail.
+	cs = connect(t, &stubQuerier{}, mcpserver.Access{
+		Principal: &rbac.Principal{Subject: "agent-2", Roles: []rbac.Role{rbac.Viewer}},
+		Audit:     log,
+	})
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "deny_actions",
+		Arguments: map[string]any{"workflow_id": "wf-1", "by": "agent-2"},
+	})
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	events, err = log.Query(audit.Filter{Action: audit.ActionPermissionDenied})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "agent-2", events[0].Actor)
+}
+
+func TestTools_ApproverIdentity(t *testing.T) {
+	principal := &rbac.Principal{
+		Subject: "u-123", Email: "lead@example.com", Issuer: "https://issuer.example",
+		TenantID: "acme", Roles: []rbac.Role{rbac.Approver},
+	}
+	tests := []struct {
+		name      string
+		principal *rbac.Principal
+		by        string
+		wantError bool
+		wantResp  *activities.ApprovalResponse
+	}{
+		{
+			name: "by defaults to principal", principal: principal,
+			wantResp: &activities.ApprovalResponse{By: "u-123", Subject: "u-123", Email: "lead@example.com", Issuer: "https://issuer.example"},
+		},
+		{
+			name: "by restates email", principal: principal, by: "lead@example.com",
+			wantResp: &activities.ApprovalResponse{By: "u-123", Subject: "u-123", Email: "lead@example.com", Issuer: "https://issuer.example"},
+		},
+		{name: "by names someone else", principal: principal, by: "cfo@example.com", wantError: true},
+		{name: "no principal trusts by", by: "ops-lead", wantResp: &activities.ApprovalResponse{By: "ops-lead"}},
+		{name: "no principal needs by", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &stubQuerier{approval: "denied", state: acmeState()}
+			cs := connect(t, q, mcpserver.Access{Principal: tt.principal})
+			args := map[string]any{"workflow_id": "wf-1"}
+			if tt.by != "" {
+				args["by"] = tt.by
+			}
+			res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "deny_actions", Arguments: args})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantError, res.IsError)
+			assert.Equal(t, tt.wantResp, q.resp)
+		})
+	}
+}
+
+func TestTools_ErrorCodes(t *testing.T) {
+	approver := &rbac.Principal{Subject: "agent", TenantID: "acme", Roles: []rbac.Role{rbac.Approver, rbac.Viewer}}
+	tests := []struct {
+		name     string
+		tool     string
+		args     map[string]any
+		access   mcpserver.Access
+		err      error
+		wantCode apperr.Code
+	}{
+		{name: "missing id", tool: "get_anomaly_state", args: map[string]any{"workflow_id": ""}, wantCode: apperr.InvalidArgument},
+		{
+			name: "not found", tool: "get_anomaly_state", args: map[string]any{"workflow_id": "wf-1"},
+			err: serviceerror.NewNotFound("workflow not found"), wantCode: apperr.NotFound,
+		},
+		{
+			name: "already decided", tool: "approve_actions", args: map[string]any{"workflow_id": "wf-1"},
+			access: mcpserver.Access{Principal: approver}, err: querier.ErrAlreadyDecided, wantCode: apperr.Conflict,
+		},
+		{
+			name: "not at the gate", tool: "deny_actions", args: map[string]any{"workflow_id": "wf-1"},
+			access: mcpserver.Access{Principal: approver}, err: querier.ErrNotAwaitingApproval, wantCode: apperr.InvalidState,
+		},
+		{
+			name: "forbidden", tool: "approve_actions", args: map[string]any{"workflow_id": "wf-1"},
+			access:   mcpserver.Access{Principal: &rbac.Principal{Subject: "agent", Roles: []rbac.Role{rbac.Viewer}}},
+			wantCode: apperr.Forbidden,
+		},
+		{
+			name: "expired credentials", tool: "approve_actions", args: map[string]any{"workflow_id": "wf-1"},
+			access: mcpserver.Access{
+				Principal:    approver,
+				Authenticate: func(context.Context) error { return errors.New("oidc: token is expired") },
+			},
+			wantCode: apperr.Unauthenticated,
+		},
+		{
+			name: "temporal down", tool: "list_anomalies", args: map[string]any{},
+			err: serviceerror.NewUnavailable("dial tcp 10.0.0.7:7233"), wantCode: apperr.UpstreamUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &stubQuerier{err: tt.err}
+			if tt.access.Principal != nil {
+				q.state = acmeState() // scoped approvers load the workflow first
+			}
+			cs := connect(t, q, tt.access)
+			res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			require.NoError(t, err)
+			require.True(t, res.IsError)
+			if tt.wantCode == apperr.Unauthenticated {
+				assert.Nil(t, q.resp, "no approval is submitted without valid credentials")
+			}
+
+			text := res.Content[0].(*mcp.TextContent).Text
+			assert.True(t, strings.HasPrefix(text, string(tt.wantCode)+": "), text)
+			data, err := json.Marshal(res.StructuredContent)
+			require.NoError(t, err)
+			var got mcpserver.ToolError
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.NotContains(t, got.Message, "10.0.0.7")
+		})
+	}
+}
@@ -2,14 +2,17 @@
 package observability
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
 	tlog "go.temporal.io/sdk/log"
 )
 
 // InitLogger configures the global slog logger with JSON output at the given level.
+// Records logged with a context carry its request ID and trace and span IDs.
 func InitLogger(level string) *slog.Logger {
 	var lvl slog.Level
 	switch strings.ToLower(level) {
@@ -23,11 +26,42 @@ func InitLogger(level string) *slog.Logger {
 		lvl = slog.LevelInfo
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl}))
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: lvl})))
 	slog.SetDefault(logger)
 	return logger
 }
 
+// ContextHandler adds the request ID, trace ID and span ID found in the
+// record's context to each record.
+type ContextHandler struct {
+	slog.Handler
+}
+
+// NewContextHandler wraps h.
+func NewContextHandler(h slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: h}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := RequestIDFromContext(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
 // TemporalSlogAdapter adapts slog.Logger to Temporal's log.Logger interface.
 type TemporalSlogAdapter struct {
 	logger *slog.Logger
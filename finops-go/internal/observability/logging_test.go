@@ -0,0 +1,60 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestContextHandler(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02},
+		SpanID:     trace.SpanID{0x03},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want map[string]string
+	}{
+		{
+			name: "request and span",
+			ctx:  trace.ContextWithSpanContext(WithRequestID(context.Background(), "req-1"), sc),
+			want: map[string]string{
+				"request_id": "req-1",
+				"trace_id":   sc.TraceID().String(),
+				"span_id":    sc.SpanID().String(),
+			},
+		},
+		{
+			name: "neither",
+			ctx:  context.Background(),
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+			logger.InfoContext(tt.ctx, "hello")
+
+			var rec map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+			assert.Equal(t, "test", rec["component"])
+			for _, k := range []string{"request_id", "trace_id", "span_id"} {
+				if want, ok := tt.want[k]; ok {
+					assert.Equal(t, want, rec[k], k)
+				} else {
+					assert.NotContains(t, rec, k)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package observability
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -11,15 +12,34 @@ import (
 
 // Metrics holds OTel metric instruments for the FinOps system.
 type Metrics struct {
-	AnomalyCount    metric.Int64Counter
-	ApprovalLatency metric.Float64Histogram
-	SavingsRealized metric.Float64Counter
-	ActivityCalls   metric.Int64Counter
+	AnomalyCount     metric.Int64Counter
+	ApprovalLatency  metric.Float64Histogram
+	SavingsRealized  metric.Float64Counter
+	ActivityCalls    metric.Int64Counter
+	WorkflowOutcomes metric.Int64Counter
+
+	ConnectorLatency   metric.Float64Histogram
+	LimiterWait        metric.Float64Histogram
+	AthenaBytesScanned metric.Int64Histogram
+	HTTPRequests       metric.Float64Histogram
+	APICost            metric.Float64Counter
+	CacheRequests      metric.Int64Counter
+	PremiumFallbacks   metric.Int64Counter
 }
 
-// NewMetrics creates the FinOps metric instruments.
+// latencyBuckets suit API calls and limiter waits, from a few
+// milliseconds to the 30s limiter timeout and multi-minute Athena queries.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// NewMetrics creates the FinOps metric instruments on the global meter
+// provider.
 func NewMetrics() (*Metrics, error) {
-	meter := otel.Meter("finops")
+	return NewMetricsFor(otel.GetMeterProvider())
+}
+
+// NewMetricsFor creates the FinOps metric instruments on mp.
+func NewMetricsFor(mp metric.MeterProvider) (*Metrics, error) {
+	meter := mp.Meter("finops")
 
 	anomalyCount, err := meter.Int64Counter("finops.anomaly.count",
 		metric.WithDescription("Number of anomalies processed"),
@@ -30,6 +50,7 @@ func NewMetrics() (*Metrics, error) {
 
 	approvalLatency, err := meter.Float64Histogram("finops.approval.latency_seconds",
 		metric.WithDescription("Time from pending to approval decision"),
+		metric.WithExplicitBucketBoundaries(60, 300, 900, 1800, 3600, 4*3600, 8*3600, 24*3600),
 	)
 	if err != nil {
 		return nil, err
@@ -49,14 +70,98 @@ func NewMetrics() (*Metrics, error) {
 		return nil, err
 	}
 
+	workflowOutcomes, err := meter.Int64Counter("finops.workflow.outcomes",
+		metric.WithDescription("Number of workflows ended, by type, status and reason"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	connectorLatency, err := meter.Float64Histogram("finops.connector.duration_seconds",
+		metric.WithDescription("Duration of connector calls, including rate limiter waits"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	limiterWait, err := meter.Float64Histogram("finops.ratelimit.wait_seconds",
+		metric.WithDescription("Time spent waiting for a rate limiter token"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	athenaBytes, err := meter.Int64Histogram("finops.athena.scanned_bytes",
+		metric.WithDescription("Bytes scanned per Athena query"),
+		metric.WithExplicitBucketBoundaries(1<<20, 10<<20, 100<<20, 1<<30, 10<<30, 100<<30, 1<<40),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequests, err := meter.Float64Histogram("finops.http.request.duration_seconds",
+		metric.WithDescription("Duration of API requests"),
+		metric.WithExplicitBucketBoundaries(latencyBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	apiCost, err := meter.Float64Counter("finops.api_cost.dollars",
+		metric.WithDescription("Estimated cost of the desk's own AWS API calls"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheRequests, err := meter.Int64Counter("finops.cache.requests",
+		metric.WithDescription("Cache lookups, by cache, operation and result (memory, disk or miss)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	premiumFallbacks, err := meter.Int64Counter("finops.premium.fallbacks",
+		metric.WithDescription("Premium tenant workflows started on the shared queues because no worker polls the tenant's queues"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Metrics{
-		AnomalyCount:    anomalyCount,
-		ApprovalLatency: approvalLatency,
-		SavingsRealized: savingsRealized,
-		ActivityCalls:   activityCalls,
+		AnomalyCount:       anomalyCount,
+		ApprovalLatency:    approvalLatency,
+		SavingsRealized:    savingsRealized,
+		ActivityCalls:      activityCalls,
+		WorkflowOutcomes:   workflowOutcomes,
+		ConnectorLatency:   connectorLatency,
+		LimiterWait:        limiterWait,
+		AthenaBytesScanned: athenaBytes,
+		HTTPRequests:       httpRequests,
+		APICost:            apiCost,
+		CacheRequests:      cacheRequests,
+		PremiumFallbacks:   premiumFallbacks,
 	}, nil
 }
 
+var defaultMetrics = sync.OnceValue(func() *Metrics {
+	m, err := NewMetrics()
+	if err != nil {
+		// Instrument names are constant; this cannot fail at run time.
+		panic(err)
+	}
+	return m
+})
+
+// Default returns the process-wide instruments. They record on the global
+// meter provider, so connectors and rate limiters record without wiring,
+// and nothing is exported until InitMetrics installs a provider.
+func Default() *Metrics {
+	return defaultMetrics()
+}
+
 // RecordAnomalyProcessed records a processed anomaly.
 func (m *Metrics) RecordAnomalyProcessed(ctx context.Context, category, severity string) {
 	m.AnomalyCount.Add(ctx, 1,
@@ -72,9 +177,104 @@ func (m *Metrics) RecordApprovalLatency(ctx context.Context, d time.Duration) {
 	m.ApprovalLatency.Record(ctx, d.Seconds())
 }
 
-// RecordActivity records an activity invocation.
-func (m *Metrics) RecordActivity(ctx context.Context, name string) {
+// RecordSavingsRealized records confirmed savings as a monthly run rate.
+func (m *Metrics) RecordSavingsRealized(ctx context.Context, dollars float64, tenantID, service string) {
+	m.SavingsRealized.Add(ctx, dollars,
+		metric.WithAttributes(
+			attribute.String("tenant_id", tenantID),
+			attribute.String("service", service),
+		),
+	)
+}
+
+// RecordActivity records a finished activity invocation.
+func (m *Metrics) RecordActivity(ctx context.Context, name string, err error) {
 	m.ActivityCalls.Add(ctx, 1,
-		metric.WithAttributes(attribute.String("activity", name)),
+		metric.WithAttributes(attribute.String("activity", name), outcome(err)),
 	)
 }
+
+// RecordWorkflowOutcome records an ended workflow. reason is the
+// workflow's own termination reason, if it reports one.
+func (m *Metrics) RecordWorkflowOutcome(ctx context.Context, workflowType, status, reason string) {
+	m.WorkflowOutcomes.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("workflow", workflowType),
+			attribute.String("status", status),
+			attribute.String("reason", reason),
+		),
+	)
+}
+
+// RecordConnectorCall records one call to an external system.
+func (m *Metrics) RecordConnectorCall(ctx context.Context, system, operation string, d time.Duration, err error) {
+	m.ConnectorLatency.Record(ctx, d.Seconds(),
+		metric.WithAttributes(
+			attribute.String("connector", system),
+			attribute.String("operation", operation),
+			outcome(err),
+		),
+	)
+}
+
+// RecordLimiterWait records time spent waiting for a token of service.
+func (m *Metrics) RecordLimiterWait(ctx context.Context, service string, d time.Duration) {
+	m.LimiterWait.Record(ctx, d.Seconds(),
+		metric.WithAttributes(attribute.String("service", service)),
+	)
+}
+
+// RecordAthenaBytesScanned records the data scanned by one Athena query.
+func (m *Metrics) RecordAthenaBytesScanned(ctx context.Context, workgroup string, n int64) {
+	m.AthenaBytesScanned.Record(ctx, n,
+		metric.WithAttributes(attribute.String("workgroup", workgroup)),
+	)
+}
+
+// RecordHTTPRequest records an API request. route is the matched mux
+// pattern, so path parameters do not multiply series.
+func (m *Metrics) RecordHTTPRequest(ctx context.Context, route string, status int, d time.Duration) {
+	m.HTTPRequests.Record(ctx, d.Seconds(),
+		metric.WithAttributes(
+			attribute.String("route", route),
+			attribute.Int("status", status),
+		),
+	)
+}
+
+// RecordAPICost records the estimated cost of an AWS API call made on
+// behalf of tenantID.
+func (m *Metrics) RecordAPICost(ctx context.Context, tenantID, service, operation string, dollars float64) {
+	m.APICost.Add(ctx, dollars,
+		metric.WithAttributes(
+			attribute.String("tenant_id", tenantID),
+			attribute.String("service", service),
+			attribute.String("operation", operation),
+		),
+	)
+}
+
+// RecordCacheLookup records a cache lookup. result is where it was
+// answered from: "memory", "disk" or "miss".
+func (m *Metrics) RecordCacheLookup(ctx context.Context, cache, operation, result string) {
+	m.CacheRequests.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("cache", cache),
+			attribute.String("operation", operation),
+			attribute.String("result", result),
+		),
+	)
+}
+
+// RecordPremiumFallback records a premium tenant's workflow started on the
+// shared queues.
+func (m *Metrics) RecordPremiumFallback(ctx context.Context, tenantID string) {
+	m.PremiumFallbacks.Add(ctx, 1, metric.WithAttributes(attribute.String("tenant_id", tenantID)))
+}
+
+func outcome(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("outcome", "error")
+	}
+	return attribute.String("outcome", "ok")
+}
@@ -0,0 +1,177 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// newTestMetrics returns instruments recording into a private reader.
+func newTestMetrics(t *testing.T) (*Metrics, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	m, err := NewMetricsFor(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	require.NoError(t, err)
+	return m, reader
+}
+
+// collect returns the data points of the named metric, keyed by their
+// attribute set.
+func collect[N int64 | float64](t *testing.T, reader *sdkmetric.ManualReader, name string) map[attribute.Distinct]any {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	points := map[attribute.Distinct]any{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			if md.Name != name {
+				continue
+			}
+			switch data := md.Data.(type) {
+			case metricdata.Sum[N]:
+				for _, dp := range data.DataPoints {
+					points[dp.Attributes.Equivalent()] = dp.Value
+				}
+			case metricdata.Histogram[N]:
+				for _, dp := range data.DataPoints {
+					points[dp.Attributes.Equivalent()] = dp
+				}
+			}
+		}
+	}
+	return points
+}
+
+func attrs(kvs ...attribute.KeyValue) attribute.Distinct {
+	set := attribute.NewSet(kvs...)
+	return set.Equivalent()
+}
+
+type lifecycleResult struct {
+	Reason string
+}
+
+func (r lifecycleResult) OutcomeReason() string { return r.Reason }
+
+// lifecycleWorkflow waits for an approval update and then runs an
+// activity that fails when the approval was a denial.
+func lifecycleWorkflow(ctx workflow.Context) (lifecycleResult, error) {
+	var approved *bool
+	if err := workflow.SetUpdateHandler(ctx, "approval", func(_ workflow.Context, ok bool) error {
+		approved = &ok
+		return nil
+	}); err != nil {
+		return lifecycleResult{}, err
+	}
+	if err := workflow.Await(ctx, func() bool { return approved != nil }); err != nil {
+		return lifecycleResult{}, err
+	}
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	if err := workflow.ExecuteActivity(ctx, "execute", *approved).Get(ctx, nil); err != nil {
+		return lifecycleResult{Reason: "execution_failed"}, nil
+	}
+	return lifecycleResult{Reason: "completed"}, nil
+}
+
+func executeActivity(_ context.Context, approved bool) error {
+	if !approved {
+		return errors.New("not approved")
+	}
+	return nil
+}
+
+func TestWorkerInterceptor(t *testing.T) {
+	tests := []struct {
+		name         string
+		approved     bool
+		wantReason   string
+		wantActivity string
+	}{
+		{"approved", true, "completed", "ok"},
+		{"activity fails", false, "execution_failed", "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, reader := newTestMetrics(t)
+
+			var s testsuite.WorkflowTestSuite
+			env := s.NewTestWorkflowEnvironment()
+			env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{m.WorkerInterceptor("approval")}})
+			env.RegisterWorkflow(lifecycleWorkflow)
+			env.RegisterActivityWithOptions(executeActivity, activity.RegisterOptions{Name: "execute"})
+			env.RegisterDelayedCallback(func() {
+				env.UpdateWorkflow("approval", "u-1", &testsuite.TestUpdateCallback{
+					OnAccept:   func() {},
+					OnReject:   func(err error) { t.Errorf("update rejected: %v", err) },
+					OnComplete: func(any, error) {},
+				}, tt.approved)
+			}, time.Hour)
+
+			env.ExecuteWorkflow(lifecycleWorkflow)
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+
+			outcomes := collect[int64](t, reader, "finops.workflow.outcomes")
+			assert.Equal(t, map[attribute.Distinct]any{
+				attrs(
+					attribute.String("workflow", "lifecycleWorkflow"),
+					attribute.String("status", "completed"),
+					attribute.String("reason", tt.wantReason),
+				): int64(1),
+			}, outcomes)
+
+			calls := collect[int64](t, reader, "finops.activity.calls")
+			// The failing activity is retried by the default retry policy,
+			// so count only that attempts were recorded under the outcome.
+			key := attrs(attribute.String("activity", "execute"), attribute.String("outcome", tt.wantActivity))
+			require.Contains(t, calls, key)
+			assert.GreaterOrEqual(t, calls[key], int64(1))
+
+			latency := collect[float64](t, reader, "finops.approval.latency_seconds")
+			require.Len(t, latency, 1)
+			for _, p := range latency {
+				dp := p.(metricdata.HistogramDataPoint[float64])
+				assert.Equal(t, uint64(1), dp.Count)
+				assert.Equal(t, time.Hour.Seconds(), dp.Sum, "latency runs from handler registration to the decision")
+			}
+		})
+	}
+}
+
+func TestInitMetrics(t *testing.T) {
+	handler, shutdown, err := InitMetrics("test")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = shutdown(context.Background()) })
+
+	Default().RecordConnectorCall(context.Background(), "Athena", "GetCURLineItems", 2*time.Second, nil)
+	Default().RecordLimiterWait(context.Background(), "athena", 100*time.Millisecond)
+	Default().RecordAthenaBytesScanned(context.Background(), "primary", 10<<20)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		`finops_connector_duration_seconds_count{connector="Athena",operation="GetCURLineItems",`,
+		`finops_ratelimit_wait_seconds_count{`,
+		`finops_athena_scanned_bytes_sum{`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, string(body), want)
+	}
+}
@@ -7,12 +7,15 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
 )
 
 // InitTracer sets up an OTel trace provider with OTLP HTTP exporter.
+// It also installs the W3C trace context and baggage propagators, so
+// incoming HTTP requests continue their caller's trace.
 // Returns a shutdown function that should be deferred.
 func InitTracer(ctx context.Context, serviceName string) (func(context.Context) error, error) {
 	exporter, err := otlptracehttp.New(ctx)
@@ -20,15 +23,9 @@ func InitTracer(ctx context.Context, serviceName string) (func(context.Context)
 		return nil, fmt.Errorf("otel: create exporter: %w", err)
 	}
 
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(serviceName),
-		),
-	)
+	res, err := newResource(serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("otel: create resource: %w", err)
+		return nil, err
 	}
 
 	tp := sdktrace.NewTracerProvider(
@@ -36,7 +33,28 @@ func InitTracer(ctx context.Context, serviceName string) (func(context.Context)
 		sdktrace.WithResource(res),
 	)
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
 
 	slog.Info("OpenTelemetry tracing initialized", "service", serviceName)
 	return tp.Shutdown, nil
 }
+
+// newResource describes this process as serviceName. The semconv version
+// must match the SDK's, or merging with resource.Default fails on
+// conflicting schema URLs.
+func newResource(serviceName string) (*resource.Resource, error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("otel: create resource: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// InitMetrics sets up an OTel meter provider backed by a Prometheus
+// exporter and installs it globally, so instruments from NewMetrics and
+// Default export through it. The returned handler serves the registry
+// in Prometheus text format, together with Go runtime and process metrics.
+// Returns a shutdown function that should be deferred.
+func InitMetrics(serviceName string) (http.Handler, func(context.Context) error, error) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	exporter, err := otelprom.New(otelprom.WithRegisterer(reg))
+	if err != nil {
+		return nil, nil, fmt.Errorf("otel: create prometheus exporter: %w", err)
+	}
+
+	res, err := newResource(serviceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(mp)
+
+	slog.Info("OpenTelemetry metrics initialized", "service", serviceName)
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{}), mp.Shutdown, nil
+}
+
+// ServeMetrics serves h on /metrics at addr until the process exits. A
+// listener failure is logged, not fatal: losing metrics should not stop
+// the API or the worker.
+func ServeMetrics(addr string, h http.Handler) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", h)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		slog.Info("metrics listening", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// RequestIDHeader is the Temporal header that carries the request ID into
+// workflows, updates and activities.
+const RequestIDHeader = "finops-request-id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the ID of the request being served.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID in ctx, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey{}).(string)
+	return v
+}
+
+// RequestIDFromWorkflow returns the request ID of the client call that
+// started the workflow or, in an update handler, sent the update.
+func RequestIDFromWorkflow(ctx workflow.Context) string {
+	v, _ := ctx.Value(requestIDKey{}).(string)
+	return v
+}
+
+// requestIDPropagator copies the request ID through Temporal headers.
+type requestIDPropagator struct{}
+
+// NewRequestIDPropagator returns a context propagator for the request ID.
+// Set it on the client of both the caller and the worker.
+func NewRequestIDPropagator() workflow.ContextPropagator {
+	return requestIDPropagator{}
+}
+
+func (requestIDPropagator) Inject(ctx context.Context, w workflow.HeaderWriter) error {
+	return injectRequestID(RequestIDFromContext(ctx), w)
+}
+
+func (requestIDPropagator) InjectFromWorkflow(ctx workflow.Context, w workflow.HeaderWriter) error {
+	return injectRequestID(RequestIDFromWorkflow(ctx), w)
+}
+
+func (requestIDPropagator) Extract(ctx context.Context, r workflow.HeaderReader) (context.Context, error) {
+	id, err := extractRequestID(r)
+	if err != nil || id == "" {
+		return ctx, err
+	}
+	return WithRequestID(ctx, id), nil
+}
+
+func (requestIDPropagator) ExtractToWorkflow(ctx workflow.Context, r workflow.HeaderReader) (workflow.Context, error) {
+	id, err := extractRequestID(r)
+	if err != nil || id == "" {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, requestIDKey{}, id), nil
+}
+
+func injectRequestID(id string, w workflow.HeaderWriter) error {
+	if id == "" {
+		return nil
+	}
+	p, err := converter.GetDefaultDataConverter().ToPayload(id)
+	if err != nil {
+		return fmt.Errorf("observability: encode request ID: %w", err)
+	}
+	w.Set(RequestIDHeader, p)
+	return nil
+}
+
+func extractRequestID(r workflow.HeaderReader) (string, error) {
+	p, ok := r.Get(RequestIDHeader)
+	if !ok {
+		return "", nil
+	}
+	var id string
+	if err := converter.GetDefaultDataConverter().FromPayload(p, &id); err != nil {
+		return "", fmt.Errorf("observability: decode request ID: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,109 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type header map[string]*commonpb.Payload
+
+func (h header) Set(key string, p *commonpb.Payload) { h[key] = p }
+
+func (h header) Get(key string) (*commonpb.Payload, bool) {
+	p, ok := h[key]
+	return p, ok
+}
+
+func (h header) ForEachKey(fn func(string, *commonpb.Payload) error) error {
+	for k, p := range h {
+		if err := fn(k, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRequestIDPropagator(t *testing.T) {
+	p := NewRequestIDPropagator()
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"with ID", "req-42"},
+		{"without ID", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.id != "" {
+				ctx = WithRequestID(ctx, tt.id)
+			}
+			h := header{}
+			require.NoError(t, p.Inject(ctx, h))
+			_, sent := h[RequestIDHeader]
+			assert.Equal(t, tt.id != "", sent)
+
+			got, err := p.Extract(context.Background(), h)
+			require.NoError(t, err)
+			assert.Equal(t, tt.id, RequestIDFromContext(got))
+		})
+	}
+}
+
+// approvalWorkflow waits for an update, then runs an activity from its
+// main coroutine, as the anomaly lifecycle does after approval.
+func approvalWorkflow(ctx workflow.Context) (string, error) {
+	var decided workflow.Context
+	if err := workflow.SetUpdateHandler(ctx, "approve", func(uctx workflow.Context) error {
+		// The test environment sends no update headers; stand in for the
+		// propagator.
+		decided = workflow.WithValue(uctx, requestIDKey{}, "req-approve")
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	if err := workflow.Await(ctx, func() bool { return decided != nil }); err != nil {
+		return "", err
+	}
+
+	ctx = ContinueTrace(ctx, decided)
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	var got string
+	err := workflow.ExecuteActivity(ctx, requestIDActivity).Get(ctx, &got)
+	return got, err
+}
+
+func requestIDActivity(ctx context.Context) (string, error) {
+	return RequestIDFromContext(ctx), nil
+}
+
+func TestContinueTrace(t *testing.T) {
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.SetContextPropagators([]workflow.ContextPropagator{NewRequestIDPropagator()})
+	env.RegisterWorkflow(approvalWorkflow)
+	env.RegisterActivityWithOptions(requestIDActivity, activity.RegisterOptions{Name: "requestIDActivity"})
+	env.RegisterDelayedCallback(func() {
+		env.UpdateWorkflow("approve", "u-1", &testsuite.TestUpdateCallback{
+			OnAccept:   func() {},
+			OnReject:   func(err error) { t.Errorf("update rejected: %v", err) },
+			OnComplete: func(any, error) {},
+		})
+	}, time.Second)
+
+	env.ExecuteWorkflow(approvalWorkflow)
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var got string
+	require.NoError(t, env.GetWorkflowResult(&got))
+	assert.Equal(t, "req-approve", got, "activity started after the update carries its request ID")
+}
@@ -0,0 +1,106 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/workflow"
+)
+
+// workflowSpanKey is where the Temporal tracing interceptor keeps the
+// current span in workflow contexts. Setting it ourselves, rather than
+// using the interceptor's private default, lets ContinueTrace move a span
+// between coroutines.
+type workflowSpanKey struct{}
+
+// ConfigureTemporal sets up opts so that client calls carry the request
+// ID and, with tracing on, the OpenTelemetry span of their context into
+// workflows, updates and activities. Workers created from the client
+// inherit both: activity spans are children of the workflow's, and
+// workflow and activity loggers add request_id, TraceID and SpanID.
+func ConfigureTemporal(opts *client.Options, tracing bool) error {
+	opts.ContextPropagators = append(opts.ContextPropagators, NewRequestIDPropagator())
+	if tracing {
+		ti, err := opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{
+			SpanContextKey: workflowSpanKey{},
+		})
+		if err != nil {
+			return fmt.Errorf("observability: temporal tracing interceptor: %w", err)
+		}
+		opts.Interceptors = append(opts.Interceptors, ti)
+	}
+	opts.Interceptors = append(opts.Interceptors, &requestIDLogging{})
+	return nil
+}
+
+// ContinueTrace returns ctx carrying the span and request ID of from, so
+// activities the workflow starts from ctx join from's trace. A workflow
+// that an update or signal unblocks uses it to continue the caller's trace
+// past the handler: the approval click and the execution it releases then
+// share one trace.
+func ContinueTrace(ctx, from workflow.Context) workflow.Context {
+	if span := from.Value(workflowSpanKey{}); span != nil {
+		ctx = workflow.WithValue(ctx, workflowSpanKey{}, span)
+	}
+	if id := RequestIDFromWorkflow(from); id != "" {
+		ctx = workflow.WithValue(ctx, requestIDKey{}, id)
+	}
+	return ctx
+}
+
+// requestIDLogging adds request_id to workflow and activity loggers.
+type requestIDLogging struct {
+	interceptor.InterceptorBase
+}
+
+func (*requestIDLogging) InterceptActivity(_ context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	return &requestIDActivityInbound{ActivityInboundInterceptorBase: interceptor.ActivityInboundInterceptorBase{Next: next}}
+}
+
+func (*requestIDLogging) InterceptWorkflow(_ workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	return &requestIDWorkflowInbound{WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next}}
+}
+
+type requestIDActivityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (i *requestIDActivityInbound) Init(outbound interceptor.ActivityOutboundInterceptor) error {
+	return i.Next.Init(&requestIDActivityOutbound{ActivityOutboundInterceptorBase: interceptor.ActivityOutboundInterceptorBase{Next: outbound}})
+}
+
+type requestIDActivityOutbound struct {
+	interceptor.ActivityOutboundInterceptorBase
+}
+
+func (o *requestIDActivityOutbound) GetLogger(ctx context.Context) log.Logger {
+	logger := o.Next.GetLogger(ctx)
+	if id := RequestIDFromContext(ctx); id != "" {
+		return log.With(logger, "request_id", id)
+	}
+	return logger
+}
+
+type requestIDWorkflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (i *requestIDWorkflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return i.Next.Init(&requestIDWorkflowOutbound{WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound}})
+}
+
+type requestIDWorkflowOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+func (o *requestIDWorkflowOutbound) GetLogger(ctx workflow.Context) log.Logger {
+	logger := o.Next.GetLogger(ctx)
+	if id := RequestIDFromWorkflow(ctx); id != "" {
+		return log.With(logger, "request_id", id)
+	}
+	return logger
+}
@@ -0,0 +1,50 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConnectorTracer is the instrumentation scope of connector spans.
+const ConnectorTracer = "github.com/finops-claw-gang/finops-go/internal/connectors"
+
+// ConnectorCall is one in-flight call to an external system. End it with
+// End, which ends its span and records finops.connector.duration_seconds.
+type ConnectorCall struct {
+	ctx       context.Context
+	span      trace.Span
+	system    string
+	operation string
+	start     time.Time
+}
+
+// StartConnectorCall starts a client span for one call to an external
+// system (Athena, CostExplorer, CloudWatch, KubeCost, aws-doctor, ...). The
+// span is named "<system> <operation>".
+func StartConnectorCall(ctx context.Context, system, operation string, attrs ...attribute.KeyValue) (context.Context, *ConnectorCall) {
+	attrs = append([]attribute.KeyValue{
+		attribute.String("finops.connector", system),
+		attribute.String("finops.operation", operation),
+	}, attrs...)
+	ctx, span := otel.Tracer(ConnectorTracer).Start(ctx, system+" "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &ConnectorCall{ctx: ctx, span: span, system: system, operation: operation, start: time.Now()}
+}
+
+// End records err on the call's span, if any, ends the span and records
+// the call's duration.
+func (c *ConnectorCall) End(err error) {
+	if err != nil {
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+	Default().RecordConnectorCall(c.ctx, c.system, c.operation, time.Since(c.start), err)
+}
@@ -0,0 +1,110 @@
+package observability
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// OutcomeReason is implemented by workflow results that report why the
+// workflow ended; the reason becomes the "reason" attribute of
+// finops.workflow.outcomes.
+type OutcomeReason interface {
+	OutcomeReason() string
+}
+
+// WorkerInterceptor returns a worker interceptor that records activity
+// calls, workflow outcomes and, for workflows that register the update
+// named approvalUpdate, the time from registering it to a successful
+// decision. Workflow metrics are recorded only outside replay, so a
+// workflow counts once however often its history is replayed.
+func (m *Metrics) WorkerInterceptor(approvalUpdate string) interceptor.WorkerInterceptor {
+	return &workerMetrics{metrics: m, approvalUpdate: approvalUpdate}
+}
+
+type workerMetrics struct {
+	interceptor.WorkerInterceptorBase
+	metrics        *Metrics
+	approvalUpdate string
+}
+
+func (w *workerMetrics) InterceptActivity(_ context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	return &activityMetrics{ActivityInboundInterceptorBase: interceptor.ActivityInboundInterceptorBase{Next: next}, metrics: w.metrics}
+}
+
+func (w *workerMetrics) InterceptWorkflow(_ workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	return &workflowMetrics{
+		WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next},
+		metrics:                        w.metrics,
+		approvalUpdate:                 w.approvalUpdate,
+	}
+}
+
+type activityMetrics struct {
+	interceptor.ActivityInboundInterceptorBase
+	metrics *Metrics
+}
+
+func (a *activityMetrics) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error) {
+	res, err := a.Next.ExecuteActivity(ctx, in)
+	a.metrics.RecordActivity(ctx, activity.GetInfo(ctx).ActivityType.Name, err)
+	return res, err
+}
+
+type workflowMetrics struct {
+	interceptor.WorkflowInboundInterceptorBase
+	metrics        *Metrics
+	approvalUpdate string
+	pendingSince   time.Time // when the approval handler was registered
+}
+
+func (w *workflowMetrics) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	return w.Next.Init(&workflowMetricsOutbound{WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound}, inbound: w})
+}
+
+func (w *workflowMetrics) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (any, error) {
+	res, err := w.Next.ExecuteWorkflow(ctx, in)
+	if workflow.IsReplaying(ctx) {
+		return res, err
+	}
+
+	status := "completed"
+	switch {
+	case workflow.IsContinueAsNewError(err):
+		status = "continued_as_new"
+	case temporal.IsCanceledError(err):
+		status = "canceled"
+	case err != nil:
+		status = "failed"
+	}
+	var reason string
+	if r, ok := res.(OutcomeReason); ok {
+		reason = r.OutcomeReason()
+	}
+	w.metrics.RecordWorkflowOutcome(context.Background(), workflow.GetInfo(ctx).WorkflowType.Name, status, reason)
+	return res, err
+}
+
+func (w *workflowMetrics) ExecuteUpdate(ctx workflow.Context, in *interceptor.UpdateInput) (any, error) {
+	res, err := w.Next.ExecuteUpdate(ctx, in)
+	if in.Name == w.approvalUpdate && err == nil && !w.pendingSince.IsZero() && !workflow.IsReplaying(ctx) {
+		w.metrics.RecordApprovalLatency(context.Background(), workflow.Now(ctx).Sub(w.pendingSince))
+	}
+	return res, err
+}
+
+type workflowMetricsOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+	inbound *workflowMetrics
+}
+
+func (o *workflowMetricsOutbound) SetUpdateHandler(ctx workflow.Context, name string, handler any, opts workflow.UpdateHandlerOptions) error {
+	if name == o.inbound.approvalUpdate {
+		o.inbound.pendingSince = workflow.Now(ctx)
+	}
+	return o.Next.SetUpdateHandler(ctx, name, handler, opts)
+}
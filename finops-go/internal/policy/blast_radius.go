@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// BlastRadiusLimits caps how much a single plan, or a single tenant in one
+// day, may change. A zero field means that dimension is unlimited.
+type BlastRadiusLimits struct {
+	MaxActionsPerWorkflow     int
+	MaxActionsPerTenantPerDay int
+	MaxActionsPerResourceType int
+	// MaxMonthlySpendAffected caps the summed EstimatedSavingsMonthly of a
+	// plan, used as a proxy for the monthly spend its actions touch.
+	MaxMonthlySpendAffected float64
+}
+
+// DefaultBlastRadiusLimits returns conservative limits for production use.
+func DefaultBlastRadiusLimits() BlastRadiusLimits {
+	return BlastRadiusLimits{
+		MaxActionsPerWorkflow:     25,
+		MaxActionsPerTenantPerDay: 200,
+		MaxActionsPerResourceType: 10,
+		MaxMonthlySpendAffected:   50000,
+	}
+}
+
+// ResourceType classifies a target for per-type limits. ARNs yield
+// "service:type" (e.g. "ec2:volume"); anything else falls back to the
+// action type.
+func ResourceType(a domain.RecommendedAction) string {
+	parts := strings.SplitN(a.TargetResource, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" {
+		res := parts[5]
+		if i := strings.IndexAny(res, "/:"); i >= 0 {
+			res = res[:i]
+		}
+		return parts[2] + ":" + res
+	}
+	return a.ActionType
+}
+
+// EnforceBlastRadius checks a plan against the limits. tenantActionsToday is
+// the number of actions the tenant has already executed today; the check
+// fails if one more would exceed the daily cap. Plan-level checks are pure,
+// so the same plan is accepted or refused identically on every retry.
+func EnforceBlastRadius(limits BlastRadiusLimits, plan []domain.RecommendedAction, tenantActionsToday int) error {
+	if limits.MaxActionsPerWorkflow > 0 && len(plan) > limits.MaxActionsPerWorkflow {
+		return fmt.Errorf("blast radius: plan has %d actions, limit %d per workflow",
+			len(plan), limits.MaxActionsPerWorkflow)
+	}
+
+	if limits.MaxActionsPerResourceType > 0 {
+		perType := map[string]int{}
+		for _, a := range plan {
+			perType[ResourceType(a)]++
+		}
+		types := make([]string, 0, len(perType))
+		for t := range perType {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			if perType[t] > limits.MaxActionsPerResourceType {
+				return fmt.Errorf("blast radius: plan touches %d resources of type %s, limit %d",
+					perType[t], t, limits.MaxActionsPerResourceType)
+			}
+		}
+	}
+
+	if limits.MaxMonthlySpendAffected > 0 {
+		var total float64
+		for _, a := range plan {
+			total += a.EstimatedSavingsMonthly
+		}
+		if total > limits.MaxMonthlySpendAffected {
+			return fmt.Errorf("blast radius: plan affects $%.2f/month, limit $%.2f",
+				total, limits.MaxMonthlySpendAffected)
+		}
+	}
+
+	if limits.MaxActionsPerTenantPerDay > 0 && tenantActionsToday+1 > limits.MaxActionsPerTenantPerDay {
+		return fmt.Errorf("blast radius: tenant has executed %d actions today, limit %d",
+			tenantActionsToday, limits.MaxActionsPerTenantPerDay)
+	}
+
+	return nil
+}
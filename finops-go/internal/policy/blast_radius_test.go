@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func volumes(n int, savingsEach float64) []domain.RecommendedAction {
+	out := make([]domain.RecommendedAction, n)
+	for i := range out {
+		a := makeAction(domain.RiskLow)
+		a.ActionType = "delete_volume"
+		a.TargetResource = fmt.Sprintf("arn:aws:ec2:us-east-1:123456789012:volume/vol-%04d", i)
+		a.EstimatedSavingsMonthly = savingsEach
+		out[i] = a
+	}
+	return out
+}
+
+func TestResourceType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		target string
+		action string
+		want   string
+	}{
+		{name: "slash arn", target: "arn:aws:ec2:us-east-1:123:volume/vol-1", want: "ec2:volume"},
+		{name: "colon arn", target: "arn:aws:rds:us-east-1:123:db:prod-db", want: "rds:db"},
+		{name: "bare arn resource", target: "arn:aws:s3:::my-bucket", want: "s3:my-bucket"},
+		{name: "non-arn falls back", target: "budget:EC2:123", action: "create_budget_alert", want: "create_budget_alert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := domain.RecommendedAction{TargetResource: tt.target, ActionType: tt.action}
+			if got := ResourceType(a); got != tt.want {
+				t.Errorf("ResourceType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnforceBlastRadius(t *testing.T) {
+	t.Parallel()
+	limits := BlastRadiusLimits{
+		MaxActionsPerWorkflow:     20,
+		MaxActionsPerTenantPerDay: 50,
+		MaxActionsPerResourceType: 5,
+		MaxMonthlySpendAffected:   1000,
+	}
+
+	tests := []struct {
+		name    string
+		limits  BlastRadiusLimits
+		plan    []domain.RecommendedAction
+		today   int
+		wantErr bool
+	}{
+		{name: "within limits", limits: limits, plan: volumes(5, 100), today: 10},
+		{name: "too many per workflow", limits: limits, plan: volumes(400, 0), wantErr: true},
+		{name: "too many of one type", limits: limits, plan: volumes(6, 10), wantErr: true},
+		{name: "too much spend", limits: limits, plan: volumes(3, 400), wantErr: true},
+		{name: "tenant daily cap reached", limits: limits, plan: volumes(1, 10), today: 50, wantErr: true},
+		{name: "zero limits unlimited", limits: BlastRadiusLimits{}, plan: volumes(400, 1000), today: 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := EnforceBlastRadius(tt.limits, tt.plan, tt.today)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EnforceBlastRadius() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
 // Package policy implements the deterministic policy engine that decides
 // whether recommended actions are auto-approved, require human approval, or
 // are denied outright. It also provides an executor safety gate that prevents
-// execution of critical actions or actions targeting protected resources.
+// execution of critical actions or actions targeting protected resources,
+// and blast-radius limits that cap how much one plan or tenant may change.
 package policy
 
 import (
@@ -21,14 +22,20 @@ type PolicyDecision struct {
 type PolicyEngine struct {
 	AutoApproveMaxRisk domain.ActionRiskLevel
 	DenyMinRisk        domain.ActionRiskLevel
+
+	// RollbackMinRisk is the lowest max-risk at which a failed action
+	// causes already-completed actions to be rolled back.
+	RollbackMinRisk domain.ActionRiskLevel
 }
 
 // NewPolicyEngine returns an engine with the default thresholds:
-// auto-approve up to low risk, deny at critical risk.
+// auto-approve up to low risk, deny at critical risk, roll back partial
+// batches at high risk.
 func NewPolicyEngine() *PolicyEngine {
 	return &PolicyEngine{
 		AutoApproveMaxRisk: domain.RiskLow,
 		DenyMinRisk:        domain.RiskCritical,
+		RollbackMinRisk:    domain.RiskHigh,
 	}
 }
 
@@ -86,15 +93,36 @@ func (pe *PolicyEngine) Decide(actions []domain.RecommendedAction) PolicyDecisio
 	}
 }
 
+// FailurePolicy decides what happens to the remaining actions when one
+// action in the batch fails.
+//
+// Rules:
+//  1. Max risk >= rollback threshold → rollback completed actions.
+//  2. Otherwise → skip the remaining actions, keep completed ones.
+//
+// FailureContinue is never chosen automatically; callers opt into it.
+func (pe *PolicyEngine) FailurePolicy(actions []domain.RecommendedAction) domain.FailurePolicy {
+	if len(actions) == 0 {
+		return domain.FailureSkipRemaining
+	}
+	if domain.RiskScore[pe.MaxRisk(actions)] >= domain.RiskScore[pe.RollbackMinRisk] {
+		return domain.FailureRollback
+	}
+	return domain.FailureSkipRemaining
+}
+
 // EnforceExecutorSafety is a hard gate invoked before any action execution.
 // It returns a non-nil error if:
 //   - The approval status is not approved or auto_approved.
 //   - Any action has critical risk level.
-//   - Any action targets a resource tagged "do-not-modify" or "manual-only".
+//   - Any action's target matches a protection rule, built-in or in rules.
+//     The error is a *ProtectionError listing every rule that matched and
+//     which resource it blocked.
 func EnforceExecutorSafety(
 	approval domain.ApprovalStatus,
 	actions []domain.RecommendedAction,
 	resourceTagsByARN map[string]map[string]string,
+	rules ProtectionRuleset,
 ) error {
 	if approval != domain.ApprovalApproved && approval != domain.ApprovalAutoApproved {
 		return fmt.Errorf("cannot execute: approval status is %s", approval)
@@ -104,15 +132,10 @@ func EnforceExecutorSafety(
 		if a.RiskLevel == domain.RiskCritical {
 			return fmt.Errorf("refuse to execute critical action %s", a.ActionID)
 		}
+	}
 
-		if a.TargetResource != "" {
-			tags, ok := resourceTagsByARN[a.TargetResource]
-			if ok {
-				if tags["do-not-modify"] == "true" || tags["manual-only"] == "true" {
-					return fmt.Errorf("refuse to execute on tagged resource %s: %v", a.TargetResource, tags)
-				}
-			}
-		}
+	if v := withDefaults(rules).EvaluateAll(actions, resourceTagsByARN); len(v) > 0 {
+		return &ProtectionError{Violations: v}
 	}
 
 	return nil
@@ -131,6 +131,41 @@ func TestMaxRisk(t *testing.T) {
 	}
 }
 
+func TestFailurePolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		actions []domain.RecommendedAction
+		want    domain.FailurePolicy
+	}{
+		{name: "no actions", actions: nil, want: domain.FailureSkipRemaining},
+		{
+			name:    "low and medium skip remaining",
+			actions: []domain.RecommendedAction{makeAction(domain.RiskLow), makeAction(domain.RiskMedium)},
+			want:    domain.FailureSkipRemaining,
+		},
+		{
+			name:    "any high rolls back",
+			actions: []domain.RecommendedAction{makeAction(domain.RiskLow), makeAction(domain.RiskHigh)},
+			want:    domain.FailureRollback,
+		},
+		{
+			name:    "critical rolls back",
+			actions: []domain.RecommendedAction{makeAction(domain.RiskCritical)},
+			want:    domain.FailureRollback,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pe := NewPolicyEngine()
+			if got := pe.FailurePolicy(tt.actions); got != tt.want {
+				t.Errorf("FailurePolicy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEnforceExecutorSafety(t *testing.T) {
 	t.Parallel()
 	lowAction := makeAction(domain.RiskLow)
@@ -190,6 +225,20 @@ func TestEnforceExecutorSafety(t *testing.T) {
 			tags:     map[string]map[string]string{taggedAction.TargetResource: {"manual-only": "true"}},
 			wantErr:  true,
 		},
+		{
+			name:     "backup-managed resource blocked",
+			approval: domain.ApprovalApproved,
+			actions:  []domain.RecommendedAction{taggedAction},
+			tags:     map[string]map[string]string{taggedAction.TargetResource: {"aws:backup:source-resource": "vol-1"}},
+			wantErr:  true,
+		},
+		{
+			name:     "cloudformation-managed resource blocked",
+			approval: domain.ApprovalApproved,
+			actions:  []domain.RecommendedAction{taggedAction},
+			tags:     map[string]map[string]string{taggedAction.TargetResource: {"aws:cloudformation:stack-name": "core"}},
+			wantErr:  true,
+		},
 		{
 			name:     "tagged resource without blocking tags passes",
 			approval: domain.ApprovalApproved,
@@ -208,7 +257,8 @@ func TestEnforceExecutorSafety(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			err := EnforceExecutorSafety(tt.approval, tt.actions, tt.tags)
+			// No configured rules: the built-in ones apply regardless.
+			err := EnforceExecutorSafety(tt.approval, tt.actions, tt.tags, ProtectionRuleset{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EnforceExecutorSafety() error = %v, wantErr %v", err, tt.wantErr)
 			}
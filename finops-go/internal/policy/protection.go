@@ -0,0 +1,257 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// ProtectionRule marks resources the executor must never touch. Every
+// criterion that is set must match (AND); within a list any entry may
+// match (OR). Patterns are globs where * matches any run of characters,
+// including ':' and '/', and ? matches one character.
+type ProtectionRule struct {
+	Name string `json:"name"`
+
+	// Tags maps tag-key patterns to tag-value patterns. Each entry must
+	// be satisfied by at least one of the resource's tags.
+	Tags map[string]string `json:"tags,omitempty"`
+	// ARNs are patterns matched against the target resource.
+	ARNs []string `json:"arns,omitempty"`
+	// Accounts are AWS account IDs parsed from the target ARN.
+	Accounts []string `json:"accounts,omitempty"`
+	// ResourceTypes are patterns matched against ResourceType (e.g. "rds:*").
+	ResourceTypes []string `json:"resource_types,omitempty"`
+}
+
+// ProtectionRuleset is an ordered list of protection rules.
+type ProtectionRuleset struct {
+	Rules []ProtectionRule `json:"rules"`
+}
+
+// Violation records which rule blocked which resource.
+type Violation struct {
+	Rule     string `json:"rule"`
+	ActionID string `json:"action_id"`
+	Resource string `json:"resource"`
+	Reason   string `json:"reason"`
+}
+
+// ProtectionError is returned by EnforceExecutorSafety when one or more
+// protection rules match. Use errors.As to inspect the violations.
+type ProtectionError struct {
+	Violations []Violation
+}
+
+func (e *ProtectionError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s blocked by rule %q (%s)", v.Resource, v.Rule, v.Reason)
+	}
+	return "refuse to execute on protected resource: " + strings.Join(parts, "; ")
+}
+
+// DefaultProtectionRules returns the built-in rules. EnforceExecutorSafety
+// applies them whatever ruleset it is given: resources opted out by tag,
+// resources AWS Backup manages or restored, and resources owned by a
+// CloudFormation stack, which would drift from their template.
+func DefaultProtectionRules() ProtectionRuleset {
+	return ProtectionRuleset{Rules: []ProtectionRule{
+		{Name: "do-not-modify", Tags: map[string]string{"do-not-modify": "true"}},
+		{Name: "manual-only", Tags: map[string]string{"manual-only": "true"}},
+		{Name: "backup-managed", Tags: map[string]string{"aws:backup:*": "*"}},
+		{Name: "cloudformation-managed", Tags: map[string]string{"aws:cloudformation:stack-name": "*"}},
+	}}
+}
+
+// withDefaults returns the built-in rules followed by rs's, leaving out
+// rules of rs identical to a built-in one so that a ruleset already
+// merged onto the defaults does not report each match twice.
+func withDefaults(rs ProtectionRuleset) ProtectionRuleset {
+	defaults := DefaultProtectionRules()
+	out := ProtectionRuleset{Rules: slices.Clone(defaults.Rules)}
+	for _, r := range rs.Rules {
+		if !slices.ContainsFunc(defaults.Rules, func(d ProtectionRule) bool { return reflect.DeepEqual(d, r) }) {
+			out.Rules = append(out.Rules, r)
+		}
+	}
+	return out
+}
+
+// ParseProtectionRules decodes and validates a JSON ruleset.
+func ParseProtectionRules(data []byte) (ProtectionRuleset, error) {
+	var rs ProtectionRuleset
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return ProtectionRuleset{}, fmt.Errorf("policy: decode protection rules: %w", err)
+	}
+	if err := rs.Validate(); err != nil {
+		return ProtectionRuleset{}, err
+	}
+	return rs, nil
+}
+
+// Validate rejects unnamed rules and rules with no criteria, which would
+// otherwise match nothing or everything.
+func (rs ProtectionRuleset) Validate() error {
+	for i, r := range rs.Rules {
+		if r.Name == "" {
+			return fmt.Errorf("policy: protection rule %d: name is required", i)
+		}
+		if len(r.Tags) == 0 && len(r.ARNs) == 0 && len(r.Accounts) == 0 && len(r.ResourceTypes) == 0 {
+			return fmt.Errorf("policy: protection rule %q: at least one criterion is required", r.Name)
+		}
+	}
+	return nil
+}
+
+// Merge returns a ruleset containing rs's rules followed by other's.
+func (rs ProtectionRuleset) Merge(other ProtectionRuleset) ProtectionRuleset {
+	rules := make([]ProtectionRule, 0, len(rs.Rules)+len(other.Rules))
+	rules = append(rules, rs.Rules...)
+	rules = append(rules, other.Rules...)
+	return ProtectionRuleset{Rules: rules}
+}
+
+// Evaluate returns one violation per rule matching the action's target.
+// Actions without a target resource are never protected.
+func (rs ProtectionRuleset) Evaluate(action domain.RecommendedAction, tags map[string]string) []Violation {
+	if action.TargetResource == "" {
+		return nil
+	}
+	var out []Violation
+	for _, r := range rs.Rules {
+		if reason, ok := r.match(action, tags); ok {
+			out = append(out, Violation{
+				Rule:     r.Name,
+				ActionID: action.ActionID,
+				Resource: action.TargetResource,
+				Reason:   reason,
+			})
+		}
+	}
+	return out
+}
+
+// match reports whether every set criterion matches, with a description
+// of what matched.
+func (r ProtectionRule) match(action domain.RecommendedAction, tags map[string]string) (string, bool) {
+	var reasons []string
+
+	if len(r.Tags) > 0 {
+		keys := make([]string, 0, len(r.Tags))
+		for k := range r.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, kp := range keys {
+			hit, ok := matchTag(kp, r.Tags[kp], tags)
+			if !ok {
+				return "", false
+			}
+			reasons = append(reasons, "tag "+hit)
+		}
+	}
+
+	if len(r.ARNs) > 0 {
+		p, ok := firstMatch(r.ARNs, action.TargetResource)
+		if !ok {
+			return "", false
+		}
+		reasons = append(reasons, "arn matches "+p)
+	}
+
+	if len(r.Accounts) > 0 {
+		acct := arnAccount(action.TargetResource)
+		if acct == "" {
+			return "", false
+		}
+		if _, ok := firstMatch(r.Accounts, acct); !ok {
+			return "", false
+		}
+		reasons = append(reasons, "account "+acct)
+	}
+
+	if len(r.ResourceTypes) > 0 {
+		rt := ResourceType(action)
+		if _, ok := firstMatch(r.ResourceTypes, rt); !ok {
+			return "", false
+		}
+		reasons = append(reasons, "resource type "+rt)
+	}
+
+	return strings.Join(reasons, ", "), true
+}
+
+// matchTag finds a tag whose key and value match the patterns and returns
+// it as "key=value".
+func matchTag(keyPattern, valuePattern string, tags map[string]string) (string, bool) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if glob(keyPattern, k) && glob(valuePattern, tags[k]) {
+			return k + "=" + tags[k], true
+		}
+	}
+	return "", false
+}
+
+func firstMatch(patterns []string, s string) (string, bool) {
+	for _, p := range patterns {
+		if glob(p, s) {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func arnAccount(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" {
+		return parts[4]
+	}
+	return ""
+}
+
+// glob matches s against pattern, where * matches any run of characters
+// and ? matches exactly one.
+func glob(pattern, s string) bool {
+	px, sx := 0, 0
+	starP, starS := -1, 0
+	for sx < len(s) {
+		switch {
+		case px < len(pattern) && (pattern[px] == '?' || pattern[px] == s[sx]):
+			px++
+			sx++
+		case px < len(pattern) && pattern[px] == '*':
+			starP, starS = px, sx
+			px++
+		case starP >= 0:
+			starS++
+			px, sx = starP+1, starS
+		default:
+			return false
+		}
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
+
+// EvaluateAll returns every violation across the actions. Tags are looked
+// up by target resource; a missing entry means the resource has no tags.
+func (rs ProtectionRuleset) EvaluateAll(actions []domain.RecommendedAction, tagsByARN map[string]map[string]string) []Violation {
+	var all []Violation
+	for _, a := range actions {
+		all = append(all, rs.Evaluate(a, tagsByARN[a.TargetResource])...)
+	}
+	return all
+}
@@ -0,0 +1,187 @@
+package policy
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+const testRules = `{
+	"rules": [
+		{"name": "prod-env", "tags": {"environment": "prod*"}},
+		{"name": "restricted-data", "tags": {"data-classification": "restricted"}},
+		{"name": "backup-managed", "tags": {"aws:backup:*": "*"}},
+		{"name": "cfn-owned", "tags": {"aws:cloudformation:stack-name": "*"}},
+		{"name": "payments-arns", "arns": ["arn:aws:*:*:*:*/payments-*"]},
+		{"name": "security-account", "accounts": ["999999999999"]},
+		{"name": "no-databases", "resource_types": ["rds:*", "dynamodb:table"]},
+		{"name": "shared-volumes-in-core", "accounts": ["111111111111"], "resource_types": ["ec2:volume"]}
+	]
+}`
+
+func target(arn string) domain.RecommendedAction {
+	a := makeAction(domain.RiskLow)
+	a.ActionType = "modify"
+	a.TargetResource = arn
+	return a
+}
+
+func TestProtectionRuleset_Evaluate(t *testing.T) {
+	t.Parallel()
+	rs, err := ParseProtectionRules([]byte(testRules))
+	if err != nil {
+		t.Fatalf("ParseProtectionRules: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		arn       string
+		tags      map[string]string
+		wantRules []string
+	}{
+		{name: "unprotected", arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", tags: map[string]string{"environment": "dev"}},
+		{name: "tag value glob", arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", tags: map[string]string{"environment": "production"}, wantRules: []string{"prod-env"}},
+		{name: "exact tag", arn: "arn:aws:s3:::bucket", tags: map[string]string{"data-classification": "restricted"}, wantRules: []string{"restricted-data"}},
+		{name: "tag key glob", arn: "arn:aws:ec2:us-east-1:123456789012:volume/vol-1", tags: map[string]string{"aws:backup:source-resource": "x"}, wantRules: []string{"backup-managed"}},
+		{name: "cloudformation owned", arn: "arn:aws:ec2:us-east-1:123456789012:instance/i-1", tags: map[string]string{"aws:cloudformation:stack-name": "core"}, wantRules: []string{"cfn-owned"}},
+		{name: "arn glob", arn: "arn:aws:ec2:us-east-1:123456789012:instance/payments-api", wantRules: []string{"payments-arns"}},
+		{name: "account list", arn: "arn:aws:ec2:us-east-1:999999999999:instance/i-1", wantRules: []string{"security-account"}},
+		{name: "resource type", arn: "arn:aws:rds:us-east-1:123456789012:db:orders", wantRules: []string{"no-databases"}},
+		{name: "combined criteria both match", arn: "arn:aws:ec2:us-east-1:111111111111:volume/vol-1", wantRules: []string{"shared-volumes-in-core"}},
+		{name: "combined criteria one matches", arn: "arn:aws:ec2:us-east-1:111111111111:instance/i-1"},
+		{
+			name:      "multiple rules reported",
+			arn:       "arn:aws:rds:us-east-1:999999999999:db:payments",
+			tags:      map[string]string{"environment": "prod"},
+			wantRules: []string{"prod-env", "security-account", "no-databases"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := rs.Evaluate(target(tt.arn), tt.tags)
+			if len(got) != len(tt.wantRules) {
+				t.Fatalf("Evaluate() = %+v, want rules %v", got, tt.wantRules)
+			}
+			for i, v := range got {
+				if v.Rule != tt.wantRules[i] {
+					t.Errorf("violation %d rule = %q, want %q", i, v.Rule, tt.wantRules[i])
+				}
+				if v.Resource != tt.arn || v.Reason == "" {
+					t.Errorf("violation %d = %+v", i, v)
+				}
+			}
+		})
+	}
+}
+
+func TestEnforceExecutorSafety_ReportsViolations(t *testing.T) {
+	t.Parallel()
+	rs, err := ParseProtectionRules([]byte(testRules))
+	if err != nil {
+		t.Fatalf("ParseProtectionRules: %v", err)
+	}
+	rs = DefaultProtectionRules().Merge(rs)
+
+	ok := target("arn:aws:ec2:us-east-1:123456789012:instance/i-ok")
+	db := target("arn:aws:rds:us-east-1:123456789012:db:orders")
+	pinned := target("arn:aws:ec2:us-east-1:123456789012:instance/i-pinned")
+	tags := map[string]map[string]string{pinned.TargetResource: {"do-not-modify": "true"}}
+
+	err = EnforceExecutorSafety(domain.ApprovalApproved, []domain.RecommendedAction{ok, db, pinned}, tags, rs)
+	var pe *ProtectionError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected *ProtectionError, got %v", err)
+	}
+	if len(pe.Violations) != 2 {
+		t.Fatalf("violations = %+v, want 2", pe.Violations)
+	}
+	if pe.Violations[0].Rule != "no-databases" || pe.Violations[0].ActionID != db.ActionID {
+		t.Errorf("first violation = %+v", pe.Violations[0])
+	}
+	if pe.Violations[1].Rule != "do-not-modify" || pe.Violations[1].Resource != pinned.TargetResource {
+		t.Errorf("second violation = %+v", pe.Violations[1])
+	}
+
+	if err := EnforceExecutorSafety(domain.ApprovalApproved, []domain.RecommendedAction{ok}, nil, rs); err != nil {
+		t.Errorf("unprotected action refused: %v", err)
+	}
+}
+
+func TestEnforceExecutorSafety_DefaultsAppliedOnce(t *testing.T) {
+	t.Parallel()
+	extra := ProtectionRuleset{Rules: []ProtectionRule{{Name: "prod-env", Tags: map[string]string{"environment": "prod"}}}}
+	pinned := target("arn:aws:ec2:us-east-1:123456789012:instance/i-pinned")
+	tags := map[string]map[string]string{pinned.TargetResource: {"do-not-modify": "true", "environment": "prod"}}
+
+	tests := []struct {
+		name  string
+		rules ProtectionRuleset
+	}{
+		{name: "configured rules only", rules: extra},
+		{name: "configured rules merged onto defaults", rules: DefaultProtectionRules().Merge(extra)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := EnforceExecutorSafety(domain.ApprovalApproved, []domain.RecommendedAction{pinned}, tags, tt.rules)
+			var pe *ProtectionError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ProtectionError, got %v", err)
+			}
+			var got []string
+			for _, v := range pe.Violations {
+				got = append(got, v.Rule)
+			}
+			if want := []string{"do-not-modify", "prod-env"}; !slices.Equal(got, want) {
+				t.Errorf("violated rules = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseProtectionRules_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		js   string
+	}{
+		{name: "bad json", js: `{"rules": [`},
+		{name: "missing name", js: `{"rules": [{"tags": {"a": "b"}}]}`},
+		{name: "no criteria", js: `{"rules": [{"name": "everything"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseProtectionRules([]byte(tt.js)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"prod*", "production", true},
+		{"prod*", "preprod", false},
+		{"*prod", "preprod", true},
+		{"arn:aws:ec2:*:*:volume/*", "arn:aws:ec2:us-east-1:123:volume/vol-1", true},
+		{"arn:aws:ec2:*:*:volume/*", "arn:aws:ec2:us-east-1:123:instance/i-1", false},
+		{"vol-????", "vol-1234", true},
+		{"vol-????", "vol-123", false},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+	}
+	for _, tt := range tests {
+		if got := glob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("glob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
@@ -2,70 +2,107 @@ package ratelimit
 
 import (
 	"fmt"
-	"sync"
+	"log/slog"
 	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
 )
 
-// ActivityBudget tracks per-tenant activity call counts within time windows.
+// ErrBudgetExceeded is returned by Reserve and Check when a tenant has used up its
+// budget for an activity in the current window.
+var ErrBudgetExceeded = apperr.NewSentinel(apperr.BudgetExceeded, "ratelimit: activity budget exceeded")
+
+// ActivityBudget tracks per-tenant activity call counts within time
+// windows. Counts live in a Store, in memory unless SetStore shares them
+// with other replicas.
 type ActivityBudget struct {
-	mu     sync.Mutex
-	counts map[string]*windowCounter
+	store Store
 
 	maxPerWindow int
 	windowSize   time.Duration
 	now          func() time.Time
-}
-
-type windowCounter struct {
-	count     int
-	windowEnd time.Time
+	prune        pruner
 }
 
 // NewActivityBudget creates a budget limiter.
 // maxPerWindow limits calls per (tenantID, activity) within windowSize.
 func NewActivityBudget(maxPerWindow int, windowSize time.Duration) *ActivityBudget {
 	return &ActivityBudget{
-		counts:       make(map[string]*windowCounter),
+		store:        NewMemoryStore(),
 		maxPerWindow: maxPerWindow,
 		windowSize:   windowSize,
 		now:          time.Now,
 	}
 }
 
+// SetStore keeps the budget's counters in s. Set it before the budget is
+// used.
+func (b *ActivityBudget) SetStore(s Store) {
+	b.store = s
+}
+
 func budgetKey(tenantID, activity string) string {
 	return tenantID + "|" + activity
 }
 
-// Check returns an error if the tenant has exceeded the budget for the activity.
-func (b *ActivityBudget) Check(tenantID, activity string) error {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+// Reserve counts an activity call for the tenant, or returns an error if
+// the tenant has used up the budget for the activity. The check and the
+// count are one store update, so replicas sharing the store cannot all
+// pass before any of them counts. When the store fails, the call is
+// allowed and the failure logged.
+func (b *ActivityBudget) Reserve(tenantID, activity string) error {
+	now := b.now()
+	b.prune.maybePrune(b.store, now)
+	var used int
+	if err := b.store.UpdateCounter(budgetKey(tenantID, activity), func(c *Counter) {
+		if now.After(c.WindowEnd) {
+			*c = Counter{WindowEnd: now.Add(b.windowSize)}
+		}
+		if used = c.Count; used < b.maxPerWindow {
+			c.Count++
+		}
+	}); err != nil {
+		slog.Warn("ratelimit: budget store failed", "tenant_id", tenantID, "activity", activity, "error", err)
+		return nil
+	}
+	if used >= b.maxPerWindow {
+		return fmt.Errorf("tenant %s activity %s (%d/%d in window): %w",
+			tenantID, activity, used, b.maxPerWindow, ErrBudgetExceeded)
+	}
+	return nil
+}
 
-	key := budgetKey(tenantID, activity)
-	wc, ok := b.counts[key]
-	if !ok || b.now().After(wc.windowEnd) {
+// Check returns an error if the tenant has exceeded the budget for the
+// activity, without counting a call. When the store fails, the call is
+// allowed and the failure logged.
+func (b *ActivityBudget) Check(tenantID, activity string) error {
+	now := b.now()
+	wc, err := b.store.LoadCounter(budgetKey(tenantID, activity))
+	if err != nil {
+		slog.Warn("ratelimit: budget store failed", "tenant_id", tenantID, "activity", activity, "error", err)
+		return nil
+	}
+	if now.After(wc.WindowEnd) {
 		return nil // no window or expired window
 	}
-	if wc.count >= b.maxPerWindow {
-		return fmt.Errorf("activity budget exceeded: tenant %s activity %s (%d/%d in window)",
-			tenantID, activity, wc.count, b.maxPerWindow)
+	if wc.Count >= b.maxPerWindow {
+		return fmt.Errorf("tenant %s activity %s (%d/%d in window): %w",
+			tenantID, activity, wc.Count, b.maxPerWindow, ErrBudgetExceeded)
 	}
 	return nil
 }
 
-// Record records an activity call for the tenant.
+// Record records an activity call for the tenant regardless of the budget.
 func (b *ActivityBudget) Record(tenantID, activity string) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	key := budgetKey(tenantID, activity)
-	wc, ok := b.counts[key]
-	if !ok || b.now().After(wc.windowEnd) {
-		b.counts[key] = &windowCounter{
-			count:     1,
-			windowEnd: b.now().Add(b.windowSize),
+	now := b.now()
+	b.prune.maybePrune(b.store, now)
+	if err := b.store.UpdateCounter(budgetKey(tenantID, activity), func(c *Counter) {
+		if now.After(c.WindowEnd) {
+			*c = Counter{Count: 1, WindowEnd: now.Add(b.windowSize)}
+			return
 		}
-		return
+		c.Count++
+	}); err != nil {
+		slog.Warn("ratelimit: budget store failed", "tenant_id", tenantID, "activity", activity, "error", err)
 	}
-	wc.count++
 }
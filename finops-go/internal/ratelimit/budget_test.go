@@ -30,7 +30,7 @@ func TestActivityBudget_ExceedsLimit(t *testing.T) {
 	b.Record("tenant-1", "TriageAnomaly")
 
 	err := b.Check("tenant-1", "TriageAnomaly")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
 	assert.Contains(t, err.Error(), "budget exceeded")
 }
 
@@ -64,3 +64,19 @@ func TestActivityBudget_DifferentTenants(t *testing.T) {
 	err = b.Check("tenant-2", "TriageAnomaly")
 	assert.NoError(t, err)
 }
+
+func TestActivityBudget_Reserve(t *testing.T) {
+	t.Parallel()
+	b := NewActivityBudget(2, time.Minute)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.Reserve("tenant-1", "TriageAnomaly"))
+	require.NoError(t, b.Reserve("tenant-1", "TriageAnomaly"))
+	err := b.Reserve("tenant-1", "TriageAnomaly")
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Contains(t, err.Error(), "(2/2 in window)", "refused calls are not counted")
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, b.Reserve("tenant-1", "TriageAnomaly"), "a new window starts fresh")
+}
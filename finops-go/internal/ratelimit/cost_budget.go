@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CostBudget tracks per-tenant spend, in dollars, within calendar months
+// (UTC) and refuses work once a tenant has spent its monthly limit. It
+// budgets what the desk itself costs to run, such as Cost Explorer
+// requests and Athena scans, not the tenant's cloud bill. Spend lives in a
+// Store, in memory unless SetStore shares it with other replicas.
+type CostBudget struct {
+	store Store
+
+	mu     sync.Mutex
+	limits map[string]float64 // per-tenant overrides of monthly
+
+	monthly float64 // <= 0 = unlimited
+	now     func() time.Time
+	prune   pruner
+}
+
+// NewCostBudget creates a budget allowing each tenant monthly dollars per
+// calendar month. A monthly of zero or less tracks spend without limiting
+// it.
+func NewCostBudget(monthly float64) *CostBudget {
+	return &CostBudget{
+		store:   NewMemoryStore(),
+		limits:  make(map[string]float64),
+		monthly: monthly,
+		now:     time.Now,
+	}
+}
+
+// SetStore keeps the monthly spend in s, so that replicas sharing it
+// enforce one limit per tenant and the spend survives restarts. Set it
+// before the budget is used.
+func (b *CostBudget) SetStore(s Store) {
+	b.store = s
+}
+
+// SetTenantLimit overrides the monthly limit for one tenant. Zero or less
+// makes the tenant unlimited.
+func (b *CostBudget) SetTenantLimit(tenantID string, monthly float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limits[tenantID] = monthly
+}
+
+func (b *CostBudget) limit(tenantID string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if limit, ok := b.limits[tenantID]; ok {
+		return limit
+	}
+	return b.monthly
+}
+
+func costKey(tenantID string) string {
+	return "cost|" + tenantID
+}
+
+// nextMonth returns the first instant of the month after t's (UTC).
+func nextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Check returns an error wrapping ErrBudgetExceeded if the tenant has
+// spent its limit for the current month. When the store fails, the work
+// is allowed and the failure logged.
+func (b *CostBudget) Check(tenantID string) error {
+	limit := b.limit(tenantID)
+	if limit <= 0 {
+		return nil
+	}
+	spent, err := b.spent(tenantID)
+	if err != nil {
+		slog.Warn("ratelimit: cost budget store failed", "tenant_id", tenantID, "error", err)
+		return nil
+	}
+	if spent >= limit {
+		return fmt.Errorf("tenant %s tooling spend ($%.2f/$%.2f this month): %w",
+			tenantID, spent, limit, ErrBudgetExceeded)
+	}
+	return nil
+}
+
+// Record adds dollars to the tenant's spend for the current month.
+func (b *CostBudget) Record(tenantID string, dollars float64) {
+	now := b.now()
+	b.prune.maybePrune(b.store, now)
+	if err := b.store.UpdateCounter(costKey(tenantID), func(c *Counter) {
+		if !now.Before(c.WindowEnd) {
+			*c = Counter{WindowEnd: nextMonth(now)}
+		}
+		c.Total += dollars
+	}); err != nil {
+		slog.Warn("ratelimit: cost budget store failed", "tenant_id", tenantID, "error", err)
+	}
+}
+
+// Spent returns the tenant's spend for the current month, or 0 when the
+// store fails.
+func (b *CostBudget) Spent(tenantID string) float64 {
+	spent, _ := b.spent(tenantID)
+	return spent
+}
+
+func (b *CostBudget) spent(tenantID string) (float64, error) {
+	now := b.now()
+	mc, err := b.store.LoadCounter(costKey(tenantID))
+	if err != nil {
+		return 0, err
+	}
+	if !now.Before(mc.WindowEnd) {
+		return 0, nil // no spend yet this month
+	}
+	return mc.Total, nil
+}
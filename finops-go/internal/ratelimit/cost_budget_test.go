@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostBudget_ExceedsLimit(t *testing.T) {
+	t.Parallel()
+	b := NewCostBudget(1.00)
+
+	b.Record("tenant-1", 0.60)
+	require.NoError(t, b.Check("tenant-1"))
+
+	b.Record("tenant-1", 0.40)
+	err := b.Check("tenant-1")
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Contains(t, err.Error(), "$1.00/$1.00")
+
+	assert.NoError(t, b.Check("tenant-2"), "spend is per tenant")
+}
+
+func TestCostBudget_MonthReset(t *testing.T) {
+	t.Parallel()
+	b := NewCostBudget(1.00)
+
+	now := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	b.Record("tenant-1", 5)
+	require.Error(t, b.Check("tenant-1"))
+
+	b.now = func() time.Time { return now.Add(2 * time.Hour) }
+	assert.NoError(t, b.Check("tenant-1"))
+	assert.Zero(t, b.Spent("tenant-1"))
+
+	b.Record("tenant-1", 0.25)
+	assert.InDelta(t, 0.25, b.Spent("tenant-1"), 1e-9)
+}
+
+func TestCostBudget_Limits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		monthly float64
+		tenant  map[string]float64
+		spend   float64
+		wantErr bool
+	}{
+		{name: "unlimited tracks only", monthly: 0, spend: 1000},
+		{name: "default limit", monthly: 10, spend: 10, wantErr: true},
+		{name: "tenant override raises", monthly: 10, tenant: map[string]float64{"t": 50}, spend: 20},
+		{name: "tenant override lowers", monthly: 10, tenant: map[string]float64{"t": 1}, spend: 2, wantErr: true},
+		{name: "tenant unlimited", monthly: 10, tenant: map[string]float64{"t": 0}, spend: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewCostBudget(tt.monthly)
+			for id, limit := range tt.tenant {
+				b.SetTenantLimit(id, limit)
+			}
+			b.Record("t", tt.spend)
+			if tt.wantErr {
+				assert.ErrorIs(t, b.Check("t"), ErrBudgetExceeded)
+			} else {
+				assert.NoError(t, b.Check("t"))
+			}
+			assert.InDelta(t, tt.spend, b.Spent("t"), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileStore is a Store kept in a JSON file. Worker replicas that open the
+// same file, for example on a shared volume, enforce one fleet-wide set of
+// rate limits and budgets, and the state survives restarts. Each update
+// holds an exclusive lock on a sibling ".lock" file while it reads the
+// state, applies the change and replaces the file, so the volume must
+// support flock. Updates that change nothing leave the file alone, and
+// loads read it without the lock: the file is only ever replaced whole.
+type FileStore struct {
+	path string
+	mu   sync.Mutex // serializes updates within the process
+}
+
+// Compile-time check.
+var _ Store = (*FileStore)(nil)
+
+// fileState is the content of a FileStore file.
+type fileState struct {
+	Buckets  map[string]*Bucket  `json:"buckets"`
+	Counters map[string]*Counter `json:"counters"`
+}
+
+// OpenFileStore opens (creating if needed) the state file at path.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("ratelimit: create dir: %w", err)
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: open %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileStore{path: path}, nil
+}
+
+func (s *FileStore) UpdateBuckets(keys []string, fn func([]*Bucket)) error {
+	return s.update(func(st *fileState) {
+		bs := make([]*Bucket, len(keys))
+		for i, key := range keys {
+			b, ok := st.Buckets[key]
+			if !ok {
+				b = &Bucket{}
+				st.Buckets[key] = b
+			}
+			bs[i] = b
+		}
+		fn(bs)
+	})
+}
+
+func (s *FileStore) UpdateCounter(key string, fn func(*Counter)) error {
+	return s.update(func(st *fileState) {
+		c, ok := st.Counters[key]
+		if !ok {
+			c = &Counter{}
+			st.Counters[key] = c
+		}
+		fn(c)
+	})
+}
+
+func (s *FileStore) LoadBucket(key string) (Bucket, error) {
+	st, _, err := s.read()
+	if err != nil {
+		return Bucket{}, err
+	}
+	if b, ok := st.Buckets[key]; ok {
+		return *b, nil
+	}
+	return Bucket{}, nil
+}
+
+func (s *FileStore) LoadCounter(key string) (Counter, error) {
+	st, _, err := s.read()
+	if err != nil {
+		return Counter{}, err
+	}
+	if c, ok := st.Counters[key]; ok {
+		return *c, nil
+	}
+	return Counter{}, nil
+}
+
+func (s *FileStore) Prune(now time.Time) error {
+	return s.update(func(st *fileState) {
+		for key, b := range st.Buckets {
+			if b.expired(now) {
+				delete(st.Buckets, key)
+			}
+		}
+		for key, c := range st.Counters {
+			if c.expired(now) {
+				delete(st.Counters, key)
+			}
+		}
+	})
+}
+
+func (s *FileStore) update(fn func(*fileState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("ratelimit: open %s: %w", s.path, err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("ratelimit: lock %s: %w", s.path, err)
+	}
+	defer unlockFile(lock)
+
+	st, old, err := s.read()
+	if err != nil {
+		return err
+	}
+	fn(st)
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("ratelimit: encode state: %w", err)
+	}
+	if bytes.Equal(data, old) {
+		return nil
+	}
+	return s.write(data)
+}
+
+// read loads the state and returns it with the file's content. A corrupt
+// file is logged and replaced by the next write rather than blocking every
+// limiter that shares it.
+func (s *FileStore) read() (*fileState, []byte, error) {
+	st := &fileState{}
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, nil, fmt.Errorf("ratelimit: read %s: %w", s.path, err)
+	default:
+		if err := json.Unmarshal(data, st); err != nil {
+			slog.Warn("ratelimit: discarding unreadable state", "path", s.path, "error", err)
+			st = &fileState{}
+		}
+	}
+	if st.Buckets == nil {
+		st.Buckets = make(map[string]*Bucket)
+	}
+	if st.Counters == nil {
+		st.Counters = make(map[string]*Counter)
+	}
+	return st, data, nil
+}
+
+// write replaces the state file with data through a temporary file that
+// is synced before the rename, so a crash never leaves a partial or empty
+// state behind.
+func (s *FileStore) write(data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("ratelimit: write %s: %w", s.path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("ratelimit: write %s: %w", s.path, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("ratelimit: sync %s: %w", s.path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("ratelimit: write %s: %w", s.path, err)
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return fmt.Errorf("ratelimit: write %s: %w", s.path, err)
+	}
+	return syncDir(filepath.Dir(s.path))
+}
+
+// syncDir flushes a directory entry change, such as a rename, to disk.
+// Filesystems that cannot sync a directory are not an error.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("ratelimit: sync %s: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) && !errors.Is(err, syscall.EINVAL) {
+		return fmt.Errorf("ratelimit: sync %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openFileStore(t *testing.T, path string) *FileStore {
+	t.Helper()
+	s, err := OpenFileStore(path)
+	require.NoError(t, err)
+	return s
+}
+
+func TestFileStore_SharedBudget(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "state", "limits.json")
+
+	// Two replicas sharing the file share one budget.
+	replicas := make([]*ActivityBudget, 2)
+	for i := range replicas {
+		replicas[i] = NewActivityBudget(3, time.Hour)
+		replicas[i].SetStore(openFileStore(t, path))
+	}
+	replicas[0].Record("tenant-1", "TriageAnomaly")
+	replicas[1].Record("tenant-1", "TriageAnomaly")
+	require.NoError(t, replicas[0].Check("tenant-1", "TriageAnomaly"))
+	replicas[0].Record("tenant-1", "TriageAnomaly")
+	assert.ErrorIs(t, replicas[1].Check("tenant-1", "TriageAnomaly"), ErrBudgetExceeded)
+
+	// A restarted replica picks up where the fleet left off.
+	restarted := NewActivityBudget(3, time.Hour)
+	restarted.SetStore(openFileStore(t, path))
+	assert.ErrorIs(t, restarted.Check("tenant-1", "TriageAnomaly"), ErrBudgetExceeded)
+	assert.NoError(t, restarted.Check("tenant-2", "TriageAnomaly"))
+}
+
+func TestFileStore_SharedCostBudget(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+
+	replicas := make([]*CostBudget, 2)
+	for i := range replicas {
+		replicas[i] = NewCostBudget(1.00)
+		replicas[i].SetStore(openFileStore(t, path))
+	}
+	replicas[0].Record("tenant-1", 0.60)
+	require.NoError(t, replicas[1].Check("tenant-1"))
+	replicas[1].Record("tenant-1", 0.40)
+	assert.ErrorIs(t, replicas[0].Check("tenant-1"), ErrBudgetExceeded, "the limit holds across replicas")
+
+	restarted := NewCostBudget(1.00)
+	restarted.SetStore(openFileStore(t, path))
+	assert.InDelta(t, 1.00, restarted.Spent("tenant-1"), 1e-9, "a restart keeps the month's spend")
+	assert.ErrorIs(t, restarted.Check("tenant-1"), ErrBudgetExceeded)
+}
+
+func TestFileStore_SharedLimiter(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	a := NewServiceLimiter(ServiceRates{CostExplorer: 10, TenantShare: 1})
+	a.SetStore(openFileStore(t, path))
+	b := NewServiceLimiter(ServiceRates{CostExplorer: 10, TenantShare: 1})
+	b.SetStore(openFileStore(t, path))
+
+	a.Observe("CostExplorer", Scope{}, &smithy.GenericAPIError{Code: "LimitExceededException"})
+	assert.Equal(t, 5.0, b.Rate("CostExplorer", Scope{}), "a throttle seen by one replica slows all of them")
+}
+
+func TestFileStore_ConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	stores := []*FileStore{openFileStore(t, path), openFileStore(t, path)}
+
+	const perStore = 25
+	var wg sync.WaitGroup
+	for _, s := range stores {
+		for range perStore {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, s.UpdateCounter("k", func(c *Counter) { c.Count++ }))
+			}()
+		}
+	}
+	wg.Wait()
+
+	got, err := stores[0].LoadCounter("k")
+	require.NoError(t, err)
+	assert.Equal(t, 2*perStore, got.Count)
+}
+
+func TestFileStore_ConcurrentReserve(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	replicas := make([]*ActivityBudget, 2)
+	for i := range replicas {
+		replicas[i] = NewActivityBudget(10, time.Hour)
+		replicas[i].SetStore(openFileStore(t, path))
+	}
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for _, b := range replicas {
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if b.Reserve("tenant-1", "TriageAnomaly") == nil {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, 10, allowed, "the fleet admits exactly the budget")
+}
+
+func TestFileStore_ReadsDoNotRewrite(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	store := openFileStore(t, path)
+	budget := NewActivityBudget(3, time.Hour)
+	budget.SetStore(store)
+	limiter := NewServiceLimiter(ServiceRates{CostExplorer: 10, TenantShare: 1})
+	limiter.SetStore(store)
+	require.NoError(t, budget.Reserve("tenant-1", "TriageAnomaly"))
+	require.NoError(t, limiter.Wait(context.Background(), "CostExplorer", Scope{}))
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, budget.Check("tenant-1", "TriageAnomaly"))
+	assert.Equal(t, 10.0, limiter.Rate("CostExplorer", Scope{}))
+	limiter.Observe("CostExplorer", Scope{}, nil) // success at the full rate
+
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after), "the state file was replaced")
+}
+
+func TestFileStore_Prune(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	s := openFileStore(t, path)
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, s.UpdateCounter("old", func(c *Counter) { *c = Counter{Count: 3, WindowEnd: now} }))
+	require.NoError(t, s.UpdateCounter("live", func(c *Counter) { *c = Counter{Count: 1, WindowEnd: now.Add(time.Minute)} }))
+	require.NoError(t, s.UpdateBuckets([]string{"idle", "busy"}, func(bs []*Bucket) {
+		*bs[0] = Bucket{Rate: 1, Last: now.Add(-bucketIdle)}
+		*bs[1] = Bucket{Rate: 1, Last: now.Add(-time.Minute)}
+	}))
+
+	require.NoError(t, s.Prune(now))
+	st, _, err := s.read()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"live"}, keys(st.Counters))
+	assert.Equal(t, []string{"busy"}, keys(st.Buckets))
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "limits.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	s := openFileStore(t, path)
+	require.NoError(t, s.UpdateCounter("k", func(c *Counter) { c.Count++ }))
+	got, err := s.LoadCounter("k")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Count)
+}
@@ -4,17 +4,26 @@ package ratelimit
 import (
 	"context"
 	"fmt"
-	"sync"
-
-	"golang.org/x/time/rate"
+	"log/slog"
+	"time"
 )
 
 // ServiceRates configures per-service request rates (requests per second).
+// Zero fields take the DefaultServiceRates value.
 type ServiceRates struct {
 	CostExplorer float64
 	Athena       float64
 	CloudWatch   float64
 	STS          float64
+	CodeDeploy   float64
+	Tagging      float64
+	ELBv2        float64
+	KubeCost     float64
+
+	// TenantShare is the fraction of a service's rate that one tenant, or
+	// one AWS account, may use, so a noisy tenant cannot starve the
+	// others. 1 disables the per-tenant and per-account buckets.
+	TenantShare float64
 }
 
 // DefaultServiceRates returns conservative AWS rate limits.
@@ -24,36 +33,213 @@ func DefaultServiceRates() ServiceRates {
 		Athena:       5,
 		CloudWatch:   20,
 		STS:          10,
+		CodeDeploy:   5,
+		Tagging:      5,
+		ELBv2:        10,
+		KubeCost:     10,
+		TenantShare:  0.5,
+	}
+}
+
+func (r ServiceRates) byService() map[string]float64 {
+	def := DefaultServiceRates()
+	or := func(v, fallback float64) float64 {
+		if v <= 0 {
+			return fallback
+		}
+		return v
 	}
+	return map[string]float64{
+		"CostExplorer": or(r.CostExplorer, def.CostExplorer),
+		"Athena":       or(r.Athena, def.Athena),
+		"CloudWatch":   or(r.CloudWatch, def.CloudWatch),
+		"STS":          or(r.STS, def.STS),
+		"CodeDeploy":   or(r.CodeDeploy, def.CodeDeploy),
+		"Tagging":      or(r.Tagging, def.Tagging),
+		"ELBv2":        or(r.ELBv2, def.ELBv2),
+		"KubeCost":     or(r.KubeCost, def.KubeCost),
+	}
+}
+
+// Scope identifies who a call is made for. Empty fields skip the
+// corresponding bucket.
+type Scope struct {
+	TenantID  string
+	AccountID string
 }
 
-// ServiceLimiter rate-limits AWS API calls per service using token buckets.
+// AIMD tuning. A throttled call halves the rate of every bucket it waited
+// on, at most once per cutCooldown so that a burst of throttles from one
+// congested period counts once. Each successful call wins back
+// increaseStep of the bucket's configured rate. See Bucket.observe.
+const (
+	decreaseFactor = 0.5
+	increaseStep   = 0.05
+	minFraction    = 0.05 // rates never drop below this fraction of the configured rate
+	cutCooldown    = time.Second
+)
+
+// ServiceLimiter rate-limits API calls per service using token buckets
+// whose rates adapt to throttling (additive increase, multiplicative
+// decrease). Each call waits on its tenant's and its account's bucket,
+// each allowed TenantShare of the service rate, and then on the
+// service-wide bucket. Buckets live in a Store, in memory unless SetStore
+// shares them with other replicas.
 type ServiceLimiter struct {
-	mu       sync.RWMutex
-	limiters map[string]*rate.Limiter
+	rates map[string]float64 // configured rate per service
+	share float64
+	store Store
+	now   func() time.Time
+	prune pruner
+}
+
+func burstFor(r float64) int {
+	return max(1, int(r))
 }
 
 // NewServiceLimiter creates a limiter with the given per-service rates.
 func NewServiceLimiter(rates ServiceRates) *ServiceLimiter {
-	limiters := map[string]*rate.Limiter{
-		"CostExplorer": rate.NewLimiter(rate.Limit(rates.CostExplorer), int(rates.CostExplorer)),
-		"Athena":       rate.NewLimiter(rate.Limit(rates.Athena), int(rates.Athena)),
-		"CloudWatch":   rate.NewLimiter(rate.Limit(rates.CloudWatch), int(rates.CloudWatch)),
-		"STS":          rate.NewLimiter(rate.Limit(rates.STS), int(rates.STS)),
+	share := rates.TenantShare
+	if share <= 0 {
+		share = DefaultServiceRates().TenantShare
+	}
+	return &ServiceLimiter{
+		rates: rates.byService(),
+		share: min(share, 1),
+		store: NewMemoryStore(),
+		now:   time.Now,
 	}
-	return &ServiceLimiter{limiters: limiters}
 }
 
-// Wait blocks until a token is available for the named service, or ctx is cancelled.
-func (sl *ServiceLimiter) Wait(ctx context.Context, service string) error {
-	sl.mu.RLock()
-	limiter, ok := sl.limiters[service]
-	sl.mu.RUnlock()
+// SetStore keeps the limiter's buckets in s. Set it before the limiter is
+// used.
+func (sl *ServiceLimiter) SetStore(s Store) {
+	sl.store = s
+}
+
+// bucketRef names a bucket and its configured rate.
+type bucketRef struct {
+	key   string
+	limit float64
+}
+
+// bucketsFor returns the buckets a call for service and scope waits on,
+// most specific first. It returns nil for unknown services.
+func (sl *ServiceLimiter) bucketsFor(service string, scope Scope) []bucketRef {
+	r, ok := sl.rates[service]
 	if !ok {
-		return nil // unknown service = no limit
+		return nil
+	}
+	var out []bucketRef
+	if sl.share < 1 {
+		if scope.TenantID != "" {
+			out = append(out, bucketRef{service + "|tenant|" + scope.TenantID, r * sl.share})
+		}
+		if scope.AccountID != "" {
+			out = append(out, bucketRef{service + "|account|" + scope.AccountID, r * sl.share})
+		}
+	}
+	return append(out, bucketRef{service, r})
+}
+
+// Wait blocks until a token is available for the named service in every
+// bucket of scope, or ctx is cancelled. It takes the tokens of all the
+// buckets in one store update and fails at once, giving them back, if ctx
+// would expire first. When the store fails, the call goes ahead: limits
+// are best effort, and AWS throttling is retried anyway.
+func (sl *ServiceLimiter) Wait(ctx context.Context, service string, scope Scope) error {
+	refs := sl.bucketsFor(service, scope)
+	if len(refs) == 0 {
+		return nil
 	}
-	if err := limiter.Wait(ctx); err != nil {
+	if err := ctx.Err(); err != nil {
 		return fmt.Errorf("rate limit %s: %w", service, err)
 	}
+	now := sl.now()
+	sl.prune.maybePrune(sl.store, now)
+	var delay time.Duration
+	err := sl.store.UpdateBuckets(keysOf(refs), func(bs []*Bucket) {
+		for i, b := range bs {
+			delay = max(delay, b.reserve(refs[i].limit, now))
+		}
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "ratelimit: store update failed", "service", service, "error", err)
+		return nil
+	}
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		sl.refund(refs)
+		return fmt.Errorf("rate limit %s: wait of %s exceeds deadline: %w", service, delay.Round(time.Millisecond), context.DeadlineExceeded)
+	}
+	t := time.NewTimer(delay)
+	select {
+	case <-ctx.Done():
+		t.Stop()
+		sl.refund(refs)
+		return fmt.Errorf("rate limit %s: %w", service, ctx.Err())
+	case <-t.C:
+	}
 	return nil
 }
+
+// refund gives back the tokens Wait took from refs for a call that is not
+// made, so failed waiters do not leave the buckets in debt.
+func (sl *ServiceLimiter) refund(refs []bucketRef) {
+	if err := sl.store.UpdateBuckets(keysOf(refs), func(bs []*Bucket) {
+		for _, b := range bs {
+			b.refund()
+		}
+	}); err != nil {
+		slog.Warn("ratelimit: store update failed", "error", err)
+	}
+}
+
+func keysOf(refs []bucketRef) []string {
+	keys := make([]string, len(refs))
+	for i, ref := range refs {
+		keys[i] = ref.key
+	}
+	return keys
+}
+
+// Observe adapts the buckets of service and scope to the outcome of a call:
+// throttling errors cut their rates and successes raise them. Other errors
+// leave them unchanged.
+func (sl *ServiceLimiter) Observe(service string, scope Scope, err error) {
+	throttled := IsThrottle(err)
+	if err != nil && !throttled {
+		return
+	}
+	refs := sl.bucketsFor(service, scope)
+	if len(refs) == 0 {
+		return
+	}
+	now := sl.now()
+	if err := sl.store.UpdateBuckets(keysOf(refs), func(bs []*Bucket) {
+		for i, b := range bs {
+			b.observe(refs[i].limit, throttled, now)
+		}
+	}); err != nil {
+		slog.Warn("ratelimit: store update failed", "service", service, "error", err)
+	}
+}
+
+// Rate returns the current rate of the most specific bucket for service
+// and scope: the tenant's, else the account's, else the service-wide one.
+// Unknown services return 0.
+func (sl *ServiceLimiter) Rate(service string, scope Scope) float64 {
+	buckets := sl.bucketsFor(service, scope)
+	if len(buckets) == 0 {
+		return 0
+	}
+	ref := buckets[0]
+	b, err := sl.store.LoadBucket(ref.key)
+	if err != nil {
+		return ref.limit
+	}
+	b.init(ref.limit, sl.now())
+	return b.Rate
+}
@@ -2,8 +2,11 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,7 +28,7 @@ func TestServiceLimiter_Wait(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			sl := NewServiceLimiter(tt.rates)
-			err := sl.Wait(context.Background(), tt.service)
+			err := sl.Wait(context.Background(), tt.service, Scope{})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -37,10 +40,87 @@ func TestServiceLimiter_Wait(t *testing.T) {
 	t.Run("cancelled context errors", func(t *testing.T) {
 		t.Parallel()
 		sl := NewServiceLimiter(ServiceRates{CostExplorer: 0.001})
-		_ = sl.Wait(context.Background(), "CostExplorer") // consume burst
+		_ = sl.Wait(context.Background(), "CostExplorer", Scope{}) // consume burst
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
-		err := sl.Wait(ctx, "CostExplorer")
+		err := sl.Wait(ctx, "CostExplorer", Scope{})
 		assert.Error(t, err)
 	})
+
+	t.Run("deadline refusal gives the token back", func(t *testing.T) {
+		t.Parallel()
+		sl := NewServiceLimiter(ServiceRates{CostExplorer: 1, TenantShare: 0.5})
+		now := time.Now()
+		sl.now = func() time.Time { return now }
+		scope := Scope{TenantID: "tenant-1"}
+		require.NoError(t, sl.Wait(context.Background(), "CostExplorer", scope)) // consume burst
+		for range 5 {
+			ctx, cancel := context.WithDeadline(context.Background(), now.Add(time.Millisecond))
+			err := sl.Wait(ctx, "CostExplorer", scope)
+			cancel()
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+		for _, key := range []string{"CostExplorer|tenant|tenant-1", "CostExplorer"} {
+			b, err := sl.store.LoadBucket(key)
+			require.NoError(t, err)
+			assert.Equal(t, 0.0, b.Tokens, "%s: failed waiters leave no debt", key)
+		}
+	})
+}
+
+func TestServiceLimiter_AIMD(t *testing.T) {
+	t.Parallel()
+	sl := NewServiceLimiter(ServiceRates{CostExplorer: 10, TenantShare: 1})
+	now := time.Now()
+	sl.now = func() time.Time { return now }
+	throttle := &smithy.GenericAPIError{Code: "LimitExceededException"}
+
+	sl.Observe("CostExplorer", Scope{}, throttle)
+	assert.Equal(t, 5.0, sl.Rate("CostExplorer", Scope{}))
+
+	sl.Observe("CostExplorer", Scope{}, throttle)
+	assert.Equal(t, 5.0, sl.Rate("CostExplorer", Scope{}), "throttles within the cooldown count once")
+
+	now = now.Add(cutCooldown)
+	for range 10 {
+		sl.Observe("CostExplorer", Scope{}, throttle)
+		now = now.Add(cutCooldown)
+	}
+	assert.Equal(t, 0.5, sl.Rate("CostExplorer", Scope{}), "rates stop at the floor")
+
+	sl.Observe("CostExplorer", Scope{}, errors.New("access denied"))
+	assert.Equal(t, 0.5, sl.Rate("CostExplorer", Scope{}), "other errors leave the rate alone")
+
+	sl.Observe("CostExplorer", Scope{}, nil)
+	assert.InDelta(t, 1.0, sl.Rate("CostExplorer", Scope{}), 1e-9)
+	for range 100 {
+		sl.Observe("CostExplorer", Scope{}, nil)
+	}
+	assert.Equal(t, 10.0, sl.Rate("CostExplorer", Scope{}), "successes recover up to the configured rate")
+}
+
+func TestServiceLimiter_TenantBuckets(t *testing.T) {
+	t.Parallel()
+	sl := NewServiceLimiter(ServiceRates{Athena: 10, TenantShare: 0.5})
+	noisy := Scope{TenantID: "noisy", AccountID: "111"}
+	quiet := Scope{TenantID: "quiet", AccountID: "222"}
+
+	assert.Equal(t, 5.0, sl.Rate("Athena", noisy))
+	assert.Equal(t, 5.0, sl.Rate("Athena", Scope{AccountID: "111"}))
+	assert.Equal(t, 10.0, sl.Rate("Athena", Scope{}))
+
+	sl.Observe("Athena", noisy, &smithy.GenericAPIError{Code: "TooManyRequestsException"})
+	assert.Equal(t, 2.5, sl.Rate("Athena", noisy))
+	assert.Equal(t, 2.5, sl.Rate("Athena", Scope{AccountID: "111"}))
+	assert.Equal(t, 5.0, sl.Rate("Athena", Scope{}))
+	assert.Equal(t, 5.0, sl.Rate("Athena", quiet), "other tenants keep their share")
+
+	// The noisy tenant's bucket runs dry without holding up the quiet one.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for range 2 {
+		require.NoError(t, sl.Wait(ctx, "Athena", noisy))
+	}
+	assert.Error(t, sl.Wait(ctx, "Athena", noisy))
+	assert.NoError(t, sl.Wait(ctx, "Athena", quiet))
 }
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package ratelimit
+
+import "os"
+
+// Without flock, updates are only serialized within one process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) {}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+
+	"github.com/finops-claw-gang/finops-go/internal/observability"
+)
+
+// throttleCodes are the AWS error codes services use for throttling.
+var throttleCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"LimitExceededException":                 true,
+	"RequestLimitExceeded":                   true,
+	"ProvisionedThroughputExceededException": true,
+	"SlowDown":                               true,
+}
+
+// statusCoder is implemented by AWS SDK response errors and by HTTP
+// connector errors that carry the response status.
+type statusCoder interface {
+	HTTPStatusCode() int
+}
+
+// IsThrottle reports whether err is an AWS throttling error or an HTTP 429.
+func IsThrottle(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && throttleCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var sc statusCoder
+	return errors.As(err, &sc) && sc.HTTPStatusCode() == http.StatusTooManyRequests
+}
+
+// IsRetryable reports whether a call failing with err may succeed when
+// retried: throttling and server-side (5xx) errors.
+func IsRetryable(err error) bool {
+	if IsThrottle(err) {
+		return true
+	}
+	var sc statusCoder
+	return errors.As(err, &sc) && sc.HTTPStatusCode() >= 500
+}
+
+// SDKRetryer returns the AWS SDK retryer for clients whose calls go
+// through Do: the SDK's standard retryer, except that it leaves the errors
+// Do retries, throttles and 5xx, to Do. Each attempt then waits on the
+// limiter and reports its outcome to Observe, and retries of the two
+// layers do not multiply. The SDK still retries connection errors.
+func SDKRetryer() aws.Retryer {
+	return sdkRetryer{RetryerV2: retry.NewStandard()}
+}
+
+type sdkRetryer struct {
+	aws.RetryerV2
+}
+
+func (r sdkRetryer) IsErrorRetryable(err error) bool {
+	return !IsRetryable(err) && r.RetryerV2.IsErrorRetryable(err)
+}
+
+// RetryPolicy configures retries of retryable errors with exponential
+// backoff and full jitter.
+type RetryPolicy struct {
+	MaxAttempts int // including the first; <= 1 = no retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy of the connector clients.
+// With SDKRetryer it is the only layer retrying throttles and 5xx.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// backoff returns the delay before retry n (0-based): a uniformly random
+// duration up to BaseDelay*2^n, capped at MaxDelay.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	ceiling := p.BaseDelay << min(n, 30)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// waitTimeout bounds rate limiter waits so that a severely constrained
+// limiter fails the call instead of holding the activity until its
+// start-to-close timeout.
+const waitTimeout = 30 * time.Second
+
+// Do calls fn for service and scope, waiting on sl before each attempt,
+// reporting each outcome to sl.Observe, and retrying retryable errors
+// under policy. A nil sl does not limit. When ctx ends during a backoff,
+// the last error from fn is returned.
+func Do[T any](ctx context.Context, sl *ServiceLimiter, service string, scope Scope, policy RetryPolicy, fn func(context.Context) (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		if sl != nil {
+			if err := wait(ctx, sl, service, scope); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		out, err := fn(ctx)
+		if sl != nil {
+			sl.Observe(service, scope, err)
+		}
+		if err == nil || !IsRetryable(err) || attempt+1 >= policy.MaxAttempts {
+			return out, err
+		}
+
+		t := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return out, err
+		case <-t.C:
+		}
+	}
+}
+
+func wait(ctx context.Context, sl *ServiceLimiter, service string, scope Scope) error {
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+	start := time.Now()
+	err := sl.Wait(ctx, service, scope)
+	observability.Default().RecordLimiterWait(ctx, service, time.Since(start))
+	return err
+}
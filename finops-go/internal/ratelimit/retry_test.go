@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func responseError(status int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("boom"),
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		throttle  bool
+		retryable bool
+	}{
+		{"nil", nil, false, false},
+		{"cost explorer limit", fmt.Errorf("costexplorer: get ri coverage: %w", &smithy.GenericAPIError{Code: "LimitExceededException"}), true, true},
+		{"throttling exception", &smithy.GenericAPIError{Code: "ThrottlingException"}, true, true},
+		{"tagging throttled", &smithy.GenericAPIError{Code: "ThrottledException"}, true, true},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDeniedException"}, false, false},
+		{"http 429", responseError(http.StatusTooManyRequests), true, true},
+		{"http 503", responseError(http.StatusServiceUnavailable), false, true},
+		{"http 400", responseError(http.StatusBadRequest), false, false},
+		{"plain error", errors.New("boom"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.throttle, IsThrottle(tt.err))
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestSDKRetryer(t *testing.T) {
+	t.Parallel()
+	r := SDKRetryer()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"throttle left to Do", &smithy.GenericAPIError{Code: "ThrottlingException"}, false},
+		{"5xx left to Do", responseError(http.StatusInternalServerError), false},
+		{"connection error retried by the SDK", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"client error", responseError(http.StatusBadRequest), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, r.IsErrorRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for n := range 40 {
+		d := p.backoff(n)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, min(p.BaseDelay<<min(n, 30), p.MaxDelay))
+	}
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	throttle := &smithy.GenericAPIError{Code: "ThrottlingException"}
+
+	tests := []struct {
+		name      string
+		errs      []error // per attempt; attempts past the end succeed
+		wantCalls int
+		wantErr   error
+	}{
+		{"success", nil, 1, nil},
+		{"retries throttling", []error{throttle, throttle}, 3, nil},
+		{"gives up after max attempts", []error{throttle, throttle, throttle, throttle}, 3, throttle},
+		{"does not retry client errors", []error{errors.New("bad request")}, 1, errors.New("bad request")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sl := NewServiceLimiter(ServiceRates{CloudWatch: 1000})
+			calls := 0
+			got, err := Do(context.Background(), sl, "CloudWatch", Scope{TenantID: "t-1"}, policy, func(context.Context) (int, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return 0, tt.errs[calls-1]
+				}
+				return 42, nil
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 42, got)
+		})
+	}
+
+	t.Run("throttles slow the limiter", func(t *testing.T) {
+		t.Parallel()
+		sl := NewServiceLimiter(ServiceRates{CloudWatch: 1000, TenantShare: 1})
+		_, _ = Do(context.Background(), sl, "CloudWatch", Scope{}, RetryPolicy{}, func(context.Context) (int, error) {
+			return 0, throttle
+		})
+		assert.Equal(t, 500.0, sl.Rate("CloudWatch", Scope{}))
+	})
+
+	t.Run("nil limiter retries", func(t *testing.T) {
+		t.Parallel()
+		calls := 0
+		_, err := Do(context.Background(), nil, "CloudWatch", Scope{}, policy, func(context.Context) (int, error) {
+			calls++
+			return 0, throttle
+		})
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("cancelled context stops retrying", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := Do(ctx, nil, "CloudWatch", Scope{}, RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}, func(context.Context) (int, error) {
+			calls++
+			cancel()
+			return 0, throttle
+		})
+		assert.ErrorIs(t, err, throttle)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,230 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Store keeps the state of ServiceLimiter buckets and ActivityBudget and
+// CostBudget counters. Each update must be atomic with respect to every limiter and
+// budget sharing the store, so a store shared by worker replicas enforces
+// one fleet-wide set of limits.
+type Store interface {
+	// UpdateBuckets applies fn to the buckets stored under keys, in the
+	// same order, in one update. Missing buckets are passed as zero
+	// Buckets.
+	UpdateBuckets(keys []string, fn func([]*Bucket)) error
+	// UpdateCounter applies fn to the counter stored under key. A missing
+	// counter is passed as the zero Counter.
+	UpdateCounter(key string, fn func(*Counter)) error
+	// LoadBucket returns the bucket stored under key, or the zero Bucket,
+	// without changing the store.
+	LoadBucket(key string) (Bucket, error)
+	// LoadCounter returns the counter stored under key, or the zero
+	// Counter, without changing the store.
+	LoadCounter(key string) (Counter, error)
+	// Prune drops the counters whose window ended by now and the buckets
+	// unused for bucketIdle.
+	Prune(now time.Time) error
+}
+
+// Pruning. A bucket unused for bucketIdle has long refilled, so dropping
+// it only forgets a rate cut that is an hour old. Limiters and budgets
+// prune their store at most once per pruneInterval.
+const (
+	bucketIdle    = time.Hour
+	pruneInterval = time.Minute
+)
+
+// expired reports whether the bucket can be dropped at now.
+func (b *Bucket) expired(now time.Time) bool {
+	return now.Sub(b.Last) >= bucketIdle
+}
+
+// expired reports whether the counter's window ended by now.
+func (c *Counter) expired(now time.Time) bool {
+	return !now.Before(c.WindowEnd)
+}
+
+// pruner calls Store.Prune at most once per pruneInterval.
+type pruner struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (p *pruner) maybePrune(s Store, now time.Time) {
+	p.mu.Lock()
+	due := now.Sub(p.last) >= pruneInterval || now.Before(p.last)
+	if due {
+		p.last = now
+	}
+	p.mu.Unlock()
+	if !due {
+		return
+	}
+	if err := s.Prune(now); err != nil {
+		slog.Warn("ratelimit: store prune failed", "error", err)
+	}
+}
+
+// Bucket is the state of one adaptive token bucket.
+type Bucket struct {
+	Rate   float64   `json:"rate"` // current rate; 0 = not yet used
+	Tokens float64   `json:"tokens"`
+	Last   time.Time `json:"last"`            // when Tokens was last refilled
+	CutAt  time.Time `json:"cut_at,omitzero"` // last throttling cut
+}
+
+// init starts an unused bucket full, at its configured rate limit, and
+// clamps a bucket whose configured rate has been lowered.
+func (b *Bucket) init(limit float64, now time.Time) {
+	if b.Rate == 0 {
+		b.Rate = limit
+		b.Tokens = float64(burstFor(limit))
+		b.Last = now
+		return
+	}
+	if b.Rate > limit {
+		b.setRate(limit, limit)
+	}
+}
+
+func (b *Bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.Last); elapsed > 0 {
+		b.Tokens = min(float64(burstFor(b.Rate)), b.Tokens+elapsed.Seconds()*b.Rate)
+		b.Last = now
+	}
+}
+
+// setRate sets the rate to r, kept within [limit*minFraction, limit].
+func (b *Bucket) setRate(limit, r float64) {
+	b.Rate = min(limit, max(r, limit*minFraction))
+	b.Tokens = min(b.Tokens, float64(burstFor(b.Rate)))
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it.
+func (b *Bucket) reserve(limit float64, now time.Time) time.Duration {
+	b.init(limit, now)
+	b.refill(now)
+	b.Tokens--
+	if b.Tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.Tokens / b.Rate * float64(time.Second))
+}
+
+// refund gives back a token reserved by a caller that never made its
+// call.
+func (b *Bucket) refund() {
+	b.Tokens = min(float64(burstFor(b.Rate)), b.Tokens+1)
+}
+
+// observe adapts the rate to the outcome of a call: throttles halve it, at
+// most once per cutCooldown, and successes raise it by increaseStep of
+// limit, the configured rate. A success at the full rate changes nothing.
+func (b *Bucket) observe(limit float64, throttled bool, now time.Time) {
+	b.init(limit, now)
+	if !throttled && b.Rate >= limit {
+		return
+	}
+	b.refill(now)
+	switch {
+	case !throttled:
+		if b.Rate < limit {
+			b.setRate(limit, b.Rate+limit*increaseStep)
+		}
+	case now.Sub(b.CutAt) >= cutCooldown:
+		b.CutAt = now
+		b.setRate(limit, b.Rate*decreaseFactor)
+	}
+}
+
+// Counter is the state of one ActivityBudget or CostBudget window.
+type Counter struct {
+	Count     int       `json:"count"`
+	Total     float64   `json:"total,omitempty"` // summed amounts, e.g. CostBudget dollars
+	WindowEnd time.Time `json:"window_end"`
+}
+
+// MemoryStore is a Store local to one process. It is the default store of
+// limiters and budgets.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*Bucket
+	counters map[string]*Counter
+}
+
+// Compile-time check.
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*Bucket),
+		counters: make(map[string]*Counter),
+	}
+}
+
+func (s *MemoryStore) UpdateBuckets(keys []string, fn func([]*Bucket)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bs := make([]*Bucket, len(keys))
+	for i, key := range keys {
+		b, ok := s.buckets[key]
+		if !ok {
+			b = &Bucket{}
+			s.buckets[key] = b
+		}
+		bs[i] = b
+	}
+	fn(bs)
+	return nil
+}
+
+func (s *MemoryStore) UpdateCounter(key string, fn func(*Counter)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &Counter{}
+		s.counters[key] = c
+	}
+	fn(c)
+	return nil
+}
+
+func (s *MemoryStore) LoadBucket(key string) (Bucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.buckets[key]; ok {
+		return *b, nil
+	}
+	return Bucket{}, nil
+}
+
+func (s *MemoryStore) LoadCounter(key string) (Counter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.counters[key]; ok {
+		return *c, nil
+	}
+	return Counter{}, nil
+}
+
+func (s *MemoryStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if b.expired(now) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, c := range s.counters {
+		if c.expired(now) {
+			delete(s.counters, key)
+		}
+	}
+	return nil
+}
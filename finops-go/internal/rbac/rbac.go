@@ -0,0 +1,161 @@
+// Package rbac maps caller roles to permissions over FinOps operations. The
+// HTTP API and the MCP server check the same matrix, and record every
+// denial with an Auditor.
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+)
+
+// Role is a named bundle of permissions, taken from OIDC roles or groups
+// claims.
+type Role string
+
+const (
+	Viewer        Role = "viewer"
+	Analyst       Role = "analyst"
+	Approver      Role = "approver"
+	ExecutorAdmin Role = "executor-admin"
+	TenantAdmin   Role = "tenant-admin"
+)
+
+// DefaultRole is assumed for an authenticated caller with no known role.
+const DefaultRole = Viewer
+
+// Permission is one class of operation.
+type Permission string
+
+const (
+	PermRead        Permission = "read"         // list and read workflows, anomalies, savings
+	PermApprove     Permission = "approve"      // approve or deny pending actions
+	PermTrigger     Permission = "trigger"      // start anomaly workflows and sweeps
+	PermTenantAdmin Permission = "tenant_admin" // manage the tenant's settings and keys
+)
+
+// Matrix lists each role's permissions.
+var Matrix = map[Role][]Permission{
+	Viewer:        {PermRead},
+	Analyst:       {PermRead, PermTrigger},
+	Approver:      {PermRead, PermApprove},
+	ExecutorAdmin: {PermRead, PermApprove, PermTrigger},
+	TenantAdmin:   {PermRead, PermApprove, PermTrigger, PermTenantAdmin},
+}
+
+// ErrDenied is returned (wrapped) when a principal lacks a permission.
+var ErrDenied = apperr.NewSentinel(apperr.Forbidden, "rbac: permission denied")
+
+// RolesFromClaims picks the known roles out of claim values, such as the
+// roles and groups claims of a token. With none, it returns DefaultRole.
+func RolesFromClaims(claims ...[]string) []Role {
+	var roles []Role
+	for _, values := range claims {
+		for _, v := range values {
+			r := Role(v)
+			if _, ok := Matrix[r]; ok && !slices.Contains(roles, r) {
+				roles = append(roles, r)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return []Role{DefaultRole}
+	}
+	return roles
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject  string
+	TenantID string
+	Roles    []Role
+	// Email and Issuer identify the subject outside its issuer, for
+	// approval records. Either may be empty.
+	Email  string
+	Issuer string
+	// Admin callers hold every permission in every tenant.
+	Admin bool
+}
+
+// Can reports whether any of the principal's roles grants perm.
+func (p Principal) Can(perm Permission) bool {
+	if p.Admin {
+		return true
+	}
+	for _, r := range p.Roles {
+		if slices.Contains(Matrix[r], perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrIdentityMismatch is returned (wrapped) when a caller acts in another
+// identity's name, such as approving "as" someone else.
+var ErrIdentityMismatch = apperr.NewSentinel(apperr.Forbidden, "rbac: identity does not match the authenticated caller")
+
+// ActingAs returns the name an authenticated principal acts under, given
+// the name the caller asserted. An empty claim, or one naming the
+// principal's subject or email, yields the subject; anything else is
+// ErrIdentityMismatch, as is a principal with no subject.
+func (p Principal) ActingAs(claimed string) (string, error) {
+	if p.Subject == "" {
+		return "", fmt.Errorf("%w: caller has no subject", ErrIdentityMismatch)
+	}
+	if claimed != "" && claimed != p.Subject && (p.Email == "" || !strings.EqualFold(claimed, p.Email)) {
+		return "", fmt.Errorf("%w: %q is not %s", ErrIdentityMismatch, claimed, p.Subject)
+	}
+	return p.Subject, nil
+}
+
+// Denial describes a refused operation.
+type Denial struct {
+	Time       time.Time  `json:"time"`
+	Surface    string     `json:"surface"` // "api" or "mcp"
+	Operation  string     `json:"operation"`
+	Permission Permission `json:"permission"`
+	Subject    string     `json:"subject,omitempty"`
+	TenantID   string     `json:"tenant_id,omitempty"`
+	Roles      []Role     `json:"roles,omitempty"`
+}
+
+// Auditor records denials.
+type Auditor interface {
+	RecordDenial(ctx context.Context, d Denial)
+}
+
+// LogAuditor records denials as structured warnings.
+type LogAuditor struct{}
+
+// RecordDenial implements Auditor.
+func (LogAuditor) RecordDenial(ctx context.Context, d Denial) {
+	slog.WarnContext(ctx, "rbac: permission denied",
+		"surface", d.Surface, "operation", d.Operation, "permission", d.Permission,
+		"subject", d.Subject, "tenant_id", d.TenantID, "roles", d.Roles)
+}
+
+// Check returns nil if p may perform an operation needing perm, and
+// otherwise audits the denial and returns ErrDenied. A nil auditor logs.
+func Check(ctx context.Context, a Auditor, p Principal, perm Permission, surface, operation string) error {
+	if p.Can(perm) {
+		return nil
+	}
+	if a == nil {
+		a = LogAuditor{}
+	}
+	a.RecordDenial(ctx, Denial{
+		Time:       time.Now().UTC(),
+		Surface:    surface,
+		Operation:  operation,
+		Permission: perm,
+		Subject:    p.Subject,
+		TenantID:   p.TenantID,
+		Roles:      p.Roles,
+	})
+	return fmt.Errorf("%w: %s requires %s", ErrDenied, operation, perm)
+}
@@ -0,0 +1,128 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRolesFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims [][]string
+		want   []Role
+	}{
+		{name: "none defaults to viewer", want: []Role{Viewer}},
+		{name: "unknown ignored", claims: [][]string{{"engineering"}}, want: []Role{Viewer}},
+		{name: "roles claim", claims: [][]string{{"approver"}}, want: []Role{Approver}},
+		{
+			name:   "roles and groups merged without duplicates",
+			claims: [][]string{{"analyst", "approver"}, {"approver", "platform"}},
+			want:   []Role{Analyst, Approver},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RolesFromClaims(tt.claims...); !slices.Equal(got, tt.want) {
+				t.Errorf("RolesFromClaims = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrincipalCan(t *testing.T) {
+	all := []Permission{PermRead, PermApprove, PermTrigger, PermTenantAdmin}
+	tests := []struct {
+		name  string
+		p     Principal
+		allow []Permission
+	}{
+		{name: "viewer", p: Principal{Roles: []Role{Viewer}}, allow: []Permission{PermRead}},
+		{name: "analyst", p: Principal{Roles: []Role{Analyst}}, allow: []Permission{PermRead, PermTrigger}},
+		{name: "approver", p: Principal{Roles: []Role{Approver}}, allow: []Permission{PermRead, PermApprove}},
+		{
+			name:  "executor-admin",
+			p:     Principal{Roles: []Role{ExecutorAdmin}},
+			allow: []Permission{PermRead, PermApprove, PermTrigger},
+		},
+		{name: "tenant-admin", p: Principal{Roles: []Role{TenantAdmin}}, allow: all},
+		{name: "roles combine", p: Principal{Roles: []Role{Viewer, Approver}}, allow: []Permission{PermRead, PermApprove}},
+		{name: "no roles", p: Principal{}, allow: nil},
+		{name: "admin", p: Principal{Admin: true}, allow: all},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, perm := range all {
+				if got, want := tt.p.Can(perm), slices.Contains(tt.allow, perm); got != want {
+					t.Errorf("Can(%s) = %v, want %v", perm, got, want)
+				}
+			}
+		})
+	}
+}
+
+type recordingAuditor struct{ denials []Denial }
+
+func (a *recordingAuditor) RecordDenial(_ context.Context, d Denial) {
+	a.denials = append(a.denials, d)
+}
+
+func TestCheck(t *testing.T) {
+	a := &recordingAuditor{}
+	viewer := Principal{Subject: "alice", TenantID: "acme", Roles: []Role{Viewer}}
+
+	if err := Check(context.Background(), a, viewer, PermRead, "api", "list_workflows"); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(a.denials) != 0 {
+		t.Fatalf("allowed call audited: %v", a.denials)
+	}
+
+	err := Check(context.Background(), a, viewer, PermApprove, "mcp", "approve_actions")
+	if !errors.Is(err, ErrDenied) {
+		t.Fatalf("approve: err = %v, want ErrDenied", err)
+	}
+	if len(a.denials) != 1 {
+		t.Fatalf("denials = %d, want 1", len(a.denials))
+	}
+	d := a.denials[0]
+	if d.Surface != "mcp" || d.Operation != "approve_actions" || d.Permission != PermApprove || d.Subject != "alice" || d.TenantID != "acme" {
+		t.Errorf("denial = %+v", d)
+	}
+	if d.Time.IsZero() {
+		t.Error("denial not timestamped")
+	}
+}
+
+func TestPrincipalActingAs(t *testing.T) {
+	p := Principal{Subject: "u-123", Email: "lead@example.com"}
+	tests := []struct {
+		name    string
+		p       Principal
+		claimed string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty claim uses subject", p: p, want: "u-123"},
+		{name: "subject", p: p, claimed: "u-123", want: "u-123"},
+		{name: "email any case", p: p, claimed: "Lead@Example.com", want: "u-123"},
+		{name: "someone else", p: p, claimed: "cfo@example.com", wantErr: true},
+		{name: "no email to match", p: Principal{Subject: "u-123"}, claimed: "", want: "u-123"},
+		{name: "no subject", p: Principal{}, claimed: "anyone", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.ActingAs(tt.claimed)
+			if tt.wantErr {
+				if !errors.Is(err, ErrIdentityMismatch) {
+					t.Fatalf("ActingAs(%q) err = %v, want ErrIdentityMismatch", tt.claimed, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ActingAs(%q) = %q, %v, want %q", tt.claimed, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package savings
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// WriteEntriesCSV writes one row per entry.
+func WriteEntriesCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"date", "tenant_id", "team", "account_id", "service", "action_type", "workflow_id", "action_id",
+		"status", "estimated_monthly", "realized_monthly", "executed_at", "verified_at",
+	})
+	for _, e := range entries {
+		_ = cw.Write([]string{
+			e.Date(), e.TenantID, e.Team, e.AccountID, e.Service, e.ActionType, e.WorkflowID, e.ActionID,
+			string(e.Status), money(e.EstimatedMonthly), money(e.RealizedMonthly), e.ExecutedAt, e.VerifiedAt,
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("savings: write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteGroupsCSV writes one row per group, with a column per dimension.
+func WriteGroupsCSV(w io.Writer, dims []Dimension, groups []Group) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, 0, len(dims)+6)
+	for _, d := range dims {
+		header = append(header, string(d))
+	}
+	header = append(header, "actions", "executed", "verified",
+		"estimated_monthly", "executed_estimated_monthly", "realized_monthly")
+	_ = cw.Write(header)
+	for _, g := range groups {
+		row := make([]string, 0, len(header))
+		for _, d := range dims {
+			row = append(row, g.Key[d])
+		}
+		row = append(row, strconv.Itoa(g.Actions), strconv.Itoa(g.Executed), strconv.Itoa(g.Verified),
+			money(g.EstimatedMonthly), money(g.ExecutedEstimatedMonthly), money(g.RealizedMonthly))
+		_ = cw.Write(row)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("savings: write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package savings
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// A FileStore compacts its file once it holds at least compactMinLines
+// lines and more than compactRatio lines per entry.
+const (
+	compactMinLines = 1000
+	compactRatio    = 2
+)
+
+// FileStore is an embedded Repository backed by an append-only JSON-lines
+// file. Each Upsert appends the entry's new version and the latest line
+// for an ID wins. Upserts hold an exclusive lock on the file, so stores in
+// several processes can share it, and once superseded lines dominate the
+// file the writer rewrites it with only the latest line per entry. Reads
+// pick up what was appended since the last one, or reread the file after
+// another store compacted it.
+type FileStore struct {
+	path string
+
+	mu sync.Mutex
+	// latest holds the entries read so far; file, offset and lines identify
+	// the file they were read from, how much of it was read and how many
+	// lines that was.
+	latest map[string]Entry
+	file   os.FileInfo
+	offset int64
+	lines  int
+}
+
+// OpenFileStore opens (creating if needed) the ledger file at path.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("savings: create ledger dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("savings: open ledger %s: %w", path, err)
+	}
+	_ = f.Close()
+	return &FileStore{path: path, latest: make(map[string]Entry)}, nil
+}
+
+// Upsert implements Repository.
+func (s *FileStore) Upsert(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.openLocked()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	defer unlockFile(f)
+
+	partial, err := s.advance(f)
+	if err != nil {
+		return err
+	}
+	if prev, ok := s.latest[e.ID]; ok && prev.PlannedAt != "" {
+		e.PlannedAt = prev.PlannedAt
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("savings: encode entry %s: %w", e.ID, err)
+	}
+	buf := append(line, '\n')
+	if partial {
+		// Close off a line left partial by a crashed writer, so it stays
+		// the only corrupt line.
+		buf = append([]byte{'\n'}, buf...)
+	}
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("savings: append entry %s: %w", e.ID, err)
+	}
+	if _, err := s.advance(f); err != nil {
+		return err
+	}
+
+	if s.lines >= compactMinLines && s.lines > compactRatio*len(s.latest) {
+		// The entry is recorded either way; a failed compaction is retried
+		// on the next Upsert.
+		if err := s.compact(); err != nil {
+			slog.Warn("savings: ledger compaction failed", "path", s.path, "error", err)
+		}
+	}
+	return nil
+}
+
+// openLocked opens the ledger file for appending and locks it. A store in
+// another process may compact, and so replace, the file while this one
+// waits for the lock, so it retries until the locked file is the one at
+// path.
+func (s *FileStore) openLocked() (*os.File, error) {
+	for {
+		f, err := os.OpenFile(s.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("savings: open ledger %s: %w", s.path, err)
+		}
+		if err := lockFile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("savings: lock ledger %s: %w", s.path, err)
+		}
+		held, err := f.Stat()
+		if err != nil {
+			unlockFile(f)
+			_ = f.Close()
+			return nil, fmt.Errorf("savings: stat ledger %s: %w", s.path, err)
+		}
+		if cur, err := os.Stat(s.path); err == nil && os.SameFile(held, cur) {
+			return f, nil
+		}
+		unlockFile(f)
+		_ = f.Close()
+	}
+}
+
+// List implements Repository.
+func (s *FileStore) List(filter Filter) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		s.reset()
+	case err != nil:
+		return nil, fmt.Errorf("savings: open ledger %s: %w", s.path, err)
+	default:
+		_, err = s.advance(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Entry, 0, len(s.latest))
+	for _, e := range s.latest {
+		if filter.Match(e) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if di, dj := out[i].Date(), out[j].Date(); di != dj {
+			return di < dj
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+// advance reads the complete lines appended to f since the last call,
+// starting over if f is not the file read before. partial reports a
+// trailing line without a newline, which is left unread: without the file
+// lock it may be an append still in progress. Lines that do not decode,
+// such as one truncated by a crash mid-append, are skipped. Callers hold
+// mu.
+func (s *FileStore) advance(f *os.File) (partial bool, err error) {
+	st, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("savings: stat ledger %s: %w", s.path, err)
+	}
+	if s.file == nil || !os.SameFile(s.file, st) || st.Size() < s.offset {
+		// Compacted, truncated or read for the first time.
+		s.reset()
+	}
+	s.file = st
+	if st.Size() == s.offset {
+		return false, nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(f, s.offset, st.Size()-s.offset))
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			return len(line) > 0, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("savings: read ledger %s: %w", s.path, err)
+		}
+		s.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		s.lines++
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			slog.Warn("savings: skipping corrupt ledger line", "path", s.path, "offset", s.offset, "error", err)
+			continue
+		}
+		s.latest[e.ID] = e
+	}
+}
+
+// reset forgets what was read, so the next advance rereads the file.
+func (s *FileStore) reset() {
+	clear(s.latest)
+	s.file = nil
+	s.offset = 0
+	s.lines = 0
+}
+
+// compact replaces the file with one holding only the latest line per
+// entry. The new file is synced before it is renamed over the old one, so
+// a crash leaves one or the other in full. Callers hold mu and the lock on
+// the current file, which keeps other writers out until they reopen the
+// path.
+func (s *FileStore) compact() error {
+	ids := make([]string, 0, len(s.latest))
+	for id := range s.latest {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var buf bytes.Buffer
+	for _, id := range ids {
+		line, err := json.Marshal(s.latest[id])
+		if err != nil {
+			return fmt.Errorf("savings: encode entry %s: %w", id, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	// Take the new file's identity from the handle: once renamed, other
+	// stores may append to it before this one looks at the path again.
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("savings: compact %s: %w", s.path, err)
+	}
+	s.file = st
+	s.offset = int64(buf.Len())
+	s.lines = len(ids)
+	return syncDir(filepath.Dir(s.path))
+}
+
+// syncDir flushes a directory entry change, such as a rename, to disk.
+// Filesystems that cannot sync a directory are not an error.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("savings: sync %s: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) && !errors.Is(err, syscall.EINVAL) {
+		return fmt.Errorf("savings: sync %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package savings
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "ledger", "savings.jsonl")
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+
+	planned := Entry{ID: "wf-1/a", TenantID: "acme", Status: StatusExecuted, PlannedAt: "2026-03-01T10:00:00Z", ExecutedAt: "2026-03-02T22:00:00Z"}
+	other := Entry{ID: "wf-2/a", TenantID: "globex", Status: StatusNotExecuted, PlannedAt: "2026-03-01T09:00:00Z"}
+	verified := planned
+	verified.Status = StatusVerified
+	verified.RealizedMonthly = 120
+	verified.PlannedAt = "2026-03-09T22:00:00Z"
+	for _, e := range []Entry{planned, other, verified} {
+		if err := s.Upsert(e); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+	}
+
+	// A second store on the same file, as another process would open it.
+	reader, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	all, err := reader.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "wf-2/a" || all[1].ID != "wf-1/a" {
+		t.Fatalf("List = %+v", all)
+	}
+	if all[1].Status != StatusVerified || all[1].RealizedMonthly != 120 {
+		t.Errorf("latest version not returned: %+v", all[1])
+	}
+	if all[1].PlannedAt != "2026-03-01T10:00:00Z" {
+		t.Errorf("PlannedAt = %q, want original", all[1].PlannedAt)
+	}
+
+	acme, err := reader.List(Filter{TenantID: "acme"})
+	if err != nil || len(acme) != 1 {
+		t.Errorf("List(acme) = %+v, %v", acme, err)
+	}
+}
+
+func TestFileStore_SkipsTruncatedLine(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "savings.jsonl")
+	if err := os.WriteFile(path, []byte(`{"id":"wf-1/a","tenant_id":"acme"}`+"\n"+`{"id":"wf-1/b","ten`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	if err := s.Upsert(Entry{ID: "wf-1/c", TenantID: "acme"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	got, err := s.List(Filter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "wf-1/a" || got[1].ID != "wf-1/c" {
+		t.Errorf("List = %+v", got)
+	}
+}
+
+func TestFileStore_Compacts(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "savings.jsonl")
+	a, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	b, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+
+	if err := b.Upsert(Entry{ID: "wf-1/a", TenantID: "acme", Status: StatusNotExecuted, PlannedAt: "2026-03-01T10:00:00Z"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if _, err := b.List(Filter{}); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	// Repeated updates of two entries push a past the compaction threshold.
+	for i := 0; i < compactMinLines; i++ {
+		id := []string{"wf-1/a", "wf-1/b"}[i%2]
+		if err := a.Upsert(Entry{ID: id, TenantID: "acme", Status: StatusExecuted, RealizedMonthly: float64(i), PlannedAt: "2026-03-05T10:00:00Z"}); err != nil {
+			t.Fatalf("Upsert %d: %v", i, err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n >= compactMinLines {
+		t.Fatalf("file has %d lines after %d upserts, want it compacted", n, compactMinLines+1)
+	}
+
+	// b read the file before it was replaced, and appends to the new one.
+	if err := b.Upsert(Entry{ID: "wf-2/a", TenantID: "globex", Status: StatusNotExecuted, PlannedAt: "2026-03-06T10:00:00Z"}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	for name, s := range map[string]*FileStore{"a": a, "b": b} {
+		got, err := s.List(Filter{})
+		if err != nil {
+			t.Fatalf("%s.List: %v", name, err)
+		}
+		if len(got) != 3 || got[0].ID != "wf-1/a" || got[1].ID != "wf-1/b" || got[2].ID != "wf-2/a" {
+			t.Fatalf("%s.List = %+v", name, got)
+		}
+		if got[0].PlannedAt != "2026-03-01T10:00:00Z" || got[0].RealizedMonthly != compactMinLines-2 {
+			t.Errorf("%s: wf-1/a = %+v, want the latest version with the original PlannedAt", name, got[0])
+		}
+		if got[1].RealizedMonthly != compactMinLines-1 {
+			t.Errorf("%s: wf-1/b = %+v, want the latest version", name, got[1])
+		}
+	}
+}
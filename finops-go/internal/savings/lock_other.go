@@ -0,0 +1,11 @@
+//go:build !unix
+
+package savings
+
+import "os"
+
+// Without flock, ledger appends and compactions are only serialized within
+// one process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) {}
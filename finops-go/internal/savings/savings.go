@@ -0,0 +1,307 @@
+// Package savings is the savings ledger: per-action records of estimated
+// savings at plan time, what was executed, and the savings realized as
+// verification confirms them.
+package savings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// DaysPerMonth converts daily savings to a monthly run rate. It matches
+// verifier.DaysPerMonth.
+const DaysPerMonth = 30.4
+
+// Status is where an action stands in the ledger.
+type Status string
+
+const (
+	StatusNotExecuted Status = "not_executed"
+	StatusExecuted    Status = "executed"
+	StatusRolledBack  Status = "rolled_back"
+	StatusVerified    Status = "verified"
+)
+
+// Entry is one action's ledger record. RealizedMonthly is the action's
+// share of the latest significant savings measurement, as a monthly run
+// rate; it counts towards realized totals once Status is verified.
+type Entry struct {
+	ID         string `json:"id"`
+	WorkflowID string `json:"workflow_id"`
+	ActionID   string `json:"action_id"`
+
+	TenantID   string `json:"tenant_id"`
+	Team       string `json:"team,omitempty"`
+	AccountID  string `json:"account_id"`
+	Service    string `json:"service"`
+	ActionType string `json:"action_type"`
+
+	Status           Status  `json:"status"`
+	EstimatedMonthly float64 `json:"estimated_monthly"`
+	RealizedMonthly  float64 `json:"realized_monthly"`
+
+	PlannedAt  string `json:"planned_at"`
+	ExecutedAt string `json:"executed_at,omitempty"`
+	VerifiedAt string `json:"verified_at,omitempty"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// EntryID keys an entry by its owning workflow and action.
+func EntryID(workflowID, actionID string) string {
+	return workflowID + "/" + actionID
+}
+
+// Date is the day (YYYY-MM-DD) the entry is reported under: its execution
+// day, or its plan day if it never executed.
+func (e Entry) Date() string {
+	if e.ExecutedAt != "" {
+		return day(e.ExecutedAt)
+	}
+	return day(e.PlannedAt)
+}
+
+func day(ts string) string {
+	if len(ts) >= 10 {
+		return ts[:10]
+	}
+	return ts
+}
+
+// Repository stores ledger entries. Implementations keep the latest
+// version of each entry by ID.
+type Repository interface {
+	// Upsert records e, replacing any entry with the same ID. PlannedAt
+	// of an existing entry is preserved.
+	Upsert(e Entry) error
+	// List returns the entries matching f, ordered by date then ID.
+	List(f Filter) ([]Entry, error)
+}
+
+// Filter selects ledger entries. Empty fields match everything; From and
+// To are inclusive YYYY-MM-DD bounds on Entry.Date.
+type Filter struct {
+	TenantID   string
+	Team       string
+	AccountID  string
+	Service    string
+	ActionType string
+	From       string
+	To         string
+}
+
+// Match reports whether e satisfies f.
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.TenantID != "" && e.TenantID != f.TenantID,
+		f.Team != "" && e.Team != f.Team,
+		f.AccountID != "" && e.AccountID != f.AccountID,
+		f.Service != "" && e.Service != f.Service,
+		f.ActionType != "" && e.ActionType != f.ActionType:
+		return false
+	}
+	d := e.Date()
+	if f.From != "" && d < f.From {
+		return false
+	}
+	if f.To != "" && d > f.To {
+		return false
+	}
+	return true
+}
+
+// Record describes one lifecycle's actions for the ledger.
+type Record struct {
+	WorkflowID string
+	Tenant     string
+	Anomaly    domain.CostAnomaly
+	Actions    []domain.RecommendedAction
+	Executions []domain.ExecutionResult
+	// Check is the latest savings verification, if any.
+	Check *domain.SavingsVerification
+	// At is the recording time (RFC3339).
+	At string
+}
+
+// Entries builds one entry per planned action. Significant savings from
+// Check are shared across the applied actions in proportion to their
+// estimates, or evenly when none has an estimate.
+func Entries(r Record) []Entry {
+	results := make(map[string]domain.ExecutionResult, len(r.Executions))
+	for _, res := range r.Executions {
+		results[res.ActionID] = res
+	}
+
+	var appliedEstimate float64
+	applied := 0
+	for _, a := range r.Actions {
+		if res, ok := results[a.ActionID]; ok && res.Success && res.Outcome != domain.OutcomeRolledBack {
+			appliedEstimate += a.EstimatedSavingsMonthly
+			applied++
+		}
+	}
+	var realized float64
+	if r.Check != nil && r.Check.Significant && r.Check.SavingsDaily > 0 {
+		realized = r.Check.SavingsDaily * DaysPerMonth
+	}
+
+	entries := make([]Entry, 0, len(r.Actions))
+	for _, a := range r.Actions {
+		e := Entry{
+			ID:               EntryID(r.WorkflowID, a.ActionID),
+			WorkflowID:       r.WorkflowID,
+			ActionID:         a.ActionID,
+			TenantID:         r.Tenant,
+			Team:             r.Anomaly.Team,
+			AccountID:        r.Anomaly.AccountID,
+			Service:          r.Anomaly.Service,
+			ActionType:       a.ActionType,
+			Status:           StatusNotExecuted,
+			EstimatedMonthly: a.EstimatedSavingsMonthly,
+			PlannedAt:        r.At,
+			UpdatedAt:        r.At,
+		}
+		res, ok := results[a.ActionID]
+		switch {
+		case !ok || !res.Success:
+		case res.Outcome == domain.OutcomeRolledBack:
+			e.Status = StatusRolledBack
+			e.ExecutedAt = res.ExecutedAt
+		default:
+			e.Status = StatusExecuted
+			e.ExecutedAt = res.ExecutedAt
+			if realized > 0 {
+				share := 1 / float64(applied)
+				if appliedEstimate > 0 {
+					share = a.EstimatedSavingsMonthly / appliedEstimate
+				}
+				e.RealizedMonthly = realized * share
+			}
+			if r.Check != nil && r.Check.Recommendation == domain.RecommendClose {
+				e.Status = StatusVerified
+				e.VerifiedAt = r.Check.CheckedAt
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// Dimension is a grouping key for Summarize.
+type Dimension string
+
+const (
+	DimTenant     Dimension = "tenant"
+	DimTeam       Dimension = "team"
+	DimAccount    Dimension = "account"
+	DimService    Dimension = "service"
+	DimActionType Dimension = "action_type"
+	DimMonth      Dimension = "month"
+)
+
+// ParseDimensions parses a comma-separated group_by list.
+func ParseDimensions(s string) ([]Dimension, error) {
+	var dims []Dimension
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		d := Dimension(p)
+		switch d {
+		case DimTenant, DimTeam, DimAccount, DimService, DimActionType, DimMonth:
+			dims = append(dims, d)
+		default:
+			return nil, fmt.Errorf("savings: unknown group_by dimension %q", p)
+		}
+	}
+	return dims, nil
+}
+
+func (d Dimension) value(e Entry) string {
+	switch d {
+	case DimTenant:
+		return e.TenantID
+	case DimTeam:
+		return e.Team
+	case DimAccount:
+		return e.AccountID
+	case DimService:
+		return e.Service
+	case DimActionType:
+		return e.ActionType
+	case DimMonth:
+		if d := e.Date(); len(d) >= 7 {
+			return d[:7]
+		}
+	}
+	return ""
+}
+
+// Totals aggregates entries. EstimatedMonthly covers every planned action,
+// ExecutedEstimatedMonthly only those executed and not rolled back, and
+// RealizedMonthly only verified ones.
+type Totals struct {
+	Actions                  int     `json:"actions"`
+	Executed                 int     `json:"executed"`
+	Verified                 int     `json:"verified"`
+	EstimatedMonthly         float64 `json:"estimated_monthly"`
+	ExecutedEstimatedMonthly float64 `json:"executed_estimated_monthly"`
+	RealizedMonthly          float64 `json:"realized_monthly"`
+}
+
+func (t *Totals) add(e Entry) {
+	t.Actions++
+	t.EstimatedMonthly += e.EstimatedMonthly
+	if e.Status == StatusExecuted || e.Status == StatusVerified {
+		t.Executed++
+		t.ExecutedEstimatedMonthly += e.EstimatedMonthly
+	}
+	if e.Status == StatusVerified {
+		t.Verified++
+		t.RealizedMonthly += e.RealizedMonthly
+	}
+}
+
+// Group is the totals for one combination of dimension values.
+type Group struct {
+	Key map[Dimension]string `json:"key"`
+	Totals
+}
+
+// Summarize totals entries by the given dimensions, ordered by key.
+func Summarize(entries []Entry, dims []Dimension) ([]Group, Totals) {
+	var total Totals
+	index := make(map[string]int)
+	var groups []Group
+	for _, e := range entries {
+		total.add(e)
+		vals := make([]string, len(dims))
+		for i, d := range dims {
+			vals[i] = d.value(e)
+		}
+		k := strings.Join(vals, "\x00")
+		i, ok := index[k]
+		if !ok {
+			key := make(map[Dimension]string, len(dims))
+			for j, d := range dims {
+				key[d] = vals[j]
+			}
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Group{Key: key})
+		}
+		groups[i].add(e)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		for _, d := range dims {
+			if a, b := groups[i].Key[d], groups[j].Key[d]; a != b {
+				return a < b
+			}
+		}
+		return false
+	})
+	return groups, total
+}
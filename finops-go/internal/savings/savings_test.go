@@ -0,0 +1,148 @@
+package savings
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func testRecord() Record {
+	return Record{
+		WorkflowID: "wf-1",
+		Tenant:     "acme",
+		Anomaly:    domain.CostAnomaly{Service: "EC2", AccountID: "123456789012", Team: "platform"},
+		Actions: []domain.RecommendedAction{
+			{ActionID: "a", ActionType: "delete_volume", EstimatedSavingsMonthly: 300},
+			{ActionID: "b", ActionType: "rightsize", EstimatedSavingsMonthly: 100},
+			{ActionID: "c", ActionType: "rightsize", EstimatedSavingsMonthly: 50},
+			{ActionID: "d", ActionType: "rightsize", EstimatedSavingsMonthly: 40},
+		},
+		Executions: []domain.ExecutionResult{
+			{ActionID: "a", Success: true, Outcome: domain.OutcomeSucceeded, ExecutedAt: "2026-03-02T22:05:00Z"},
+			{ActionID: "b", Success: true, Outcome: domain.OutcomeSucceeded, ExecutedAt: "2026-03-02T22:06:00Z"},
+			{ActionID: "c", Success: true, Outcome: domain.OutcomeRolledBack, ExecutedAt: "2026-03-02T22:07:00Z"},
+		},
+		At: "2026-03-01T10:00:00Z",
+	}
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+	r := testRecord()
+	r.Check = &domain.SavingsVerification{
+		CheckedAt: "2026-03-09T22:00:00Z", SavingsDaily: 10, Significant: true, Recommendation: domain.RecommendClose,
+	}
+
+	got := Entries(r)
+	if len(got) != 4 {
+		t.Fatalf("got %d entries, want 4", len(got))
+	}
+	want := []struct {
+		status   Status
+		realized float64
+	}{
+		{StatusVerified, 304 * 0.75},
+		{StatusVerified, 304 * 0.25},
+		{StatusRolledBack, 0},
+		{StatusNotExecuted, 0},
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Status != w.status {
+			t.Errorf("%s: status = %q, want %q", e.ActionID, e.Status, w.status)
+		}
+		if math.Abs(e.RealizedMonthly-w.realized) > 1e-9 {
+			t.Errorf("%s: realized = %v, want %v", e.ActionID, e.RealizedMonthly, w.realized)
+		}
+	}
+	if got[0].ID != "wf-1/a" || got[0].Team != "platform" || got[0].VerifiedAt != "2026-03-09T22:00:00Z" {
+		t.Errorf("entry a = %+v", got[0])
+	}
+	if got[3].Date() != "2026-03-01" || got[0].Date() != "2026-03-02" {
+		t.Errorf("dates = %s, %s", got[0].Date(), got[3].Date())
+	}
+}
+
+func TestEntries_UnconfirmedSavings(t *testing.T) {
+	t.Parallel()
+	r := testRecord()
+	r.Check = &domain.SavingsVerification{SavingsDaily: 8, CILow: -1, Recommendation: domain.RecommendMonitor}
+	for _, e := range Entries(r)[:2] {
+		if e.Status != StatusExecuted || e.RealizedMonthly != 0 {
+			t.Errorf("%s: status %q realized %v, want executed with nothing realized", e.ActionID, e.Status, e.RealizedMonthly)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+	e := Entry{TenantID: "acme", Service: "EC2", Team: "platform", ExecutedAt: "2026-03-02T22:05:00Z"}
+	tests := []struct {
+		name string
+		f    Filter
+		want bool
+	}{
+		{"empty", Filter{}, true},
+		{"tenant", Filter{TenantID: "acme"}, true},
+		{"other tenant", Filter{TenantID: "globex"}, false},
+		{"team and service", Filter{Team: "platform", Service: "EC2"}, true},
+		{"inclusive range", Filter{From: "2026-03-02", To: "2026-03-02"}, true},
+		{"before range", Filter{From: "2026-03-03"}, false},
+		{"after range", Filter{To: "2026-03-01"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	r := testRecord()
+	r.Check = &domain.SavingsVerification{SavingsDaily: 10, Significant: true, Recommendation: domain.RecommendClose}
+	entries := Entries(r)
+
+	dims, err := ParseDimensions("action_type, month")
+	if err != nil {
+		t.Fatalf("ParseDimensions: %v", err)
+	}
+	groups, total := Summarize(entries, dims)
+	// Executed and plan-day-only rightsizes fall in the same month.
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", groups)
+	}
+	if groups[0].Key[DimActionType] != "delete_volume" || groups[0].Verified != 1 {
+		t.Errorf("group 0 = %+v", groups[0])
+	}
+	if g := groups[1]; g.Key[DimMonth] != "2026-03" || g.Actions != 3 || g.Executed != 1 || math.Abs(g.RealizedMonthly-76) > 1e-9 {
+		t.Errorf("group 1 = %+v", g)
+	}
+	if total.Actions != 4 || total.Executed != 2 || total.Verified != 2 {
+		t.Errorf("total counts = %+v", total)
+	}
+	if math.Abs(total.EstimatedMonthly-490) > 1e-9 || math.Abs(total.ExecutedEstimatedMonthly-400) > 1e-9 ||
+		math.Abs(total.RealizedMonthly-304) > 1e-9 {
+		t.Errorf("total dollars = %+v", total)
+	}
+
+	if _, err := ParseDimensions("service,region"); err == nil {
+		t.Error("expected error for unknown dimension")
+	}
+}
+
+func TestWriteGroupsCSV(t *testing.T) {
+	t.Parallel()
+	groups := []Group{{Key: map[Dimension]string{DimService: "EC2"}, Totals: Totals{Actions: 2, Verified: 1, RealizedMonthly: 12.5}}}
+	var buf bytes.Buffer
+	if err := WriteGroupsCSV(&buf, []Dimension{DimService}, groups); err != nil {
+		t.Fatalf("WriteGroupsCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "service,actions,") || lines[1] != "EC2,2,0,1,0.00,0.00,12.50" {
+		t.Errorf("csv = %q", buf.String())
+	}
+}
@@ -46,7 +46,7 @@ func (r *GoRunner) Run(ctx context.Context, service string, delta float64) ([]by
 	}
 
 	analysisResult, err := analysis.AnalyzeAndRecommend(
-		anomaly.AccountID, anomaly.Service, "2026-02-01", "2026-02-16", cost,
+		ctx, anomaly.AccountID, anomaly.Service, "2026-02-01", "2026-02-16", cost,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("analysis: %w", err)
@@ -2,12 +2,24 @@ package activities
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 
 	"github.com/finops-claw-gang/finops-go/internal/analysis"
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/calendar"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/executor"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
 	"github.com/finops-claw-gang/finops-go/internal/triage"
 	"github.com/finops-claw-gang/finops-go/internal/verifier"
 )
@@ -25,6 +37,7 @@ type CostDeps interface {
 type InfraDeps interface {
 	triage.InfraQuerier
 	executor.TagFetcher
+	verifier.HealthChecker
 }
 
 // AWSDocDeps provides aws-doctor waste query capability to activities.
@@ -51,17 +64,45 @@ type Activities struct {
 	Executor *executor.Executor
 	Tenants  TenantDeps                // nil in stub mode
 	Budget   *ratelimit.ActivityBudget // nil = no budget enforcement
+	Calendar *calendar.Calendar        // nil = changes allowed at any time
+	Metrics  *observability.Metrics    // nil = no metrics
+	Savings  savings.Repository        // nil = no savings ledger
+	History  history.Repository        // nil = no anomaly history
+	Audit    audit.Logger              // nil = no audit trail
+	// CostBudget is the tenants' monthly budget for the desk's own AWS API
+	// spend, which the apicost.Meter on the AWS clients charges to it.
+	CostBudget *ratelimit.CostBudget // nil = tooling spend not limited
 }
 
-// checkBudget enforces per-tenant activity budgets when configured.
+// ErrTypeBudgetExceeded is the application error type of activities
+// refused by the tenant's activity or tooling cost budget.
+const ErrTypeBudgetExceeded = apperr.TypeBudgetExceeded
+
+// checkBudget enforces per-tenant activity budgets when configured. A
+// refusal is an application error of type ErrTypeBudgetExceeded, so the
+// reason survives the trip through Temporal.
 func (a *Activities) checkBudget(tenantID, activityName string) error {
 	if a.Budget == nil {
 		return nil
 	}
-	if err := a.Budget.Check(tenantID, activityName); err != nil {
-		return err
+	if err := a.Budget.Reserve(tenantID, activityName); err != nil {
+		return temporal.NewApplicationErrorWithCause(err.Error(), ErrTypeBudgetExceeded, err)
+	}
+	return nil
+}
+
+// checkCostBudget refuses new work for a tenant that has spent its monthly
+// tooling budget. The refusal is not retryable: the budget only frees up
+// when the month turns. Only activities that start work check it;
+// execution, verification and rollback of already approved changes run
+// regardless.
+func (a *Activities) checkCostBudget(tenantID string) error {
+	if a.CostBudget == nil {
+		return nil
+	}
+	if err := a.CostBudget.Check(tenantID); err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeBudgetExceeded, err)
 	}
-	a.Budget.Record(tenantID, activityName)
 	return nil
 }
 
@@ -86,6 +127,10 @@ func (a *Activities) TriageAnomaly(ctx context.Context, in TriageInput) (TriageO
 	if err := a.checkBudget(in.Tenant.TenantID, "TriageAnomaly"); err != nil {
 		return TriageOutput{}, err
 	}
+	if err := a.checkCostBudget(in.Tenant.TenantID); err != nil {
+		return TriageOutput{}, err
+	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
 	cost, err := a.resolveCost(ctx, in.Tenant)
 	if err != nil {
 		return TriageOutput{}, fmt.Errorf("triage activity: resolve cost: %w", err)
@@ -98,6 +143,9 @@ func (a *Activities) TriageAnomaly(ctx context.Context, in TriageInput) (TriageO
 	if err != nil {
 		return TriageOutput{}, fmt.Errorf("triage activity: %w", err)
 	}
+	if a.Metrics != nil {
+		a.Metrics.RecordAnomalyProcessed(ctx, string(result.Category), string(result.Severity))
+	}
 	return TriageOutput{Result: result}, nil
 }
 
@@ -106,23 +154,30 @@ func (a *Activities) PlanActions(ctx context.Context, in PlanActionsInput) (Plan
 	if err := a.checkBudget(in.Tenant.TenantID, "PlanActions"); err != nil {
 		return PlanActionsOutput{}, err
 	}
+	if err := a.checkCostBudget(in.Tenant.TenantID); err != nil {
+		return PlanActionsOutput{}, err
+	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
 	cost, err := a.resolveCost(ctx, in.Tenant)
 	if err != nil {
 		return PlanActionsOutput{}, fmt.Errorf("plan actions activity: resolve cost: %w", err)
 	}
-	result, err := analysis.AnalyzeAndRecommend(in.AccountID, in.Service, in.WindowStart, in.WindowEnd, cost)
+	result, err := analysis.AnalyzeAndRecommend(ctx, in.AccountID, in.Service, in.WindowStart, in.WindowEnd, cost)
 	if err != nil {
 		return PlanActionsOutput{}, fmt.Errorf("plan actions activity: %w", err)
 	}
 	return PlanActionsOutput{Result: result}, nil
 }
 
-// ExecuteActions gathers resource tags and runs the executor.
+// ExecuteActions gathers resource tags and runs the executor over every
+// action, each through the same safety gate as ExecuteAction.
 // Tags are fetched inside the activity boundary (I/O belongs here, not in the workflow).
+// Retained for workflow histories recorded before per-action execution.
 func (a *Activities) ExecuteActions(ctx context.Context, in ExecuteActionsInput) (ExecuteActionsOutput, error) {
 	if err := a.checkBudget(in.Tenant.TenantID, "ExecuteActions"); err != nil {
 		return ExecuteActionsOutput{}, err
 	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
 	infra, err := a.resolveInfra(ctx, in.Tenant)
 	if err != nil {
 		return ExecuteActionsOutput{}, fmt.Errorf("execute activity: resolve infra: %w", err)
@@ -133,36 +188,292 @@ func (a *Activities) ExecuteActions(ctx context.Context, in ExecuteActionsInput)
 		if action.TargetResource == "" {
 			continue
 		}
-		tags, err := infra.ResourceTags(action.TargetResource)
+		tags, err := infra.ResourceTags(ctx, action.TargetResource)
 		if err != nil {
 			return ExecuteActionsOutput{}, fmt.Errorf("execute activity: fetch tags for %s: %w", action.TargetResource, err)
 		}
 		tagsByARN[action.TargetResource] = tags
 	}
 
-	results, err := a.Executor.ExecuteActions(in.Approval, in.Actions, tagsByARN)
+	var workflowID string
+	if activity.IsActivity(ctx) {
+		workflowID = activity.GetInfo(ctx).WorkflowExecution.ID
+	}
+	results, err := a.Executor.ExecuteActions(ctx, workflowID, in.Tenant.TenantID, in.Approval, in.Actions, tagsByARN)
+	if errors.Is(err, executor.ErrRefused) {
+		return ExecuteActionsOutput{}, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("execute activity: %v", err), "ExecutorRefused", err)
+	}
 	if err != nil {
 		return ExecuteActionsOutput{}, fmt.Errorf("execute activity: %w", err)
 	}
 	return ExecuteActionsOutput{Results: results}, nil
 }
 
-// VerifyOutcome checks service health and observed cost reduction.
+// ExecuteAction runs a single approved action. It is safe to retry: the
+// executor deduplicates on the idempotency key and checks current state
+// before acting. Safety-gate refusals are returned as non-retryable errors.
+func (a *Activities) ExecuteAction(ctx context.Context, in ExecuteActionInput) (ExecuteActionOutput, error) {
+	if err := a.checkBudget(in.Tenant.TenantID, "ExecuteAction"); err != nil {
+		return ExecuteActionOutput{}, err
+	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
+	infra, err := a.resolveInfra(ctx, in.Tenant)
+	if err != nil {
+		return ExecuteActionOutput{}, fmt.Errorf("execute action activity: resolve infra: %w", err)
+	}
+
+	var tags map[string]string
+	if in.Action.TargetResource != "" {
+		tags, err = infra.ResourceTags(ctx, in.Action.TargetResource)
+		if err != nil {
+			return ExecuteActionOutput{}, fmt.Errorf("execute action activity: fetch tags for %s: %w", in.Action.TargetResource, err)
+		}
+	}
+
+	result, err := a.Executor.ExecuteAction(ctx, executor.ExecuteRequest{
+		IdempotencyKey: in.IdempotencyKey,
+		TenantID:       in.Tenant.TenantID,
+		Approval:       in.Approval,
+		Action:         in.Action,
+		Plan:           in.Plan,
+		ResourceTags:   tags,
+	})
+	a.auditAction(ctx, in.Tenant.TenantID, audit.ActionExecuted, in.Action, in.IdempotencyKey, result.Success, err)
+	if errors.Is(err, executor.ErrRefused) {
+		return ExecuteActionOutput{}, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("execute action activity: %v", err), "ExecutorRefused", err)
+	}
+	if err != nil {
+		return ExecuteActionOutput{}, fmt.Errorf("execute action activity: %w", err)
+	}
+	return ExecuteActionOutput{Result: result}, nil
+}
+
+// auditAction records an executor action in the audit trail. Each attempt
+// is recorded, so retries appear as separate events.
+func (a *Activities) auditAction(ctx context.Context, tenantID, action string, act domain.RecommendedAction, key string, success bool, err error) {
+	if a.Audit == nil {
+		return
+	}
+	e := audit.Event{
+		Actor:    audit.SystemActor,
+		Source:   audit.SourceActivity,
+		TenantID: tenantID,
+		Action:   action,
+		Resource: act.TargetResource,
+		Outcome:  "failed",
+		Details: map[string]string{
+			"action_id":       act.ActionID,
+			"action_type":     act.ActionType,
+			"idempotency_key": key,
+		},
+	}
+	switch {
+	case errors.Is(err, executor.ErrRefused):
+		e.Outcome, e.Reason = "refused", err.Error()
+	case err != nil:
+		e.Reason = err.Error()
+	case success:
+		e.Outcome = "succeeded"
+	}
+	if activity.IsActivity(ctx) {
+		e.WorkflowID = activity.GetInfo(ctx).WorkflowExecution.ID
+	}
+	audit.Record(ctx, a.Audit, e)
+}
+
+// NextChangeWindow finds the earliest time at which every action's target
+// may be changed under the change calendar. An empty At means no window
+// opens within the calendar's search horizon.
+func (a *Activities) NextChangeWindow(ctx context.Context, in NextChangeWindowInput) (NextChangeWindowOutput, error) {
+	infra, err := a.resolveInfra(ctx, in.Tenant)
+	if err != nil {
+		return NextChangeWindowOutput{}, fmt.Errorf("next change window activity: resolve infra: %w", err)
+	}
+
+	tagSets := make([]map[string]string, 0, len(in.Actions))
+	for _, act := range in.Actions {
+		if act.TargetResource == "" {
+			tagSets = append(tagSets, nil)
+			continue
+		}
+		tags, err := infra.ResourceTags(ctx, act.TargetResource)
+		if err != nil {
+			return NextChangeWindowOutput{}, fmt.Errorf("next change window activity: fetch tags for %s: %w", act.TargetResource, err)
+		}
+		tagSets = append(tagSets, tags)
+	}
+
+	next, err := a.Calendar.NextAllowedAll(time.Now().UTC(), in.Tenant.TenantID, tagSets)
+	if errors.Is(err, calendar.ErrNoWindow) {
+		return NextChangeWindowOutput{}, nil
+	}
+	if err != nil {
+		return NextChangeWindowOutput{}, fmt.Errorf("next change window activity: %w", err)
+	}
+	return NextChangeWindowOutput{At: next.UTC().Format(time.RFC3339)}, nil
+}
+
+// RollbackAction reverses a previously executed action.
+func (a *Activities) RollbackAction(ctx context.Context, in RollbackActionInput) (RollbackActionOutput, error) {
+	if err := a.checkBudget(in.Tenant.TenantID, "RollbackAction"); err != nil {
+		return RollbackActionOutput{}, err
+	}
+	result, err := a.Executor.RollbackAction(ctx, in.IdempotencyKey, in.Action, in.PreActionSnapshot)
+	a.auditAction(ctx, in.Tenant.TenantID, audit.ActionRolledBack, in.Action, in.IdempotencyKey, err == nil && result.Success, err)
+	if err != nil {
+		return RollbackActionOutput{}, fmt.Errorf("rollback action activity: %w", err)
+	}
+	return RollbackActionOutput{Result: result}, nil
+}
+
+// VerifyOutcome checks the health of the changed resources and observed
+// cost reduction.
 func (a *Activities) VerifyOutcome(ctx context.Context, in VerifyOutcomeInput) (VerifyOutcomeOutput, error) {
 	if err := a.checkBudget(in.Tenant.TenantID, "VerifyOutcome"); err != nil {
 		return VerifyOutcomeOutput{}, err
 	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
 	cost, err := a.resolveCost(ctx, in.Tenant)
 	if err != nil {
 		return VerifyOutcomeOutput{}, fmt.Errorf("verify activity: resolve cost: %w", err)
 	}
-	result, err := verifier.Verify(in.Service, in.AccountID, cost, in.WindowStart, in.WindowEnd)
+	infra, err := a.resolveInfra(ctx, in.Tenant)
+	if err != nil {
+		return VerifyOutcomeOutput{}, fmt.Errorf("verify activity: resolve infra: %w", err)
+	}
+	change := verifier.Change{Resources: in.Resources, ExecutedAt: time.Now().UTC()}
+	if in.ExecutedAt != "" {
+		t, err := time.Parse(time.RFC3339, in.ExecutedAt)
+		if err != nil {
+			return VerifyOutcomeOutput{}, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("verify activity: invalid executed_at %q", in.ExecutedAt), "InvalidInput", err)
+		}
+		change.ExecutedAt = t
+	}
+	if in.CheckedAt != "" {
+		t, err := time.Parse(time.RFC3339, in.CheckedAt)
+		if err != nil {
+			return VerifyOutcomeOutput{}, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("verify activity: invalid checked_at %q", in.CheckedAt), "InvalidInput", err)
+		}
+		change.CheckedAt = t
+	}
+	result, err := verifier.Verify(ctx, in.Service, in.AccountID, cost, infra, change, in.WindowStart, in.WindowEnd)
 	if err != nil {
 		return VerifyOutcomeOutput{}, fmt.Errorf("verify activity: %w", err)
 	}
 	return VerifyOutcomeOutput{Result: result}, nil
 }
 
+// DefaultBaselineDays is the pre-execution window for savings checks.
+const DefaultBaselineDays = 14
+
+// VerifySavings compares daily spend after execution with the
+// pre-execution baseline. Confirmed savings are recorded to the
+// SavingsRealized metric as a monthly run rate.
+func (a *Activities) VerifySavings(ctx context.Context, in VerifySavingsInput) (VerifySavingsOutput, error) {
+	if err := a.checkBudget(in.Tenant.TenantID, "VerifySavings"); err != nil {
+		return VerifySavingsOutput{}, err
+	}
+	ctx = apicost.WithTenant(ctx, in.Tenant.TenantID)
+	executedAt, err := time.Parse(time.RFC3339, in.ExecutedAt)
+	if err != nil {
+		return VerifySavingsOutput{}, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("verify savings activity: invalid executed_at %q", in.ExecutedAt), "InvalidInput", err)
+	}
+	cost, err := a.resolveCost(ctx, in.Tenant)
+	if err != nil {
+		return VerifySavingsOutput{}, fmt.Errorf("verify savings activity: resolve cost: %w", err)
+	}
+
+	baselineDays := in.BaselineDays
+	if baselineDays <= 0 {
+		baselineDays = DefaultBaselineDays
+	}
+	// The execution day is partial, so it belongs to neither window.
+	// Cost Explorer end dates are exclusive.
+	day := executedAt.UTC().Truncate(24 * time.Hour)
+	const layout = "2006-01-02"
+
+	before, err := cost.GetCostTimeseries(ctx, in.Service, in.AccountID,
+		day.AddDate(0, 0, -baselineDays).Format(layout), day.Format(layout))
+	if err != nil {
+		return VerifySavingsOutput{}, fmt.Errorf("verify savings activity: baseline: %w", err)
+	}
+	after, err := cost.GetCostTimeseries(ctx, in.Service, in.AccountID,
+		day.AddDate(0, 0, 1).Format(layout), day.AddDate(0, 0, in.DaysAfter).Format(layout))
+	if err != nil {
+		return VerifySavingsOutput{}, fmt.Errorf("verify savings activity: post-execution: %w", err)
+	}
+
+	check, err := verifier.AssessSavings(verifier.DailyAmounts(before), verifier.DailyAmounts(after), in.ExpectedSavingsMonthly)
+	if errors.Is(err, verifier.ErrInsufficientData) {
+		check = domain.SavingsVerification{
+			ExpectedSavingsDaily: in.ExpectedSavingsMonthly / verifier.DaysPerMonth,
+			Details:              err.Error(),
+			Recommendation:       domain.RecommendMonitor,
+		}
+	}
+	check.CheckedAt = time.Now().UTC().Format(time.RFC3339)
+	check.DaysAfter = in.DaysAfter
+
+	if check.Recommendation == domain.RecommendClose && a.Metrics != nil {
+		a.Metrics.RecordSavingsRealized(ctx, check.SavingsDaily*verifier.DaysPerMonth, in.Tenant.TenantID, in.Service)
+	}
+	return VerifySavingsOutput{Check: check}, nil
+}
+
+// RecordSavings writes one ledger entry per planned action, replacing the
+// lifecycle's earlier entries. Writes are keyed by workflow and action, so
+// retries are safe.
+func (a *Activities) RecordSavings(_ context.Context, in RecordSavingsInput) (RecordSavingsOutput, error) {
+	if a.Savings == nil {
+		return RecordSavingsOutput{}, nil
+	}
+	entries := savings.Entries(savings.Record{
+		WorkflowID: in.WorkflowID,
+		Tenant:     in.Tenant.TenantID,
+		Anomaly:    in.Anomaly,
+		Actions:    in.Actions,
+		Executions: in.Executions,
+		Check:      in.Check,
+		At:         time.Now().UTC().Format(time.RFC3339),
+	})
+	for _, e := range entries {
+		if err := a.Savings.Upsert(e); err != nil {
+			return RecordSavingsOutput{}, fmt.Errorf("record savings activity: %w", err)
+		}
+	}
+	return RecordSavingsOutput{Entries: len(entries)}, nil
+}
+
+// RecordHistory upserts the workflow's projection in the anomaly history
+// store. Each call replaces the previous projection, so retries are safe.
+func (a *Activities) RecordHistory(_ context.Context, in RecordHistoryInput) error {
+	if a.History == nil {
+		return nil
+	}
+	rec := history.FromState(in.WorkflowID, in.State, in.Reason, time.Now().UTC().Format(time.RFC3339))
+	if err := a.History.Upsert(rec); err != nil {
+		return fmt.Errorf("record history activity: %w", err)
+	}
+	return nil
+}
+
+// RecordAudit appends a workflow decision to the audit trail. The trail is
+// append-only, so a retry after a lost response may record it twice.
+func (a *Activities) RecordAudit(ctx context.Context, e audit.Event) error {
+	if a.Audit == nil {
+		return nil
+	}
+	if _, err := a.Audit.Append(e); err != nil {
+		return fmt.Errorf("record audit activity: %w", err)
+	}
+	return nil
+}
+
 // RunAWSDocWaste runs an aws-doctor waste scan and returns domain-level findings.
 func (a *Activities) RunAWSDocWaste(ctx context.Context, in AWSDocWasteInput) (AWSDocWasteOutput, error) {
 	if a.AWSDoc == nil {
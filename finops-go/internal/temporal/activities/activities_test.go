@@ -2,10 +2,21 @@ package activities_test
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/calendar"
+	"github.com/finops-claw-gang/finops-go/internal/connectors/aws/apicost"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/executor"
+	"github.com/finops-claw-gang/finops-go/internal/history"
+	"github.com/finops-claw-gang/finops-go/internal/ratelimit"
+	"github.com/finops-claw-gang/finops-go/internal/savings"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/testutil"
 )
@@ -109,6 +120,173 @@ func TestExecuteActions_UnapprovedDenied(t *testing.T) {
 	}
 }
 
+func TestExecuteAction_HappyPath(t *testing.T) {
+	a := newTestActivities()
+	action := domain.NewRecommendedAction(
+		"create budget alert",
+		"create_budget_alert",
+		domain.RiskLow,
+		"disable alert",
+	)
+	action.TargetResource = "budget:EC2:123456789012"
+	key := executor.IdempotencyKey("wf-1", action.ActionID)
+
+	in := activities.ExecuteActionInput{
+		Approval:       domain.ApprovalAutoApproved,
+		IdempotencyKey: key,
+		Action:         action,
+	}
+	first, err := a.ExecuteAction(context.Background(), in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first.Result.Success || first.Result.Outcome != domain.OutcomeSucceeded {
+		t.Errorf("unexpected result: %+v", first.Result)
+	}
+
+	// A retried activity with the same key returns the recorded result.
+	again, err := a.ExecuteAction(context.Background(), in)
+	if err != nil {
+		t.Fatalf("retry: unexpected error: %v", err)
+	}
+	if again.Result.ExecutedAt != first.Result.ExecutedAt || again.Result.IdempotencyKey != key {
+		t.Errorf("retry returned a new result: %+v", again.Result)
+	}
+}
+
+func TestExecuteAction_RefusedIsNonRetryable(t *testing.T) {
+	a := newTestActivities()
+	action := domain.NewRecommendedAction("something", "do_thing", domain.RiskLow, "undo thing")
+	_, err := a.ExecuteAction(context.Background(), activities.ExecuteActionInput{
+		Approval:       domain.ApprovalPending,
+		IdempotencyKey: executor.IdempotencyKey("wf-1", action.ActionID),
+		Action:         action,
+	})
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected ApplicationError, got %v", err)
+	}
+	if !appErr.NonRetryable() {
+		t.Error("expected refusal to be non-retryable")
+	}
+}
+
+// tenantCost records the tenant the cost queries were charged to.
+type tenantCost struct {
+	*testutil.StubCost
+	tenant string
+}
+
+func (c *tenantCost) GetCURLineItems(ctx context.Context, accountID, startDate, endDate, service string) ([]map[string]any, error) {
+	c.tenant = apicost.TenantFromContext(ctx)
+	return c.StubCost.GetCURLineItems(ctx, accountID, startDate, endDate, service)
+}
+
+func TestCostBudget(t *testing.T) {
+	a := newTestActivities()
+	cost := &tenantCost{StubCost: &testutil.StubCost{FixturesDir: testutil.GoldenDir()}}
+	a.Cost = cost
+	a.CostBudget = ratelimit.NewCostBudget(1.00)
+	tenant := domain.TenantContext{TenantID: "t-1"}
+
+	plan := activities.PlanActionsInput{
+		Tenant:      tenant,
+		AccountID:   "123456789012",
+		Service:     "EC2",
+		WindowStart: "2026-02-01",
+		WindowEnd:   "2026-02-16",
+	}
+	if _, err := a.PlanActions(context.Background(), plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cost.tenant != "t-1" {
+		t.Errorf("cost queries charged to %q, want t-1", cost.tenant)
+	}
+
+	a.CostBudget.Record("t-1", 1.00)
+
+	_, err := a.PlanActions(context.Background(), plan)
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) || appErr.Type() != activities.ErrTypeBudgetExceeded {
+		t.Fatalf("expected %s error, got %v", activities.ErrTypeBudgetExceeded, err)
+	}
+	if !appErr.NonRetryable() {
+		t.Error("expected the refusal to be non-retryable until the month turns")
+	}
+	if _, err := a.TriageAnomaly(context.Background(), activities.TriageInput{Tenant: tenant}); !errors.As(err, &appErr) {
+		t.Errorf("expected triage to be refused, got %v", err)
+	}
+
+	// Verifying an executed change is never refused.
+	if _, err := a.VerifyOutcome(context.Background(), activities.VerifyOutcomeInput{
+		Tenant:      tenant,
+		Service:     "EC2",
+		AccountID:   "123456789012",
+		WindowStart: "2026-02-01",
+		WindowEnd:   "2026-02-16",
+	}); err != nil {
+		t.Errorf("verification refused: %v", err)
+	}
+}
+
+func TestNextChangeWindow(t *testing.T) {
+	freezeEnd := time.Now().UTC().Add(2 * time.Hour).Truncate(time.Second)
+	cal := &calendar.Calendar{Freezes: []calendar.Freeze{{
+		Name:  "release freeze",
+		Start: freezeEnd.Add(-24 * time.Hour),
+		End:   freezeEnd,
+	}}}
+	if err := cal.Compile(); err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		calendar *calendar.Calendar
+		wantAt   func(t *testing.T, at string)
+	}{
+		{
+			name: "no calendar runs now",
+			wantAt: func(t *testing.T, at string) {
+				t.Helper()
+				got, err := time.Parse(time.RFC3339, at)
+				if err != nil {
+					t.Fatalf("parse At: %v", err)
+				}
+				if d := time.Since(got); d < 0 || d > time.Minute {
+					t.Errorf("At = %s, want roughly now", at)
+				}
+			},
+		},
+		{
+			name:     "freeze defers to its end",
+			calendar: cal,
+			wantAt: func(t *testing.T, at string) {
+				t.Helper()
+				if at != freezeEnd.Format(time.RFC3339) {
+					t.Errorf("At = %s, want %s", at, freezeEnd.Format(time.RFC3339))
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestActivities()
+			a.Calendar = tt.calendar
+			action := domain.NewRecommendedAction("tag", "tag", domain.RiskLow, "untag")
+			action.TargetResource = "arn:aws:ec2:us-east-1:123456789012:instance/i-abc"
+			out, err := a.NextChangeWindow(context.Background(), activities.NextChangeWindowInput{
+				Tenant:  domain.NewTenantContext("t1"),
+				Actions: []domain.RecommendedAction{action},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.wantAt(t, out.At)
+		})
+	}
+}
+
 func TestVerifyOutcome_HappyPath(t *testing.T) {
 	a := newTestActivities()
 	out, err := a.VerifyOutcome(context.Background(), activities.VerifyOutcomeInput{
@@ -125,6 +303,111 @@ func TestVerifyOutcome_HappyPath(t *testing.T) {
 	}
 }
 
+func TestVerifyOutcome_HealthyResources(t *testing.T) {
+	a := newTestActivities()
+	out, err := a.VerifyOutcome(context.Background(), activities.VerifyOutcomeInput{
+		Service:     "ELB",
+		AccountID:   "123456789012",
+		WindowStart: "2026-02-01",
+		WindowEnd:   "2026-02-16",
+		Resources:   []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"},
+		ExecutedAt:  "2026-02-16T12:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Result.ServiceHealthOK {
+		t.Errorf("expected healthy from fixtures, got %q", out.Result.HealthCheckDetails)
+	}
+	if out.Result.Recommendation != domain.RecommendMonitor {
+		t.Errorf("recommendation = %q, want monitor", out.Result.Recommendation)
+	}
+}
+
+func TestVerifyOutcome_InvalidExecutedAt(t *testing.T) {
+	a := newTestActivities()
+	_, err := a.VerifyOutcome(context.Background(), activities.VerifyOutcomeInput{
+		Service:    "EC2",
+		AccountID:  "123456789012",
+		Resources:  []string{"arn:aws:ec2:us-east-1:123456789012:instance/i-1"},
+		ExecutedAt: "yesterday",
+	})
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) || !appErr.NonRetryable() {
+		t.Fatalf("expected non-retryable error, got %v", err)
+	}
+}
+
+func TestVerifySavings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cost    activities.CostDeps
+		wantRec domain.VerificationRecommendation
+	}{
+		{
+			name:    "flat fixture spend monitors",
+			cost:    &testutil.StubCost{FixturesDir: testutil.GoldenDir()},
+			wantRec: domain.RecommendMonitor,
+		},
+		{
+			name: "baseline drop closes",
+			cost: &windowCost{
+				testutil.StubCost{FixturesDir: testutil.GoldenDir()},
+				map[string][]float64{
+					"2026-02-02": {100, 102, 98, 101, 99, 100},
+					"2026-02-17": {60, 61, 59},
+				},
+			},
+			wantRec: domain.RecommendClose,
+		},
+		{
+			name: "too few days monitors",
+			cost: &windowCost{
+				testutil.StubCost{FixturesDir: testutil.GoldenDir()},
+				map[string][]float64{"2026-02-02": {100, 102}, "2026-02-17": {60}},
+			},
+			wantRec: domain.RecommendMonitor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestActivities()
+			a.Cost = tt.cost
+			out, err := a.VerifySavings(context.Background(), activities.VerifySavingsInput{
+				Service:                "EC2",
+				AccountID:              "123456789012",
+				ExecutedAt:             "2026-02-16T15:04:05Z",
+				DaysAfter:              4,
+				ExpectedSavingsMonthly: 1200,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Check.Recommendation != tt.wantRec {
+				t.Errorf("recommendation = %q, want %q (%s)", out.Check.Recommendation, tt.wantRec, out.Check.Details)
+			}
+			if out.Check.DaysAfter != 4 || out.Check.CheckedAt == "" {
+				t.Errorf("check metadata = %+v", out.Check)
+			}
+		})
+	}
+}
+
+// windowCost returns daily amounts keyed by the requested start date, so
+// tests can check which windows VerifySavings asks for.
+type windowCost struct {
+	testutil.StubCost
+	byStart map[string][]float64
+}
+
+func (w *windowCost) GetCostTimeseries(_ context.Context, _, _, start, _ string) (map[string]any, error) {
+	points := make([]map[string]any, 0, len(w.byStart[start]))
+	for _, amt := range w.byStart[start] {
+		points = append(points, map[string]any{"amount": amt})
+	}
+	return map[string]any{"points": points}, nil
+}
+
 func TestNotifySlack_Stub(t *testing.T) {
 	a := newTestActivities()
 	err := a.NotifySlack(context.Background(), activities.NotifySlackInput{
@@ -197,3 +480,124 @@ func TestRunAWSDocTrend_Stub(t *testing.T) {
 		t.Errorf("velocity_pct = %f, want 0", out.VelocityPct)
 	}
 }
+
+func TestRecordSavings(t *testing.T) {
+	store, err := savings.OpenFileStore(filepath.Join(t.TempDir(), "savings.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	acts := newTestActivities()
+	acts.Savings = store
+
+	in := activities.RecordSavingsInput{
+		Tenant:     domain.NewTenantContext("acme"),
+		WorkflowID: "wf-1",
+		Anomaly:    domain.CostAnomaly{Service: "EC2", AccountID: "123456789012", Team: "platform"},
+		Actions: []domain.RecommendedAction{
+			{ActionID: "a", ActionType: "delete_volume", EstimatedSavingsMonthly: 200},
+		},
+		Executions: []domain.ExecutionResult{
+			{ActionID: "a", Success: true, Outcome: domain.OutcomeSucceeded, ExecutedAt: "2026-03-02T22:00:00Z"},
+		},
+	}
+	if out, err := acts.RecordSavings(context.Background(), in); err != nil || out.Entries != 1 {
+		t.Fatalf("RecordSavings = %+v, %v", out, err)
+	}
+	in.Check = &domain.SavingsVerification{
+		CheckedAt: "2026-03-09T22:00:00Z", SavingsDaily: 5, Significant: true, Recommendation: domain.RecommendClose,
+	}
+	if _, err := acts.RecordSavings(context.Background(), in); err != nil {
+		t.Fatalf("RecordSavings: %v", err)
+	}
+
+	entries, err := store.List(savings.Filter{TenantID: "acme"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want 1", entries)
+	}
+	e := entries[0]
+	if e.ID != "wf-1/a" || e.Status != savings.StatusVerified || e.RealizedMonthly != 5*savings.DaysPerMonth {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestRecordSavings_NoLedger(t *testing.T) {
+	acts := newTestActivities()
+	if out, err := acts.RecordSavings(context.Background(), activities.RecordSavingsInput{}); err != nil || out.Entries != 0 {
+		t.Errorf("RecordSavings = %+v, %v", out, err)
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	acts := newTestActivities()
+	store := history.NewMemoryStore()
+	acts.History = store
+
+	state := domain.NewFinOpsState(domain.NewTenantContext("acme"))
+	state.CurrentPhase = "triage"
+	state.Anomaly = &domain.CostAnomaly{Service: "EC2", DeltaDollars: 250}
+	state.Triage = &domain.TriageResult{Category: domain.CategoryResourceWaste, Severity: domain.SeverityHigh}
+	if err := acts.RecordHistory(context.Background(), activities.RecordHistoryInput{WorkflowID: "wf-1", State: state}); err != nil {
+		t.Fatalf("RecordHistory: %v", err)
+	}
+
+	page, err := store.Query(history.Query{Category: domain.CategoryResourceWaste})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(page.Records) != 1 || page.Records[0].WorkflowID != "wf-1" || page.Records[0].DeltaDollars != 250 {
+		t.Errorf("records = %+v", page.Records)
+	}
+}
+
+func TestExecuteAction_Audited(t *testing.T) {
+	a := newTestActivities()
+	log, err := audit.OpenFileLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Audit = log
+
+	ok := domain.NewRecommendedAction("create budget alert", "create_budget_alert", domain.RiskLow, "disable alert")
+	ok.TargetResource = "budget:EC2:123456789012"
+	if _, err := a.ExecuteAction(context.Background(), activities.ExecuteActionInput{
+		Tenant:         domain.NewTenantContext("acme"),
+		Approval:       domain.ApprovalAutoApproved,
+		IdempotencyKey: executor.IdempotencyKey("wf-1", ok.ActionID),
+		Action:         ok,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	refused := domain.NewRecommendedAction("something", "do_thing", domain.RiskLow, "undo thing")
+	_, _ = a.ExecuteAction(context.Background(), activities.ExecuteActionInput{
+		Tenant:         domain.NewTenantContext("acme"),
+		Approval:       domain.ApprovalPending,
+		IdempotencyKey: executor.IdempotencyKey("wf-1", refused.ActionID),
+		Action:         refused,
+	})
+
+	events, err := log.Query(audit.Filter{Action: audit.ActionExecuted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if e := events[1]; e.Outcome != "succeeded" || e.Actor != audit.SystemActor || e.TenantID != "acme" || e.Resource != ok.TargetResource {
+		t.Errorf("executed event = %+v", e)
+	}
+	if e := events[0]; e.Outcome != "refused" || e.Reason == "" || e.Details["action_id"] != refused.ActionID {
+		t.Errorf("refused event = %+v", e)
+	}
+	if _, err := audit.Verify(log.Path()); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+func TestRecordAudit_NoLog(t *testing.T) {
+	if err := newTestActivities().RecordAudit(context.Background(), audit.Event{Action: audit.ActionPolicyDecision}); err != nil {
+		t.Errorf("RecordAudit without a log: %v", err)
+	}
+}
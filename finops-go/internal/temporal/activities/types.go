@@ -45,13 +45,63 @@ type ExecuteActionsOutput struct {
 	Results []domain.ExecutionResult `json:"results"`
 }
 
+// ExecuteActionInput is the activity input for executing a single action.
+// IdempotencyKey is derived in the workflow from its ID and the ActionID.
+type ExecuteActionInput struct {
+	Tenant         domain.TenantContext     `json:"tenant,omitempty"`
+	Approval       domain.ApprovalStatus    `json:"approval"`
+	IdempotencyKey string                   `json:"idempotency_key"`
+	Action         domain.RecommendedAction `json:"action"`
+
+	// Plan is the full action list, checked against blast-radius limits.
+	Plan []domain.RecommendedAction `json:"plan,omitempty"`
+}
+
+// ExecuteActionOutput is the activity output from executing a single action.
+type ExecuteActionOutput struct {
+	Result domain.ExecutionResult `json:"result"`
+}
+
+// NextChangeWindowInput is the activity input for change-calendar lookup.
+type NextChangeWindowInput struct {
+	Tenant  domain.TenantContext       `json:"tenant,omitempty"`
+	Actions []domain.RecommendedAction `json:"actions"`
+}
+
+// NextChangeWindowOutput is the activity output from change-calendar lookup.
+// At is RFC3339; empty means no window within the search horizon.
+type NextChangeWindowOutput struct {
+	At string `json:"at"`
+}
+
+// RollbackActionInput is the activity input for rolling back a single action.
+type RollbackActionInput struct {
+	Tenant            domain.TenantContext     `json:"tenant,omitempty"`
+	IdempotencyKey    string                   `json:"idempotency_key"`
+	Action            domain.RecommendedAction `json:"action"`
+	PreActionSnapshot map[string]any           `json:"pre_action_snapshot"`
+}
+
+// RollbackActionOutput is the activity output from rolling back a single action.
+type RollbackActionOutput struct {
+	Result domain.ExecutionResult `json:"result"`
+}
+
 // VerifyOutcomeInput is the activity input for post-execution verification.
+// Resources are the ARNs the execution changed and ExecutedAt (RFC3339)
+// splits the before and after health windows; empty means now.
 type VerifyOutcomeInput struct {
 	Tenant      domain.TenantContext `json:"tenant,omitempty"`
 	Service     string               `json:"service"`
 	AccountID   string               `json:"account_id"`
 	WindowStart string               `json:"window_start"`
 	WindowEnd   string               `json:"window_end"`
+	Resources   []string             `json:"resources,omitempty"`
+	ExecutedAt  string               `json:"executed_at,omitempty"`
+	// CheckedAt (RFC 3339) ends the health window compared with the
+	// baseline before ExecutedAt. Empty means the first verifier.HealthSpan
+	// after ExecutedAt.
+	CheckedAt string `json:"checked_at,omitempty"`
 }
 
 // VerifyOutcomeOutput is the activity output from verification.
@@ -59,6 +109,24 @@ type VerifyOutcomeOutput struct {
 	Result domain.VerificationResult `json:"result"`
 }
 
+// VerifySavingsInput is the activity input for a delayed savings check.
+// Spend for the BaselineDays before the execution day is compared with
+// spend from the day after execution up to DaysAfter days after it.
+type VerifySavingsInput struct {
+	Tenant                 domain.TenantContext `json:"tenant,omitempty"`
+	Service                string               `json:"service"`
+	AccountID              string               `json:"account_id"`
+	ExecutedAt             string               `json:"executed_at"`
+	DaysAfter              int                  `json:"days_after"`
+	BaselineDays           int                  `json:"baseline_days,omitempty"` // 0 = DefaultBaselineDays
+	ExpectedSavingsMonthly float64              `json:"expected_savings_monthly"`
+}
+
+// VerifySavingsOutput is the activity output from a delayed savings check.
+type VerifySavingsOutput struct {
+	Check domain.SavingsVerification `json:"check"`
+}
+
 // NotifySlackInput is the activity input for Slack notifications.
 type NotifySlackInput struct {
 	Channel string `json:"channel"`
@@ -83,6 +151,12 @@ type ApprovalResponse struct {
 	Approved bool   `json:"approved"`
 	By       string `json:"by"`
 	Reason   string `json:"reason,omitempty"`
+
+	// Subject, Email and Issuer are the verified identity of the caller,
+	// empty when authentication is off.
+	Subject string `json:"subject,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Issuer  string `json:"issuer,omitempty"`
 }
 
 // AWSDocWasteInput is the activity input for aws-doctor waste scans.
@@ -109,3 +183,28 @@ type AWSDocTrendOutput struct {
 	TrendDirection string  `json:"trend_direction"`
 	VelocityPct    float64 `json:"velocity_pct"`
 }
+
+// RecordSavingsInput is the activity input for writing a lifecycle's
+// actions to the savings ledger. Check is the latest savings verification,
+// nil before the first one.
+type RecordSavingsInput struct {
+	Tenant     domain.TenantContext        `json:"tenant,omitempty"`
+	WorkflowID string                      `json:"workflow_id"`
+	Anomaly    domain.CostAnomaly          `json:"anomaly"`
+	Actions    []domain.RecommendedAction  `json:"actions"`
+	Executions []domain.ExecutionResult    `json:"executions"`
+	Check      *domain.SavingsVerification `json:"check,omitempty"`
+}
+
+// RecordSavingsOutput is the activity output from a ledger write.
+type RecordSavingsOutput struct {
+	Entries int `json:"entries"`
+}
+
+// RecordHistoryInput is the activity input for projecting workflow state
+// to the anomaly history store. Reason is empty while the workflow runs.
+type RecordHistoryInput struct {
+	WorkflowID string             `json:"workflow_id"`
+	State      domain.FinOpsState `json:"state"`
+	Reason     string             `json:"reason,omitempty"`
+}
@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 
+	"github.com/finops-claw-gang/finops-go/internal/apperr"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
 )
@@ -15,3 +16,39 @@ type WorkflowQuerier interface {
 	DescribeWorkflow(ctx context.Context, workflowID string) (*WorkflowDescription, error)
 	SubmitApproval(ctx context.Context, workflowID string, resp activities.ApprovalResponse) (string, error)
 }
+
+// WorkflowStarter starts workflows under caller-chosen, deterministic IDs.
+// Starting an ID that is already taken does not start a second workflow:
+// the existing one is returned if it was started by the same request, as
+// identified by fingerprint, and ErrIdempotencyConflict otherwise. The
+// HTTP API uses it when its querier implements it.
+type WorkflowStarter interface {
+	StartAnomaly(ctx context.Context, workflowID, fingerprint string, input workflows.WorkflowInput) (StartResult, error)
+	StartSweep(ctx context.Context, workflowID, fingerprint string, input workflows.SweepInput) (StartResult, error)
+}
+
+// QueueChecker reports whether any worker polls a task queue for
+// activities. The HTTP API uses it, when its querier implements it, to send
+// premium tenants to their dedicated queues only while workers poll them.
+type QueueChecker interface {
+	TaskQueuePolled(ctx context.Context, queue string) (bool, error)
+}
+
+// ErrIdempotencyConflict is returned when a workflow ID was already used
+// by a different request.
+var ErrIdempotencyConflict = apperr.NewSentinel(apperr.Conflict, "querier: workflow ID already used by a different request")
+
+var (
+	// ErrNotRunning is returned when a workflow has closed without a
+	// result to read, or closed before an approval reached it.
+	ErrNotRunning = apperr.NewSentinel(apperr.InvalidState, "querier: workflow is not running")
+	// ErrNotAwaitingApproval is returned by SubmitApproval when the
+	// workflow has not reached its approval gate.
+	ErrNotAwaitingApproval = apperr.NewSentinel(apperr.InvalidState, "querier: workflow is not awaiting approval")
+	// ErrAlreadyDecided is returned by SubmitApproval when the workflow
+	// has already been approved or denied.
+	ErrAlreadyDecided = apperr.NewSentinel(apperr.Conflict, "querier: approval already decided")
+	// ErrInvalidApproval is returned by SubmitApproval when the workflow
+	// rejects the approval as malformed.
+	ErrInvalidApproval = apperr.NewSentinel(apperr.InvalidArgument, "querier: invalid approval")
+)
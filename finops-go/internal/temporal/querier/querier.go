@@ -2,13 +2,20 @@ package querier
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
 
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
 )
 
@@ -24,17 +31,7 @@ func New(c client.Client) *TemporalQuerier {
 
 // ListWorkflows lists workflow executions using Temporal's visibility API.
 func (q *TemporalQuerier) ListWorkflows(ctx context.Context, opts ListOptions) ([]WorkflowSummary, error) {
-	query := ""
-	if opts.TaskQueue != "" {
-		query = fmt.Sprintf("TaskQueue = %q", opts.TaskQueue)
-	}
-	if opts.StatusFilter != "" {
-		if query != "" {
-			query += " AND "
-		}
-		query += fmt.Sprintf("ExecutionStatus = %q", opts.StatusFilter)
-	}
-
+	query := opts.Query()
 	pageSize := opts.PageSize
 	if pageSize <= 0 {
 		pageSize = 50
@@ -65,6 +62,31 @@ func (q *TemporalQuerier) ListWorkflows(ctx context.Context, opts ListOptions) (
 	return summaries, nil
 }
 
+// Query returns the visibility list filter for the options, with clauses
+// joined by AND. An empty string matches every workflow.
+func (o ListOptions) Query() string {
+	var clauses []string
+	eq := func(field, value string) {
+		if value != "" {
+			clauses = append(clauses, fmt.Sprintf("%s = %q", field, value))
+		}
+	}
+	eq("TaskQueue", o.TaskQueue)
+	eq("ExecutionStatus", o.StatusFilter)
+	eq(workflows.SearchAttrTenantID.GetName(), o.TenantID)
+	eq(workflows.SearchAttrService.GetName(), o.Service)
+	eq(workflows.SearchAttrAccountID.GetName(), o.AccountID)
+	eq(workflows.SearchAttrCategory.GetName(), o.Category)
+	eq(workflows.SearchAttrSeverity.GetName(), o.Severity)
+	eq(workflows.SearchAttrPhase.GetName(), o.Phase)
+	eq(workflows.SearchAttrApprovalStatus.GetName(), o.ApprovalStatus)
+	if o.MinDeltaDollars != nil {
+		clauses = append(clauses, fmt.Sprintf("%s >= %s",
+			workflows.SearchAttrDeltaDollars.GetName(), strconv.FormatFloat(*o.MinDeltaDollars, 'f', -1, 64)))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
 // GetWorkflowState returns the current workflow result.
 // For completed workflows, extracts the result directly.
 // For running workflows, uses the Query handler.
@@ -96,7 +118,7 @@ func (q *TemporalQuerier) GetWorkflowState(ctx context.Context, workflowID strin
 		return &result, nil
 	}
 
-	return nil, fmt.Errorf("workflow %s has status %s, cannot read state", workflowID, status)
+	return nil, fmt.Errorf("workflow %s has status %s, cannot read state: %w", workflowID, status, ErrNotRunning)
 }
 
 // DescribeWorkflow returns detailed information about a workflow execution.
@@ -131,12 +153,106 @@ func (q *TemporalQuerier) SubmitApproval(ctx context.Context, workflowID string,
 		WaitForStage: client.WorkflowUpdateStageCompleted,
 	})
 	if err != nil {
-		return "", fmt.Errorf("submit approval: %w", err)
+		return "", fmt.Errorf("submit approval: %w", q.approvalError(ctx, workflowID, err))
 	}
 
 	var result string
 	if err := handle.Get(ctx, &result); err != nil {
-		return "", fmt.Errorf("get approval result: %w", err)
+		return "", fmt.Errorf("get approval result: %w", q.approvalError(ctx, workflowID, err))
 	}
 	return result, nil
 }
+
+// approvalError explains why an approval update failed: the workflow
+// rejected it, had not registered the update yet, or has already closed.
+// Other errors are returned unchanged.
+func (q *TemporalQuerier) approvalError(ctx context.Context, workflowID string, err error) error {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		switch {
+		case appErr.Type() == workflows.ErrTypeApprovalDecided:
+			return ErrAlreadyDecided
+		case appErr.Type() == workflows.ErrTypeInvalidApproval:
+			return fmt.Errorf("%w: %s", ErrInvalidApproval, appErr.Message())
+		case strings.HasPrefix(appErr.Message(), "unknown update"):
+			// The SDK rejects updates without a handler; the approval
+			// handler is registered once the workflow reaches its gate.
+			return ErrNotAwaitingApproval
+		}
+	}
+	// Updates to a closed workflow are not found; tell that apart from a
+	// workflow that does not exist.
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		if _, derr := q.client.DescribeWorkflowExecution(ctx, workflowID, ""); derr == nil {
+			return fmt.Errorf("workflow %s: %w", workflowID, ErrNotRunning)
+		}
+	}
+	return err
+}
+
+// memoFingerprint is the memo field holding the fingerprint of the request
+// that started a workflow.
+const memoFingerprint = "request_fingerprint"
+
+// StartAnomaly implements WorkflowStarter.
+func (q *TemporalQuerier) StartAnomaly(ctx context.Context, workflowID, fingerprint string, input workflows.WorkflowInput) (StartResult, error) {
+	return q.start(ctx, workflowID, fingerprint, workflows.TenantPriority(input.Tenant), workflows.AnomalyLifecycleWorkflow, input)
+}
+
+// StartSweep implements WorkflowStarter.
+func (q *TemporalQuerier) StartSweep(ctx context.Context, workflowID, fingerprint string, input workflows.SweepInput) (StartResult, error) {
+	return q.start(ctx, workflowID, fingerprint, workflows.TenantPriority(input.TenantContext("")), workflows.AWSDocSweepWorkflow, input)
+}
+
+// TaskQueuePolled reports whether any worker has recently polled queue for
+// activity tasks.
+func (q *TemporalQuerier) TaskQueuePolled(ctx context.Context, queue string) (bool, error) {
+	resp, err := q.client.DescribeTaskQueue(ctx, queue, enumspb.TASK_QUEUE_TYPE_ACTIVITY)
+	if err != nil {
+		return false, fmt.Errorf("describe task queue %s: %w", queue, err)
+	}
+	return len(resp.GetPollers()) > 0, nil
+}
+
+// start starts wf on the anomaly queue with priority. IDs are never reused,
+// even after the workflow closes, so a retried request cannot start a
+// duplicate.
+func (q *TemporalQuerier) start(ctx context.Context, workflowID, fingerprint string, priority temporal.Priority, wf, input any) (StartResult, error) {
+	run, err := q.client.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:                                       workflowID,
+		TaskQueue:                                versioning.QueueAnomaly,
+		Priority:                                 priority,
+		WorkflowIDReusePolicy:                    enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+		Memo:                                     map[string]any{memoFingerprint: fingerprint},
+	}, wf, input)
+	var started *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &started) {
+		return q.existing(ctx, workflowID, fingerprint)
+	}
+	if err != nil {
+		return StartResult{}, fmt.Errorf("start workflow %s: %w", workflowID, err)
+	}
+	return StartResult{WorkflowID: run.GetID(), RunID: run.GetRunID()}, nil
+}
+
+// existing returns the workflow already holding workflowID if the same
+// request started it.
+func (q *TemporalQuerier) existing(ctx context.Context, workflowID, fingerprint string) (StartResult, error) {
+	desc, err := q.client.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return StartResult{}, fmt.Errorf("describe workflow: %w", err)
+	}
+	info := desc.WorkflowExecutionInfo
+	var got string
+	if p, ok := info.GetMemo().GetFields()[memoFingerprint]; ok {
+		if err := converter.GetDefaultDataConverter().FromPayload(p, &got); err != nil {
+			return StartResult{}, fmt.Errorf("decode workflow memo: %w", err)
+		}
+	}
+	if got != fingerprint {
+		return StartResult{}, fmt.Errorf("%w: %s", ErrIdempotencyConflict, workflowID)
+	}
+	return StartResult{WorkflowID: workflowID, RunID: info.Execution.RunId, Existing: true}, nil
+}
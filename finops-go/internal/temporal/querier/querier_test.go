@@ -5,9 +5,19 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
 
 	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/querier"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
 )
@@ -87,3 +97,149 @@ func TestWorkflowSummaryFields(t *testing.T) {
 	assert.Equal(t, "finops-anomaly-t1-abc123", s.WorkflowID)
 	assert.Equal(t, "Running", s.Status)
 }
+
+func TestListOptionsQuery(t *testing.T) {
+	minDelta := 500.0
+	tests := []struct {
+		name string
+		opts querier.ListOptions
+		want string
+	}{
+		{name: "empty", opts: querier.ListOptions{}, want: ""},
+		{
+			name: "queue and status",
+			opts: querier.ListOptions{TaskQueue: "finops-anomaly", StatusFilter: "Running"},
+			want: `TaskQueue = "finops-anomaly" AND ExecutionStatus = "Running"`,
+		},
+		{
+			name: "pending critical for tenant",
+			opts: querier.ListOptions{TenantID: "acme", Severity: "critical", ApprovalStatus: "pending"},
+			want: `TenantID = "acme" AND Severity = "critical" AND ApprovalStatus = "pending"`,
+		},
+		{
+			name: "all attributes",
+			opts: querier.ListOptions{
+				Service: "EC2", AccountID: "123", Category: "config_drift", Phase: "hil_gate", MinDeltaDollars: &minDelta,
+			},
+			want: `Service = "EC2" AND AccountID = "123" AND Category = "config_drift" AND Phase = "hil_gate" AND DeltaDollars >= 500`,
+		},
+		{
+			name: "quotes are escaped",
+			opts: querier.ListOptions{TenantID: `a"b`},
+			want: `TenantID = "a\"b"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.Query())
+		})
+	}
+}
+
+func TestTemporalQuerier_Start(t *testing.T) {
+	memo := func(fingerprint string) *commonpb.Memo {
+		p, err := converter.GetDefaultDataConverter().ToPayload(fingerprint)
+		require.NoError(t, err)
+		return &commonpb.Memo{Fields: map[string]*commonpb.Payload{"request_fingerprint": p}}
+	}
+	alreadyStarted := serviceerror.NewWorkflowExecutionAlreadyStarted("started", "", "run-0")
+	input := workflows.WorkflowInput{Tenant: domain.NewTenantContext("acme")}
+
+	tests := []struct {
+		name     string
+		startErr error
+		memo     string
+		want     querier.StartResult
+		wantErr  error
+	}{
+		{name: "new", want: querier.StartResult{WorkflowID: "wf-1", RunID: "run-1"}},
+		{name: "same request", startErr: alreadyStarted, memo: "fp", want: querier.StartResult{WorkflowID: "wf-1", RunID: "run-0", Existing: true}},
+		{name: "different request", startErr: alreadyStarted, memo: "other", wantErr: querier.ErrIdempotencyConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &mocks.Client{}
+			run := &mocks.WorkflowRun{}
+			run.On("GetID").Return("wf-1").Maybe()
+			run.On("GetRunID").Return("run-1").Maybe()
+			c.On("ExecuteWorkflow", mock.Anything, mock.MatchedBy(func(o client.StartWorkflowOptions) bool {
+				return o.ID == "wf-1" && o.WorkflowExecutionErrorWhenAlreadyStarted && o.Memo["request_fingerprint"] == "fp"
+			}), mock.Anything, input).Return(run, tt.startErr)
+			if tt.startErr != nil {
+				c.On("DescribeWorkflowExecution", mock.Anything, "wf-1", "").Return(&workflowservice.DescribeWorkflowExecutionResponse{
+					WorkflowExecutionInfo: &workflowpb.WorkflowExecutionInfo{
+						Execution: &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-0"},
+						Memo:      memo(tt.memo),
+					},
+				}, nil)
+			}
+
+			got, err := querier.New(c).StartAnomaly(context.Background(), "wf-1", "fp", input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			c.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTemporalQuerier_SubmitApprovalErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		updateErr error
+		getErr    error
+		describe  error
+		wantErr   error
+		// wantNotFound expects Temporal's NotFound, not ErrNotRunning.
+		wantNotFound bool
+	}{
+		{
+			name:    "already decided",
+			getErr:  temporal.NewApplicationError("approval already received", workflows.ErrTypeApprovalDecided),
+			wantErr: querier.ErrAlreadyDecided,
+		},
+		{
+			name:    "invalid approval",
+			getErr:  temporal.NewApplicationError("approval 'by' field is required", workflows.ErrTypeInvalidApproval),
+			wantErr: querier.ErrInvalidApproval,
+		},
+		{
+			name:    "before the gate",
+			getErr:  temporal.NewApplicationError("unknown update approval. KnownUpdates=[]", ""),
+			wantErr: querier.ErrNotAwaitingApproval,
+		},
+		{
+			name:      "closed",
+			updateErr: serviceerror.NewNotFound("workflow execution already completed"),
+			wantErr:   querier.ErrNotRunning,
+		},
+		{
+			name:         "missing",
+			updateErr:    serviceerror.NewNotFound("workflow not found"),
+			describe:     serviceerror.NewNotFound("workflow not found"),
+			wantNotFound: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &mocks.Client{}
+			handle := &mocks.WorkflowUpdateHandle{}
+			handle.On("Get", mock.Anything, mock.Anything).Return(tt.getErr).Maybe()
+			c.On("UpdateWorkflow", mock.Anything, mock.Anything).Return(handle, tt.updateErr)
+			c.On("DescribeWorkflowExecution", mock.Anything, "wf-1", "").
+				Return(&workflowservice.DescribeWorkflowExecutionResponse{}, tt.describe).Maybe()
+
+			_, err := querier.New(c).SubmitApproval(context.Background(), "wf-1", activities.ApprovalResponse{By: "alice"})
+			if tt.wantNotFound {
+				var notFound *serviceerror.NotFound
+				assert.ErrorAs(t, err, &notFound)
+				assert.NotErrorIs(t, err, querier.ErrNotRunning)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -9,6 +9,19 @@ type ListOptions struct {
 	TaskQueue string
 	// StatusFilter filters by workflow status (e.g. "Running", "Completed").
 	StatusFilter string
+
+	// The remaining filters match the lifecycle's custom search attributes
+	// (see workflows.SearchAttributeKeys). Empty means no filter.
+	TenantID       string
+	Service        string
+	AccountID      string
+	Category       string
+	Severity       string
+	Phase          string
+	ApprovalStatus string
+	// MinDeltaDollars keeps anomalies with at least this daily delta.
+	MinDeltaDollars *float64
+
 	// PageSize limits the number of results.
 	PageSize int
 }
@@ -28,3 +41,11 @@ type WorkflowDescription struct {
 	WorkflowSummary
 	SearchAttributes map[string]any `json:"search_attributes,omitempty"`
 }
+
+// StartResult identifies a started workflow. Existing is true when the ID
+// was already taken by the same request and nothing new was started.
+type StartResult struct {
+	WorkflowID string `json:"workflow_id"`
+	RunID      string `json:"run_id"`
+	Existing   bool   `json:"existing,omitempty"`
+}
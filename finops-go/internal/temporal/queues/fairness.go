@@ -0,0 +1,251 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.temporal.io/sdk/interceptor"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// Fairness configures how a shared queue's worker admits activities from
+// different tenants. The zero value admits every activity as soon as the
+// worker has a free slot.
+type Fairness struct {
+	// Capacity is how many activities run at once across all tenants.
+	// 0 disables the scheduler.
+	Capacity int
+	// TenantCap is how many of them one tenant may run at once.
+	// 0 means Capacity.
+	TenantCap int
+}
+
+// Scheduler admits activities to run with weighted fair queueing: while
+// tenants wait, the next free place goes to the tenant that has received
+// the least service relative to its weight, so a tenant with weight 2 gets
+// twice the places of a tenant with weight 1, and no tenant holds more than
+// TenantCap of them. A tenant returning from idle starts level with the
+// others rather than cashing in the time it was away.
+type Scheduler struct {
+	mu        sync.Mutex
+	capacity  int
+	tenantCap int
+	running   int
+	vclock    float64 // virtual start time of the latest admission
+	tenants   map[string]*tenantQueue
+}
+
+type tenantQueue struct {
+	running int
+	vtime   float64 // virtual finish time of the tenant's latest admission
+	waiters []*waiter
+}
+
+type waiter struct {
+	weight   float64
+	ready    chan struct{}
+	admitted bool
+}
+
+// NewScheduler creates a scheduler for cfg. It returns nil when cfg
+// disables scheduling; a nil Scheduler admits everything.
+func NewScheduler(cfg Fairness) *Scheduler {
+	if cfg.Capacity <= 0 {
+		return nil
+	}
+	tenantCap := cfg.TenantCap
+	if tenantCap <= 0 || tenantCap > cfg.Capacity {
+		tenantCap = cfg.Capacity
+	}
+	return &Scheduler{
+		capacity:  cfg.Capacity,
+		tenantCap: tenantCap,
+		tenants:   make(map[string]*tenantQueue),
+	}
+}
+
+// Acquire blocks until an activity of tenantID may run, or ctx is done.
+// weight is the tenant's share relative to other tenants; values <= 0
+// mean 1. The caller must call release when the activity finishes.
+func (s *Scheduler) Acquire(ctx context.Context, tenantID string, weight float64) (release func(), err error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	if weight <= 0 {
+		weight = 1
+	}
+	w := &waiter{weight: weight, ready: make(chan struct{})}
+
+	s.mu.Lock()
+	tq := s.tenants[tenantID]
+	if tq == nil {
+		tq = &tenantQueue{}
+		s.tenants[tenantID] = tq
+	}
+	tq.waiters = append(tq.waiters, w)
+	s.dispatch()
+	s.mu.Unlock()
+
+	var once sync.Once
+	release = func() { once.Do(func() { s.release(tenantID) }) }
+
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	if w.admitted {
+		s.mu.Unlock()
+		release()
+		return nil, fmt.Errorf("queues: wait for tenant %q slot: %w", tenantID, ctx.Err())
+	}
+	for i, other := range tq.waiters {
+		if other == w {
+			tq.waiters = append(tq.waiters[:i], tq.waiters[i+1:]...)
+			break
+		}
+	}
+	s.forget(tenantID, tq)
+	s.dispatch()
+	s.mu.Unlock()
+	return nil, fmt.Errorf("queues: wait for tenant %q slot: %w", tenantID, ctx.Err())
+}
+
+func (s *Scheduler) release(tenantID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running--
+	tq := s.tenants[tenantID]
+	tq.running--
+	s.forget(tenantID, tq)
+	s.dispatch()
+}
+
+// forget drops an idle tenant once it is no further ahead than the virtual
+// clock, since a new tenant would start from the clock anyway. When nothing
+// runs or waits, no tenant is owed anything and the scheduler starts over.
+func (s *Scheduler) forget(tenantID string, tq *tenantQueue) {
+	if tq.running == 0 && len(tq.waiters) == 0 && tq.vtime <= s.vclock {
+		delete(s.tenants, tenantID)
+	}
+	if s.running > 0 {
+		return
+	}
+	for _, other := range s.tenants {
+		if len(other.waiters) > 0 {
+			return
+		}
+	}
+	clear(s.tenants)
+	s.vclock = 0
+}
+
+// dispatch admits waiters while there is capacity. s.mu must be held.
+func (s *Scheduler) dispatch() {
+	for s.running < s.capacity {
+		id, start := s.next()
+		if start < 0 {
+			return
+		}
+		tq := s.tenants[id]
+		w := tq.waiters[0]
+		tq.waiters = tq.waiters[1:]
+		tq.vtime = start + 1/w.weight
+		s.vclock = start
+		tq.running++
+		s.running++
+		w.admitted = true
+		close(w.ready)
+	}
+}
+
+// next returns the eligible tenant with the earliest virtual start time,
+// breaking ties by tenant ID, or start -1 when no tenant is eligible.
+func (s *Scheduler) next() (tenantID string, start float64) {
+	ids := make([]string, 0, len(s.tenants))
+	for id := range s.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	start = -1
+	for _, id := range ids {
+		tq := s.tenants[id]
+		if len(tq.waiters) == 0 || tq.running >= s.tenantCap {
+			continue
+		}
+		st := max(tq.vtime, s.vclock)
+		if start < 0 || st < start {
+			tenantID, start = id, st
+		}
+	}
+	return tenantID, start
+}
+
+// WorkerInterceptor returns a worker interceptor that runs each activity
+// only once the scheduler admits it, on behalf of the tenant named by the
+// domain.TenantContext in its input. Activities without one share the
+// empty tenant. Time spent waiting counts toward the activity's
+// start-to-close timeout. The caps hold per worker process: each worker
+// polling the queue admits up to its own Capacity. A nil Scheduler
+// returns nil.
+func (s *Scheduler) WorkerInterceptor() interceptor.WorkerInterceptor {
+	if s == nil {
+		return nil
+	}
+	return &fairWorker{scheduler: s}
+}
+
+type fairWorker struct {
+	interceptor.WorkerInterceptorBase
+	scheduler *Scheduler
+}
+
+func (w *fairWorker) InterceptActivity(_ context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	return &fairActivity{ActivityInboundInterceptorBase: interceptor.ActivityInboundInterceptorBase{Next: next}, scheduler: w.scheduler}
+}
+
+type fairActivity struct {
+	interceptor.ActivityInboundInterceptorBase
+	scheduler *Scheduler
+}
+
+func (a *fairActivity) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (any, error) {
+	tenant := tenantOf(in.Args)
+	release, err := a.scheduler.Acquire(ctx, tenant.TenantID, tenant.Weight)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return a.Next.ExecuteActivity(ctx, in)
+}
+
+var tenantContextType = reflect.TypeOf(domain.TenantContext{})
+
+// tenantOf returns the Tenant field of the first activity argument that is
+// a struct, or a pointer to one, with a domain.TenantContext field named
+// Tenant.
+func tenantOf(args []any) domain.TenantContext {
+	for _, arg := range args {
+		v := reflect.ValueOf(arg)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if f := v.FieldByName("Tenant"); f.IsValid() && f.Type() == tenantContextType {
+			return f.Interface().(domain.TenantContext)
+		}
+	}
+	return domain.TenantContext{}
+}
@@ -0,0 +1,146 @@
+package queues
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// waiting returns how many activities of tenantID wait for admission.
+func (s *Scheduler) waiting(tenantID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tq := s.tenants[tenantID]; tq != nil {
+		return len(tq.waiters)
+	}
+	return 0
+}
+
+func acquire(t *testing.T, s *Scheduler, tenantID string) func() {
+	t.Helper()
+	release, err := s.Acquire(context.Background(), tenantID, 1)
+	require.NoError(t, err)
+	return release
+}
+
+func TestScheduler_TenantCap(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(Fairness{Capacity: 3, TenantCap: 2})
+	acquire(t, s, "noisy")
+	acquire(t, s, "noisy")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := s.Acquire(ctx, "noisy", 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "a tenant at its cap waits")
+
+	release := acquire(t, s, "quiet")
+	release()
+	release() // releasing twice is harmless
+	acquire(t, s, "quiet")
+}
+
+func TestScheduler_WeightedFairQueueing(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(Fairness{Capacity: 1})
+	hold := acquire(t, s, "hold")
+
+	type admission struct {
+		tenant  string
+		release func()
+	}
+	admitted := make(chan admission)
+	enqueue := func(tenant string, weight float64, n int) {
+		for range n {
+			go func() {
+				release, err := s.Acquire(context.Background(), tenant, weight)
+				if assert.NoError(t, err) {
+					admitted <- admission{tenant, release}
+				}
+			}()
+		}
+		require.Eventually(t, func() bool { return s.waiting(tenant) == n }, time.Second, time.Millisecond)
+	}
+	enqueue("heavy", 2, 6)
+	enqueue("light", 1, 6)
+
+	hold()
+	counts := map[string]int{}
+	for range 9 {
+		a := <-admitted
+		counts[a.tenant]++
+		a.release()
+	}
+	assert.Equal(t, map[string]int{"heavy": 6, "light": 3}, counts, "places follow the 2:1 weights")
+	for range 3 {
+		(<-admitted).release()
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(Fairness{Capacity: 1})
+	hold := acquire(t, s, "hold")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() {
+		_, err := s.Acquire(ctx, "waiter", 1)
+		errc <- err
+	}()
+	require.Eventually(t, func() bool { return s.waiting("waiter") == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-errc, context.Canceled)
+
+	hold()
+	acquire(t, s, "other")()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Zero(t, s.running)
+	assert.Empty(t, s.tenants, "idle tenants are forgotten")
+}
+
+func TestScheduler_Disabled(t *testing.T) {
+	t.Parallel()
+	s := NewScheduler(Fairness{})
+	assert.Nil(t, s)
+	assert.Nil(t, s.WorkerInterceptor())
+	release, err := s.Acquire(context.Background(), "t-1", 1)
+	require.NoError(t, err)
+	release()
+}
+
+func TestTenantOf(t *testing.T) {
+	t.Parallel()
+	type withTenant struct {
+		Tenant domain.TenantContext
+	}
+	type otherTenant struct {
+		Tenant string
+	}
+	acme := domain.TenantContext{TenantID: "acme", Weight: 2}
+
+	tests := []struct {
+		name string
+		args []any
+		want domain.TenantContext
+	}{
+		{"struct", []any{withTenant{Tenant: acme}}, acme},
+		{"pointer", []any{&withTenant{Tenant: acme}}, acme},
+		{"later argument", []any{"x", withTenant{Tenant: acme}}, acme},
+		{"nil pointer", []any{(*withTenant)(nil)}, domain.TenantContext{}},
+		{"tenant of another type", []any{otherTenant{Tenant: "acme"}}, domain.TenantContext{}},
+		{"no arguments", nil, domain.TenantContext{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tenantOf(tt.args))
+		})
+	}
+}
@@ -1,4 +1,5 @@
-// Package queues defines per-queue worker configuration for task-queue partitioning.
+// Package queues defines per-queue worker configuration for task-queue
+// partitioning and fair scheduling of tenants on shared queues.
 package queues
 
 import (
@@ -12,26 +13,42 @@ import (
 
 // QueueConfig holds worker options for a single task queue.
 type QueueConfig struct {
-	Name    string
+	Name string
+	// Base is the shared queue whose work this queue takes: Name itself,
+	// or the shared queue a premium tenant's dedicated queue stands in for.
+	Base    string
 	Options worker.Options
+	// Fairness caps and interleaves tenants on shared queues. Dedicated
+	// queues serve one tenant and leave it zero.
+	Fairness Fairness
 }
 
-// DefaultConfigs returns the standard per-queue worker options.
+// DefaultConfigs returns the standard per-queue worker options, plus
+// dedicated anomaly and exec queues for each premium tenant.
 //
 //   - QueueAnomaly: stateful lifecycle workflows, generous concurrency
 //   - QueueDetect: read-heavy detection, higher concurrency
 //   - QueueExec: restricted writes, tight concurrency
-func DefaultConfigs() map[string]QueueConfig {
-	return map[string]QueueConfig{
+//
+// Tenants share the anomaly and exec queues fairly through the fairness
+// keys their workflows start with, which the server applies before any
+// worker polls a task. Within one worker, Fairness also caps how many of
+// the queue's activity slots one tenant may fill. A premium tenant's queue
+// has the concurrency of the shared queue it stands in for.
+func DefaultConfigs(premium ...string) map[string]QueueConfig {
+	configs := map[string]QueueConfig{
 		versioning.QueueAnomaly: {
 			Name: versioning.QueueAnomaly,
+			Base: versioning.QueueAnomaly,
 			Options: worker.Options{
 				MaxConcurrentActivityExecutionSize:     10,
 				MaxConcurrentWorkflowTaskExecutionSize: 10,
 			},
+			Fairness: Fairness{Capacity: 10, TenantCap: 4},
 		},
 		versioning.QueueDetect: {
 			Name: versioning.QueueDetect,
+			Base: versioning.QueueDetect,
 			Options: worker.Options{
 				MaxConcurrentActivityExecutionSize:     20,
 				MaxConcurrentWorkflowTaskExecutionSize: 5,
@@ -39,12 +56,43 @@ func DefaultConfigs() map[string]QueueConfig {
 		},
 		versioning.QueueExec: {
 			Name: versioning.QueueExec,
+			Base: versioning.QueueExec,
 			Options: worker.Options{
 				MaxConcurrentActivityExecutionSize:     3,
 				MaxConcurrentWorkflowTaskExecutionSize: 1,
 			},
+			Fairness: Fairness{Capacity: 3, TenantCap: 2},
 		},
 	}
+	for _, tenantID := range premium {
+		for _, base := range []string{versioning.QueueAnomaly, versioning.QueueExec} {
+			name := versioning.TenantQueue(base, tenantID)
+			configs[name] = QueueConfig{
+				Name: name,
+				Base: base,
+				Options: worker.Options{
+					MaxConcurrentActivityExecutionSize: configs[base].Options.MaxConcurrentActivityExecutionSize,
+				},
+			}
+		}
+	}
+	return configs
+}
+
+// WithTenantQueues adds to names the dedicated queues of each premium
+// tenant for the shared anomaly and exec queues in names, so a worker that
+// polls a shared queue also polls the premium queues standing in for it.
+func WithTenantQueues(names []string, premium []string) []string {
+	out := append([]string(nil), names...)
+	for _, base := range names {
+		if base != versioning.QueueAnomaly && base != versioning.QueueExec {
+			continue
+		}
+		for _, tenantID := range premium {
+			out = append(out, versioning.TenantQueue(base, tenantID))
+		}
+	}
+	return out
 }
 
 // ParseQueues parses a comma-separated queue list (e.g. "anomaly,exec")
@@ -20,6 +20,34 @@ func TestDefaultConfigs(t *testing.T) {
 	// Exec queue should have tightest concurrency.
 	execCfg := configs[versioning.QueueExec]
 	assert.Equal(t, 3, execCfg.Options.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, execCfg.Options.MaxConcurrentActivityExecutionSize, execCfg.Fairness.Capacity, "no slot beyond the scheduler's capacity")
+	assert.Less(t, execCfg.Fairness.TenantCap, execCfg.Fairness.Capacity, "one tenant cannot take every exec slot")
+	assert.Equal(t, 10, configs[versioning.QueueAnomaly].Fairness.Capacity)
+	assert.Zero(t, configs[versioning.QueueDetect].Fairness)
+}
+
+func TestDefaultConfigs_Premium(t *testing.T) {
+	t.Parallel()
+	configs := DefaultConfigs("acme")
+	assert.Len(t, configs, 5)
+
+	exec := configs["finops-exec-acme"]
+	assert.Equal(t, "finops-exec-acme", exec.Name)
+	assert.Equal(t, versioning.QueueExec, exec.Base)
+	assert.Zero(t, exec.Fairness, "dedicated queues serve one tenant")
+	assert.Equal(t, 3, exec.Options.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, versioning.QueueAnomaly, configs["finops-anomaly-acme"].Base)
+}
+
+func TestWithTenantQueues(t *testing.T) {
+	t.Parallel()
+	got := WithTenantQueues([]string{versioning.QueueAnomaly, versioning.QueueDetect, versioning.QueueExec}, []string{"acme", "globex"})
+	assert.Equal(t, []string{
+		versioning.QueueAnomaly, versioning.QueueDetect, versioning.QueueExec,
+		"finops-anomaly-acme", "finops-anomaly-globex",
+		"finops-exec-acme", "finops-exec-globex",
+	}, got)
+	assert.Equal(t, []string{versioning.QueueDetect}, WithTenantQueues([]string{versioning.QueueDetect}, []string{"acme"}))
 }
 
 func TestParseQueues(t *testing.T) {
@@ -5,6 +5,7 @@ const (
 	// Workflow versions for determinism tracking.
 	AnomalyLifecycleV1 = "anomaly-lifecycle-v1"
 	AnomalyLifecycleV2 = "anomaly-lifecycle-v2" // Phase 6: exec-queue-routing + tenant-context
+	AnomalyLifecycleV3 = "anomaly-lifecycle-v3" // per-action-execution
 	DetectionV1        = "detection-v1"
 	AWSDocSweepV1      = "awsdoc-sweep-v1"
 
@@ -15,3 +16,9 @@ const (
 	QueueDetect  = "finops-detect"
 	QueueExec    = "finops-exec"
 )
+
+// TenantQueue returns the dedicated task queue that stands in for the
+// shared queue base for a premium tenant, e.g. "finops-exec-acme".
+func TenantQueue(base, tenantID string) string {
+	return base + "-" + tenantID
+}
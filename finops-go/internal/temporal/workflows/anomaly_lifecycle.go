@@ -8,10 +8,13 @@ import (
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 
+	"github.com/finops-claw-gang/finops-go/internal/audit"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/observability"
 	"github.com/finops-claw-gang/finops-go/internal/policy"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
+	"github.com/finops-claw-gang/finops-go/internal/verifier"
 )
 
 // UpdateNameApproval is the Temporal Update handler name for HIL.
@@ -20,9 +23,21 @@ const UpdateNameApproval = "approval"
 // QueryNameState is the Temporal Query handler name for reading workflow state.
 const QueryNameState = "state"
 
+// Application error types the approval update is rejected with, so that
+// callers can tell a repeated answer from a malformed one.
+const (
+	ErrTypeApprovalDecided = "ApprovalAlreadyDecided"
+	ErrTypeInvalidApproval = "InvalidApproval"
+)
+
 // HILTimeout is how long the workflow waits for human approval.
 const HILTimeout = 24 * time.Hour
 
+// VerifyDelay is how long after execution the workflow waits before
+// verifying health: the span of metrics compared on each side of the
+// change, plus time for CloudWatch to publish the last datapoints.
+const VerifyDelay = verifier.HealthSpan + 10*time.Minute
+
 // TerminationReason describes why the workflow ended.
 type TerminationReason string
 
@@ -36,7 +51,9 @@ const (
 	ReasonApprovalTimedOut             TerminationReason = "approval_timed_out"
 	ReasonTriageError                  TerminationReason = "triage_error"
 	ReasonPlanError                    TerminationReason = "plan_error"
+	ReasonNoChangeWindow               TerminationReason = "no_change_window"
 	ReasonExecutionError               TerminationReason = "execution_error"
+	ReasonRolledBack                   TerminationReason = "rolled_back"
 	ReasonVerifyError                  TerminationReason = "verify_error"
 )
 
@@ -46,6 +63,22 @@ type WorkflowInput struct {
 	Anomaly     *domain.CostAnomaly  `json:"anomaly"`
 	WindowStart string               `json:"window_start"`
 	WindowEnd   string               `json:"window_end"`
+
+	// OnFailure overrides the policy engine's choice of what to do with the
+	// remaining actions when one fails. Empty means use the policy default.
+	OnFailure domain.FailurePolicy `json:"on_failure,omitempty"`
+
+	// SavingsCheckDays are the days after execution at which savings are
+	// verified. Empty means DefaultSavingsCheckDays.
+	SavingsCheckDays []int `json:"savings_check_days,omitempty"`
+
+	// MonitorDays is how long a "monitor" recommendation is re-verified
+	// daily. Zero means DefaultMonitorDays.
+	MonitorDays int `json:"monitor_days,omitempty"`
+
+	// Resume carries the state into a continued-as-new monitoring run.
+	// Callers starting a new lifecycle leave it nil.
+	Resume *domain.FinOpsState `json:"resume,omitempty"`
 }
 
 // WorkflowResult is the output of the anomaly lifecycle workflow.
@@ -56,16 +89,24 @@ type WorkflowResult struct {
 	Reason TerminationReason  `json:"reason"`
 }
 
+// OutcomeReason reports the termination reason to the worker metrics
+// interceptor (observability.OutcomeReason).
+func (r WorkflowResult) OutcomeReason() string { return string(r.Reason) }
+
 // AnomalyLifecycleWorkflow is the main Temporal workflow that replaces
 // Python's LangGraph StateGraph. The flow is:
 //
-//	watcher -> triage -> analyst -> hil_gate -> executor -> verifier -> END
+//	watcher -> triage -> analyst -> hil_gate -> scheduled -> executor -> verifier -> verifying_savings -> monitoring -> END
 //
-// Each step may short-circuit to END via early returns.
+// Each step may short-circuit to END via early returns. Monitoring spans
+// several runs linked by continue-as-new; a resumed run skips straight to it.
 // Policy runs in-workflow (pure function, no I/O, determinism-safe).
 func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (WorkflowResult, error) {
 	logger := workflow.GetLogger(ctx)
 	state := domain.NewFinOpsState(input.Tenant)
+	if input.Resume != nil {
+		state = *input.Resume
+	}
 
 	// Register Query handler before any blocking call (determinism-safe).
 	if err := workflow.SetQueryHandler(ctx, QueryNameState, func() (WorkflowResult, error) {
@@ -74,8 +115,22 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		return WorkflowResult{}, fmt.Errorf("register state query: %w", err)
 	}
 
+	// end projects the final state to the history store and returns it.
+	end := func(reason TerminationReason) (WorkflowResult, error) {
+		projectHistory(ctx, &state, reason)
+		return WorkflowResult{State: state, Reason: reason}, nil
+	}
+
+	if input.Resume != nil && state.Monitoring != nil && state.Verification != nil && input.Anomaly != nil {
+		return monitorPostChange(ctx, input, &state)
+	}
+
+	// Premium tenants' activities run on their dedicated queues.
+	anomalyQueue, tenantExecQueue := tenantQueues(ctx, input.Tenant)
+
 	// Activity options: generous timeout, no retry by default (safety first).
 	actOpts := workflow.ActivityOptions{
+		TaskQueue:           anomalyQueue,
 		StartToCloseTimeout: 2 * time.Minute,
 		RetryPolicy: &temporal.RetryPolicy{
 			MaximumAttempts: 1,
@@ -90,9 +145,10 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 	if input.Anomaly == nil {
 		logger.Info("no anomaly provided, exiting")
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonNoAnomaly}, nil
+		return end(ReasonNoAnomaly)
 	}
 	state.Anomaly = input.Anomaly
+	upsertSearchAttributes(ctx, &state)
 
 	// ------------------------------------------------------------------
 	// Triage: classify the anomaly
@@ -109,16 +165,17 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		errMsg := fmt.Sprintf("triage failed: %v", err)
 		state.Error = &errMsg
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonTriageError}, nil
+		return end(ReasonTriageError)
 	}
 	state.Triage = &triageOut.Result
+	projectHistory(ctx, &state, "")
 	logger.Info("triage complete", "category", triageOut.Result.Category, "confidence", triageOut.Result.Confidence)
 
 	// Early exit: expected growth with high confidence
 	if triageOut.Result.Category == domain.CategoryExpectedGrowth && triageOut.Result.Confidence >= 0.85 {
 		logger.Info("expected growth with high confidence, exiting early")
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonExpectedGrowthHighConfidence}, nil
+		return end(ReasonExpectedGrowthHighConfidence)
 	}
 
 	// ------------------------------------------------------------------
@@ -137,16 +194,23 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		errMsg := fmt.Sprintf("plan actions failed: %v", err)
 		state.Error = &errMsg
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonPlanError}, nil
+		return end(ReasonPlanError)
 	}
 	state.Analysis = &planOut.Result
+	projectHistory(ctx, &state, "")
 	logger.Info("analysis complete", "actions", len(planOut.Result.RecommendedActions))
 
 	// Early exit: no recommended actions
 	if len(planOut.Result.RecommendedActions) == 0 {
 		logger.Info("no actions recommended, exiting")
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonNoActions}, nil
+		return end(ReasonNoActions)
+	}
+
+	// Record the estimates now, so plans that are denied, time out or fail
+	// before execution still show in the savings ledger.
+	if workflow.GetVersion(ctx, "savings-at-plan", workflow.DefaultVersion, 1) == 1 {
+		recordSavings(ctx, input, &state, nil)
 	}
 
 	// ------------------------------------------------------------------
@@ -156,6 +220,7 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 	pe := policy.NewPolicyEngine()
 	decision := pe.Decide(planOut.Result.RecommendedActions)
 	state.ApprovalDetails = decision.Details
+	auditDecision(ctx, &state, audit.ActionPolicyDecision, string(decision.Approval), decision.Details)
 
 	switch decision.Approval {
 	case domain.ApprovalAutoApproved:
@@ -166,15 +231,19 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		logger.Info("denied by policy", "details", decision.Details)
 		state.Approval = domain.ApprovalDenied
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonPolicyDenied}, nil
+		return end(ReasonPolicyDenied)
 
 	case domain.ApprovalPending:
 		logger.Info("pending human approval", "details", decision.Details)
 		state.Approval = domain.ApprovalPending
-		approval, err := waitForApproval(ctx)
+		projectHistory(ctx, &state, "")
+		approval, decidedCtx, err := waitForApproval(ctx, &state)
 		if err != nil {
 			return WorkflowResult{}, fmt.Errorf("hil gate: %w", err)
 		}
+		// What follows runs on behalf of the approver: continue their trace.
+		ctx = observability.ContinueTrace(ctx, decidedCtx)
+		actCtx = workflow.WithActivityOptions(ctx, actOpts)
 
 		switch approval {
 		case domain.ApprovalApproved:
@@ -182,47 +251,112 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		case domain.ApprovalDenied:
 			state.Approval = domain.ApprovalDenied
 			state.ShouldTerminate = true
-			return WorkflowResult{State: state, Reason: ReasonHumanDenied}, nil
+			return end(ReasonHumanDenied)
 		case domain.ApprovalTimedOut:
 			state.Approval = domain.ApprovalTimedOut
 			state.ShouldTerminate = true
-			return WorkflowResult{State: state, Reason: ReasonApprovalTimedOut}, nil
+			auditDecision(ctx, &state, audit.ActionApprovalTimeout, string(domain.ApprovalTimedOut), "no decision within the approval timeout")
+			return end(ReasonApprovalTimedOut)
 		}
 	}
+	projectHistory(ctx, &state, "")
 
 	// ------------------------------------------------------------------
-	// Executor: run approved actions (no retries for safety)
-	// Route to QueueExec for write-permission isolation (V2+).
+	// Scheduled: hold approved actions until the change calendar allows them.
+	// ------------------------------------------------------------------
+	if workflow.GetVersion(ctx, "change-window", workflow.DefaultVersion, 1) == 1 {
+		if reason, msg := waitForChangeWindow(ctx, actCtx, input.Tenant, planOut.Result.RecommendedActions, &state); reason != "" {
+			state.Error = &msg
+			state.ShouldTerminate = true
+			return end(reason)
+		}
+	}
+
+	// ------------------------------------------------------------------
+	// Executor: run approved actions
+	// Route to QueueExec for write-permission isolation (V2+), or to the
+	// premium tenant's exec queue.
 	// ------------------------------------------------------------------
 	state.CurrentPhase = "executor"
-	execCtx := actCtx
+	upsertSearchAttributes(ctx, &state)
+	execQueue := ""
 	v := workflow.GetVersion(ctx, "exec-queue-routing", workflow.DefaultVersion, 1)
 	if v == 1 {
-		execCtx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
-			TaskQueue:           versioning.QueueExec,
+		execQueue = versioning.QueueExec
+		if tenantExecQueue != "" {
+			execQueue = tenantExecQueue
+		}
+	}
+
+	perAction := workflow.GetVersion(ctx, "per-action-execution", workflow.DefaultVersion, 1)
+	if perAction == 1 {
+		onFailure := input.OnFailure
+		if !onFailure.Valid() {
+			onFailure = pe.FailurePolicy(planOut.Result.RecommendedActions)
+		}
+		// Each action is idempotent, so transient failures are retried.
+		// Safety-gate refusals come back non-retryable.
+		execCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           execQueue,
 			StartToCloseTimeout: 2 * time.Minute,
-			RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+			RetryPolicy: &temporal.RetryPolicy{
+				InitialInterval: 5 * time.Second,
+				MaximumAttempts: ExecuteActionMaxAttempts,
+			},
 		})
+		outcome := executeActions(ctx, execCtx, input.Tenant, state.Approval, planOut.Result.RecommendedActions, onFailure, &state)
+		if outcome.reason != "" {
+			state.Error = &outcome.message
+			state.ShouldTerminate = true
+			return end(outcome.reason)
+		}
+		logger.Info("execution complete", "results", len(state.Executions), "failed", outcome.failed)
+	} else {
+		// Legacy path: one batch activity, no retries for safety.
+		execCtx := actCtx
+		if execQueue != "" {
+			execCtx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+				TaskQueue:           execQueue,
+				StartToCloseTimeout: 2 * time.Minute,
+				RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+			})
+		}
+		var execOut activities.ExecuteActionsOutput
+		err = workflow.ExecuteActivity(execCtx, "ExecuteActions", activities.ExecuteActionsInput{
+			Tenant:   input.Tenant,
+			Approval: state.Approval,
+			Actions:  planOut.Result.RecommendedActions,
+		}).Get(ctx, &execOut)
+		if err != nil {
+			errMsg := fmt.Sprintf("execution failed: %v", err)
+			state.Error = &errMsg
+			state.ShouldTerminate = true
+			return end(ReasonExecutionError)
+		}
+		state.Executions = execOut.Results
+		logger.Info("execution complete", "results", len(execOut.Results))
 	}
-	var execOut activities.ExecuteActionsOutput
-	err = workflow.ExecuteActivity(execCtx, "ExecuteActions", activities.ExecuteActionsInput{
-		Tenant:   input.Tenant,
-		Approval: state.Approval,
-		Actions:  planOut.Result.RecommendedActions,
-	}).Get(ctx, &execOut)
-	if err != nil {
-		errMsg := fmt.Sprintf("execution failed: %v", err)
-		state.Error = &errMsg
-		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonExecutionError}, nil
-	}
-	state.Executions = execOut.Results
-	logger.Info("execution complete", "results", len(execOut.Results))
+
+	recordSavings(ctx, input, &state, nil)
+	projectHistory(ctx, &state, "")
 
 	// ------------------------------------------------------------------
 	// Verifier: check outcomes
 	// ------------------------------------------------------------------
 	state.CurrentPhase = "verifier"
+	upsertSearchAttributes(ctx, &state)
+	executionDone := workflow.Now(ctx)
+	changed, executedAt := changedResources(planOut.Result.RecommendedActions, state.Executions)
+	if len(changed) > 0 && workflow.GetVersion(ctx, "verify-health-delay", workflow.DefaultVersion, 1) == 1 {
+		// Verifying at once would compare against an empty after window.
+		logger.Info("waiting for post-change metrics", "wait", VerifyDelay)
+		if err := workflow.Sleep(ctx, VerifyDelay); err != nil {
+			errMsg := fmt.Sprintf("verification wait interrupted: %v", err)
+			state.Error = &errMsg
+			state.ShouldTerminate = true
+			return end(ReasonVerifyError)
+		}
+	}
 	var verifyOut activities.VerifyOutcomeOutput
 	err = workflow.ExecuteActivity(actCtx, "VerifyOutcome", activities.VerifyOutcomeInput{
 		Tenant:      input.Tenant,
@@ -230,37 +364,69 @@ func AnomalyLifecycleWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		AccountID:   input.Anomaly.AccountID,
 		WindowStart: input.WindowStart,
 		WindowEnd:   input.WindowEnd,
+		Resources:   changed,
+		ExecutedAt:  executedAt,
 	}).Get(ctx, &verifyOut)
 	if err != nil {
 		errMsg := fmt.Sprintf("verification failed: %v", err)
 		state.Error = &errMsg
 		state.ShouldTerminate = true
-		return WorkflowResult{State: state, Reason: ReasonVerifyError}, nil
+		return end(ReasonVerifyError)
 	}
 	state.Verification = &verifyOut.Result
+
+	// ------------------------------------------------------------------
+	// Savings: once health checks pass, compare spend with the baseline
+	// on durable timers days after execution.
+	// ------------------------------------------------------------------
+	if workflow.GetVersion(ctx, "delayed-savings-verification", workflow.DefaultVersion, 1) == 1 {
+		rec := verifyOut.Result.Recommendation
+		if (rec == domain.RecommendClose || rec == domain.RecommendMonitor) && len(changed) > 0 {
+			if reason, msg := verifyDelayedSavings(ctx, actCtx, input, planOut.Result.RecommendedActions, executionDone, &state); reason != "" {
+				state.Error = &msg
+				state.ShouldTerminate = true
+				return end(reason)
+			}
+		}
+	}
+
+	// ------------------------------------------------------------------
+	// Monitoring: savings are not yet confirmed, so keep re-verifying
+	// daily for the monitoring period.
+	// ------------------------------------------------------------------
+	if state.Verification.Recommendation == domain.RecommendMonitor && len(changed) > 0 &&
+		workflow.GetVersion(ctx, "post-change-monitoring", workflow.DefaultVersion, 1) == 1 {
+		return startMonitoring(ctx, input, planOut.Result.RecommendedActions, changed, executionDone, &state)
+	}
 	state.CurrentPhase = "completed"
 	state.ShouldTerminate = true
-	logger.Info("workflow completed", "recommendation", verifyOut.Result.Recommendation)
+	logger.Info("workflow completed", "recommendation", state.Verification.Recommendation)
 
-	return WorkflowResult{State: state, Reason: ReasonCompleted}, nil
+	return end(ReasonCompleted)
 }
 
 // waitForApproval registers a Temporal Update handler and waits for either
-// human approval/denial or a 24-hour timeout, whichever comes first.
-func waitForApproval(ctx workflow.Context) (domain.ApprovalStatus, error) {
+// human approval/denial or a 24-hour timeout, whichever comes first. The
+// answering caller is recorded in state.Approver. The returned context is
+// the update handler's, carrying the approver's trace and request ID; on
+// timeout it is ctx.
+func waitForApproval(ctx workflow.Context, state *domain.FinOpsState) (domain.ApprovalStatus, workflow.Context, error) {
 	logger := workflow.GetLogger(ctx)
 
 	var result domain.ApprovalStatus
 	responded := false
+	decidedCtx := ctx
 
 	err := workflow.SetUpdateHandlerWithOptions(
 		ctx,
 		UpdateNameApproval,
 		func(ctx workflow.Context, resp activities.ApprovalResponse) (string, error) {
 			if responded {
-				return "", fmt.Errorf("approval already received")
+				return "", temporal.NewApplicationError("approval already received", ErrTypeApprovalDecided)
 			}
 			responded = true
+			decidedCtx = ctx
+			state.Approver = &domain.Approver{By: resp.By, Subject: resp.Subject, Email: resp.Email, Issuer: resp.Issuer}
 			if resp.Approved {
 				result = domain.ApprovalApproved
 				logger.Info("human approved", "by", resp.By)
@@ -273,17 +439,17 @@ func waitForApproval(ctx workflow.Context) (domain.ApprovalStatus, error) {
 		workflow.UpdateHandlerOptions{
 			Validator: func(resp activities.ApprovalResponse) error {
 				if resp.By == "" {
-					return fmt.Errorf("approval 'by' field is required")
+					return temporal.NewApplicationError("approval 'by' field is required", ErrTypeInvalidApproval)
 				}
 				if responded {
-					return fmt.Errorf("approval already received")
+					return temporal.NewApplicationError("approval already received", ErrTypeApprovalDecided)
 				}
 				return nil
 			},
 		},
 	)
 	if err != nil {
-		return "", fmt.Errorf("register approval handler: %w", err)
+		return "", ctx, fmt.Errorf("register approval handler: %w", err)
 	}
 
 	// Race: approval update vs 24h timeout
@@ -304,5 +470,5 @@ func waitForApproval(ctx workflow.Context) (domain.ApprovalStatus, error) {
 		selector.Select(ctx)
 	}
 
-	return result, nil
+	return result, decidedCtx, nil
 }
@@ -1,16 +1,23 @@
 package workflows_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
 
+	"github.com/finops-claw-gang/finops-go/internal/audit"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+	"github.com/finops-claw-gang/finops-go/internal/verifier"
 )
 
 type AnomalyLifecycleSuite struct {
@@ -65,11 +72,12 @@ func (s *AnomalyLifecycleSuite) TestHappyPath_AutoApproved() {
 		},
 	}, nil)
 
-	s.env.OnActivity("ExecuteActions", testAnyCtx, testAnyInput).Return(activities.ExecuteActionsOutput{
-		Results: []domain.ExecutionResult{{
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(activities.ExecuteActionOutput{
+		Result: domain.ExecutionResult{
 			ActionID: action.ActionID,
 			Success:  true,
-		}},
+			Outcome:  domain.OutcomeSucceeded,
+		},
 	}, nil)
 
 	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
@@ -142,6 +150,105 @@ func (s *AnomalyLifecycleSuite) TestNoActionsEarlyExit() {
 	s.Equal(workflows.ReasonNoActions, result.Reason)
 }
 
+// AnomalyHistory_ProjectsPhases: the history store is updated after each
+// phase and once more with the termination reason.
+func (s *AnomalyLifecycleSuite) TestAnomalyHistory_ProjectsPhases() {
+	input := s.baseInput()
+	s.mockThroughPlan(domain.RiskCritical, 1)
+
+	var projected []activities.RecordHistoryInput
+	s.env.OnActivity("RecordHistory", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.RecordHistoryInput) error {
+			projected = append(projected, in)
+			return nil
+		}).Times(3)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	s.Require().Len(projected, 3)
+	s.Equal("triage", projected[0].State.CurrentPhase)
+	s.NotNil(projected[0].State.Triage)
+	s.Nil(projected[0].State.Analysis)
+	s.Equal("analyst", projected[1].State.CurrentPhase)
+	s.Empty(projected[1].Reason)
+	s.Equal(string(workflows.ReasonPolicyDenied), projected[2].Reason)
+	s.Equal(domain.ApprovalDenied, projected[2].State.Approval)
+	s.NotEmpty(projected[2].WorkflowID)
+}
+
+func (s *AnomalyLifecycleSuite) TestSearchAttributes_TrackPhases() {
+	input := s.baseInput()
+	s.mockThroughPlan(domain.RiskMedium, 1)
+
+	var upserts []temporal.SearchAttributes
+	s.env.OnUpsertTypedSearchAttributes(mock.Anything).Run(func(args mock.Arguments) {
+		upserts = append(upserts, args.Get(0).(temporal.SearchAttributes))
+	}).Return(nil)
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.UpdateWorkflowNoRejection(workflows.UpdateNameApproval, "deny-1", s.T(),
+			activities.ApprovalResponse{Approved: false, By: "ops-engineer"})
+	}, time.Second)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	s.Require().NotEmpty(upserts)
+	first := upserts[0]
+	phase, _ := first.GetKeyword(workflows.SearchAttrPhase)
+	s.Equal("watcher", phase)
+	_, ok := first.GetKeyword(workflows.SearchAttrSeverity)
+	s.False(ok, "severity is unknown before triage")
+
+	var pending *temporal.SearchAttributes
+	for i := range upserts {
+		if v, _ := upserts[i].GetKeyword(workflows.SearchAttrApprovalStatus); v == string(domain.ApprovalPending) {
+			pending = &upserts[i]
+		}
+	}
+	s.Require().NotNil(pending, "pending approval was never published")
+	tenant, _ := pending.GetKeyword(workflows.SearchAttrTenantID)
+	s.Equal("tenant-1", tenant)
+	service, _ := pending.GetKeyword(workflows.SearchAttrService)
+	s.Equal("EC2", service)
+	phase, _ = pending.GetKeyword(workflows.SearchAttrPhase)
+	s.Equal("hil_gate", phase)
+	severity, ok := pending.GetKeyword(workflows.SearchAttrSeverity)
+	s.True(ok)
+	s.NotEmpty(severity)
+	delta, _ := pending.GetFloat64(workflows.SearchAttrDeltaDollars)
+	s.Equal(750.0, delta)
+
+	last, _ := upserts[len(upserts)-1].GetKeyword(workflows.SearchAttrApprovalStatus)
+	s.Equal(string(domain.ApprovalDenied), last)
+}
+
+func (s *AnomalyLifecycleSuite) TestAudit_PolicyDecisionAndTimeout() {
+	input := s.baseInput()
+	s.mockThroughPlan(domain.RiskMedium, 1)
+
+	var events []audit.Event
+	s.env.OnActivity("RecordAudit", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, e audit.Event) error {
+			events = append(events, e)
+			return nil
+		}).Times(2)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	s.Require().Len(events, 2)
+	s.Equal(audit.ActionPolicyDecision, events[0].Action)
+	s.Equal(string(domain.ApprovalPending), events[0].Outcome)
+	s.Equal(audit.SystemActor, events[0].Actor)
+	s.Equal(audit.SourceWorkflow, events[0].Source)
+	s.Equal("tenant-1", events[0].TenantID)
+	s.NotEmpty(events[0].WorkflowID)
+	s.Equal("EC2/123456789012", events[0].Resource)
+	s.Equal(audit.ActionApprovalTimeout, events[1].Action)
+}
+
 // 4. PolicyDenied: critical risk
 func (s *AnomalyLifecycleSuite) TestPolicyDenied() {
 	input := s.baseInput()
@@ -190,8 +297,8 @@ func (s *AnomalyLifecycleSuite) TestHIL_Approved() {
 		},
 	}, nil)
 
-	s.env.OnActivity("ExecuteActions", testAnyCtx, testAnyInput).Return(activities.ExecuteActionsOutput{
-		Results: []domain.ExecutionResult{{ActionID: medAction.ActionID, Success: true}},
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(activities.ExecuteActionOutput{
+		Result: domain.ExecutionResult{ActionID: medAction.ActionID, Success: true},
 	}, nil)
 
 	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
@@ -246,6 +353,9 @@ func (s *AnomalyLifecycleSuite) TestHIL_Denied() {
 				Approved: false,
 				By:       "ops-lead",
 				Reason:   "not safe right now",
+				Subject:  "ops-lead",
+				Email:    "lead@example.com",
+				Issuer:   "https://issuer.example",
 			})
 	}, 1*time.Second)
 
@@ -257,6 +367,9 @@ func (s *AnomalyLifecycleSuite) TestHIL_Denied() {
 	s.NoError(s.env.GetWorkflowResult(&result))
 	s.Equal(workflows.ReasonHumanDenied, result.Reason)
 	s.Equal(domain.ApprovalDenied, result.State.Approval)
+	s.Equal(&domain.Approver{
+		By: "ops-lead", Subject: "ops-lead", Email: "lead@example.com", Issuer: "https://issuer.example",
+	}, result.State.Approver)
 }
 
 // 7. HIL_Timeout: no response in 24h
@@ -325,8 +438,36 @@ func (s *AnomalyLifecycleSuite) TestExecutionActivityError() {
 		},
 	}, nil)
 
-	s.env.OnActivity("ExecuteActions", testAnyCtx, testAnyInput).Return(
-		activities.ExecuteActionsOutput{}, fmt.Errorf("executor safety gate failure"))
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		activities.ExecuteActionOutput{}, fmt.Errorf("executor safety gate failure"))
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonExecutionError, result.Reason)
+	s.NotNil(result.State.Error)
+}
+
+// PartialFailure_SkipRemaining: second of three low-risk actions fails;
+// the first result is kept and the third is skipped.
+func (s *AnomalyLifecycleSuite) TestPartialFailure_SkipRemaining() {
+	input := s.baseInput()
+	actions := s.mockThroughPlan(domain.RiskLow, 3)
+
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[0].ActionID
+	})).Return(func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+		return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+			ActionID: in.Action.ActionID, IdempotencyKey: in.IdempotencyKey,
+			Success: true, Outcome: domain.OutcomeSucceeded,
+		}}, nil
+	}).Once()
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[1].ActionID
+	})).Return(activities.ExecuteActionOutput{}, fmt.Errorf("throttled")).Times(workflows.ExecuteActionMaxAttempts)
 
 	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
 	s.True(s.env.IsWorkflowCompleted())
@@ -336,9 +477,483 @@ func (s *AnomalyLifecycleSuite) TestExecutionActivityError() {
 	s.NoError(s.env.GetWorkflowResult(&result))
 	s.Equal(workflows.ReasonExecutionError, result.Reason)
 	s.NotNil(result.State.Error)
+	s.Require().Len(result.State.Executions, 3)
+	s.Equal(domain.OutcomeSucceeded, result.State.Executions[0].Outcome)
+	s.Equal(domain.OutcomeFailed, result.State.Executions[1].Outcome)
+	s.Equal(domain.OutcomeSkipped, result.State.Executions[2].Outcome)
+	for i, r := range result.State.Executions {
+		s.Equal("default-test-workflow-id/"+actions[i].ActionID, r.IdempotencyKey)
+	}
+}
+
+// PartialFailure_Rollback: OnFailure=rollback undoes the completed action.
+func (s *AnomalyLifecycleSuite) TestPartialFailure_Rollback() {
+	input := s.baseInput()
+	input.OnFailure = domain.FailureRollback
+	actions := s.mockThroughPlan(domain.RiskLow, 2)
+
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[0].ActionID
+	})).Return(func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+		return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+			ActionID: in.Action.ActionID, IdempotencyKey: in.IdempotencyKey,
+			Success: true, Outcome: domain.OutcomeSucceeded,
+		}}, nil
+	}).Once()
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[1].ActionID
+	})).Return(activities.ExecuteActionOutput{}, temporal.NewNonRetryableApplicationError("refused", "ExecutorRefused", nil)).Once()
+	s.env.OnActivity("RollbackAction", testAnyCtx, mock.MatchedBy(func(in activities.RollbackActionInput) bool {
+		return in.Action.ActionID == actions[0].ActionID && in.IdempotencyKey != ""
+	})).Return(activities.RollbackActionOutput{
+		Result: domain.ExecutionResult{ActionID: actions[0].ActionID, Outcome: domain.OutcomeRolledBack},
+	}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonRolledBack, result.Reason)
+	s.Require().Len(result.State.Executions, 2)
+	s.Equal(domain.OutcomeRolledBack, result.State.Executions[0].Outcome)
+	s.Equal(domain.OutcomeFailed, result.State.Executions[1].Outcome)
+}
+
+// PartialFailure_RollbackSkipsAlreadyApplied: only the action this run
+// applied is rolled back; one that was already in place is left alone.
+func (s *AnomalyLifecycleSuite) TestPartialFailure_RollbackSkipsAlreadyApplied() {
+	input := s.baseInput()
+	input.OnFailure = domain.FailureRollback
+	actions := s.mockThroughPlan(domain.RiskLow, 3)
+
+	for i, outcome := range []domain.ExecutionOutcome{domain.OutcomeSucceeded, domain.OutcomeAlreadyApplied} {
+		s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+			return in.Action.ActionID == actions[i].ActionID
+		})).Return(func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, IdempotencyKey: in.IdempotencyKey,
+				Success: true, Outcome: outcome, RollbackAvailable: outcome == domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+	}
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[2].ActionID
+	})).Return(activities.ExecuteActionOutput{}, temporal.NewNonRetryableApplicationError("refused", "ExecutorRefused", nil)).Once()
+	s.env.OnActivity("RollbackAction", testAnyCtx, mock.MatchedBy(func(in activities.RollbackActionInput) bool {
+		return in.Action.ActionID == actions[0].ActionID
+	})).Return(activities.RollbackActionOutput{
+		Result: domain.ExecutionResult{ActionID: actions[0].ActionID, Outcome: domain.OutcomeRolledBack},
+	}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonRolledBack, result.Reason)
+	s.Require().Len(result.State.Executions, 3)
+	s.Equal(domain.OutcomeRolledBack, result.State.Executions[0].Outcome)
+	s.Equal(domain.OutcomeAlreadyApplied, result.State.Executions[1].Outcome)
+	s.Equal(domain.OutcomeFailed, result.State.Executions[2].Outcome)
+	s.env.AssertActivityNumberOfCalls(s.T(), "RollbackAction", 1)
+}
+
+// PartialFailure_Continue: OnFailure=continue runs every action and still verifies.
+func (s *AnomalyLifecycleSuite) TestPartialFailure_Continue() {
+	input := s.baseInput()
+	input.OnFailure = domain.FailureContinue
+	actions := s.mockExecutedTargets(2, 0)
+
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[0].ActionID
+	})).Return(activities.ExecuteActionOutput{}, temporal.NewNonRetryableApplicationError("refused", "ExecutorRefused", nil)).Once()
+	s.env.OnActivity("ExecuteAction", testAnyCtx, mock.MatchedBy(func(in activities.ExecuteActionInput) bool {
+		return in.Action.ActionID == actions[1].ActionID
+	})).Return(activities.ExecuteActionOutput{
+		Result: domain.ExecutionResult{
+			ActionID: actions[1].ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			ExecutedAt: "2026-02-16T12:00:00Z",
+		},
+	}, nil).Once()
+	// Only the action that actually changed something is health-checked.
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, mock.MatchedBy(func(in activities.VerifyOutcomeInput) bool {
+		return len(in.Resources) == 1 && in.Resources[0] == actions[1].TargetResource &&
+			in.ExecutedAt == "2026-02-16T12:00:00Z"
+	})).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{Recommendation: domain.RecommendMonitor},
+	}, nil).Once()
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(activities.VerifySavingsOutput{
+		Check: domain.SavingsVerification{Recommendation: domain.RecommendClose},
+	}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Require().Len(result.State.Executions, 2)
+	s.Equal(domain.OutcomeFailed, result.State.Executions[0].Outcome)
+	s.Equal(domain.OutcomeSucceeded, result.State.Executions[1].Outcome)
+}
+
+// ChangeWindow_Holds: approved actions wait on a durable timer until the
+// next change window, exposing the scheduled time via the state query.
+func (s *AnomalyLifecycleSuite) TestChangeWindow_Holds() {
+	input := s.baseInput()
+	s.mockThroughPlan(domain.RiskLow, 1)
+
+	windowAt := s.env.Now().Add(6 * time.Hour).UTC().Truncate(time.Second)
+	s.env.OnActivity("NextChangeWindow", testAnyCtx, testAnyInput).Return(activities.NextChangeWindowOutput{
+		At: windowAt.Format(time.RFC3339),
+	}, nil).Once()
+
+	var executedAt time.Time
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			executedAt = s.env.Now()
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{Recommendation: domain.RecommendClose},
+	}, nil)
+
+	s.env.RegisterDelayedCallback(func() {
+		val, err := s.env.QueryWorkflow(workflows.QueryNameState)
+		s.Require().NoError(err)
+		var res workflows.WorkflowResult
+		s.Require().NoError(val.Get(&res))
+		s.Equal("scheduled", res.State.CurrentPhase)
+		s.Equal(windowAt.Format(time.RFC3339), res.State.ScheduledFor)
+		s.Empty(res.State.Executions)
+	}, time.Hour)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.False(executedAt.Before(windowAt), "executed at %s before window %s", executedAt, windowAt)
+}
+
+// ChangeWindow_None: no window within the horizon terminates without executing.
+func (s *AnomalyLifecycleSuite) TestChangeWindow_None() {
+	input := s.baseInput()
+	s.mockThroughPlan(domain.RiskLow, 1)
+	s.env.OnActivity("NextChangeWindow", testAnyCtx, testAnyInput).Return(activities.NextChangeWindowOutput{}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonNoChangeWindow, result.Reason)
+	s.NotNil(result.State.Error)
+	s.Empty(result.State.Executions)
+}
+
+// SavingsVerification_ClosesOnLaterCheck: savings are checked on durable
+// timers after execution and the result follows the latest check.
+func (s *AnomalyLifecycleSuite) TestSavingsVerification_ClosesOnLaterCheck() {
+	input := s.baseInput()
+	actions := s.mockExecutedTargets(2, 300)
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendClose},
+	}, nil).Once()
+
+	var execDone time.Time
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			execDone = s.env.Now()
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Times(len(actions))
+
+	var checkedDays []int
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			s.Equal(600.0, in.ExpectedSavingsMonthly)
+			s.False(s.env.Now().Before(execDone.Add(time.Duration(in.DaysAfter)*24*time.Hour)),
+				"day %d check ran early at %s", in.DaysAfter, s.env.Now())
+			checkedDays = append(checkedDays, in.DaysAfter)
+			if in.DaysAfter == 3 {
+				return activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+					DaysAfter: 3, SavingsDaily: 5, CILow: -2, CIHigh: 12, Recommendation: domain.RecommendMonitor,
+				}}, nil
+			}
+			return activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+				DaysAfter: in.DaysAfter, SavingsDaily: 18, CILow: 12, CIHigh: 24, Significant: true,
+				Recommendation: domain.RecommendClose,
+			}}, nil
+		}).Twice()
+
+	s.env.RegisterDelayedCallback(func() {
+		val, err := s.env.QueryWorkflow(workflows.QueryNameState)
+		s.Require().NoError(err)
+		var res workflows.WorkflowResult
+		s.Require().NoError(val.Get(&res))
+		s.Equal("verifying_savings", res.State.CurrentPhase)
+		s.Equal(domain.RecommendMonitor, res.State.Verification.Recommendation)
+	}, 24*time.Hour)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal([]int{3, 7}, checkedDays)
+	v := result.State.Verification
+	s.Require().NotNil(v)
+	s.Equal(domain.RecommendClose, v.Recommendation)
+	s.True(v.CostReductionObserved)
+	s.Equal(18.0, v.ObservedSavingsDaily)
+	s.Len(v.SavingsChecks, 2)
+}
+
+// VerifyOutcome_WaitsForAfterWindow: health is verified only once a full
+// health span of metrics exists after the change.
+func (s *AnomalyLifecycleSuite) TestVerifyOutcome_WaitsForAfterWindow() {
+	input := s.baseInput()
+	actions := s.mockExecutedTargets(1, 0)
+
+	var execDone time.Time
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			execDone = s.env.Now()
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+				ExecutedAt: execDone.UTC().Format(time.RFC3339),
+			}}, nil
+		}).Times(len(actions))
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifyOutcomeInput) (activities.VerifyOutcomeOutput, error) {
+			executedAt, err := time.Parse(time.RFC3339, in.ExecutedAt)
+			s.Require().NoError(err)
+			s.False(s.env.Now().Before(executedAt.Add(verifier.HealthSpan)),
+				"verified at %s, before the after window [%s, +%s) filled", s.env.Now(), executedAt, verifier.HealthSpan)
+			return activities.VerifyOutcomeOutput{
+				Result: domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendMonitor},
+			}, nil
+		}).Once()
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(activities.VerifySavingsOutput{
+		Check: domain.SavingsVerification{Recommendation: domain.RecommendClose},
+	}, nil)
+
+	s.env.RegisterDelayedCallback(func() {
+		val, err := s.env.QueryWorkflow(workflows.QueryNameState)
+		s.Require().NoError(err)
+		var res workflows.WorkflowResult
+		s.Require().NoError(val.Get(&res))
+		s.Equal("verifier", res.State.CurrentPhase)
+		s.Nil(res.State.Verification, "verification waits for post-change metrics")
+	}, verifier.HealthSpan/2)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.env.AssertActivityNumberOfCalls(s.T(), "VerifyOutcome", 1)
+}
+
+// SavingsLedger_Records: the ledger is written when the plan is produced,
+// after execution and after each savings check, and a failed write does not
+// stop the lifecycle.
+func (s *AnomalyLifecycleSuite) TestSavingsLedger_Records() {
+	input := s.baseInput()
+	input.SavingsCheckDays = []int{3}
+	s.mockExecutedTargets(1, 150)
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendClose},
+	}, nil).Once()
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+		DaysAfter: 3, SavingsDaily: 5, CILow: 2, CIHigh: 8, Significant: true, Recommendation: domain.RecommendClose,
+	}}, nil).Once()
+
+	var recorded []activities.RecordSavingsInput
+	s.env.OnActivity("RecordSavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.RecordSavingsInput) (activities.RecordSavingsOutput, error) {
+			recorded = append(recorded, in)
+			if in.Check == nil {
+				return activities.RecordSavingsOutput{}, temporal.NewNonRetryableApplicationError("disk full", "LedgerError", nil)
+			}
+			return activities.RecordSavingsOutput{Entries: len(in.Actions)}, nil
+		}).Times(3)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Require().Len(recorded, 3)
+	s.Nil(recorded[0].Check)
+	s.Empty(recorded[0].Executions)
+	s.Len(recorded[0].Actions, 1)
+	s.Equal(150.0, recorded[0].Actions[0].EstimatedSavingsMonthly)
+	s.Nil(recorded[1].Check)
+	s.Len(recorded[1].Executions, 1)
+	s.Equal("EC2", recorded[1].Anomaly.Service)
+	s.NotEmpty(recorded[1].WorkflowID)
+	s.Require().NotNil(recorded[2].Check)
+	s.Equal(domain.RecommendClose, recorded[2].Check.Recommendation)
+}
+
+// SavingsVerification_EscalatesEarly: a significant spend increase stops
+// further checks.
+func (s *AnomalyLifecycleSuite) TestSavingsVerification_EscalatesEarly() {
+	input := s.baseInput()
+	input.SavingsCheckDays = []int{2, 5, 9}
+	s.mockExecutedTargets(1, 100)
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendMonitor},
+	}, nil).Once()
+	s.env.OnActivity("VerifySavings", testAnyCtx, mock.MatchedBy(func(in activities.VerifySavingsInput) bool {
+		return in.DaysAfter == 2
+	})).Return(activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+		SavingsDaily: -40, CILow: -60, CIHigh: -20, Significant: true, Recommendation: domain.RecommendEscalate,
+	}}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal(domain.RecommendEscalate, result.State.Verification.Recommendation)
+	s.False(result.State.Verification.CostReductionObserved)
+	s.Len(result.State.Verification.SavingsChecks, 1)
+}
+
+// SavingsVerification_SkippedWhenUnhealthy: a rollback recommendation from
+// health checks ends the workflow without waiting on savings.
+func (s *AnomalyLifecycleSuite) TestSavingsVerification_SkippedWhenUnhealthy() {
+	input := s.baseInput()
+	s.mockExecutedTargets(1, 100)
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{Recommendation: domain.RecommendRollback},
+	}, nil).Once()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal(domain.RecommendRollback, result.State.Verification.Recommendation)
+	s.Empty(result.State.Verification.SavingsChecks)
+}
+
+// mockExecutedTargets stubs triage, planning, and the change calendar for n
+// low-risk actions on real target resources, each estimated to save
+// savingsEach per month.
+func (s *AnomalyLifecycleSuite) mockExecutedTargets(n int, savingsEach float64) []domain.RecommendedAction {
+	actions := s.mockThroughPlan(domain.RiskLow, n)
+	for i := range actions {
+		// actions shares its backing array with the PlanActions mock result.
+		actions[i].TargetResource = fmt.Sprintf("arn:aws:ec2:us-east-1:123456789012:volume/vol-%d", i)
+		actions[i].EstimatedSavingsMonthly = savingsEach
+	}
+	s.env.OnActivity("NextChangeWindow", testAnyCtx, testAnyInput).Return(activities.NextChangeWindowOutput{
+		At: s.env.Now().UTC().Format(time.RFC3339),
+	}, nil).Once()
+	return actions
+}
+
+// mockThroughPlan stubs triage and planning to return n actions at the given risk.
+func (s *AnomalyLifecycleSuite) mockThroughPlan(risk domain.ActionRiskLevel, n int) []domain.RecommendedAction {
+	s.env.OnActivity("TriageAnomaly", testAnyCtx, testAnyInput).Return(activities.TriageOutput{
+		Result: domain.TriageResult{
+			Category:   domain.CategoryResourceWaste,
+			Severity:   domain.SeverityMedium,
+			Confidence: 0.8,
+		},
+	}, nil)
+
+	actions := make([]domain.RecommendedAction, n)
+	for i := range actions {
+		actions[i] = domain.NewRecommendedAction(fmt.Sprintf("action %d", i), "delete_volume", risk, "restore from snapshot")
+	}
+	s.env.OnActivity("PlanActions", testAnyCtx, testAnyInput).Return(activities.PlanActionsOutput{
+		Result: domain.AnalysisResult{RecommendedActions: actions},
+	}, nil)
+	return actions
 }
 
 // NoAnomaly: nil anomaly input
+// Premium tenants run their activities on their dedicated queues; others
+// stay on the shared ones.
+func (s *AnomalyLifecycleSuite) TestTenantQueues() {
+	for _, premium := range []bool{false, true} {
+		s.Run(fmt.Sprintf("premium=%v", premium), func() {
+			s.SetupTest()
+			input := s.baseInput()
+			input.Tenant.Premium = premium
+			queues := map[string]string{}
+			record := func(ctx context.Context) {
+				info := activity.GetInfo(ctx)
+				queues[info.ActivityType.Name] = info.TaskQueue
+			}
+
+			s.env.OnActivity("TriageAnomaly", testAnyCtx, testAnyInput).Return(func(ctx context.Context, _ activities.TriageInput) (activities.TriageOutput, error) {
+				record(ctx)
+				return activities.TriageOutput{Result: domain.TriageResult{Category: domain.CategoryDeployRelated, Confidence: 0.7}}, nil
+			})
+			action := domain.NewRecommendedAction("create alert", "create_budget_alert", domain.RiskLow, "disable alert")
+			s.env.OnActivity("PlanActions", testAnyCtx, testAnyInput).Return(activities.PlanActionsOutput{
+				Result: domain.AnalysisResult{RecommendedActions: []domain.RecommendedAction{action}},
+			}, nil)
+			s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(func(ctx context.Context, _ activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+				record(ctx)
+				return activities.ExecuteActionOutput{Result: domain.ExecutionResult{ActionID: action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded}}, nil
+			})
+			s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+				Result: domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendClose},
+			}, nil)
+
+			s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+			s.True(s.env.IsWorkflowCompleted())
+			s.NoError(s.env.GetWorkflowError())
+
+			if premium {
+				s.Equal("finops-anomaly-tenant-1", queues["TriageAnomaly"])
+				s.Equal("finops-exec-tenant-1", queues["ExecuteAction"])
+			} else {
+				s.NotEqual("finops-anomaly-tenant-1", queues["TriageAnomaly"])
+				s.Equal(versioning.QueueExec, queues["ExecuteAction"])
+			}
+		})
+	}
+}
+
 func (s *AnomalyLifecycleSuite) TestNoAnomaly() {
 	input := workflows.WorkflowInput{
 		Tenant:  domain.NewTenantContext("tenant-1"),
@@ -0,0 +1,40 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/audit"
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// auditDecision records an automated lifecycle decision, such as the
+// policy's approval verdict, in the audit trail. Like the history
+// projection it is best effort and never fails the lifecycle.
+func auditDecision(ctx workflow.Context, state *domain.FinOpsState, action, outcome, reason string) {
+	if workflow.GetVersion(ctx, "audit-log", workflow.DefaultVersion, 1) != 1 {
+		return
+	}
+	e := audit.Event{
+		Time:       workflow.Now(ctx).UTC().Format(time.RFC3339Nano),
+		Actor:      audit.SystemActor,
+		Source:     audit.SourceWorkflow,
+		TenantID:   state.Tenant.TenantID,
+		Action:     action,
+		WorkflowID: workflow.GetInfo(ctx).WorkflowExecution.ID,
+		Outcome:    outcome,
+		Reason:     reason,
+	}
+	if a := state.Anomaly; a != nil {
+		e.Resource = a.Service + "/" + a.AccountID
+	}
+	auditCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	if err := workflow.ExecuteActivity(auditCtx, "RecordAudit", e).Get(ctx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("audit write failed", "action", action, "error", err)
+	}
+}
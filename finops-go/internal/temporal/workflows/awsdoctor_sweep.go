@@ -17,7 +17,26 @@ const WasteSavingsThreshold = 100.0
 
 // SweepInput configures which accounts to scan.
 type SweepInput struct {
+	// Tenant owns the anomalies the sweep starts. Empty means each
+	// account is its own tenant.
+	Tenant   string         `json:"tenant,omitempty"`
 	Accounts []SweepAccount `json:"accounts"`
+
+	// Premium and Weight schedule the anomalies of Tenant, see
+	// domain.TenantContext. They do not apply to per-account tenants.
+	Premium bool    `json:"premium,omitempty"`
+	Weight  float64 `json:"weight,omitempty"`
+}
+
+// TenantContext returns the tenant owning the anomalies found in accountID.
+func (in SweepInput) TenantContext(accountID string) domain.TenantContext {
+	if in.Tenant == "" {
+		return domain.NewTenantContext(accountID)
+	}
+	tenant := domain.NewTenantContext(in.Tenant)
+	tenant.Premium = in.Premium
+	tenant.Weight = in.Weight
+	return tenant
 }
 
 // SweepAccount identifies one AWS account to scan.
@@ -85,14 +104,16 @@ func AWSDocSweepWorkflow(ctx workflow.Context, input SweepInput) (SweepResult, e
 		anomaly.DeltaDollars = wasteOut.TotalSavings
 		anomaly.DeltaPercent = 0 // waste is absolute, not relative
 
+		tenant := input.TenantContext(acct.AccountID)
 		childOpts := workflow.ChildWorkflowOptions{
 			WorkflowID: fmt.Sprintf("waste-%s-%s", acct.AccountID, anomaly.AnomalyID),
+			Priority:   TenantPriority(tenant),
 		}
 		childCtx := workflow.WithChildOptions(ctx, childOpts)
 
 		var childResult WorkflowResult
 		err = workflow.ExecuteChildWorkflow(childCtx, AnomalyLifecycleWorkflow, WorkflowInput{
-			Tenant:  domain.NewTenantContext(acct.AccountID),
+			Tenant:  tenant,
 			Anomaly: &anomaly,
 		}).Get(ctx, &childResult)
 		if err != nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
 
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
@@ -44,6 +45,7 @@ func (s *SweepSuite) TestNoAccounts() {
 
 func (s *SweepSuite) TestWasteAboveThreshold_SpawnsChild() {
 	input := workflows.SweepInput{
+		Tenant: "acme",
 		Accounts: []workflows.SweepAccount{
 			{AccountID: "123456789012", Region: "us-east-1", Profile: "prod"},
 		},
@@ -65,9 +67,12 @@ func (s *SweepSuite) TestWasteAboveThreshold_SpawnsChild() {
 	}, nil)
 
 	// The child AnomalyLifecycleWorkflow mock — ctx + input
-	s.env.OnWorkflow(workflows.AnomalyLifecycleWorkflow, testAnyCtx, testAnyInput).Return(workflows.WorkflowResult{
-		Reason: workflows.ReasonCompleted,
-	}, nil)
+	var childTenant string
+	s.env.OnWorkflow(workflows.AnomalyLifecycleWorkflow, testAnyCtx, testAnyInput).Return(
+		func(_ workflow.Context, in workflows.WorkflowInput) (workflows.WorkflowResult, error) {
+			childTenant = in.Tenant.TenantID
+			return workflows.WorkflowResult{Reason: workflows.ReasonCompleted}, nil
+		})
 
 	s.env.ExecuteWorkflow(workflows.AWSDocSweepWorkflow, input)
 	s.True(s.env.IsWorkflowCompleted())
@@ -75,6 +80,7 @@ func (s *SweepSuite) TestWasteAboveThreshold_SpawnsChild() {
 
 	var result workflows.SweepResult
 	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal("acme", childTenant)
 	s.Equal(1, result.AccountsScanned)
 	s.Equal(1, result.WasteAnomalies)
 	s.Equal(1, result.ChildWorkflowsRun)
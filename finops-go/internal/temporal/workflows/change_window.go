@@ -0,0 +1,55 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// waitForChangeWindow asks the change calendar when the actions may run and,
+// if that is in the future, sleeps on a durable timer until then. While
+// waiting, the phase is "scheduled" and state.ScheduledFor is set so queries
+// and the UI can show when execution will happen. A non-empty reason means
+// the lifecycle must terminate.
+func waitForChangeWindow(
+	ctx, actCtx workflow.Context,
+	tenant domain.TenantContext,
+	actions []domain.RecommendedAction,
+	state *domain.FinOpsState,
+) (TerminationReason, string) {
+	logger := workflow.GetLogger(ctx)
+
+	var out activities.NextChangeWindowOutput
+	err := workflow.ExecuteActivity(actCtx, "NextChangeWindow", activities.NextChangeWindowInput{
+		Tenant:  tenant,
+		Actions: actions,
+	}).Get(ctx, &out)
+	if err != nil {
+		return ReasonExecutionError, fmt.Sprintf("change window lookup failed: %v", err)
+	}
+	if out.At == "" {
+		return ReasonNoChangeWindow, "no change window opens within the calendar search horizon"
+	}
+	at, err := time.Parse(time.RFC3339, out.At)
+	if err != nil {
+		return ReasonExecutionError, fmt.Sprintf("change window lookup returned invalid time %q", out.At)
+	}
+
+	wait := at.Sub(workflow.Now(ctx))
+	if wait <= 0 {
+		return "", ""
+	}
+	state.CurrentPhase = "scheduled"
+	state.ScheduledFor = out.At
+	upsertSearchAttributes(ctx, state)
+	logger.Info("holding actions for change window", "scheduled_for", out.At, "wait", wait)
+	if err := workflow.Sleep(ctx, wait); err != nil {
+		return ReasonExecutionError, fmt.Sprintf("change window wait interrupted: %v", err)
+	}
+	state.ScheduledFor = ""
+	return "", ""
+}
@@ -0,0 +1,183 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/executor"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// ExecuteActionMaxAttempts bounds retries of a single ExecuteAction activity.
+const ExecuteActionMaxAttempts = 3
+
+// executionOutcome summarises a per-action execution run. A non-empty
+// reason means the lifecycle must terminate with that reason and message.
+type executionOutcome struct {
+	failed  int
+	reason  TerminationReason
+	message string
+}
+
+// executeActions runs each action as its own activity keyed by
+// (workflow ID, action ID). Every result is appended to state as it lands,
+// so partial progress is visible to queries and recorded in history even
+// if a later action fails. When an action fails, onFailure decides whether
+// to continue, skip the rest, or also roll back what already ran.
+func executeActions(
+	ctx, execCtx workflow.Context,
+	tenant domain.TenantContext,
+	approval domain.ApprovalStatus,
+	actions []domain.RecommendedAction,
+	onFailure domain.FailurePolicy,
+	state *domain.FinOpsState,
+) executionOutcome {
+	logger := workflow.GetLogger(ctx)
+	wfID := workflow.GetInfo(ctx).WorkflowExecution.ID
+
+	var out executionOutcome
+	var firstErr string
+	for i, a := range actions {
+		key := executor.IdempotencyKey(wfID, a.ActionID)
+
+		if out.failed > 0 && onFailure != domain.FailureContinue {
+			state.Executions = append(state.Executions, domain.ExecutionResult{
+				ActionID:       a.ActionID,
+				IdempotencyKey: key,
+				Outcome:        domain.OutcomeSkipped,
+				Details:        fmt.Sprintf("skipped after earlier failure (on_failure=%s)", onFailure),
+			})
+			continue
+		}
+
+		var actOut activities.ExecuteActionOutput
+		err := workflow.ExecuteActivity(execCtx, "ExecuteAction", activities.ExecuteActionInput{
+			Tenant:         tenant,
+			Approval:       approval,
+			IdempotencyKey: key,
+			Action:         a,
+			Plan:           actions,
+		}).Get(ctx, &actOut)
+		if err != nil {
+			logger.Warn("action failed", "action_id", a.ActionID, "index", i, "error", err)
+			out.failed++
+			if firstErr == "" {
+				firstErr = fmt.Sprintf("action %s failed: %v", a.ActionID, err)
+			}
+			state.Executions = append(state.Executions, domain.ExecutionResult{
+				ActionID:       a.ActionID,
+				IdempotencyKey: key,
+				ExecutedAt:     workflow.Now(ctx).UTC().Format(time.RFC3339),
+				Success:        false,
+				Outcome:        domain.OutcomeFailed,
+				Details:        err.Error(),
+			})
+			continue
+		}
+		state.Executions = append(state.Executions, actOut.Result)
+	}
+
+	if out.failed == 0 {
+		return out
+	}
+
+	switch onFailure {
+	case domain.FailureContinue:
+		if out.failed < len(actions) {
+			return out
+		}
+		out.reason = ReasonExecutionError
+		out.message = fmt.Sprintf("all %d actions failed; first: %s", len(actions), firstErr)
+
+	case domain.FailureRollback:
+		rollbackFailures := rollbackCompleted(ctx, execCtx, tenant, actions, state)
+		out.reason = ReasonRolledBack
+		out.message = fmt.Sprintf("%s; completed actions rolled back", firstErr)
+		if rollbackFailures > 0 {
+			out.message = fmt.Sprintf("%s; %d rollback(s) failed, manual follow-up required", firstErr, rollbackFailures)
+		}
+
+	default:
+		out.reason = ReasonExecutionError
+		out.message = fmt.Sprintf("%s; remaining actions skipped", firstErr)
+	}
+	return out
+}
+
+// rollbackCompleted rolls back every action this run applied, in reverse
+// order, replacing its entry in state.Executions with the rollback result.
+// Actions found already applied were not changed by this run and are left
+// alone. It returns the number of rollbacks that failed.
+func rollbackCompleted(
+	ctx, execCtx workflow.Context,
+	tenant domain.TenantContext,
+	actions []domain.RecommendedAction,
+	state *domain.FinOpsState,
+) int {
+	logger := workflow.GetLogger(ctx)
+	byID := make(map[string]domain.RecommendedAction, len(actions))
+	for _, a := range actions {
+		byID[a.ActionID] = a
+	}
+
+	skipAlreadyApplied := workflow.GetVersion(ctx, "rollback-skip-already-applied", workflow.DefaultVersion, 1) == 1
+
+	failures := 0
+	for i := len(state.Executions) - 1; i >= 0; i-- {
+		res := state.Executions[i]
+		if !res.Success {
+			continue
+		}
+		if skipAlreadyApplied && res.Outcome == domain.OutcomeAlreadyApplied {
+			continue
+		}
+		var rbOut activities.RollbackActionOutput
+		err := workflow.ExecuteActivity(execCtx, "RollbackAction", activities.RollbackActionInput{
+			Tenant:            tenant,
+			IdempotencyKey:    res.IdempotencyKey,
+			Action:            byID[res.ActionID],
+			PreActionSnapshot: res.PreActionSnapshot,
+		}).Get(ctx, &rbOut)
+		if err != nil {
+			logger.Error("rollback failed", "action_id", res.ActionID, "error", err)
+			failures++
+			state.Executions[i].Details = fmt.Sprintf("%s; rollback failed: %v", res.Details, err)
+			continue
+		}
+		state.Executions[i] = rbOut.Result
+	}
+	return failures
+}
+
+// changedResources returns the target resources of actions that executed
+// and were not rolled back, for health verification, together with the
+// earliest execution time among them.
+func changedResources(actions []domain.RecommendedAction, executions []domain.ExecutionResult) ([]string, string) {
+	targets := make(map[string]string, len(actions))
+	for _, a := range actions {
+		targets[a.ActionID] = a.TargetResource
+	}
+
+	var resources []string
+	var earliest string
+	seen := make(map[string]bool)
+	for _, res := range executions {
+		if !res.Success || res.Outcome == domain.OutcomeRolledBack {
+			continue
+		}
+		target := targets[res.ActionID]
+		if target == "" || seen[target] {
+			continue
+		}
+		seen[target] = true
+		resources = append(resources, target)
+		// RFC3339 UTC timestamps order lexically.
+		if res.ExecutedAt != "" && (earliest == "" || res.ExecutedAt < earliest) {
+			earliest = res.ExecutedAt
+		}
+	}
+	return resources, earliest
+}
@@ -0,0 +1,34 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// projectHistory publishes the state at a phase boundary: to visibility as
+// search attributes, and to the anomaly history store; reason is set once
+// the lifecycle ends. Like the savings ledger, the projection is best
+// effort and never fails the lifecycle.
+func projectHistory(ctx workflow.Context, state *domain.FinOpsState, reason TerminationReason) {
+	upsertSearchAttributes(ctx, state)
+	if state.Anomaly == nil || workflow.GetVersion(ctx, "anomaly-history", workflow.DefaultVersion, 1) != 1 {
+		return
+	}
+	histCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	err := workflow.ExecuteActivity(histCtx, "RecordHistory", activities.RecordHistoryInput{
+		WorkflowID: workflow.GetInfo(ctx).WorkflowExecution.ID,
+		State:      *state,
+		Reason:     string(reason),
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("anomaly history write failed", "error", err)
+	}
+}
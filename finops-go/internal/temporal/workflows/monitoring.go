@@ -0,0 +1,214 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// DefaultMonitorDays is the monitoring period when WorkflowInput.MonitorDays
+// is zero.
+const DefaultMonitorDays = 14
+
+// MonitorCheckMaxAttempts bounds retries of each daily check's activities.
+// Checks are read-only, so a transient AWS error should not end monitoring.
+const MonitorCheckMaxAttempts = 5
+
+// MonitorInconclusiveLimit is how many consecutive inconclusive health
+// checks monitoring tolerates before it escalates. Alarms of a stopped or
+// deleted resource report INSUFFICIENT_DATA, which says nothing about
+// whether the change broke anything.
+const MonitorInconclusiveLimit = 3
+
+// monitorDaysPerRun is how many daily checks one run performs before it
+// continues as new, keeping event history bounded over long periods.
+const monitorDaysPerRun = 7
+
+// startMonitoring enters post-change monitoring for a "monitor"
+// recommendation.
+func startMonitoring(
+	ctx workflow.Context,
+	input WorkflowInput,
+	actions []domain.RecommendedAction,
+	resources []string,
+	executedAt time.Time,
+	state *domain.FinOpsState,
+) (WorkflowResult, error) {
+	days := input.MonitorDays
+	if days <= 0 {
+		days = DefaultMonitorDays
+	}
+	state.Monitoring = &domain.MonitoringStatus{
+		TotalDays:              days,
+		StartedAt:              workflow.Now(ctx).UTC().Format(time.RFC3339),
+		ExecutedAt:             executedAt.UTC().Format(time.RFC3339),
+		Resources:              resources,
+		ExpectedSavingsMonthly: expectedSavingsMonthly(actions, state.Executions),
+	}
+	return monitorPostChange(ctx, input, state)
+}
+
+// monitorPostChange re-verifies health and savings once a day until the
+// monitoring period ends. It escalates as soon as health degrades or spend
+// regresses, closes once savings are confirmed, and escalates if the period
+// ends without confirmation. Every monitorDaysPerRun checks it continues as
+// new, carrying the state in WorkflowInput.Resume.
+func monitorPostChange(ctx workflow.Context, input WorkflowInput, state *domain.FinOpsState) (WorkflowResult, error) {
+	logger := workflow.GetLogger(ctx)
+	m := state.Monitoring
+	state.CurrentPhase = "monitoring"
+	upsertSearchAttributes(ctx, state)
+
+	start, err := time.Parse(time.RFC3339, m.StartedAt)
+	if err != nil {
+		return finishMonitoring(ctx, state, ReasonVerifyError, fmt.Sprintf("invalid monitoring start %q: %v", m.StartedAt, err))
+	}
+	executedAt, err := time.Parse(time.RFC3339, m.ExecutedAt)
+	if err != nil {
+		return finishMonitoring(ctx, state, ReasonVerifyError, fmt.Sprintf("invalid execution time %q: %v", m.ExecutedAt, err))
+	}
+
+	anomalyQueue, _ := tenantQueues(ctx, input.Tenant)
+	monCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           anomalyQueue,
+		StartToCloseTimeout: 2 * time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval: time.Minute,
+			MaximumAttempts: MonitorCheckMaxAttempts,
+		},
+	})
+
+	for checks := 0; m.Day < m.TotalDays; checks++ {
+		if checks == monitorDaysPerRun {
+			next := input
+			resume := *state
+			next.Resume = &resume
+			logger.Info("continuing monitoring as new", "day", m.Day, "total_days", m.TotalDays)
+			projectHistory(ctx, state, "")
+			return WorkflowResult{}, workflow.NewContinueAsNewError(ctx, AnomalyLifecycleWorkflow, next)
+		}
+
+		m.Day++
+		at := start.Add(time.Duration(m.Day) * 24 * time.Hour)
+		m.NextCheckAt = at.UTC().Format(time.RFC3339)
+		if wait := at.Sub(workflow.Now(ctx)); wait > 0 {
+			if err := workflow.Sleep(ctx, wait); err != nil {
+				return finishMonitoring(ctx, state, ReasonVerifyError, fmt.Sprintf("monitoring wait interrupted: %v", err))
+			}
+		}
+
+		done, err := monitorCheck(ctx, monCtx, input, executedAt, state)
+		if err != nil {
+			return finishMonitoring(ctx, state, ReasonVerifyError, fmt.Sprintf("monitoring day %d failed: %v", m.Day, err))
+		}
+		if done {
+			return finishMonitoring(ctx, state, ReasonCompleted, "")
+		}
+		projectHistory(ctx, state, "")
+	}
+
+	state.Verification.Recommendation = domain.RecommendEscalate
+	m.Outcome = fmt.Sprintf("savings not confirmed after %d days of monitoring", m.TotalDays)
+	logger.Info("monitoring period ended without confirmed savings", "total_days", m.TotalDays)
+	return finishMonitoring(ctx, state, ReasonCompleted, "")
+}
+
+// monitorCheck runs one day's health and savings checks. It reports done
+// once the recommendation is no longer monitor. Degraded health escalates
+// at once; inconclusive health only after MonitorInconclusiveLimit checks
+// in a row, and meanwhile savings are still checked.
+func monitorCheck(ctx, monCtx workflow.Context, input WorkflowInput, executedAt time.Time, state *domain.FinOpsState) (bool, error) {
+	logger := workflow.GetLogger(ctx)
+	m := state.Monitoring
+	v := state.Verification
+
+	verifyIn := activities.VerifyOutcomeInput{
+		Tenant:      input.Tenant,
+		Service:     input.Anomaly.Service,
+		AccountID:   input.Anomaly.AccountID,
+		WindowStart: input.WindowStart,
+		WindowEnd:   input.WindowEnd,
+		Resources:   m.Resources,
+		ExecutedAt:  m.ExecutedAt,
+	}
+	if workflow.GetVersion(ctx, "monitor-trailing-health", workflow.DefaultVersion, 1) == 1 {
+		// Compare the hour up to this check with the baseline before the
+		// change, so a regression days later is seen.
+		verifyIn.CheckedAt = workflow.Now(ctx).UTC().Format(time.RFC3339)
+	}
+	var health activities.VerifyOutcomeOutput
+	err := workflow.ExecuteActivity(monCtx, "VerifyOutcome", verifyIn).Get(ctx, &health)
+	if err != nil {
+		return false, fmt.Errorf("health check: %w", err)
+	}
+	v.ServiceHealthOK = health.Result.ServiceHealthOK
+	v.HealthCheckDetails = health.Result.HealthCheckDetails
+	// The verifier recommends rollback for degraded health and escalation
+	// when it cannot tell.
+	inconclusive := !health.Result.ServiceHealthOK && health.Result.Recommendation != domain.RecommendRollback &&
+		workflow.GetVersion(ctx, "monitor-inconclusive-health", workflow.DefaultVersion, 1) == 1
+	switch {
+	case inconclusive:
+		m.InconclusiveChecks++
+		if m.InconclusiveChecks >= MonitorInconclusiveLimit {
+			v.Recommendation = domain.RecommendEscalate
+			m.Outcome = fmt.Sprintf("health inconclusive for %d checks on day %d: %s", m.InconclusiveChecks, m.Day, health.Result.HealthCheckDetails)
+			logger.Info("monitoring escalated on inconclusive health", "day", m.Day, "checks", m.InconclusiveChecks)
+			return true, nil
+		}
+		logger.Info("monitoring health inconclusive", "day", m.Day, "checks", m.InconclusiveChecks, "details", health.Result.HealthCheckDetails)
+	case !health.Result.ServiceHealthOK:
+		v.Recommendation = domain.RecommendEscalate
+		m.Outcome = fmt.Sprintf("health degraded on day %d: %s", m.Day, health.Result.HealthCheckDetails)
+		logger.Info("monitoring escalated on health", "day", m.Day, "details", health.Result.HealthCheckDetails)
+		return true, nil
+	default:
+		m.InconclusiveChecks = 0
+	}
+
+	var savings activities.VerifySavingsOutput
+	err = workflow.ExecuteActivity(monCtx, "VerifySavings", activities.VerifySavingsInput{
+		Tenant:                 input.Tenant,
+		Service:                input.Anomaly.Service,
+		AccountID:              input.Anomaly.AccountID,
+		ExecutedAt:             m.ExecutedAt,
+		DaysAfter:              int(workflow.Now(ctx).Sub(executedAt) / (24 * time.Hour)),
+		ExpectedSavingsMonthly: m.ExpectedSavingsMonthly,
+	}).Get(ctx, &savings)
+	if err != nil {
+		return false, fmt.Errorf("savings check: %w", err)
+	}
+	check := savings.Check
+	applySavingsCheck(v, check)
+	recordSavings(ctx, input, state, &check)
+	logger.Info("monitoring check", "day", m.Day, "recommendation", check.Recommendation,
+		"savings_daily", check.SavingsDaily)
+
+	switch check.Recommendation {
+	case domain.RecommendClose:
+		m.Outcome = fmt.Sprintf("savings confirmed on day %d: %s", m.Day, check.Details)
+		return true, nil
+	case domain.RecommendEscalate:
+		m.Outcome = fmt.Sprintf("cost regressed on day %d: %s", m.Day, check.Details)
+		return true, nil
+	}
+	return false, nil
+}
+
+// finishMonitoring ends the lifecycle from the monitoring phase.
+func finishMonitoring(ctx workflow.Context, state *domain.FinOpsState, reason TerminationReason, errMsg string) (WorkflowResult, error) {
+	state.Monitoring.NextCheckAt = ""
+	state.ShouldTerminate = true
+	if errMsg != "" {
+		state.Error = &errMsg
+	} else {
+		state.CurrentPhase = "completed"
+	}
+	projectHistory(ctx, state, reason)
+	return WorkflowResult{State: *state, Reason: reason}, nil
+}
@@ -0,0 +1,255 @@
+package workflows_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/workflows"
+	"github.com/finops-claw-gang/finops-go/internal/verifier"
+)
+
+// mockMonitoredExecution stubs a single healthy execution whose delayed
+// savings checks stay at monitor, so the lifecycle enters monitoring.
+func (s *AnomalyLifecycleSuite) mockMonitoredExecution() {
+	s.mockExecutedTargets(1, 300)
+	s.env.OnActivity("ExecuteAction", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.ExecuteActionInput) (activities.ExecuteActionOutput, error) {
+			return activities.ExecuteActionOutput{Result: domain.ExecutionResult{
+				ActionID: in.Action.ActionID, Success: true, Outcome: domain.OutcomeSucceeded,
+			}}, nil
+		}).Once()
+}
+
+func healthy() activities.VerifyOutcomeOutput {
+	return activities.VerifyOutcomeOutput{Result: domain.VerificationResult{
+		ServiceHealthOK: true, HealthCheckDetails: "all alarms OK", Recommendation: domain.RecommendMonitor,
+	}}
+}
+
+func notYetSignificant(in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+	return activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+		DaysAfter: in.DaysAfter, SavingsDaily: 4, CILow: -3, CIHigh: 11, Recommendation: domain.RecommendMonitor,
+	}}, nil
+}
+
+// Monitoring_ClosesOnConfirmedSavings: daily checks continue until savings
+// are confirmed, and the query reports the monitoring day.
+func (s *AnomalyLifecycleSuite) TestMonitoring_ClosesOnConfirmedSavings() {
+	input := s.baseInput()
+	input.MonitorDays = 5
+	s.mockMonitoredExecution()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Times(4)
+
+	var days []int
+	monitoring := false
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			if !monitoring {
+				// Delayed checks on days 3 and 7.
+				monitoring = in.DaysAfter == 7
+				return notYetSignificant(in)
+			}
+			s.Equal(300.0, in.ExpectedSavingsMonthly)
+			days = append(days, in.DaysAfter)
+			if len(days) < 3 {
+				return notYetSignificant(in)
+			}
+			return activities.VerifySavingsOutput{Check: domain.SavingsVerification{
+				DaysAfter: in.DaysAfter, SavingsDaily: 12, CILow: 6, CIHigh: 18, Significant: true,
+				Recommendation: domain.RecommendClose, Details: "savings realized",
+			}}, nil
+		}).Times(5)
+
+	// Day 7 check, then monitoring day 2 starts after the first daily check.
+	s.env.RegisterDelayedCallback(func() {
+		val, err := s.env.QueryWorkflow(workflows.QueryNameState)
+		s.Require().NoError(err)
+		var res workflows.WorkflowResult
+		s.Require().NoError(val.Get(&res))
+		s.Equal("monitoring", res.State.CurrentPhase)
+		s.Require().NotNil(res.State.Monitoring)
+		s.Equal("monitoring day 2/5", res.State.Monitoring.Label())
+		s.NotEmpty(res.State.Monitoring.NextCheckAt)
+	}, (7*24+36)*time.Hour)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal("completed", result.State.CurrentPhase)
+	s.Equal([]int{8, 9, 10}, days)
+	s.Equal(domain.RecommendClose, result.State.Verification.Recommendation)
+	s.Equal(12.0, result.State.Verification.ObservedSavingsDaily)
+	m := result.State.Monitoring
+	s.Require().NotNil(m)
+	s.Equal(3, m.Day)
+	s.Contains(m.Outcome, "savings confirmed on day 3")
+	s.Empty(m.NextCheckAt)
+}
+
+// Monitoring_EscalatesOnHealthDegradation: a failing health check during
+// monitoring escalates without checking savings.
+func (s *AnomalyLifecycleSuite) TestMonitoring_EscalatesOnHealthDegradation() {
+	input := s.baseInput()
+	s.mockMonitoredExecution()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(activities.VerifyOutcomeOutput{
+		Result: domain.VerificationResult{HealthCheckDetails: "alarm web-5xx in ALARM", Recommendation: domain.RecommendRollback},
+	}, nil).Once()
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			return notYetSignificant(in)
+		}).Twice()
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	v := result.State.Verification
+	s.Equal(domain.RecommendEscalate, v.Recommendation)
+	s.False(v.ServiceHealthOK)
+	s.Equal(1, result.State.Monitoring.Day)
+	s.Contains(result.State.Monitoring.Outcome, "health degraded on day 1: alarm web-5xx in ALARM")
+}
+
+// Monitoring_EscalatesOnLateRegression: each daily health check compares
+// the hour before it with the pre-change baseline, so a metric that
+// regresses between days 2 and 3 is caught on day 3.
+func (s *AnomalyLifecycleSuite) TestMonitoring_EscalatesOnLateRegression() {
+	input := s.baseInput()
+	s.mockMonitoredExecution()
+
+	var firstCheck time.Time
+	var windows []time.Time
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifyOutcomeInput) (activities.VerifyOutcomeOutput, error) {
+			if in.CheckedAt == "" {
+				return healthy(), nil // verification right after execution
+			}
+			until, err := time.Parse(time.RFC3339, in.CheckedAt)
+			s.Require().NoError(err)
+			windows = append(windows, until)
+			if firstCheck.IsZero() {
+				firstCheck = until
+			}
+			// Errors rise 36h after the first monitoring check.
+			if until.Add(-verifier.HealthSpan).Before(firstCheck.Add(36 * time.Hour)) {
+				return healthy(), nil
+			}
+			return activities.VerifyOutcomeOutput{Result: domain.VerificationResult{
+				HealthCheckDetails: "Errors/Invocations error rate 0.0% -> 8.0%", Recommendation: domain.RecommendRollback,
+			}}, nil
+		}).Times(4)
+	// Delayed checks on days 3 and 7, then monitoring days 1 and 2.
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			return notYetSignificant(in)
+		}).Times(4)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(domain.RecommendEscalate, result.State.Verification.Recommendation)
+	m := result.State.Monitoring
+	s.Equal(3, m.Day)
+	s.Contains(m.Outcome, "health degraded on day 3")
+	s.Require().Len(windows, 3)
+	s.Equal(24*time.Hour, windows[1].Sub(windows[0]), "each check ends its window at the check")
+	s.Equal(24*time.Hour, windows[2].Sub(windows[1]))
+}
+
+// Monitoring_InconclusiveHealth: inconclusive health checks keep monitoring
+// going, and escalate only once they repeat MonitorInconclusiveLimit times
+// in a row.
+func (s *AnomalyLifecycleSuite) TestMonitoring_InconclusiveHealth() {
+	input := s.baseInput()
+	s.mockMonitoredExecution()
+	inconclusive := activities.VerifyOutcomeOutput{Result: domain.VerificationResult{
+		HealthCheckDetails: "alarm vol-1-idle in INSUFFICIENT_DATA", Recommendation: domain.RecommendEscalate,
+	}}
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Once()
+	// Day 1 inconclusive, day 2 healthy, then inconclusive from day 3.
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(inconclusive, nil).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Once()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(inconclusive, nil).Times(workflows.MonitorInconclusiveLimit)
+	// Delayed checks on days 3 and 7, then days 1 to 4 of monitoring.
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			return notYetSignificant(in)
+		}).Times(6)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result workflows.WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal(domain.RecommendEscalate, result.State.Verification.Recommendation)
+	m := result.State.Monitoring
+	s.Equal(5, m.Day)
+	s.Equal(workflows.MonitorInconclusiveLimit, m.InconclusiveChecks)
+	s.Equal("health inconclusive for 3 checks on day 5: alarm vol-1-idle in INSUFFICIENT_DATA", m.Outcome)
+}
+
+// Monitoring_ContinuesAsNew: a long period spans runs linked by
+// continue-as-new, and escalates when savings are never confirmed.
+func (s *AnomalyLifecycleSuite) TestMonitoring_ContinuesAsNew() {
+	input := s.baseInput()
+	input.MonitorDays = 10
+	s.mockMonitoredExecution()
+	s.env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Times(8)
+	s.env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			return notYetSignificant(in)
+		}).Times(9)
+
+	s.env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, input)
+	s.True(s.env.IsWorkflowCompleted())
+
+	var canErr *workflow.ContinueAsNewError
+	s.Require().True(errors.As(s.env.GetWorkflowError(), &canErr), "got %v", s.env.GetWorkflowError())
+	var next workflows.WorkflowInput
+	s.Require().NoError(converter.GetDefaultDataConverter().FromPayloads(canErr.Input, &next))
+	s.Require().NotNil(next.Resume)
+	s.Require().NotNil(next.Resume.Monitoring)
+	s.Equal(7, next.Resume.Monitoring.Day)
+	s.Len(next.Resume.Verification.SavingsChecks, 9)
+
+	// The next run resumes at day 8 without re-running the lifecycle.
+	env := s.NewTestWorkflowEnvironment()
+	env.SetStartTime(s.env.Now())
+	env.RegisterActivity(&activities.Activities{})
+	env.OnActivity("VerifyOutcome", testAnyCtx, testAnyInput).Return(healthy(), nil).Times(3)
+	env.OnActivity("VerifySavings", testAnyCtx, testAnyInput).Return(
+		func(_ context.Context, in activities.VerifySavingsInput) (activities.VerifySavingsOutput, error) {
+			return notYetSignificant(in)
+		}).Times(3)
+
+	env.ExecuteWorkflow(workflows.AnomalyLifecycleWorkflow, next)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	env.AssertExpectations(s.T())
+
+	var result workflows.WorkflowResult
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal(workflows.ReasonCompleted, result.Reason)
+	s.Equal(domain.RecommendEscalate, result.State.Verification.Recommendation)
+	s.Equal(10, result.State.Monitoring.Day)
+	s.Equal("savings not confirmed after 10 days of monitoring", result.State.Monitoring.Outcome)
+	s.Len(result.State.Verification.SavingsChecks, 12)
+}
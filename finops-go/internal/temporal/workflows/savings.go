@@ -0,0 +1,135 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/activities"
+)
+
+// DefaultSavingsCheckDays are the days after execution at which savings are
+// checked when WorkflowInput.SavingsCheckDays is empty.
+var DefaultSavingsCheckDays = []int{3, 7}
+
+// verifyDelayedSavings sleeps on durable timers until each check day after
+// executedAt and compares post-execution spend with the baseline. The
+// verification result follows the latest check; checks stop early once one
+// closes or escalates. While waiting, the phase is "verifying_savings" and
+// the recommendation is monitor. A non-empty reason means the lifecycle
+// must terminate.
+func verifyDelayedSavings(
+	ctx, actCtx workflow.Context,
+	input WorkflowInput,
+	actions []domain.RecommendedAction,
+	executedAt time.Time,
+	state *domain.FinOpsState,
+) (TerminationReason, string) {
+	logger := workflow.GetLogger(ctx)
+
+	days := input.SavingsCheckDays
+	if len(days) == 0 {
+		days = DefaultSavingsCheckDays
+	}
+	expected := expectedSavingsMonthly(actions, state.Executions)
+
+	v := state.Verification
+	v.Recommendation = domain.RecommendMonitor
+	v.CostReductionObserved = false
+	v.ObservedSavingsDaily = 0
+	state.CurrentPhase = "verifying_savings"
+	upsertSearchAttributes(ctx, state)
+
+	for _, d := range days {
+		if wait := executedAt.Add(time.Duration(d) * 24 * time.Hour).Sub(workflow.Now(ctx)); wait > 0 {
+			logger.Info("waiting for savings check", "days_after", d, "wait", wait)
+			if err := workflow.Sleep(ctx, wait); err != nil {
+				return ReasonVerifyError, fmt.Sprintf("savings check wait interrupted: %v", err)
+			}
+		}
+
+		var out activities.VerifySavingsOutput
+		err := workflow.ExecuteActivity(actCtx, "VerifySavings", activities.VerifySavingsInput{
+			Tenant:                 input.Tenant,
+			Service:                input.Anomaly.Service,
+			AccountID:              input.Anomaly.AccountID,
+			ExecutedAt:             executedAt.UTC().Format(time.RFC3339),
+			DaysAfter:              d,
+			ExpectedSavingsMonthly: expected,
+		}).Get(ctx, &out)
+		if err != nil {
+			return ReasonVerifyError, fmt.Sprintf("savings check at day %d failed: %v", d, err)
+		}
+
+		check := out.Check
+		applySavingsCheck(v, check)
+		recordSavings(ctx, input, state, &check)
+		logger.Info("savings check", "days_after", d, "recommendation", check.Recommendation,
+			"savings_daily", check.SavingsDaily, "ci_low", check.CILow, "ci_high", check.CIHigh)
+
+		if check.Recommendation != domain.RecommendMonitor {
+			break
+		}
+	}
+	return "", ""
+}
+
+// applySavingsCheck records a savings check and makes the verification
+// result follow it.
+func applySavingsCheck(v *domain.VerificationResult, check domain.SavingsVerification) {
+	v.SavingsChecks = append(v.SavingsChecks, check)
+	v.Recommendation = check.Recommendation
+	v.CostReductionObserved = check.Significant && check.SavingsDaily > 0
+	v.ObservedSavingsDaily = 0
+	if v.CostReductionObserved {
+		v.ObservedSavingsDaily = check.SavingsDaily
+	}
+}
+
+// recordSavings writes the lifecycle's actions, executions and latest
+// savings check to the savings ledger. The ledger is bookkeeping, so a
+// failed write is logged and the lifecycle carries on.
+func recordSavings(ctx workflow.Context, input WorkflowInput, state *domain.FinOpsState, check *domain.SavingsVerification) {
+	if workflow.GetVersion(ctx, "savings-ledger", workflow.DefaultVersion, 1) != 1 {
+		return
+	}
+	if state.Analysis == nil || input.Anomaly == nil {
+		return
+	}
+	ledgerCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 3},
+	})
+	err := workflow.ExecuteActivity(ledgerCtx, "RecordSavings", activities.RecordSavingsInput{
+		Tenant:     input.Tenant,
+		WorkflowID: workflow.GetInfo(ctx).WorkflowExecution.ID,
+		Anomaly:    *input.Anomaly,
+		Actions:    state.Analysis.RecommendedActions,
+		Executions: state.Executions,
+		Check:      check,
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("savings ledger write failed", "error", err)
+	}
+}
+
+// expectedSavingsMonthly sums the estimated savings of actions that executed
+// and were not rolled back.
+func expectedSavingsMonthly(actions []domain.RecommendedAction, executions []domain.ExecutionResult) float64 {
+	applied := make(map[string]bool, len(executions))
+	for _, res := range executions {
+		if res.Success && res.Outcome != domain.OutcomeRolledBack {
+			applied[res.ActionID] = true
+		}
+	}
+	var total float64
+	for _, a := range actions {
+		if applied[a.ActionID] {
+			total += a.EstimatedSavingsMonthly
+		}
+	}
+	return total
+}
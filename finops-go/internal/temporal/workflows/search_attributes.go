@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// Custom search attributes the lifecycle upserts as it progresses, so
+// visibility queries can filter anomalies without querying each workflow.
+// They must be registered on the namespace before workers start, e.g.
+//
+//	temporal operator search-attribute create --name TenantID --type Keyword
+var (
+	SearchAttrTenantID       = temporal.NewSearchAttributeKeyKeyword("TenantID")
+	SearchAttrService        = temporal.NewSearchAttributeKeyKeyword("Service")
+	SearchAttrAccountID      = temporal.NewSearchAttributeKeyKeyword("AccountID")
+	SearchAttrCategory       = temporal.NewSearchAttributeKeyKeyword("Category")
+	SearchAttrSeverity       = temporal.NewSearchAttributeKeyKeyword("Severity")
+	SearchAttrDeltaDollars   = temporal.NewSearchAttributeKeyFloat64("DeltaDollars")
+	SearchAttrPhase          = temporal.NewSearchAttributeKeyKeyword("Phase")
+	SearchAttrApprovalStatus = temporal.NewSearchAttributeKeyKeyword("ApprovalStatus")
+)
+
+// SearchAttributeKeys lists every custom search attribute, for registration.
+var SearchAttributeKeys = []temporal.SearchAttributeKey{
+	SearchAttrTenantID,
+	SearchAttrService,
+	SearchAttrAccountID,
+	SearchAttrCategory,
+	SearchAttrSeverity,
+	SearchAttrDeltaDollars,
+	SearchAttrPhase,
+	SearchAttrApprovalStatus,
+}
+
+// upsertSearchAttributes publishes the state's filterable fields to
+// visibility. Fields not yet known (category before triage, approval before
+// the gate) are left unset. Failures are logged, never fatal.
+func upsertSearchAttributes(ctx workflow.Context, state *domain.FinOpsState) {
+	if workflow.GetVersion(ctx, "search-attributes", workflow.DefaultVersion, 1) != 1 {
+		return
+	}
+	updates := []temporal.SearchAttributeUpdate{
+		keyword(SearchAttrTenantID, state.Tenant.TenantID),
+		keyword(SearchAttrPhase, state.CurrentPhase),
+		keyword(SearchAttrApprovalStatus, string(state.Approval)),
+	}
+	if a := state.Anomaly; a != nil {
+		updates = append(updates,
+			keyword(SearchAttrService, a.Service),
+			keyword(SearchAttrAccountID, a.AccountID),
+			SearchAttrDeltaDollars.ValueSet(a.DeltaDollars),
+		)
+	}
+	if t := state.Triage; t != nil {
+		updates = append(updates,
+			keyword(SearchAttrCategory, string(t.Category)),
+			keyword(SearchAttrSeverity, string(t.Severity)),
+		)
+	}
+	if err := workflow.UpsertTypedSearchAttributes(ctx, updates...); err != nil {
+		workflow.GetLogger(ctx).Warn("search attribute upsert failed", "error", err)
+	}
+}
+
+func keyword(key temporal.SearchAttributeKeyKeyword, v string) temporal.SearchAttributeUpdate {
+	if v == "" {
+		return key.ValueUnset()
+	}
+	return key.ValueSet(v)
+}
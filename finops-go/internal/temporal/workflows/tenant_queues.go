@@ -0,0 +1,34 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+	"github.com/finops-claw-gang/finops-go/internal/temporal/versioning"
+)
+
+// tenantQueues returns the dedicated task queues that stand in for
+// QueueAnomaly and QueueExec for a premium tenant, or empty names for
+// tenants on the shared queues.
+func tenantQueues(ctx workflow.Context, tenant domain.TenantContext) (anomaly, exec string) {
+	if !tenant.Premium || tenant.TenantID == "" {
+		return "", ""
+	}
+	if workflow.GetVersion(ctx, "tenant-queues", workflow.DefaultVersion, 1) != 1 {
+		return "", ""
+	}
+	return versioning.TenantQueue(versioning.QueueAnomaly, tenant.TenantID),
+		versioning.TenantQueue(versioning.QueueExec, tenant.TenantID)
+}
+
+// TenantPriority returns the priority a tenant's workflows start with. The
+// fairness key and weight make the Temporal server interleave tasks of
+// different tenants on shared queues in proportion to their weights, and
+// the workflow's activities and children inherit them.
+func TenantPriority(tenant domain.TenantContext) temporal.Priority {
+	return temporal.Priority{
+		FairnessKey:    tenant.TenantID,
+		FairnessWeight: float32(tenant.Weight),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/finops-claw-gang/finops-go/internal/connectors/awsdoctor"
 	"github.com/finops-claw-gang/finops-go/internal/domain"
@@ -24,43 +25,43 @@ func (s *StubCost) load(name string, target any) error {
 	return json.Unmarshal(data, target)
 }
 
-func (s *StubCost) GetCostTimeseries(service, accountID, startDate, endDate string) (map[string]any, error) {
+func (s *StubCost) GetCostTimeseries(_ context.Context, service, accountID, startDate, endDate string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("cost_timeseries.json", &m)
 	return m, err
 }
 
-func (s *StubCost) GetCURLineItems(accountID, startDate, endDate string, service string) ([]map[string]any, error) {
+func (s *StubCost) GetCURLineItems(_ context.Context, accountID, startDate, endDate string, service string) ([]map[string]any, error) {
 	var items []map[string]any
 	err := s.load("cur_line_items.json", &items)
 	return items, err
 }
 
-func (s *StubCost) GetRICoverage(accountID, startDate, endDate string) (map[string]any, error) {
+func (s *StubCost) GetRICoverage(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("ri_coverage.json", &m)
 	return m, err
 }
 
-func (s *StubCost) GetRIUtilization(accountID, startDate, endDate string) (map[string]any, error) {
+func (s *StubCost) GetRIUtilization(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("ri_utilization.json", &m)
 	return m, err
 }
 
-func (s *StubCost) GetSPCoverage(accountID, startDate, endDate string) (map[string]any, error) {
+func (s *StubCost) GetSPCoverage(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("sp_coverage.json", &m)
 	return m, err
 }
 
-func (s *StubCost) GetSPUtilization(accountID, startDate, endDate string) (map[string]any, error) {
+func (s *StubCost) GetSPUtilization(_ context.Context, accountID, startDate, endDate string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("sp_utilization.json", &m)
 	return m, err
 }
 
-// StubInfra satisfies triage.InfraQuerier and executor.TagFetcher.
+// StubInfra satisfies triage.InfraQuerier, executor.TagFetcher, and verifier.HealthChecker.
 type StubInfra struct {
 	FixturesDir string
 }
@@ -73,30 +74,36 @@ func (s *StubInfra) load(name string, target any) error {
 	return json.Unmarshal(data, target)
 }
 
-func (s *StubInfra) RecentDeploys(service string) ([]map[string]any, error) {
+func (s *StubInfra) RecentDeploys(_ context.Context, service string) ([]map[string]any, error) {
 	var deploys []map[string]any
 	err := s.load("deploys.json", &deploys)
 	return deploys, err
 }
 
-func (s *StubInfra) CloudWatchMetrics(resourceID, metricName, namespace string) (map[string]any, error) {
+func (s *StubInfra) CloudWatchMetrics(_ context.Context, resourceID, metricName, namespace string) (map[string]any, error) {
 	var m map[string]any
 	err := s.load("cloudwatch_metrics.json", &m)
 	return m, err
 }
 
-func (s *StubInfra) ResourceTags(resourceARN string) (map[string]string, error) {
+func (s *StubInfra) ResourceTags(_ context.Context, resourceARN string) (map[string]string, error) {
 	var tags map[string]string
 	err := s.load("resource_tags.json", &tags)
 	return tags, err
 }
 
+func (s *StubInfra) HealthSignals(_ context.Context, resourceARNs []string, executedAt, until time.Time) (domain.HealthSignals, error) {
+	var sig domain.HealthSignals
+	err := s.load("health_signals.json", &sig)
+	return sig, err
+}
+
 // StubKubeCost satisfies triage.KubeCostQuerier.
 type StubKubeCost struct {
 	FixturesDir string
 }
 
-func (s *StubKubeCost) Allocation(window, aggregate string) (map[string]any, error) {
+func (s *StubKubeCost) Allocation(_ context.Context, window, aggregate string) (map[string]any, error) {
 	data, err := os.ReadFile(filepath.Join(s.FixturesDir, "kubecost_allocation.json"))
 	if err != nil {
 		return nil, err
@@ -8,20 +8,20 @@ import (
 
 // CostFetcher provides cost data needed by the triage classifier.
 type CostFetcher interface {
-	GetRICoverage(accountID, startDate, endDate string) (map[string]any, error)
-	GetSPCoverage(accountID, startDate, endDate string) (map[string]any, error)
-	GetCURLineItems(accountID, startDate, endDate string, service string) ([]map[string]any, error)
+	GetRICoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error)
+	GetSPCoverage(ctx context.Context, accountID, startDate, endDate string) (map[string]any, error)
+	GetCURLineItems(ctx context.Context, accountID, startDate, endDate string, service string) ([]map[string]any, error)
 }
 
 // InfraQuerier provides infrastructure data needed by the triage classifier.
 type InfraQuerier interface {
-	RecentDeploys(service string) ([]map[string]any, error)
-	CloudWatchMetrics(resourceID, metricName, namespace string) (map[string]any, error)
+	RecentDeploys(ctx context.Context, service string) ([]map[string]any, error)
+	CloudWatchMetrics(ctx context.Context, resourceID, metricName, namespace string) (map[string]any, error)
 }
 
 // KubeCostQuerier provides KubeCost allocation data.
 type KubeCostQuerier interface {
-	Allocation(window, aggregate string) (map[string]any, error)
+	Allocation(ctx context.Context, window, aggregate string) (map[string]any, error)
 }
 
 // WasteQuerier provides resource waste data from aws-doctor scans.
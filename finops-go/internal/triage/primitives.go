@@ -115,11 +115,11 @@ func Triage(
 	// ---------------------------------------------------------------
 	// 1) Commitment coverage drift (RI / SP)
 	// ---------------------------------------------------------------
-	riCov, err := cost.GetRICoverage(anomaly.AccountID, windowStart, windowEnd)
+	riCov, err := cost.GetRICoverage(ctx, anomaly.AccountID, windowStart, windowEnd)
 	if err != nil {
 		return domain.TriageResult{}, fmt.Errorf("GetRICoverage: %w", err)
 	}
-	spCov, err := cost.GetSPCoverage(anomaly.AccountID, windowStart, windowEnd)
+	spCov, err := cost.GetSPCoverage(ctx, anomaly.AccountID, windowStart, windowEnd)
 	if err != nil {
 		return domain.TriageResult{}, fmt.Errorf("GetSPCoverage: %w", err)
 	}
@@ -142,7 +142,7 @@ func Triage(
 	// ---------------------------------------------------------------
 	// 2) Credits / refunds / fees (CUR line-item types)
 	// ---------------------------------------------------------------
-	cur, err := cost.GetCURLineItems(anomaly.AccountID, windowStart, windowEnd, anomaly.Service)
+	cur, err := cost.GetCURLineItems(ctx, anomaly.AccountID, windowStart, windowEnd, anomaly.Service)
 	if err != nil {
 		return domain.TriageResult{}, fmt.Errorf("GetCURLineItems: %w", err)
 	}
@@ -250,7 +250,7 @@ func Triage(
 	// 6) KubeCost namespace allocation shift (optional)
 	// ---------------------------------------------------------------
 	if kubecost != nil {
-		alloc, err := kubecost.Allocation("24h", "namespace")
+		alloc, err := kubecost.Allocation(ctx, "24h", "namespace")
 		if err != nil {
 			return domain.TriageResult{}, fmt.Errorf("kubecost.Allocation: %w", err)
 		}
@@ -286,7 +286,7 @@ func Triage(
 	// ---------------------------------------------------------------
 	// 7) Deploy correlation
 	// ---------------------------------------------------------------
-	deploys, err := infra.RecentDeploys(anomaly.Service)
+	deploys, err := infra.RecentDeploys(ctx, anomaly.Service)
 	if err != nil {
 		return domain.TriageResult{}, fmt.Errorf("RecentDeploys: %w", err)
 	}
@@ -310,7 +310,7 @@ func Triage(
 	// ---------------------------------------------------------------
 	// 8) Expected growth (usage pct vs cost pct)
 	// ---------------------------------------------------------------
-	metrics, err := infra.CloudWatchMetrics(anomaly.Service, "Requests", "Service")
+	metrics, err := infra.CloudWatchMetrics(ctx, anomaly.Service, "Requests", "Service")
 	if err != nil {
 		return domain.TriageResult{}, fmt.Errorf("CloudWatchMetrics: %w", err)
 	}
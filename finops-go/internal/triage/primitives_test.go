@@ -400,15 +400,15 @@ type mockCostFetcher struct {
 	curItems   []map[string]any
 }
 
-func (m *mockCostFetcher) GetRICoverage(_, _, _ string) (map[string]any, error) {
+func (m *mockCostFetcher) GetRICoverage(_ context.Context, _, _, _ string) (map[string]any, error) {
 	return m.riCoverage, nil
 }
 
-func (m *mockCostFetcher) GetSPCoverage(_, _, _ string) (map[string]any, error) {
+func (m *mockCostFetcher) GetSPCoverage(_ context.Context, _, _, _ string) (map[string]any, error) {
 	return m.spCoverage, nil
 }
 
-func (m *mockCostFetcher) GetCURLineItems(_, _, _, _ string) ([]map[string]any, error) {
+func (m *mockCostFetcher) GetCURLineItems(_ context.Context, _, _, _, _ string) ([]map[string]any, error) {
 	return m.curItems, nil
 }
 
@@ -417,11 +417,11 @@ type mockInfraQuerier struct {
 	metrics map[string]any
 }
 
-func (m *mockInfraQuerier) RecentDeploys(_ string) ([]map[string]any, error) {
+func (m *mockInfraQuerier) RecentDeploys(_ context.Context, _ string) ([]map[string]any, error) {
 	return m.deploys, nil
 }
 
-func (m *mockInfraQuerier) CloudWatchMetrics(_, _, _ string) (map[string]any, error) {
+func (m *mockInfraQuerier) CloudWatchMetrics(_ context.Context, _, _, _ string) (map[string]any, error) {
 	return m.metrics, nil
 }
 
@@ -429,7 +429,7 @@ type mockKubeCostQuerier struct {
 	allocation map[string]any
 }
 
-func (m *mockKubeCostQuerier) Allocation(_, _ string) (map[string]any, error) {
+func (m *mockKubeCostQuerier) Allocation(_ context.Context, _, _ string) (map[string]any, error) {
 	return m.allocation, nil
 }
 
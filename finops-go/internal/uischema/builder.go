@@ -52,6 +52,11 @@ func Build(state domain.FinOpsState) UISchema {
 		}
 	}
 
+	// Approved but waiting for a change window.
+	if state.ScheduledFor != "" {
+		schema.Components = append(schema.Components, executionSchedule(state))
+	}
+
 	// After execution: results.
 	if len(state.Executions) > 0 {
 		schema.Components = append(schema.Components, executionResults(state.Executions))
@@ -78,6 +83,11 @@ func Build(state domain.FinOpsState) UISchema {
 		}
 	}
 
+	// Daily post-change monitoring of a "monitor" recommendation.
+	if state.Monitoring != nil {
+		schema.Components = append(schema.Components, monitoringStatus(state.Monitoring))
+	}
+
 	return schema
 }
 
@@ -242,6 +242,50 @@ func TestBuild_AfterExecution(t *testing.T) {
 	assert.True(t, found, "expected execution_results component")
 }
 
+func TestBuild_ScheduledForChangeWindow(t *testing.T) {
+	state := baseState()
+	state.CurrentPhase = "scheduled"
+	state.Analysis = &domain.AnalysisResult{
+		RecommendedActions: []domain.RecommendedAction{
+			domain.NewRecommendedAction("alert", "create_budget_alert", domain.RiskLow, "disable"),
+		},
+	}
+	state.Approval = domain.ApprovalApproved
+	state.ScheduledFor = "2026-03-02T22:00:00Z"
+
+	schema := uischema.Build(state)
+	var sched *uischema.Component
+	for i, c := range schema.Components {
+		if c.Type == uischema.ComponentExecutionSchedule {
+			sched = &schema.Components[i]
+		}
+	}
+	require.NotNil(t, sched, "expected execution_schedule component")
+	assert.Equal(t, "Scheduled for 2026-03-02T22:00:00Z", sched.Title)
+	assert.Equal(t, "2026-03-02T22:00:00Z", sched.Data["scheduled_for"])
+	assert.Empty(t, schema.Actions)
+}
+
+func TestBuild_Monitoring(t *testing.T) {
+	state := baseState()
+	state.CurrentPhase = "monitoring"
+	state.Verification = &domain.VerificationResult{ServiceHealthOK: true, Recommendation: domain.RecommendMonitor}
+	state.Monitoring = &domain.MonitoringStatus{Day: 3, TotalDays: 14, NextCheckAt: "2026-03-05T10:00:00Z"}
+
+	schema := uischema.Build(state)
+	var mon *uischema.Component
+	for i, c := range schema.Components {
+		if c.Type == uischema.ComponentMonitoringStatus {
+			mon = &schema.Components[i]
+		}
+	}
+	require.NotNil(t, mon, "expected monitoring_status component")
+	assert.Equal(t, "Post-change monitoring day 3/14", mon.Title)
+	assert.Equal(t, 3, mon.Data["day"])
+	assert.Equal(t, 14, mon.Data["total_days"])
+	assert.Equal(t, "2026-03-05T10:00:00Z", mon.Data["next_check_at"])
+}
+
 func TestBuild_AfterVerification_Rollback(t *testing.T) {
 	state := baseState()
 	state.CurrentPhase = "completed"
@@ -309,6 +353,9 @@ func TestBuild_AfterVerification_Close(t *testing.T) {
 		Recommendation:        domain.RecommendClose,
 		CostReductionObserved: true,
 		ServiceHealthOK:       true,
+		SavingsChecks: []domain.SavingsVerification{
+			{DaysAfter: 3, SavingsDaily: 20, CILow: 12, CIHigh: 28, Recommendation: domain.RecommendClose},
+		},
 	}
 
 	schema := uischema.Build(state)
@@ -319,6 +366,7 @@ func TestBuild_AfterVerification_Close(t *testing.T) {
 	for _, c := range schema.Components {
 		if c.Type == uischema.ComponentVerificationDashboard {
 			found = true
+			assert.Len(t, c.Data["savings_checks"], 1)
 		}
 	}
 	assert.True(t, found)
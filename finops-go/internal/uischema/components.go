@@ -235,6 +235,21 @@ func approvalQueue(state domain.FinOpsState) Component {
 	}
 }
 
+// executionSchedule builds the "scheduled for <time>" banner shown while
+// approved actions wait for a change window.
+func executionSchedule(state domain.FinOpsState) Component {
+	return Component{
+		Type:       ComponentExecutionSchedule,
+		Title:      "Scheduled for " + state.ScheduledFor,
+		Priority:   45,
+		Visibility: VisibilityVisible,
+		Data: map[string]any{
+			"scheduled_for":   state.ScheduledFor,
+			"approval_status": string(state.Approval),
+		},
+	}
+}
+
 // executionResults builds the post-execution summary.
 func executionResults(executions []domain.ExecutionResult) Component {
 	results := make([]map[string]any, len(executions))
@@ -271,6 +286,25 @@ func verificationDashboard(v *domain.VerificationResult) Component {
 			"service_health_ok":       v.ServiceHealthOK,
 			"health_check_details":    v.HealthCheckDetails,
 			"recommendation":          string(v.Recommendation),
+			"savings_checks":          v.SavingsChecks,
+		},
+	}
+}
+
+// monitoringStatus builds the "monitoring day 3/14" progress panel shown
+// while post-change monitoring runs, and its outcome once it ends.
+func monitoringStatus(m *domain.MonitoringStatus) Component {
+	return Component{
+		Type:       ComponentMonitoringStatus,
+		Title:      "Post-change " + m.Label(),
+		Priority:   65,
+		Visibility: VisibilityVisible,
+		Data: map[string]any{
+			"day":           m.Day,
+			"total_days":    m.TotalDays,
+			"next_check_at": m.NextCheckAt,
+			"resources":     m.Resources,
+			"outcome":       m.Outcome,
 		},
 	}
 }
@@ -27,8 +27,10 @@ const (
 	ComponentDataTransferSpike     ComponentType = "data_transfer_spike"
 	ComponentActionPlan            ComponentType = "action_plan"
 	ComponentApprovalQueue         ComponentType = "approval_queue"
+	ComponentExecutionSchedule     ComponentType = "execution_schedule"
 	ComponentExecutionResults      ComponentType = "execution_results"
 	ComponentVerificationDashboard ComponentType = "verification_dashboard"
+	ComponentMonitoringStatus      ComponentType = "monitoring_status"
 	ComponentActionEditor          ComponentType = "action_editor"
 )
 
@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,47 +10,86 @@ import (
 
 // CostChecker provides cost timeseries data for post-execution verification.
 type CostChecker interface {
-	GetCostTimeseries(service, accountID, startDate, endDate string) (map[string]any, error)
+	GetCostTimeseries(ctx context.Context, service, accountID, startDate, endDate string) (map[string]any, error)
 }
 
-// Verify performs post-execution verification by checking service health and
-// observed cost reduction, then recommends close, rollback, or monitor.
+// Change describes what an execution touched, for health verification.
+// CheckedAt ends the window compared with the baseline before ExecutedAt;
+// zero means the first HealthSpan after the change.
+type Change struct {
+	Resources  []string
+	ExecutedAt time.Time
+	CheckedAt  time.Time
+}
+
+// Verify performs post-execution verification by checking the health of the
+// changed resources and the observed cost reduction. Degraded health
+// recommends rollback regardless of savings; health that cannot be judged
+// recommends escalation to a human. Healthy changes close on observed
+// savings and are monitored otherwise.
 func Verify(
+	ctx context.Context,
 	service, accountID string,
 	cost CostChecker,
+	health HealthChecker,
+	change Change,
 	windowStart, windowEnd string,
 ) (domain.VerificationResult, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
 
-	// TODO: production impl should perform real health checks (CloudWatch alarms, error rates, etc.)
-	healthDetails := "stub: ok"
-
-	ts, err := cost.GetCostTimeseries(service, accountID, windowStart, windowEnd)
+	ts, err := cost.GetCostTimeseries(ctx, service, accountID, windowStart, windowEnd)
 	if err != nil {
 		return domain.VerificationResult{}, fmt.Errorf("verifier: get cost timeseries: %w", err)
 	}
 
 	observed := extractFloat(ts, "observed_savings_daily")
-
+	result := domain.VerificationResult{
+		VerifiedAt:            now,
+		CostReductionObserved: observed > 0,
+		ServiceHealthOK:       true,
+	}
 	if observed > 0 {
-		return domain.VerificationResult{
-			VerifiedAt:            now,
-			CostReductionObserved: true,
-			ObservedSavingsDaily:  observed,
-			ServiceHealthOK:       true,
-			HealthCheckDetails:    healthDetails,
-			Recommendation:        domain.RecommendClose,
-		}, nil
+		result.ObservedSavingsDaily = observed
 	}
 
-	return domain.VerificationResult{
-		VerifiedAt:            now,
-		CostReductionObserved: false,
-		ObservedSavingsDaily:  0.0,
-		ServiceHealthOK:       true,
-		HealthCheckDetails:    healthDetails,
-		Recommendation:        domain.RecommendMonitor,
-	}, nil
+	assessment := assessHealth(ctx, health, change)
+	result.HealthCheckDetails = assessment.Details()
+
+	switch {
+	case assessment.Status == HealthDegraded:
+		result.ServiceHealthOK = false
+		result.Recommendation = domain.RecommendRollback
+	case assessment.Status == HealthInconclusive:
+		result.ServiceHealthOK = false
+		result.Recommendation = domain.RecommendEscalate
+	case observed > 0:
+		result.Recommendation = domain.RecommendClose
+	default:
+		result.Recommendation = domain.RecommendMonitor
+	}
+	return result, nil
+}
+
+// assessHealth gathers and evaluates signals for the change. A change that
+// touched no resources has nothing to degrade. A checker failure is
+// inconclusive rather than an error, so a human looks at it instead of the
+// workflow failing verification outright.
+func assessHealth(ctx context.Context, health HealthChecker, change Change) HealthAssessment {
+	if len(change.Resources) == 0 {
+		return HealthAssessment{Status: HealthHealthy, Findings: []string{"no resources changed"}}
+	}
+	if health == nil {
+		return HealthAssessment{Status: HealthInconclusive, Findings: []string{"no health checker configured"}}
+	}
+	until := change.CheckedAt
+	if until.IsZero() {
+		until = change.ExecutedAt.Add(HealthSpan)
+	}
+	signals, err := health.HealthSignals(ctx, change.Resources, change.ExecutedAt, until)
+	if err != nil {
+		return HealthAssessment{Status: HealthInconclusive, Findings: []string{"health check failed: " + err.Error()}}
+	}
+	return EvaluateHealth(signals, DefaultHealthThresholds())
 }
 
 // extractFloat safely extracts a float64 from a map[string]any.
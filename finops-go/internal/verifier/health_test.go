@@ -1,8 +1,11 @@
 package verifier
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/finops-claw-gang/finops-go/internal/domain"
 	"github.com/finops-claw-gang/finops-go/internal/testutil"
@@ -63,7 +66,7 @@ func TestVerify(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			result, err := Verify("EC2", "123456789012", tt.cost, "2026-02-01", "2026-02-16")
+			result, err := Verify(context.Background(), "EC2", "123456789012", tt.cost, healthy, testChange, "2026-02-01", "2026-02-16")
 			if err != nil {
 				t.Fatalf("Verify: %v", err)
 			}
@@ -86,12 +89,110 @@ func TestVerify(t *testing.T) {
 func TestVerifyError(t *testing.T) {
 	t.Parallel()
 	cost := &mockCostChecker{err: errStub}
-	_, err := Verify("EC2", "123456789012", cost, "2026-02-01", "2026-02-16")
+	_, err := Verify(context.Background(), "EC2", "123456789012", cost, healthy, testChange, "2026-02-01", "2026-02-16")
 	if err == nil {
 		t.Error("expected error from failing CostChecker")
 	}
 }
 
+func TestVerify_Health(t *testing.T) {
+	t.Parallel()
+	savings := &mockCostChecker{timeseries: map[string]any{"observed_savings_daily": 50.0}}
+	lb := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/abc"
+
+	tests := []struct {
+		name         string
+		health       HealthChecker
+		change       Change
+		wantRec      domain.VerificationRecommendation
+		wantHealthOK bool
+		wantDetail   string
+	}{
+		{
+			name:         "healthy closes on savings",
+			health:       healthy,
+			change:       testChange,
+			wantRec:      domain.RecommendClose,
+			wantHealthOK: true,
+			wantDetail:   "healthy",
+		},
+		{
+			name: "alarm firing rolls back despite savings",
+			health: &mockHealthChecker{signals: domain.HealthSignals{
+				Alarms: []domain.AlarmSignal{{Name: "web-5xx", Resource: lb, State: domain.AlarmStateAlarm}},
+			}},
+			change:     testChange,
+			wantRec:    domain.RecommendRollback,
+			wantDetail: "alarm web-5xx in ALARM",
+		},
+		{
+			name:       "no signals escalates",
+			health:     &mockHealthChecker{},
+			change:     testChange,
+			wantRec:    domain.RecommendEscalate,
+			wantDetail: "inconclusive",
+		},
+		{
+			name:       "checker failure escalates",
+			health:     &mockHealthChecker{err: errStub},
+			change:     testChange,
+			wantRec:    domain.RecommendEscalate,
+			wantDetail: "health check failed",
+		},
+		{
+			name:       "no checker escalates",
+			change:     testChange,
+			wantRec:    domain.RecommendEscalate,
+			wantDetail: "no health checker",
+		},
+		{
+			name:         "nothing changed skips health",
+			health:       &mockHealthChecker{err: errStub},
+			wantRec:      domain.RecommendClose,
+			wantHealthOK: true,
+			wantDetail:   "no resources changed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Verify(context.Background(), "ELB", "123456789012", savings, tt.health, tt.change, "2026-02-01", "2026-02-16")
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.Recommendation != tt.wantRec {
+				t.Errorf("recommendation = %q, want %q", result.Recommendation, tt.wantRec)
+			}
+			if result.ServiceHealthOK != tt.wantHealthOK {
+				t.Errorf("service_health_ok = %v, want %v", result.ServiceHealthOK, tt.wantHealthOK)
+			}
+			if !strings.Contains(result.HealthCheckDetails, tt.wantDetail) {
+				t.Errorf("health_check_details = %q, want it to contain %q", result.HealthCheckDetails, tt.wantDetail)
+			}
+			if !result.CostReductionObserved {
+				t.Error("cost reduction should be recorded regardless of health")
+			}
+		})
+	}
+}
+
+var (
+	healthy    = &testutil.StubInfra{FixturesDir: testutil.GoldenDir()}
+	testChange = Change{
+		Resources:  []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/50dc6c495c0c9188"},
+		ExecutedAt: time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC),
+	}
+)
+
+type mockHealthChecker struct {
+	signals domain.HealthSignals
+	err     error
+}
+
+func (m *mockHealthChecker) HealthSignals(_ context.Context, _ []string, _, _ time.Time) (domain.HealthSignals, error) {
+	return m.signals, m.err
+}
+
 type mockCostChecker struct {
 	timeseries map[string]any
 	err        error
@@ -99,7 +200,7 @@ type mockCostChecker struct {
 
 var errStub = fmt.Errorf("stub error")
 
-func (m *mockCostChecker) GetCostTimeseries(_, _, _, _ string) (map[string]any, error) {
+func (m *mockCostChecker) GetCostTimeseries(_ context.Context, _, _, _, _ string) (map[string]any, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
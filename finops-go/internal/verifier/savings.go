@@ -0,0 +1,141 @@
+package verifier
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// DaysPerMonth converts EstimatedSavingsMonthly to a daily figure.
+const DaysPerMonth = 30.4
+
+// MinRealization is the fraction of expected savings that must be
+// realized, at statistical significance, to close.
+const MinRealization = 0.5
+
+// ErrInsufficientData is returned when either window has fewer than two days.
+var ErrInsufficientData = errors.New("verifier: need at least two days on each side")
+
+// AssessSavings compares post-execution daily spend with the baseline using
+// Welch's difference of means and a 95% confidence interval, then weighs
+// the result against the plan's expected monthly savings:
+//
+//   - spend significantly up: escalate
+//   - savings significant and at least MinRealization of expected: close
+//   - anything else (not yet significant, or only partly realized): monitor
+func AssessSavings(baseline, post []float64, expectedMonthly float64) (domain.SavingsVerification, error) {
+	if len(baseline) < 2 || len(post) < 2 {
+		return domain.SavingsVerification{}, ErrInsufficientData
+	}
+
+	mb, vb := meanVar(baseline)
+	mp, vp := meanVar(post)
+	diff := mb - mp
+
+	nb, np := float64(len(baseline)), float64(len(post))
+	se := math.Sqrt(vb/nb + vp/np)
+	half := 0.0
+	if se > 0 {
+		// Welch–Satterthwaite degrees of freedom.
+		df := math.Pow(vb/nb+vp/np, 2) /
+			(math.Pow(vb/nb, 2)/(nb-1) + math.Pow(vp/np, 2)/(np-1))
+		half = tCritical95(df) * se
+	}
+
+	sv := domain.SavingsVerification{
+		BaselineDays:         len(baseline),
+		PostDays:             len(post),
+		BaselineDailyMean:    mb,
+		PostDailyMean:        mp,
+		SavingsDaily:         diff,
+		CILow:                diff - half,
+		CIHigh:               diff + half,
+		ExpectedSavingsDaily: expectedMonthly / DaysPerMonth,
+	}
+	sv.Significant = sv.CILow > 0 || sv.CIHigh < 0
+	if sv.ExpectedSavingsDaily > 0 {
+		sv.RealizationPct = diff / sv.ExpectedSavingsDaily * 100
+	}
+
+	ci := fmt.Sprintf("$%.2f/day (95%% CI %.2f..%.2f)", diff, sv.CILow, sv.CIHigh)
+	switch {
+	case sv.CIHigh < 0:
+		sv.Recommendation = domain.RecommendEscalate
+		sv.Details = "spend increased after execution: " + ci
+	case sv.CILow <= 0:
+		sv.Recommendation = domain.RecommendMonitor
+		sv.Details = "savings not yet significant: " + ci
+	case sv.ExpectedSavingsDaily > 0 && diff < MinRealization*sv.ExpectedSavingsDaily:
+		sv.Recommendation = domain.RecommendMonitor
+		sv.Details = fmt.Sprintf("savings %s are %.0f%% of expected $%.2f/day",
+			ci, sv.RealizationPct, sv.ExpectedSavingsDaily)
+	default:
+		sv.Recommendation = domain.RecommendClose
+		sv.Details = "savings realized: " + ci
+	}
+	return sv, nil
+}
+
+// meanVar returns the mean and unbiased sample variance.
+func meanVar(xs []float64) (float64, float64) {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	mean := sum / float64(len(xs))
+	var ss float64
+	for _, x := range xs {
+		ss += (x - mean) * (x - mean)
+	}
+	return mean, ss / float64(len(xs)-1)
+}
+
+// tTable holds two-sided 95% Student t critical values for 1..30 degrees of freedom.
+var tTable = [...]float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// tCritical95 rounds fractional degrees of freedom down, which widens the
+// interval slightly rather than overstating confidence.
+func tCritical95(df float64) float64 {
+	d := int(math.Floor(df))
+	switch {
+	case d < 1:
+		return tTable[0]
+	case d <= len(tTable):
+		return tTable[d-1]
+	case d <= 40:
+		return 2.021
+	case d <= 60:
+		return 2.000
+	case d <= 120:
+		return 1.980
+	default:
+		return 1.960
+	}
+}
+
+// DailyAmounts extracts the per-day "amount" values from a cost timeseries
+// in the CostChecker shape ({"points": [{"amount": ...}, ...]}).
+func DailyAmounts(ts map[string]any) []float64 {
+	var points []map[string]any
+	switch ps := ts["points"].(type) {
+	case []map[string]any:
+		points = ps
+	case []any:
+		for _, p := range ps {
+			if m, ok := p.(map[string]any); ok {
+				points = append(points, m)
+			}
+		}
+	}
+	out := make([]float64, 0, len(points))
+	for _, p := range points {
+		out = append(out, extractFloat(p, "amount"))
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package verifier
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func TestAssessSavings(t *testing.T) {
+	t.Parallel()
+	baseline := []float64{100, 104, 98, 102, 96, 101, 99, 103, 97, 100, 102, 98, 101, 99}
+
+	tests := []struct {
+		name            string
+		post            []float64
+		expectedMonthly float64
+		wantRec         domain.VerificationRecommendation
+		wantSignificant bool
+	}{
+		{
+			name:            "clear savings close",
+			post:            []float64{70, 72, 69, 71, 70, 68},
+			expectedMonthly: 30 * DaysPerMonth,
+			wantRec:         domain.RecommendClose,
+			wantSignificant: true,
+		},
+		{
+			name:            "noisy small drop monitors",
+			post:            []float64{90, 110},
+			expectedMonthly: 5 * DaysPerMonth,
+			wantRec:         domain.RecommendMonitor,
+		},
+		{
+			name:            "significant but well short of expected monitors",
+			post:            []float64{90, 91, 89, 90, 90, 91, 89},
+			expectedMonthly: 50 * DaysPerMonth,
+			wantRec:         domain.RecommendMonitor,
+			wantSignificant: true,
+		},
+		{
+			name:            "significant increase escalates",
+			post:            []float64{130, 128, 132, 131, 129},
+			expectedMonthly: 10 * DaysPerMonth,
+			wantRec:         domain.RecommendEscalate,
+			wantSignificant: true,
+		},
+		{
+			name:            "no expectation closes on significant savings",
+			post:            []float64{80, 81, 79, 80},
+			wantRec:         domain.RecommendClose,
+			wantSignificant: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := AssessSavings(baseline, tt.post, tt.expectedMonthly)
+			if err != nil {
+				t.Fatalf("AssessSavings: %v", err)
+			}
+			if got.Recommendation != tt.wantRec {
+				t.Errorf("recommendation = %q, want %q (%s)", got.Recommendation, tt.wantRec, got.Details)
+			}
+			if got.Significant != tt.wantSignificant {
+				t.Errorf("significant = %v, want %v (%s)", got.Significant, tt.wantSignificant, got.Details)
+			}
+			if got.CILow > got.SavingsDaily || got.CIHigh < got.SavingsDaily {
+				t.Errorf("CI [%.2f, %.2f] does not contain %.2f", got.CILow, got.CIHigh, got.SavingsDaily)
+			}
+			if got.Details == "" {
+				t.Error("details should explain the recommendation")
+			}
+		})
+	}
+}
+
+func TestAssessSavings_Interval(t *testing.T) {
+	t.Parallel()
+	// Equal variances (2/3) and sizes (4): df = 2(n-1) = 6, t = 2.447,
+	// se = sqrt(2 * (2/3) / 4).
+	got, err := AssessSavings([]float64{9, 10, 11, 10}, []float64{4, 5, 6, 5}, 0)
+	if err != nil {
+		t.Fatalf("AssessSavings: %v", err)
+	}
+	wantHalf := 2.447 * math.Sqrt(2*(2.0/3.0)/4)
+	if math.Abs(got.SavingsDaily-5) > 1e-9 {
+		t.Errorf("savings = %v, want 5", got.SavingsDaily)
+	}
+	if math.Abs((got.CIHigh-got.CILow)/2-wantHalf) > 1e-6 {
+		t.Errorf("half-width = %v, want %v", (got.CIHigh-got.CILow)/2, wantHalf)
+	}
+	if got.BaselineDays != 4 || got.PostDays != 4 {
+		t.Errorf("days = %d/%d, want 4/4", got.BaselineDays, got.PostDays)
+	}
+}
+
+func TestAssessSavings_InsufficientData(t *testing.T) {
+	t.Parallel()
+	if _, err := AssessSavings([]float64{1, 2, 3}, []float64{1}, 0); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("expected ErrInsufficientData, got %v", err)
+	}
+}
+
+func TestDailyAmounts(t *testing.T) {
+	t.Parallel()
+	decoded := map[string]any{"points": []any{
+		map[string]any{"date": "2026-02-15", "amount": 3150.0},
+		map[string]any{"date": "2026-02-16", "amount": 3100.0},
+	}}
+	typed := map[string]any{"points": []map[string]any{{"amount": 1.5}}}
+
+	if got := DailyAmounts(decoded); len(got) != 2 || got[1] != 3100 {
+		t.Errorf("DailyAmounts(decoded) = %v", got)
+	}
+	if got := DailyAmounts(typed); len(got) != 1 || got[0] != 1.5 {
+		t.Errorf("DailyAmounts(typed) = %v", got)
+	}
+	if got := DailyAmounts(map[string]any{}); len(got) != 0 {
+		t.Errorf("DailyAmounts(empty) = %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+// HealthChecker gathers health signals for the resources an execution
+// changed, comparing the window before executedAt with the HealthSpan
+// ending at until, which starts no earlier than executedAt.
+type HealthChecker interface {
+	HealthSignals(ctx context.Context, resourceARNs []string, executedAt, until time.Time) (domain.HealthSignals, error)
+}
+
+// HealthSpan is the length of the windows before and after a change that
+// health checkers compare. The after window only fills once HealthSpan
+// has passed since the change.
+const HealthSpan = time.Hour
+
+// HealthThresholds decide when a metric change counts as degradation.
+type HealthThresholds struct {
+	// ErrorRateIncrease is the absolute rise in error rate tolerated
+	// (0.01 = one percentage point).
+	ErrorRateIncrease float64
+	// LatencyIncreasePct is the relative latency rise tolerated (0.5 = +50%).
+	LatencyIncreasePct float64
+	// FiveXXIncreasePct is the relative 5xx rise tolerated, and Min5xxIncrease
+	// the absolute rise below which 5xx changes are treated as noise.
+	FiveXXIncreasePct float64
+	Min5xxIncrease    float64
+	// MinSamples is the datapoints required on each side of a comparison.
+	MinSamples int
+}
+
+// DefaultHealthThresholds returns thresholds suitable for most services.
+func DefaultHealthThresholds() HealthThresholds {
+	return HealthThresholds{
+		ErrorRateIncrease:  0.01,
+		LatencyIncreasePct: 0.5,
+		FiveXXIncreasePct:  0.5,
+		Min5xxIncrease:     10,
+		MinSamples:         3,
+	}
+}
+
+// HealthStatus is the overall verdict from the signals.
+type HealthStatus string
+
+const (
+	HealthHealthy      HealthStatus = "healthy"
+	HealthDegraded     HealthStatus = "degraded"
+	HealthInconclusive HealthStatus = "inconclusive"
+)
+
+// HealthAssessment is the verdict with the findings that produced it.
+type HealthAssessment struct {
+	Status   HealthStatus
+	Findings []string
+}
+
+// Details renders the assessment for VerificationResult.HealthCheckDetails.
+func (h HealthAssessment) Details() string {
+	if len(h.Findings) == 0 {
+		return string(h.Status)
+	}
+	return string(h.Status) + ": " + strings.Join(h.Findings, "; ")
+}
+
+// EvaluateHealth judges the signals. Any alarm firing, unhealthy target, or
+// metric regression beyond the thresholds is degraded. Otherwise at least
+// one usable signal (an alarm in OK, a fully healthy target group, or a
+// metric with enough samples on both sides) is needed to call it healthy;
+// with none the result is inconclusive.
+func EvaluateHealth(s domain.HealthSignals, th HealthThresholds) HealthAssessment {
+	var degraded, notes []string
+	evidence := 0
+
+	alarms := append([]domain.AlarmSignal(nil), s.Alarms...)
+	sort.Slice(alarms, func(i, j int) bool { return alarms[i].Name < alarms[j].Name })
+	for _, a := range alarms {
+		switch a.State {
+		case domain.AlarmStateAlarm:
+			degraded = append(degraded, fmt.Sprintf("alarm %s in ALARM", a.Name))
+		case domain.AlarmStateOK:
+			evidence++
+		default:
+			notes = append(notes, fmt.Sprintf("alarm %s has insufficient data", a.Name))
+		}
+	}
+
+	for _, t := range s.Targets {
+		switch {
+		case t.Unhealthy > 0:
+			degraded = append(degraded, fmt.Sprintf("target group %s has %d/%d unhealthy targets",
+				t.TargetGroup, t.Unhealthy, t.Healthy+t.Unhealthy))
+		case t.Healthy > 0:
+			evidence++
+		default:
+			notes = append(notes, fmt.Sprintf("target group %s has no registered targets", t.TargetGroup))
+		}
+	}
+
+	for _, m := range s.Metrics {
+		if m.BeforeSamples < th.MinSamples || m.AfterSamples < th.MinSamples {
+			notes = append(notes, fmt.Sprintf("%s on %s has too few datapoints", m.Name, m.Resource))
+			continue
+		}
+		evidence++
+		if msg, bad := regressed(m, th); bad {
+			degraded = append(degraded, msg)
+		}
+	}
+
+	switch {
+	case len(degraded) > 0:
+		return HealthAssessment{Status: HealthDegraded, Findings: degraded}
+	case evidence == 0:
+		if len(notes) == 0 {
+			notes = []string{"no health signals found for changed resources"}
+		}
+		return HealthAssessment{Status: HealthInconclusive, Findings: notes}
+	default:
+		return HealthAssessment{
+			Status:   HealthHealthy,
+			Findings: []string{fmt.Sprintf("%d signals within thresholds", evidence)},
+		}
+	}
+}
+
+// regressed reports whether a metric moved past its kind's threshold.
+func regressed(m domain.MetricSignal, th HealthThresholds) (string, bool) {
+	switch m.Kind {
+	case domain.MetricErrorRate:
+		if m.After-m.Before > th.ErrorRateIncrease {
+			return fmt.Sprintf("%s on %s rose from %.2f%% to %.2f%%",
+				m.Name, m.Resource, m.Before*100, m.After*100), true
+		}
+	case domain.MetricLatency:
+		if m.Before > 0 && m.After > m.Before*(1+th.LatencyIncreasePct) {
+			return fmt.Sprintf("%s on %s rose from %.3g to %.3g",
+				m.Name, m.Resource, m.Before, m.After), true
+		}
+	case domain.Metric5xx:
+		delta := m.After - m.Before
+		if delta >= th.Min5xxIncrease && m.After > m.Before*(1+th.FiveXXIncreasePct) {
+			return fmt.Sprintf("%s on %s rose from %.0f to %.0f",
+				m.Name, m.Resource, m.Before, m.After), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,104 @@
+package verifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/finops-claw-gang/finops-go/internal/domain"
+)
+
+func metric(kind domain.MetricKind, before, after float64, samples int) domain.MetricSignal {
+	return domain.MetricSignal{
+		Resource: "arn:lb", Name: string(kind), Kind: kind,
+		Before: before, After: after, BeforeSamples: samples, AfterSamples: samples,
+	}
+}
+
+func TestEvaluateHealth(t *testing.T) {
+	t.Parallel()
+	ok := domain.AlarmSignal{Name: "ok", State: domain.AlarmStateOK}
+
+	tests := []struct {
+		name        string
+		signals     domain.HealthSignals
+		want        HealthStatus
+		wantFinding string
+	}{
+		{name: "no signals", want: HealthInconclusive, wantFinding: "no health signals"},
+		{name: "alarm ok", signals: domain.HealthSignals{Alarms: []domain.AlarmSignal{ok}}, want: HealthHealthy},
+		{
+			name:        "alarm firing",
+			signals:     domain.HealthSignals{Alarms: []domain.AlarmSignal{ok, {Name: "5xx", State: domain.AlarmStateAlarm}}},
+			want:        HealthDegraded,
+			wantFinding: "alarm 5xx in ALARM",
+		},
+		{
+			name:        "only insufficient data",
+			signals:     domain.HealthSignals{Alarms: []domain.AlarmSignal{{Name: "new", State: domain.AlarmStateInsufficientData}}},
+			want:        HealthInconclusive,
+			wantFinding: "insufficient data",
+		},
+		{
+			name:    "healthy targets",
+			signals: domain.HealthSignals{Targets: []domain.TargetSignal{{TargetGroup: "tg", Healthy: 3}}},
+			want:    HealthHealthy,
+		},
+		{
+			name:        "unhealthy target",
+			signals:     domain.HealthSignals{Targets: []domain.TargetSignal{{TargetGroup: "tg", Healthy: 2, Unhealthy: 1}}},
+			want:        HealthDegraded,
+			wantFinding: "1/3 unhealthy",
+		},
+		{
+			name:    "error rate within threshold",
+			signals: domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.MetricErrorRate, 0.01, 0.015, 12)}},
+			want:    HealthHealthy,
+		},
+		{
+			name:        "error rate regressed",
+			signals:     domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.MetricErrorRate, 0.01, 0.05, 12)}},
+			want:        HealthDegraded,
+			wantFinding: "rose from 1.00% to 5.00%",
+		},
+		{
+			name:        "latency regressed",
+			signals:     domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.MetricLatency, 0.2, 0.4, 12)}},
+			want:        HealthDegraded,
+			wantFinding: "latency",
+		},
+		{
+			name:    "latency within threshold",
+			signals: domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.MetricLatency, 0.2, 0.25, 12)}},
+			want:    HealthHealthy,
+		},
+		{
+			name:    "5xx doubled but below noise floor",
+			signals: domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.Metric5xx, 2, 5, 12)}},
+			want:    HealthHealthy,
+		},
+		{
+			name:        "5xx regressed",
+			signals:     domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.Metric5xx, 10, 40, 12)}},
+			want:        HealthDegraded,
+			wantFinding: "5xx",
+		},
+		{
+			name:        "regression with too few samples is ignored",
+			signals:     domain.HealthSignals{Metrics: []domain.MetricSignal{metric(domain.MetricErrorRate, 0, 0.5, 1)}},
+			want:        HealthInconclusive,
+			wantFinding: "too few datapoints",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := EvaluateHealth(tt.signals, DefaultHealthThresholds())
+			if got.Status != tt.want {
+				t.Errorf("status = %q, want %q (findings %v)", got.Status, tt.want, got.Findings)
+			}
+			if tt.wantFinding != "" && !strings.Contains(got.Details(), tt.wantFinding) {
+				t.Errorf("details = %q, want it to contain %q", got.Details(), tt.wantFinding)
+			}
+		})
+	}
+}
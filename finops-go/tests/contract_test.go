@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -73,7 +74,7 @@ func TestContractCURLineItemsSchema(t *testing.T) {
 	t.Parallel()
 	dir := goldenDir()
 	cost := &testutil.StubCost{FixturesDir: dir}
-	items, err := cost.GetCURLineItems("test", "2026-02-01", "2026-02-16", "EC2")
+	items, err := cost.GetCURLineItems(context.Background(), "test", "2026-02-01", "2026-02-16", "EC2")
 	if err != nil {
 		t.Fatalf("load CUR items: %v", err)
 	}
@@ -95,7 +96,7 @@ func TestContractCostTimeseriesSchema(t *testing.T) {
 	t.Parallel()
 	dir := goldenDir()
 	cost := &testutil.StubCost{FixturesDir: dir}
-	ts, err := cost.GetCostTimeseries("EC2", "test", "2026-02-01", "2026-02-16")
+	ts, err := cost.GetCostTimeseries(context.Background(), "EC2", "test", "2026-02-01", "2026-02-16")
 	if err != nil {
 		t.Fatalf("load timeseries: %v", err)
 	}
@@ -110,7 +111,7 @@ func TestContractCoverageSchema(t *testing.T) {
 	dir := goldenDir()
 	cost := &testutil.StubCost{FixturesDir: dir}
 
-	ri, err := cost.GetRICoverage("test", "2026-02-01", "2026-02-16")
+	ri, err := cost.GetRICoverage(context.Background(), "test", "2026-02-01", "2026-02-16")
 	if err != nil {
 		t.Fatalf("load RI coverage: %v", err)
 	}
@@ -118,7 +119,7 @@ func TestContractCoverageSchema(t *testing.T) {
 		t.Error("RI coverage missing coverage_delta")
 	}
 
-	sp, err := cost.GetSPCoverage("test", "2026-02-01", "2026-02-16")
+	sp, err := cost.GetSPCoverage(context.Background(), "test", "2026-02-01", "2026-02-16")
 	if err != nil {
 		t.Fatalf("load SP coverage: %v", err)
 	}
@@ -132,7 +133,7 @@ func TestContractResourceTagsSchema(t *testing.T) {
 	t.Parallel()
 	dir := goldenDir()
 	infra := &testutil.StubInfra{FixturesDir: dir}
-	tags, err := infra.ResourceTags("test-arn")
+	tags, err := infra.ResourceTags(context.Background(), "test-arn")
 	if err != nil {
 		t.Fatalf("load tags: %v", err)
 	}
@@ -146,7 +147,7 @@ func TestContractKubeCostSchema(t *testing.T) {
 	t.Parallel()
 	dir := goldenDir()
 	kube := &testutil.StubKubeCost{FixturesDir: dir}
-	alloc, err := kube.Allocation("24h", "namespace")
+	alloc, err := kube.Allocation(context.Background(), "24h", "namespace")
 	if err != nil {
 		t.Fatalf("load kubecost: %v", err)
 	}
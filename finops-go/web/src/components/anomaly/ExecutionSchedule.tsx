@@ -0,0 +1,22 @@
+import type { UIComponent } from "@/lib/types";
+
+export function ExecutionSchedule({ component }: { component: UIComponent }) {
+  const { data } = component;
+  const scheduledFor = String(data?.scheduled_for ?? "");
+  const local = scheduledFor ? new Date(scheduledFor).toLocaleString() : "";
+  return (
+    <section className="border-2 border-blue-300 rounded-lg p-4 bg-blue-50">
+      <h2 className="text-lg font-semibold mb-2">{component.title}</h2>
+      <p className="text-sm text-gray-600">
+        Approved actions are held until the next change window
+        {local && (
+          <>
+            {" "}
+            (<span className="font-medium">{local}</span> local time)
+          </>
+        )}
+        .
+      </p>
+    </section>
+  );
+}
@@ -0,0 +1,32 @@
+import type { UIComponent } from "@/lib/types";
+
+export function MonitoringStatus({ component }: { component: UIComponent }) {
+  const { data } = component;
+  const day = Number(data?.day ?? 0);
+  const total = Number(data?.total_days ?? 0);
+  const outcome = String(data?.outcome ?? "");
+  const nextCheck = String(data?.next_check_at ?? "");
+  const pct = total > 0 ? Math.min(100, (day / total) * 100) : 0;
+  return (
+    <section className="border-2 border-amber-300 rounded-lg p-4 bg-amber-50">
+      <h2 className="text-lg font-semibold mb-2">{component.title}</h2>
+      <div className="h-2 bg-amber-100 rounded mb-2">
+        <div className="h-2 bg-amber-500 rounded" style={{ width: `${pct}%` }} />
+      </div>
+      {outcome ? (
+        <p className="text-sm text-gray-700">{outcome}</p>
+      ) : (
+        <p className="text-sm text-gray-600">
+          Health and savings are re-checked daily
+          {nextCheck && (
+            <>
+              ; next check{" "}
+              <span className="font-medium">{new Date(nextCheck).toLocaleString()}</span>
+            </>
+          )}
+          .
+        </p>
+      )}
+    </section>
+  );
+}
@@ -1,4 +1,4 @@
-import type { UIComponent } from "@/lib/types";
+import type { SavingsVerification, UIComponent } from "@/lib/types";
 
 export function VerificationDashboard({
   component,
@@ -6,6 +6,7 @@ export function VerificationDashboard({
   component: UIComponent;
 }) {
   const { data } = component;
+  const checks = (data?.savings_checks ?? []) as SavingsVerification[];
   return (
     <section className="border rounded-lg p-4">
       <h2 className="text-lg font-semibold mb-2">{component.title}</h2>
@@ -45,6 +46,25 @@ export function VerificationDashboard({
           </span>
         </div>
       </div>
+      {checks.length > 0 && (
+        <ul className="mt-3 space-y-1 text-sm">
+          {checks.map((c) => (
+            <li key={c.days_after}>
+              <span className="text-gray-500">Day {c.days_after}:</span>{" "}
+              <span className="font-mono">
+                ${c.savings_daily.toFixed(2)}/day (95% CI{" "}
+                {c.ci_low.toFixed(2)}..{c.ci_high.toFixed(2)})
+              </span>
+              {c.expected_savings_daily > 0 && (
+                <span className="text-gray-500">
+                  {" "}
+                  · {c.realization_pct.toFixed(0)}% of expected
+                </span>
+              )}
+            </li>
+          ))}
+        </ul>
+      )}
     </section>
   );
 }
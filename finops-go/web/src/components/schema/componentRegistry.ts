@@ -5,8 +5,10 @@ import { TriageCard } from "../anomaly/TriageCard";
 import { EvidencePanel } from "../anomaly/EvidencePanel";
 import { ActionPlan } from "../anomaly/ActionPlan";
 import { ApprovalQueue } from "../anomaly/ApprovalQueue";
+import { ExecutionSchedule } from "../anomaly/ExecutionSchedule";
 import { ExecutionResults } from "../anomaly/ExecutionResults";
 import { VerificationDashboard } from "../anomaly/VerificationDashboard";
+import { MonitoringStatus } from "../anomaly/MonitoringStatus";
 
 // Maps ComponentType -> React component.
 // Components not in this registry are silently skipped.
@@ -24,7 +26,9 @@ export const componentRegistry: Partial<
   data_transfer_spike: EvidencePanel,
   action_plan: ActionPlan,
   approval_queue: ApprovalQueue,
+  execution_schedule: ExecutionSchedule,
   execution_results: ExecutionResults,
   verification_dashboard: VerificationDashboard,
+  monitoring_status: MonitoringStatus,
   action_editor: ActionPlan,
 };
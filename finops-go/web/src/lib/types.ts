@@ -64,6 +64,23 @@ export interface ExecutionResult {
   rollback_available: boolean;
 }
 
+export interface SavingsVerification {
+  checked_at: string;
+  days_after: number;
+  baseline_days: number;
+  post_days: number;
+  baseline_daily_mean: number;
+  post_daily_mean: number;
+  savings_daily: number;
+  ci_low: number;
+  ci_high: number;
+  significant: boolean;
+  expected_savings_daily: number;
+  realization_pct: number;
+  details: string;
+  recommendation: string;
+}
+
 export interface VerificationResult {
   verified_at: string;
   cost_reduction_observed: boolean;
@@ -71,6 +88,7 @@ export interface VerificationResult {
   service_health_ok: boolean;
   health_check_details: string;
   recommendation: string;
+  savings_checks?: SavingsVerification[];
 }
 
 export interface FinOpsState {
@@ -83,11 +101,23 @@ export interface FinOpsState {
   approval_details: string;
   executions: ExecutionResult[];
   verification?: VerificationResult;
+  monitoring?: MonitoringStatus;
   current_phase: string;
   should_terminate: boolean;
   error?: string;
 }
 
+export interface MonitoringStatus {
+  day: number;
+  total_days: number;
+  started_at: string;
+  executed_at: string;
+  next_check_at?: string;
+  resources?: string[];
+  expected_savings_monthly: number;
+  outcome?: string;
+}
+
 export interface WorkflowResult {
   state: FinOpsState;
   reason: string;
@@ -116,8 +146,10 @@ export type ComponentType =
   | "data_transfer_spike"
   | "action_plan"
   | "approval_queue"
+  | "execution_schedule"
   | "execution_results"
   | "verification_dashboard"
+  | "monitoring_status"
   | "action_editor";
 
 export interface UIComponent {